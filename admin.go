@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"services-api/internal/business"
+	"services-api/internal/db"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// runMigrateCommand implements "migrate", which creates or updates the
+// database schema, e.g. in a job run before the servers are rolled out
+func runMigrateCommand(args []string) int {
+	fs := newFlagSet("migrate", "")
+	cfg, err := loadCommandConfig(fs, args)
+	if err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitStatus(errUsage)
+	}
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return exitStatus(err)
+	}
+	defer db.Close(database)
+
+	fmt.Println("Database migrated")
+	return 0
+}
+
+// runCheckConfigCommand implements "check-config", which validates the
+// configuration and connects to the database without changing it. It fails
+// on the first problem so it can gate deployments.
+func runCheckConfigCommand(args []string) int {
+	fs := newFlagSet("check-config", "")
+	offline := fs.Bool("offline", false, "Don't connect to the database")
+	print := fs.Bool("print", false, "Print the effective configuration with secrets redacted")
+	cfg, err := loadCommandConfig(fs, args)
+	if err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitStatus(errUsage)
+	}
+
+	if *print {
+		if err := cfg.Print(os.Stdout); err != nil {
+			return exitStatus(fmt.Errorf("failed to print configuration: %w", err))
+		}
+	}
+	if !*offline {
+		database, err := db.Initialize(cfg.Database.URL)
+		if err != nil {
+			return exitStatus(err)
+		}
+		defer db.Close(database)
+		// Configuring the pool pings the database
+		if err := db.ConfigureConnectionPool(database, cfg.Database.MaxIdleConns, cfg.Database.MaxOpenConns, cfg.Database.ConnMaxLifetime); err != nil {
+			return exitStatus(err)
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Configuration is valid")
+	return 0
+}
+
+// runCreateAPIKeyCommand implements "create-api-key", which creates an API
+// key without going through the API, e.g. the first admin key of a new
+// deployment. Only the key is written to stdout so it can be captured.
+func runCreateAPIKeyCommand(args []string) int {
+	fs := newFlagSet("create-api-key", "")
+	name := fs.String("name", "", "Name of the key (required)")
+	scopes := fs.String("scopes", "", "Comma-separated scopes of the key, e.g. read,write:versions or admin (required)")
+	serviceIDs := fs.String("service-ids", "", "Comma-separated IDs of the only services the key may write")
+	expiresIn := fs.Duration("expires-in", 0, "Validity of the key, 0 for no expiry")
+	cfg, err := loadCommandConfig(fs, args)
+	if err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitStatus(errUsage)
+	}
+
+	req := models.APIKeyRequest{Name: *name, Scopes: splitList(*scopes)}
+	for _, id := range splitList(*serviceIDs) {
+		parsed, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return exitStatus(fmt.Errorf("invalid service ID %q", id))
+		}
+		req.ServiceIDs = append(req.ServiceIDs, uint(parsed))
+	}
+	if *expiresIn != 0 {
+		expiresAt := time.Now().Add(*expiresIn)
+		req.ExpiresAt = &expiresAt
+	}
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return exitStatus(err)
+	}
+	defer db.Close(database)
+
+	apiKeys := business.NewAPIKeyBusiness(repository.NewAPIKeyRepository(database))
+	key, err := apiKeys.CreateAPIKey(context.Background(), req)
+	if err != nil {
+		return exitStatus(fmt.Errorf("failed to create API key: %w", err))
+	}
+
+	fmt.Fprintf(os.Stderr, "Created API key %q (ID %d, prefix %s) with scopes %s\n", key.Name, key.ID, key.Prefix, strings.Join(key.Scopes, ","))
+	fmt.Println(key.Key)
+	return 0
+}
+
+// runPurgeDeletedCommand implements "purge-deleted", which deletes the API
+// keys revoked or expired longer than a grace period ago. Revoking a key
+// only marks it, so its rows accumulate without a scheduled purge.
+func runPurgeDeletedCommand(args []string) int {
+	fs := newFlagSet("purge-deleted", "")
+	olderThan := fs.Duration("older-than", 30*24*time.Hour, "Time API keys are kept after they were revoked or expired")
+	cfg, err := loadCommandConfig(fs, args)
+	if err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitStatus(errUsage)
+	}
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return exitStatus(err)
+	}
+	defer db.Close(database)
+
+	apiKeys := business.NewAPIKeyBusiness(repository.NewAPIKeyRepository(database))
+	deleted, err := apiKeys.PurgeAPIKeys(context.Background(), *olderThan)
+	if err != nil {
+		return exitStatus(fmt.Errorf("failed to purge API keys: %w", err))
+	}
+
+	fmt.Printf("Purged %d API keys revoked or expired more than %s ago\n", deleted, *olderThan)
+	return 0
+}
+
+// splitList splits a comma-separated flag value, ignoring empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
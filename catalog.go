@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"services-api/internal/catalog"
+	"services-api/internal/db"
+	"services-api/internal/models"
+	"services-api/internal/reconcile"
+)
+
+// sampleCatalog is the catalog seed imports without a file. It's embedded
+// so seeding works from the image, which only holds the binary.
+//
+//go:embed scripts/sample_catalog.yaml
+var sampleCatalog []byte
+
+// runSeedCommand implements "seed", which imports the sample catalog or a
+// catalog file into the database. In merge mode, the default, running it
+// again only creates what is missing.
+func runSeedCommand(args []string) int {
+	fs := newFlagSet("seed", "[file]")
+	format := fs.String("format", "", "Format of the file: yaml, json or csv (default from the extension)")
+	mode := fs.String("mode", models.ImportMerge, "merge keeps the services missing from the file, replace deletes them")
+	dryRun := fs.Bool("dry-run", false, "Print the changes without applying them")
+	cfg, err := loadCommandConfig(fs, args)
+	if err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitStatus(errUsage)
+	}
+
+	var desired *models.Catalog
+	if fs.NArg() == 0 {
+		desired, err = catalog.Decode(bytes.NewReader(sampleCatalog), catalog.FormatYAML)
+	} else {
+		desired, err = readCatalogFile(fs.Arg(0), *format)
+	}
+	if err != nil {
+		return exitStatus(err)
+	}
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return exitStatus(err)
+	}
+	defer db.Close(database)
+
+	report, err := newCatalogBusiness(database).Import(context.Background(), *desired, models.ImportOptions{Mode: *mode, DryRun: *dryRun})
+	if err != nil {
+		return exitStatus(fmt.Errorf("failed to import catalog: %w", err))
+	}
+
+	// The plan refers to skipped records by the name of their service
+	result := &reconcile.Result{Report: report}
+	for _, service := range desired.Services {
+		result.Files = append(result.Files, service.Name)
+	}
+	if err := reconcile.WritePlan(os.Stdout, result); err != nil {
+		return exitStatus(err)
+	}
+	if len(report.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runExportCommand implements "export", which writes the whole catalog,
+// e.g. for backups or to seed another environment
+func runExportCommand(args []string) int {
+	fs := newFlagSet("export", "")
+	format := fs.String("format", catalog.FormatYAML, "Format of the catalog: yaml, json or csv")
+	file := fs.String("file", "", "File to write (default stdout)")
+	cfg, err := loadCommandConfig(fs, args)
+	if err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitStatus(errUsage)
+	}
+	if !validCatalogFormat(*format) {
+		return exitStatus(fmt.Errorf("%w %q", catalog.ErrUnsupportedFormat, *format))
+	}
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return exitStatus(err)
+	}
+	defer db.Close(database)
+
+	exported, err := newCatalogBusiness(database).Export(context.Background())
+	if err != nil {
+		return exitStatus(fmt.Errorf("failed to export catalog: %w", err))
+	}
+
+	var w io.Writer = os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return exitStatus(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := catalog.Encode(w, *format, *exported); err != nil {
+		return exitStatus(fmt.Errorf("failed to write catalog: %w", err))
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d services\n", len(exported.Services))
+	return 0
+}
+
+// readCatalogFile decodes a catalog file in format, or in the format of its
+// extension when format is empty
+func readCatalogFile(path, format string) (*models.Catalog, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if format == "yml" {
+			format = catalog.FormatYAML
+		}
+	}
+	if !validCatalogFormat(format) {
+		return nil, fmt.Errorf("%w %q, set -format", catalog.ErrUnsupportedFormat, format)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return catalog.Decode(f, format)
+}
+
+func validCatalogFormat(format string) bool {
+	return format == catalog.FormatYAML || format == catalog.FormatJSON || format == catalog.FormatCSV
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ListCustomFields returns the custom fields services can be given
+func (c *Client) ListCustomFields(ctx context.Context) ([]CustomField, error) {
+	var fields []CustomField
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/custom-fields"}, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// GetCustomField returns a custom field
+func (c *Client) GetCustomField(ctx context.Context, id uint) (*CustomField, error) {
+	var field CustomField
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/custom-fields/%d", id)}, &field); err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// CreateCustomField defines a custom field
+func (c *Client) CreateCustomField(ctx context.Context, req CustomFieldRequest) (*CustomField, error) {
+	var field CustomField
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/custom-fields", body: req}, &field); err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// UpdateCustomField updates the definition of a custom field
+func (c *Client) UpdateCustomField(ctx context.Context, id uint, req CustomFieldRequest) (*CustomField, error) {
+	var field CustomField
+	if _, err := c.do(ctx, request{method: http.MethodPatch, path: fmt.Sprintf("/custom-fields/%d", id), body: req}, &field); err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// DeleteCustomField deletes a custom field
+func (c *Client) DeleteCustomField(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/custom-fields/%d", id)}, nil)
+	return err
+}
+
+// CreateAPIKey creates an API key. Its secret is only returned here.
+func (c *Client) CreateAPIKey(ctx context.Context, req APIKeyRequest) (*APIKeyWithSecret, error) {
+	var key APIKeyWithSecret
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/api-keys", body: req}, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys returns the API keys, without their secrets
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/api-keys"}, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RotateAPIKey replaces the secret of an API key
+func (c *Client) RotateAPIKey(ctx context.Context, id uint) (*APIKeyWithSecret, error) {
+	var key APIKeyWithSecret
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api-keys/%d/rotate", id)}, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey revokes an API key
+func (c *Client) RevokeAPIKey(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/api-keys/%d", id)}, nil)
+	return err
+}
+
+// CreateWebhook subscribes a URL to events. Its signing secret is only returned here.
+func (c *Client) CreateWebhook(ctx context.Context, req WebhookRequest) (*WebhookWithSecret, error) {
+	var webhook WebhookWithSecret
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/webhooks", body: req}, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks returns the webhooks
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks []Webhook
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/webhooks"}, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook
+func (c *Client) GetWebhook(ctx context.Context, id uint) (*Webhook, error) {
+	var webhook Webhook
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/webhooks/%d", id)}, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// UpdateWebhook replaces a webhook
+func (c *Client) UpdateWebhook(ctx context.Context, id uint, req WebhookRequest) (*Webhook, error) {
+	var webhook Webhook
+	if _, err := c.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/webhooks/%d", id), body: req}, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook deletes a webhook
+func (c *Client) DeleteWebhook(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/webhooks/%d", id)}, nil)
+	return err
+}
+
+// ListDeliveries returns the latest deliveries of a webhook, up to limit or
+// the server default when limit is 0
+func (c *Client) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]WebhookDelivery, error) {
+	query := url.Values{}
+	setInt(query, "limit", limit)
+	var deliveries []WebhookDelivery
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/webhooks/%d/deliveries", webhookID), query: query}, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues a new attempt of a delivery
+func (c *Client) Redeliver(ctx context.Context, webhookID, deliveryID uint) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/webhooks/%d/deliveries/%d/redeliver", webhookID, deliveryID)}, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
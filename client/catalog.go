@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	catalogfmt "services-api/internal/catalog"
+)
+
+// ImportOptions controls an import
+type ImportOptions struct {
+	Mode   string // ImportMerge (the default) or ImportReplace
+	DryRun bool   // Report the changes without applying them
+	Force  bool   // In replace mode, also delete services other services depend on
+}
+
+// BatchServices applies several service operations at once. A best-effort
+// batch whose operations partly failed is not an error; the results tell
+// which operations failed.
+func (c *Client) BatchServices(ctx context.Context, req ServiceBatchRequest) (*BatchResponse, error) {
+	var response BatchResponse
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/services:batch", body: req}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// BatchVersions applies several operations on the versions of a service at once
+func (c *Client) BatchVersions(ctx context.Context, serviceID uint, req VersionBatchRequest) (*BatchResponse, error) {
+	var response BatchResponse
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/services/%d/versions:batch", serviceID), body: req}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Export returns the catalog of every service
+func (c *Client) Export(ctx context.Context) (*Catalog, error) {
+	var catalog Catalog
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/export", query: url.Values{"format": {"json"}}}, &catalog); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// ExportFormat returns the catalog encoded in format: yaml, json or csv
+func (c *Client) ExportFormat(ctx context.Context, format string) ([]byte, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/export", query: url.Values{"format": {format}}, header: http.Header{"Accept": {"*/*"}}})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Import creates, updates and, in replace mode, deletes services to match catalog
+func (c *Client) Import(ctx context.Context, catalog Catalog, opts ImportOptions) (*ImportReport, error) {
+	query := url.Values{"format": {"json"}}
+	setString(query, "mode", opts.Mode)
+	setBool(query, "dry_run", opts.DryRun)
+	setBool(query, "force", opts.Force)
+	var report ImportReport
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/import", query: query, body: catalog}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ImportFormat imports a catalog encoded in format: yaml, json or csv
+func (c *Client) ImportFormat(ctx context.Context, format string, catalog []byte, opts ImportOptions) (*ImportReport, error) {
+	query := url.Values{"format": {format}}
+	setString(query, "mode", opts.Mode)
+	setBool(query, "dry_run", opts.DryRun)
+	setBool(query, "force", opts.Force)
+	var report ImportReport
+	req := request{method: http.MethodPost, path: "/import", query: query, body: catalog, contentType: catalogfmt.ContentType(format)}
+	if _, err := c.do(ctx, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Search returns the services, versions and labels matching a full-text query
+func (c *Client) Search(ctx context.Context, query SearchQuery) (*SearchResponse, error) {
+	values := url.Values{"q": {query.Query}}
+	setString(values, "type", strings.Join(query.Types, ","))
+	setInt(values, "page", query.Page)
+	setInt(values, "limit", query.Limit)
+	var response SearchResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/search", query: values}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListChanges returns up to limit changes with a sequence above since, or
+// the server default when limit is 0. Changes that were pruned return an
+// error matching ErrGone, after which the catalog has to be reloaded.
+func (c *Client) ListChanges(ctx context.Context, since uint64, limit int) (*ChangeFeed, error) {
+	query := url.Values{"since": {strconv.FormatUint(since, 10)}}
+	setInt(query, "limit", limit)
+	var feed ChangeFeed
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/changes", query: query}, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// AllChanges iterates over the changes with a sequence above since until
+// the latest one. The sequence of the last change yielded is where the
+// next iteration resumes. The iteration stops after yielding an error.
+func (c *Client) AllChanges(ctx context.Context, since uint64) iter.Seq2[Change, error] {
+	return func(yield func(Change, error) bool) {
+		for {
+			feed, err := c.ListChanges(ctx, since, 0)
+			if err != nil {
+				yield(Change{}, err)
+				return
+			}
+			for _, change := range feed.Changes {
+				if !yield(change, nil) {
+					return
+				}
+			}
+			if !feed.HasMore {
+				return
+			}
+			since = feed.NextSince
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package client is a Go client for the services API. It covers every
+// endpoint with the request and response types of the server, retries
+// requests that fail with 429 or a 5xx status, and returns error responses
+// as *Error values that can be matched with errors.Is against ErrNotFound
+// and the other sentinel errors.
+//
+//	c, err := client.New(client.Config{BaseURL: "http://localhost:8080", APIKey: key})
+//	for service, err := range c.AllServices(ctx, client.ListServicesOptions{Selector: "tier=critical"}) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// apiPrefix is the path of the API under the base URL
+	apiPrefix = "/api/v1"
+
+	defaultMaxRetries = 3
+	defaultMinBackoff = 200 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// Config configures a client
+type Config struct {
+	BaseURL    string        // Address of the server, e.g. https://services.example.com
+	APIKey     string        // Sent in the X-API-Key header
+	Token      string        // Bearer token, sent when APIKey is empty
+	UserAgent  string        // Sent in the User-Agent header when set
+	HTTPClient *http.Client  // http.DefaultClient when nil
+	MaxRetries int           // Retries of failed requests, 3 when 0 and none when negative
+	MinBackoff time.Duration // Delay before the first retry, 200ms when 0
+	MaxBackoff time.Duration // Longest delay between retries, 5s when 0
+}
+
+// Client calls the services API. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	config  Config
+	http    *http.Client
+}
+
+// New creates a client for the server at cfg.BaseURL
+func New(cfg Config) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", cfg.BaseURL)
+	}
+
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: baseURL, config: cfg, http: httpClient}, nil
+}
+
+// request describes an API call. Body is encoded as JSON unless it is a
+// []byte, which is sent as is with contentType.
+type request struct {
+	method      string
+	path        string // Relative to the API prefix
+	unversioned bool   // The path is relative to the base URL instead
+	query       url.Values
+	body        any
+	contentType string
+	header      http.Header
+}
+
+// do sends req, retrying it when it fails with a retryable status, and
+// decodes the JSON response into out when it isn't nil. Returns the status
+// of the response.
+func (c *Client) do(ctx context.Context, req request, out any) (int, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode %s %s response: %w", req.method, req.path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// send sends req until it succeeds or the retries are exhausted. Error
+// responses are returned as *Error; the caller closes the body of a
+// successful response.
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	var body []byte
+	contentType := req.contentType
+	switch value := req.body.(type) {
+	case nil:
+	case []byte:
+		body = value
+	default:
+		var err error
+		if body, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("failed to encode %s %s request: %w", req.method, req.path, err)
+		}
+		contentType = "application/json"
+	}
+
+	for attempt := 0; ; attempt++ {
+		httpReq, err := c.newRequest(ctx, req, body, contentType)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.http.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < 400 {
+			return resp, nil
+		}
+
+		apiErr := decodeError(resp)
+		resp.Body.Close()
+		if attempt >= c.config.MaxRetries || !retryable(req.method, resp.StatusCode) {
+			return nil, apiErr
+		}
+
+		timer := time.NewTimer(c.backoff(attempt, resp.Header.Get("Retry-After")))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Join(ctx.Err(), apiErr)
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, req request, body []byte, contentType string) (*http.Request, error) {
+	endpoint := *c.baseURL
+	if req.unversioned {
+		endpoint.Path += req.path
+	} else {
+		endpoint.Path += apiPrefix + req.path
+	}
+	endpoint.RawQuery = req.query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if httpReq.Header.Get("Accept") == "" {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+	if c.config.APIKey != "" {
+		httpReq.Header.Set("X-API-Key", c.config.APIKey)
+	} else if c.config.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+	if c.config.UserAgent != "" {
+		httpReq.Header.Set("User-Agent", c.config.UserAgent)
+	}
+	return httpReq, nil
+}
+
+// retryable reports whether a request that failed with status can be sent
+// again. Requests rejected by the rate limiter or an unavailable server
+// weren't applied; other server errors are only retried for idempotent
+// methods, as a POST may have been applied before failing.
+func retryable(method string, status int) bool {
+	switch {
+	case status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+		return true
+	case status >= 500:
+		return method != http.MethodPost && method != http.MethodPatch
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before retry attempt+1: the Retry-After of the
+// response when it has one, and otherwise a jittered exponential delay
+func (c *Client) backoff(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	delay := c.config.MinBackoff
+	for i := 0; i < attempt && delay < c.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, c.config.MaxBackoff)
+	return delay/2 + rand.N(delay/2+1)
+}
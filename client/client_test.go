@@ -0,0 +1,456 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/auth"
+	"services-api/internal/business"
+	"services-api/internal/config"
+	"services-api/internal/models"
+	"services-api/internal/server"
+)
+
+const testAPIKey = "sk_test_secret"
+
+// memoryCatalog implements the service and version business logic in memory
+type memoryCatalog struct {
+	mu       sync.Mutex
+	services map[uint]*models.Service
+	nextID   uint
+}
+
+func newMemoryCatalog() *memoryCatalog {
+	return &memoryCatalog{services: make(map[uint]*models.Service)}
+}
+
+// ListServices pages the services by ID; the cursor is the offset of the next page
+func (m *memoryCatalog) ListServices(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]int, 0, len(m.services))
+	for id := range m.services {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+
+	offset := 0
+	if filter.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(filter.Cursor); err != nil {
+			return nil, business.ErrInvalidCursor
+		}
+	}
+	response := &models.ServiceResponse{Services: []models.ServiceModel{}, Pagination: models.Pagination{ItemsPerPage: filter.Limit}}
+	for i := offset; i < len(ids) && i < offset+filter.Limit; i++ {
+		service := m.services[uint(ids[i])]
+		response.Services = append(response.Services, models.ServiceModel{ID: service.ID, Name: service.Name, Description: service.Description})
+	}
+	if offset+filter.Limit < len(ids) {
+		response.Pagination.NextCursor = strconv.Itoa(offset + filter.Limit)
+	}
+	return response, nil
+}
+
+func (m *memoryCatalog) GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	service, ok := m.services[id]
+	if !ok {
+		return nil, business.ErrServiceNotFound
+	}
+	copied := *service
+	copied.Versions = append([]models.Version(nil), service.Versions...)
+	copied.VersionCount = len(service.Versions)
+	return &copied, nil
+}
+
+func (m *memoryCatalog) CreateService(ctx context.Context, service models.Service) (*models.Service, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if service.Name == "" {
+		return nil, business.ErrInvalidService
+	}
+	m.nextID++
+	service.ID = m.nextID
+	m.services[service.ID] = &service
+	return &service, nil
+}
+
+func (m *memoryCatalog) UpdateService(ctx context.Context, service models.Service) (*models.Service, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.services[service.ID]
+	if !ok {
+		return nil, business.ErrServiceNotFound
+	}
+	if service.Name != "" {
+		existing.Name = service.Name
+	}
+	if service.Description != "" {
+		existing.Description = service.Description
+	}
+	return existing, nil
+}
+
+func (m *memoryCatalog) DeleteService(ctx context.Context, id uint, force bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.services[id]; !ok {
+		return business.ErrServiceNotFound
+	}
+	delete(m.services, id)
+	return nil
+}
+
+func (m *memoryCatalog) ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error) {
+	return []models.LabelFacet{}, nil
+}
+
+// memoryVersions adapts the catalog to VersionBusiness
+type memoryVersions struct{ *memoryCatalog }
+
+func (m memoryVersions) CreateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	service, ok := m.services[version.ServiceID]
+	if !ok {
+		return nil, business.ErrServiceNotFound
+	}
+	m.nextID++
+	version.ID = m.nextID
+	version.IsActive = true
+	service.Versions = append(service.Versions, version)
+	return &version, nil
+}
+
+func (m memoryVersions) GetVersion(ctx context.Context, serviceID uint, versionID uint) (*models.Version, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if version := m.find(serviceID, versionID); version != nil {
+		copied := *version
+		return &copied, nil
+	}
+	return nil, business.ErrVersionNotFound
+}
+
+func (m memoryVersions) UpdateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing := m.find(version.ServiceID, version.ID)
+	if existing == nil {
+		return nil, business.ErrVersionNotFound
+	}
+	existing.Version, existing.Description = version.Version, version.Description
+	copied := *existing
+	return &copied, nil
+}
+
+func (m memoryVersions) DeleteVersion(ctx context.Context, versionID uint, serviceID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	service, ok := m.services[serviceID]
+	if !ok {
+		return business.ErrVersionNotFound
+	}
+	for i, version := range service.Versions {
+		if version.ID == versionID {
+			service.Versions = append(service.Versions[:i], service.Versions[i+1:]...)
+			return nil
+		}
+	}
+	return business.ErrVersionNotFound
+}
+
+func (m memoryVersions) DeprecateVersion(ctx context.Context, serviceID uint, versionID uint) (*models.Version, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing := m.find(serviceID, versionID)
+	if existing == nil {
+		return nil, business.ErrVersionNotFound
+	}
+	existing.IsActive = false
+	copied := *existing
+	return &copied, nil
+}
+
+func (m memoryVersions) ListVersions(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	service, ok := m.services[filter.ServiceID]
+	if !ok {
+		return nil, business.ErrServiceNotFound
+	}
+	total := len(service.Versions)
+	pages := (total + filter.Limit - 1) / filter.Limit
+	start := min((filter.Page-1)*filter.Limit, total)
+	end := min(start+filter.Limit, total)
+	return &models.VersionResponse{
+		Versions:   append([]models.Version{}, service.Versions[start:end]...),
+		Pagination: models.Pagination{CurrentPage: filter.Page, TotalPages: &pages, TotalItems: &total, ItemsPerPage: filter.Limit},
+	}, nil
+}
+
+func (m memoryVersions) find(serviceID, versionID uint) *models.Version {
+	if service, ok := m.services[serviceID]; ok {
+		for i := range service.Versions {
+			if service.Versions[i].ID == versionID {
+				return &service.Versions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// stubAPIKeys only verifies testAPIKey; the other API key operations aren't served
+type stubAPIKeys struct {
+	business.APIKeyBusiness
+}
+
+func (stubAPIKeys) VerifyAPIKey(ctx context.Context, key string) (*auth.Identity, error) {
+	if key != testAPIKey {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &auth.Identity{Subject: "1", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeAdmin}}, nil
+}
+
+// newTestServer serves the API router over an in-memory catalog, requiring
+// testAPIKey
+func newTestServer(t *testing.T) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.Auth.Required = true
+	catalog := newMemoryCatalog()
+	srv := server.NewServerWith(nil, cfg, server.Businesses{
+		Services: catalog,
+		Versions: memoryVersions{catalog},
+		APIKeys:  stubAPIKeys{},
+	})
+	ts := httptest.NewServer(srv.Router())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func newTestClient(t *testing.T, baseURL, apiKey string) *Client {
+	c, err := New(Config{BaseURL: baseURL, APIKey: apiKey, MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestClient_ServiceCRUD(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts.URL, testAPIKey)
+	ctx := context.Background()
+
+	created, err := c.CreateService(ctx, ServiceRequest{Name: "payments", Description: "Takes payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", created.Name)
+
+	service, err := c.GetService(ctx, created.ID, GetServiceOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Takes payments", service.Description)
+
+	updated, err := c.UpdateService(ctx, created.ID, ServiceRequest{Description: "Takes card payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", updated.Name)
+	assert.Equal(t, "Takes card payments", updated.Description)
+
+	assert.NoError(t, c.DeleteService(ctx, created.ID, DeleteOptions{}))
+	_, err = c.GetService(ctx, created.ID, GetServiceOptions{})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_VersionCRUD(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts.URL, testAPIKey)
+	ctx := context.Background()
+
+	service, err := c.CreateService(ctx, ServiceRequest{Name: "payments"})
+	assert.NoError(t, err)
+	version, err := c.CreateVersion(ctx, service.ID, VersionRequest{Version: "1.0.0"})
+	assert.NoError(t, err)
+	_, err = c.CreateVersion(ctx, service.ID, VersionRequest{Version: "1.1.0"})
+	assert.NoError(t, err)
+
+	page, err := c.ListVersions(ctx, service.ID, ListVersionsOptions{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, page.Versions, 1)
+	assert.Equal(t, 2, *page.Pagination.TotalItems)
+
+	var versions []string
+	for version, err := range c.AllVersions(ctx, service.ID, ListVersionsOptions{Limit: 1}) {
+		assert.NoError(t, err)
+		versions = append(versions, version.Version)
+	}
+	assert.Equal(t, []string{"1.0.0", "1.1.0"}, versions)
+
+	updated, err := c.UpdateVersion(ctx, service.ID, version.ID, VersionRequest{Version: "1.0.1", Description: "Patch"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.1", updated.Version)
+
+	got, err := c.GetVersion(ctx, service.ID, version.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Patch", got.Description)
+
+	deprecated, err := c.DeprecateVersion(ctx, service.ID, version.ID)
+	assert.NoError(t, err)
+	assert.False(t, deprecated.IsActive)
+
+	assert.NoError(t, c.DeleteVersion(ctx, service.ID, version.ID))
+	_, err = c.GetVersion(ctx, service.ID, version.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_AllServices(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts.URL, testAPIKey)
+	ctx := context.Background()
+
+	for i := range 7 {
+		_, err := c.CreateService(ctx, ServiceRequest{Name: "service-" + strconv.Itoa(i)})
+		assert.NoError(t, err)
+	}
+
+	var names []string
+	for service, err := range c.AllServices(ctx, ListServicesOptions{Limit: 3}) {
+		if !assert.NoError(t, err) {
+			break
+		}
+		names = append(names, service.Name)
+	}
+	assert.Len(t, names, 7)
+	assert.Equal(t, "service-0", names[0])
+	assert.Equal(t, "service-6", names[6])
+
+	// Stopping early doesn't fetch the next pages
+	count := 0
+	for range c.AllServices(ctx, ListServicesOptions{Limit: 3}) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, count)
+}
+
+func TestClient_Errors(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts.URL, testAPIKey)
+	ctx := context.Background()
+
+	_, err := c.GetService(ctx, 42, GetServiceOptions{})
+	var apiErr *Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "service_not_found", apiErr.Code)
+	}
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrConflict)
+
+	_, err = c.ListServices(ctx, ListServicesOptions{Selector: "tier in ("})
+	assert.ErrorIs(t, err, ErrBadRequest)
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, "invalid_selector", apiErr.Code)
+	}
+
+	// Requests are authenticated with the configured key
+	anonymous := newTestClient(t, ts.URL, "")
+	_, err = anonymous.ListServices(ctx, ListServicesOptions{})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	wrongKey := newTestClient(t, ts.URL, "sk_wrong")
+	_, err = wrongKey.ListServices(ctx, ListServicesOptions{})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestClient_Retries(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.Method]++
+		attempt := attempts[r.Method]
+		mu.Unlock()
+		if attempt < 3 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"code":"internal_error","message":"Upstream failed"}`))
+			return
+		}
+		w.Write([]byte(`{"id":1,"name":"payments"}`))
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL, testAPIKey)
+	ctx := context.Background()
+
+	// Idempotent requests are retried on server errors
+	service, err := c.GetService(ctx, 1, GetServiceOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", service.Name)
+	assert.Equal(t, 3, attempts[http.MethodGet])
+
+	// A POST may have been applied, so it isn't
+	_, err = c.CreateService(ctx, ServiceRequest{Name: "payments"})
+	assert.ErrorIs(t, err, ErrServer)
+	assert.Equal(t, 1, attempts[http.MethodPost])
+
+	// Retries stop once exhausted
+	noRetries, _ := New(Config{BaseURL: ts.URL, MaxRetries: -1})
+	attempts[http.MethodDelete] = 0
+	err = noRetries.DeleteService(ctx, 1, DeleteOptions{})
+	assert.ErrorIs(t, err, ErrServer)
+	assert.Equal(t, 1, attempts[http.MethodDelete])
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1,"name":"payments"}`))
+	}))
+	defer ts.Close()
+	c := newTestClient(t, ts.URL, testAPIKey)
+
+	// Rate-limited requests weren't applied, so even a POST is retried
+	_, err := c.CreateService(context.Background(), ServiceRequest{Name: "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestClient_ContextCancelsRetries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	c, _ := New(Config{BaseURL: ts.URL, MinBackoff: time.Hour, MaxBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.ListServices(ctx, ListServicesOptions{})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.ErrorIs(t, err, ErrServer)
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://example.com"} {
+		_, err := New(Config{BaseURL: baseURL})
+		assert.Error(t, err, baseURL)
+	}
+}
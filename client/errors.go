@@ -0,0 +1,83 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody bounds the error response read from the server
+const maxErrorBody = 64 << 10
+
+// Sentinel errors matching the status of an *Error with errors.Is
+var (
+	ErrBadRequest   = errors.New("bad request")  // 400
+	ErrUnauthorized = errors.New("unauthorized") // 401
+	ErrForbidden    = errors.New("forbidden")    // 403
+	ErrNotFound     = errors.New("not found")    // 404
+	ErrConflict     = errors.New("conflict")     // 409
+	ErrGone         = errors.New("gone")         // 410, e.g. expired changes
+	ErrRateLimited  = errors.New("rate limited") // 429
+	ErrServer       = errors.New("server error") // 5xx
+)
+
+// Error is an error response of the API
+type Error struct {
+	StatusCode int    // HTTP status of the response
+	Code       string // Machine-readable error code, e.g. service_not_found
+	Message    string // Human-readable message
+	Details    any    // Additional details, decoded from JSON
+}
+
+// Error returns the status, code and message of the response
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("services api: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("services api: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is matches the sentinel error of the status of the response
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrGone:
+		return e.StatusCode == http.StatusGone
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// decodeError reads the ErrorResponse of resp. Responses that aren't JSON,
+// such as those of proxies, keep their body as the message.
+func decodeError(resp *http.Response) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	var decoded struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Details any    `json:"details"`
+	}
+	if err := json.Unmarshal(body, &decoded); err == nil && decoded.Code != "" {
+		apiErr.Code, apiErr.Message, apiErr.Details = decoded.Code, decoded.Message, decoded.Details
+		return apiErr
+	}
+	apiErr.Message = strings.TrimSpace(string(body))
+	return apiErr
+}
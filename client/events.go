@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StreamOptions filters an event stream
+type StreamOptions struct {
+	ServiceIDs  []uint   // Only events of these services
+	Types       []string // Only events of these types
+	Selector    string   // Only events of services matching this label selector
+	LastEventID uint64   // Replay the events after this one first
+}
+
+// StreamedEvent is an event received on a stream. ID is its position in
+// the event log, to pass as LastEventID when reconnecting.
+type StreamedEvent struct {
+	ID uint64
+	Event
+}
+
+// StreamEvents iterates over the events pushed by the server until ctx is
+// done or the connection is lost, which is yielded as an error. The request
+// isn't retried; reconnect with the ID of the last event received to resume.
+func (c *Client) StreamEvents(ctx context.Context, opts StreamOptions) iter.Seq2[StreamedEvent, error] {
+	return func(yield func(StreamedEvent, error) bool) {
+		query := url.Values{}
+		if len(opts.ServiceIDs) > 0 {
+			ids := make([]string, len(opts.ServiceIDs))
+			for i, id := range opts.ServiceIDs {
+				ids[i] = strconv.FormatUint(uint64(id), 10)
+			}
+			query.Set("service_id", strings.Join(ids, ","))
+		}
+		setString(query, "type", strings.Join(opts.Types, ","))
+		setString(query, "selector", opts.Selector)
+		header := http.Header{"Accept": {"text/event-stream"}}
+		if opts.LastEventID > 0 {
+			header.Set("Last-Event-ID", strconv.FormatUint(opts.LastEventID, 10))
+		}
+
+		resp, err := c.stream(ctx, request{method: http.MethodGet, path: "/events/stream", query: query, header: header})
+		if err != nil {
+			yield(StreamedEvent{}, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		// Messages are blocks of field lines ended by a blank line;
+		// comments such as heartbeats start with a colon
+		var event StreamedEvent
+		var data strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64<<10), 4<<20)
+		for scanner.Scan() {
+			line := scanner.Text()
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "id":
+				event.ID, _ = strconv.ParseUint(value, 10, 64)
+			case "data":
+				data.WriteString(value)
+			case "":
+				if line != "" || data.Len() == 0 {
+					continue
+				}
+				if err := json.Unmarshal([]byte(data.String()), &event.Event); err != nil {
+					yield(StreamedEvent{}, fmt.Errorf("failed to decode event %d: %w", event.ID, err))
+					return
+				}
+				if !yield(event, nil) {
+					return
+				}
+				event = StreamedEvent{}
+				data.Reset()
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		err = scanner.Err()
+		if err == nil {
+			err = fmt.Errorf("event stream closed by the server")
+		}
+		yield(StreamedEvent{}, err)
+	}
+}
+
+// stream sends req once, without retries, as the response is read as it arrives
+func (c *Client) stream(ctx context.Context, req request) (*http.Response, error) {
+	httpReq, err := c.newRequest(ctx, req, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp, nil
+}
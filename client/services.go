@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListServicesOptions filters, sorts and pages a list of services. Zero
+// values leave the server defaults.
+type ListServicesOptions struct {
+	Name         string            // Partial, case-insensitive match on the name
+	Description  string            // Partial, case-insensitive match on the description
+	Filter       string            // Filter expression, e.g. version_count>=3 and name~"pay"
+	Selector     string            // Label selector, e.g. tier=critical,lang in (go,rust)
+	Metadata     map[string]string // Exact matches on custom fields
+	Sort         string            // Comma-separated sort fields, prefixed with - for descending
+	Order        string            // asc or desc, for a single sort field without prefix
+	Page         int               // Page number in offset mode
+	Cursor       string            // Cursor from a previous page, replaces Page, Sort and Order
+	Limit        int               // Services per page, up to 100
+	IncludeTotal *bool             // Count the matching services
+	Fields       []string          // Fields to return, all when empty
+	Expand       []string          // Related resources to embed: versions, dependencies, owner
+	VersionLimit int               // Embedded versions per service
+	VersionSort  string            // Order of the embedded versions, e.g. -created_at
+}
+
+func (o ListServicesOptions) values() url.Values {
+	query := url.Values{}
+	setString(query, "name", o.Name)
+	setString(query, "description", o.Description)
+	setString(query, "filter", o.Filter)
+	setString(query, "selector", o.Selector)
+	for name, value := range o.Metadata {
+		query.Set("metadata."+name, value)
+	}
+	setString(query, "sort", o.Sort)
+	setString(query, "order", o.Order)
+	setInt(query, "page", o.Page)
+	setString(query, "cursor", o.Cursor)
+	setInt(query, "limit", o.Limit)
+	if o.IncludeTotal != nil {
+		query.Set("include_total", strconv.FormatBool(*o.IncludeTotal))
+	}
+	setString(query, "fields", strings.Join(o.Fields, ","))
+	if o.Expand != nil {
+		query.Set("expand", strings.Join(o.Expand, ","))
+	}
+	setInt(query, "versions.limit", o.VersionLimit)
+	setString(query, "versions.sort", o.VersionSort)
+	return query
+}
+
+// GetServiceOptions selects what a service is returned with. All versions
+// are embedded when Expand is nil, unless VersionLimit is set; an empty
+// Expand embeds nothing. Expanded versions are bounded, AllVersions pages
+// through all of them.
+type GetServiceOptions struct {
+	Fields       []string
+	Expand       []string
+	VersionLimit int
+	VersionSort  string
+}
+
+// DeleteOptions controls a deletion
+type DeleteOptions struct {
+	Force bool // Delete a service even if other services depend on it
+}
+
+// ListServices returns a page of services
+func (c *Client) ListServices(ctx context.Context, opts ListServicesOptions) (*ServiceResponse, error) {
+	var page ServiceResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/services", query: opts.values()}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AllServices iterates over every service matching opts, following the
+// cursors of the pages. Page and Cursor are ignored. The iteration stops
+// after yielding an error.
+func (c *Client) AllServices(ctx context.Context, opts ListServicesOptions) iter.Seq2[ServiceModel, error] {
+	return func(yield func(ServiceModel, error) bool) {
+		opts.Page, opts.Cursor = 0, ""
+		for {
+			page, err := c.ListServices(ctx, opts)
+			if err != nil {
+				yield(ServiceModel{}, err)
+				return
+			}
+			for _, service := range page.Services {
+				if !yield(service, nil) {
+					return
+				}
+			}
+			if page.Pagination.NextCursor == "" {
+				return
+			}
+			opts.Cursor = page.Pagination.NextCursor
+		}
+	}
+}
+
+// GetService returns a service
+func (c *Client) GetService(ctx context.Context, id uint, opts GetServiceOptions) (*Service, error) {
+	query := url.Values{}
+	setString(query, "fields", strings.Join(opts.Fields, ","))
+	if opts.Expand != nil {
+		query.Set("expand", strings.Join(opts.Expand, ","))
+	}
+	setInt(query, "versions.limit", opts.VersionLimit)
+	setString(query, "versions.sort", opts.VersionSort)
+
+	var service Service
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d", id), query: query}, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// CreateService creates a service
+func (c *Client) CreateService(ctx context.Context, req ServiceRequest) (*ServiceModel, error) {
+	var service ServiceModel
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/services", body: req}, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// UpdateService updates the non-empty fields of req on a service
+func (c *Client) UpdateService(ctx context.Context, id uint, req ServiceRequest) (*ServiceModel, error) {
+	var service ServiceModel
+	if _, err := c.do(ctx, request{method: http.MethodPatch, path: fmt.Sprintf("/services/%d", id), body: req}, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// DeleteService deletes a service with its versions
+func (c *Client) DeleteService(ctx context.Context, id uint, opts DeleteOptions) error {
+	query := url.Values{}
+	setBool(query, "force", opts.Force)
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/services/%d", id), query: query}, nil)
+	return err
+}
+
+// PreviewServiceDeletion reports what deleting a service would remove or
+// break, without deleting anything
+func (c *Client) PreviewServiceDeletion(ctx context.Context, id uint, opts DeleteOptions) (*DeletionReport, error) {
+	query := url.Values{"dry_run": {"true"}}
+	setBool(query, "force", opts.Force)
+	var report DeletionReport
+	if _, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/services/%d", id), query: query}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListLabels returns the label keys with their values and counts, or only
+// key when it isn't empty
+func (c *Client) ListLabels(ctx context.Context, key string) ([]LabelFacet, error) {
+	query := url.Values{}
+	setString(query, "key", key)
+	var facets []LabelFacet
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/labels", query: query}, &facets); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// ServiceImpact returns the services affected by retiring a service
+func (c *Client) ServiceImpact(ctx context.Context, id uint) (*ImpactReport, error) {
+	var report ImpactReport
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d/impact", id)}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListDependencies returns the dependencies of a service
+func (c *Client) ListDependencies(ctx context.Context, serviceID uint) ([]Dependency, error) {
+	var dependencies []Dependency
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d/dependencies", serviceID)}, &dependencies); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// ListDependents returns the dependencies of other services on a service
+func (c *Client) ListDependents(ctx context.Context, serviceID uint) ([]Dependency, error) {
+	var dependencies []Dependency
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d/dependents", serviceID)}, &dependencies); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// GetDependency returns a dependency of a service
+func (c *Client) GetDependency(ctx context.Context, serviceID, dependencyID uint) (*Dependency, error) {
+	var dependency Dependency
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d/dependencies/%d", serviceID, dependencyID)}, &dependency); err != nil {
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// CreateDependency records that a service depends on another one
+func (c *Client) CreateDependency(ctx context.Context, serviceID uint, req DependencyRequest) (*Dependency, error) {
+	var dependency Dependency
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/services/%d/dependencies", serviceID), body: req}, &dependency); err != nil {
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// UpdateDependency changes the version constraint of a dependency
+func (c *Client) UpdateDependency(ctx context.Context, serviceID, dependencyID uint, req DependencyRequest) (*Dependency, error) {
+	var dependency Dependency
+	if _, err := c.do(ctx, request{method: http.MethodPatch, path: fmt.Sprintf("/services/%d/dependencies/%d", serviceID, dependencyID), body: req}, &dependency); err != nil {
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// DeleteDependency removes a dependency of a service
+func (c *Client) DeleteDependency(ctx context.Context, serviceID, dependencyID uint) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/services/%d/dependencies/%d", serviceID, dependencyID)}, nil)
+	return err
+}
+
+// GetGraph returns the dependency graph of all services
+func (c *Client) GetGraph(ctx context.Context) (*Graph, error) {
+	var graph Graph
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/graph", query: url.Values{"format": {"json"}}}, &graph); err != nil {
+		return nil, err
+	}
+	return &graph, nil
+}
+
+// Health returns the health of the server. An unhealthy server is
+// reported as an *Error with status 503.
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	var health HealthStatus
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/health", unversioned: true}, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+func setString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setInt(query url.Values, key string, value int) {
+	if value != 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setBool(query url.Values, key string, value bool) {
+	if value {
+		query.Set(key, "true")
+	}
+}
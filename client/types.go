@@ -0,0 +1,89 @@
+package client
+
+import "services-api/internal/models"
+
+// The request and response types are those of the server, so the client
+// can't drift from the API.
+type (
+	Service               = models.Service
+	ServiceModel          = models.ServiceModel
+	ServiceRequest        = models.ServiceRequest
+	ServiceResponse       = models.ServiceResponse
+	Pagination            = models.Pagination
+	Version               = models.Version
+	VersionRequest        = models.VersionRequest
+	VersionResponse       = models.VersionResponse
+	DeletionReport        = models.DeletionReport
+	LabelFacet            = models.LabelFacet
+	LabelValueCount       = models.LabelValueCount
+	Dependency            = models.Dependency
+	DependencyRequest     = models.DependencyRequest
+	Graph                 = models.Graph
+	GraphNode             = models.GraphNode
+	GraphEdge             = models.GraphEdge
+	ImpactReport          = models.ImpactReport
+	ImpactedService       = models.ImpactedService
+	SearchQuery           = models.SearchQuery
+	SearchResponse        = models.SearchResponse
+	SearchHit             = models.SearchHit
+	SearchFacet           = models.SearchFacet
+	ServiceBatchRequest   = models.ServiceBatchRequest
+	ServiceBatchOperation = models.ServiceBatchOperation
+	VersionBatchRequest   = models.VersionBatchRequest
+	VersionBatchOperation = models.VersionBatchOperation
+	BatchResponse         = models.BatchResponse
+	BatchResult           = models.BatchResult
+	Catalog               = models.Catalog
+	CatalogService        = models.CatalogService
+	CatalogVersion        = models.CatalogVersion
+	ImportReport          = models.ImportReport
+	ImportChange          = models.ImportChange
+	ImportError           = models.ImportError
+	Change                = models.Change
+	ChangeFeed            = models.ChangeFeed
+	Event                 = models.Event
+	CustomField           = models.CustomField
+	CustomFieldRequest    = models.CustomFieldRequest
+	APIKey                = models.APIKey
+	APIKeyRequest         = models.APIKeyRequest
+	APIKeyWithSecret      = models.APIKeyWithSecret
+	Webhook               = models.Webhook
+	WebhookRequest        = models.WebhookRequest
+	WebhookWithSecret     = models.WebhookWithSecret
+	WebhookDelivery       = models.WebhookDelivery
+)
+
+// Values of the request and response fields
+const (
+	BatchCreate     = models.BatchCreate
+	BatchUpdate     = models.BatchUpdate
+	BatchDelete     = models.BatchDelete
+	BatchAtomic     = models.BatchAtomic
+	BatchBestEffort = models.BatchBestEffort
+
+	ImportMerge   = models.ImportMerge
+	ImportReplace = models.ImportReplace
+	ImportCreate  = models.ImportCreate
+	ImportUpdate  = models.ImportUpdate
+	ImportDelete  = models.ImportDelete
+
+	ChangeEntityService = models.ChangeEntityService
+	ChangeEntityVersion = models.ChangeEntityVersion
+	ChangeCreate        = models.ChangeCreate
+	ChangeUpdate        = models.ChangeUpdate
+	ChangeDelete        = models.ChangeDelete
+
+	EventServiceCreated = models.EventServiceCreated
+	EventServiceUpdated = models.EventServiceUpdated
+	EventServiceDeleted = models.EventServiceDeleted
+	EventVersionCreated = models.EventVersionCreated
+	EventVersionUpdated = models.EventVersionUpdated
+	EventVersionDeleted = models.EventVersionDeleted
+)
+
+// HealthStatus is the response of the health check
+type HealthStatus struct {
+	Status     string            `json:"status"`
+	Version    string            `json:"version"`
+	Components map[string]string `json:"components"`
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// ListVersionsOptions sorts and pages the versions of a service. Zero
+// values leave the server defaults.
+type ListVersionsOptions struct {
+	Sort  string // created_at, updated_at or version, prefixed with - for descending
+	Page  int    // Page number
+	Limit int    // Versions per page, up to 100
+}
+
+// ListVersions returns a page of the versions of a service
+func (c *Client) ListVersions(ctx context.Context, serviceID uint, opts ListVersionsOptions) (*VersionResponse, error) {
+	query := url.Values{}
+	setString(query, "sort", opts.Sort)
+	setInt(query, "page", opts.Page)
+	setInt(query, "limit", opts.Limit)
+
+	var page VersionResponse
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d/versions", serviceID), query: query}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AllVersions iterates over every version of a service, page by page from
+// opts.Page. The iteration stops after yielding an error.
+func (c *Client) AllVersions(ctx context.Context, serviceID uint, opts ListVersionsOptions) iter.Seq2[Version, error] {
+	return func(yield func(Version, error) bool) {
+		opts.Page = max(opts.Page, 1)
+		for {
+			page, err := c.ListVersions(ctx, serviceID, opts)
+			if err != nil {
+				yield(Version{}, err)
+				return
+			}
+			for _, version := range page.Versions {
+				if !yield(version, nil) {
+					return
+				}
+			}
+			if len(page.Versions) == 0 || page.Pagination.TotalPages == nil || opts.Page >= *page.Pagination.TotalPages {
+				return
+			}
+			opts.Page++
+		}
+	}
+}
+
+// GetVersion returns a version of a service
+func (c *Client) GetVersion(ctx context.Context, serviceID, versionID uint) (*Version, error) {
+	var version Version
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d/versions/%d", serviceID, versionID)}, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// CreateVersion creates a version of a service
+func (c *Client) CreateVersion(ctx context.Context, serviceID uint, req VersionRequest) (*Version, error) {
+	var version Version
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/services/%d/versions", serviceID), body: req}, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// UpdateVersion replaces a version of a service
+func (c *Client) UpdateVersion(ctx context.Context, serviceID, versionID uint, req VersionRequest) (*Version, error) {
+	var version Version
+	if _, err := c.do(ctx, request{method: http.MethodPut, path: fmt.Sprintf("/services/%d/versions/%d", serviceID, versionID), body: req}, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// DeleteVersion deletes a version of a service
+func (c *Client) DeleteVersion(ctx context.Context, serviceID, versionID uint) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/services/%d/versions/%d", serviceID, versionID)}, nil)
+	return err
+}
+
+// DeprecateVersion marks a version as no longer active
+func (c *Client) DeprecateVersion(ctx context.Context, serviceID, versionID uint) (*Version, error) {
+	var version Version
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/services/%d/versions/%d/deprecate", serviceID, versionID)}, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// PreviewVersionDeletion reports the dependencies that deleting a version
+// would leave without a matching version, without deleting it
+func (c *Client) PreviewVersionDeletion(ctx context.Context, serviceID, versionID uint) (*DeletionReport, error) {
+	var report DeletionReport
+	query := url.Values{"dry_run": {"true"}}
+	if _, err := c.do(ctx, request{method: http.MethodDelete, path: fmt.Sprintf("/services/%d/versions/%d", serviceID, versionID), query: query}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// VersionImpact returns the services affected by retiring a version
+func (c *Client) VersionImpact(ctx context.Context, serviceID, versionID uint) (*ImpactReport, error) {
+	var report ImpactReport
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/services/%d/versions/%d/impact", serviceID, versionID)}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"services-api/client"
+)
+
+// Catalog formats
+var catalogFormats = []string{"yaml", "json", "csv"}
+
+// readCatalog reads a catalog file, or stdin for "-", in the given format
+// or the one of its extension
+func (a *app) readCatalog(path, format string) ([]byte, string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			format = "json"
+		case ".csv":
+			format = "csv"
+		default:
+			format = "yaml"
+		}
+	}
+	if !slices.Contains(catalogFormats, format) {
+		return nil, "", fmt.Errorf("invalid format %q, must be one of yaml, json or csv", format)
+	}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(a.stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return data, format, err
+}
+
+var importCommand = &command{
+	name:    "import",
+	args:    "<file>",
+	summary: "Create and update services and versions from a catalog file, or - for stdin",
+	setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+		format := fs.String("format", "", "Format of the file: yaml, json or csv (default from the extension)")
+		mode := fs.String("mode", client.ImportMerge, "merge keeps the services missing from the file, replace deletes them")
+		dryRun := fs.Bool("dry-run", false, "Show the changes without applying them")
+		force := fs.Bool("force", false, "With replace, also delete services other services depend on")
+		return func(ctx context.Context, a *app, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			data, format, err := a.readCatalog(args[0], *format)
+			if err != nil {
+				return err
+			}
+			api, err := a.client()
+			if err != nil {
+				return err
+			}
+			report, err := api.ImportFormat(ctx, format, data, client.ImportOptions{Mode: *mode, DryRun: *dryRun, Force: *force})
+			if err != nil {
+				return err
+			}
+			if err := a.printReport(report); err != nil {
+				return err
+			}
+			if len(report.Errors) > 0 {
+				return fmt.Errorf("%d services were skipped", len(report.Errors))
+			}
+			return nil
+		}
+	},
+}
+
+var exportCommand = &command{
+	name:    "export",
+	summary: "Write the catalog of every service with its versions",
+	setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+		format := fs.String("format", "yaml", "Format of the catalog: yaml, json or csv")
+		file := fs.String("file", "", "File to write, stdout when empty")
+		return func(ctx context.Context, a *app, args []string) error {
+			if len(args) != 0 {
+				return errUsage
+			}
+			if !slices.Contains(catalogFormats, *format) {
+				return fmt.Errorf("invalid format %q, must be one of yaml, json or csv", *format)
+			}
+			api, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := api.ExportFormat(ctx, *format)
+			if err != nil {
+				return err
+			}
+			if *file != "" {
+				return os.WriteFile(*file, data, 0o644)
+			}
+			_, err = a.stdout.Write(data)
+			return err
+		}
+	},
+}
+
+var diffCommand = &command{
+	name:    "diff",
+	args:    "<file>",
+	summary: "Show what importing a catalog file would change; exits with 1 when there are changes",
+	setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+		format := fs.String("format", "", "Format of the file: yaml, json or csv (default from the extension)")
+		mode := fs.String("mode", client.ImportMerge, "replace also shows the services missing from the file as deleted")
+		return func(ctx context.Context, a *app, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			data, format, err := a.readCatalog(args[0], *format)
+			if err != nil {
+				return err
+			}
+			api, err := a.client()
+			if err != nil {
+				return err
+			}
+			report, err := api.ImportFormat(ctx, format, data, client.ImportOptions{Mode: *mode, DryRun: true})
+			if err != nil {
+				return err
+			}
+			if err := a.printReport(report); err != nil {
+				return err
+			}
+			if len(report.Changes) > 0 || len(report.Errors) > 0 {
+				return errDifferences
+			}
+			return nil
+		}
+	},
+}
+
+// printReport writes the changes of an import, in the notation of a diff
+func (a *app) printReport(report *client.ImportReport) error {
+	format, err := a.outputFormat()
+	if err != nil {
+		return err
+	}
+	if format != outputTable {
+		return a.print(report, nil)
+	}
+
+	changes := append([]client.ImportChange(nil), report.Changes...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Service < changes[j].Service })
+	symbols := map[string]string{client.ImportCreate: "+", client.ImportUpdate: "~", client.ImportDelete: "-"}
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s %s\t%s\n", symbols[change.Action], change.Service, describeChange(change))
+	}
+	for _, e := range report.Errors {
+		fmt.Fprintf(w, "! %s\tskipped: %s\n", firstNonEmpty(e.Service, fmt.Sprintf("record %d", e.Index)), e.Error)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	summary := "%d to create, %d to update, %d to delete, %d unchanged, %d skipped\n"
+	if !report.DryRun {
+		summary = "%d created, %d updated, %d deleted, %d unchanged, %d skipped\n"
+	}
+	_, err = fmt.Fprintf(a.stdout, summary, report.Created, report.Updated, report.Deleted, report.Unchanged, len(report.Errors))
+	return err
+}
+
+func describeChange(change client.ImportChange) string {
+	var details []string
+	if len(change.Fields) > 0 {
+		details = append(details, "fields: "+strings.Join(change.Fields, ", "))
+	}
+	if len(change.AddedVersions) > 0 {
+		details = append(details, "+versions: "+strings.Join(change.AddedVersions, ", "))
+	}
+	if len(change.UpdatedVersions) > 0 {
+		details = append(details, "~versions: "+strings.Join(change.UpdatedVersions, ", "))
+	}
+	if len(change.RemovedVersions) > 0 {
+		details = append(details, "-versions: "+strings.Join(change.RemovedVersions, ", "))
+	}
+	return strings.Join(details, "; ")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// completeCommand is the hidden command the completion scripts call with
+// the words of the command line, the last one being completed. It prints
+// the candidates, one per line.
+const completeCommand = "__complete"
+
+// completionScripts load the completion of servicesctl in each shell
+var completionScripts = map[string]string{
+	"bash": `_servicesctl() {
+	local IFS=$'\n'
+	COMPREPLY=($(compgen -W "$(servicesctl __complete "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null)" -- "${COMP_WORDS[COMP_CWORD]}"))
+}
+complete -o default -F _servicesctl servicesctl
+`,
+	"zsh": `#compdef servicesctl
+_servicesctl() {
+	local -a candidates
+	candidates=("${(@f)$(servicesctl __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	if (( ${#candidates[@]} )) && [[ -n ${candidates[1]} ]]; then
+		compadd -a candidates
+	else
+		_files
+	fi
+}
+compdef _servicesctl servicesctl
+`,
+	"fish": `complete -c servicesctl -f -a '(servicesctl __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+}
+
+var completionCommand = &command{
+	name:    "completion",
+	args:    "bash|zsh|fish",
+	summary: "Print the shell completion script, e.g. source <(servicesctl completion bash)",
+	setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+		return func(ctx context.Context, a *app, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			script, ok := completionScripts[args[0]]
+			if !ok {
+				return fmt.Errorf("unsupported shell %q, must be one of bash, zsh or fish", args[0])
+			}
+			_, err := fmt.Fprint(a.stdout, script)
+			return err
+		}
+	},
+}
+
+// flagValues are the candidates of the flags taking one of a few values
+var flagValues = map[string][]string{
+	"output": outputFormats,
+	"o":      outputFormats,
+	"format": catalogFormats,
+	"mode":   {"merge", "replace"},
+}
+
+// complete prints the candidates for the last of words: subcommands,
+// flags, or flag values
+func (a *app) complete(words []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+
+	cmd := commands
+	var fs *flag.FlagSet
+	for i, word := range words[:len(words)-1] {
+		if cmd.setup != nil {
+			if fs == nil {
+				fs = flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+				new(globals).register(fs)
+				cmd.setup(fs)
+			}
+			// Complete the value of the flag before the current word
+			if i == len(words)-2 {
+				if values, ok := a.flagCandidates(fs, word); ok {
+					printCandidates(a, values, current)
+					return
+				}
+			}
+			continue
+		}
+		sub := findCommand(cmd, word)
+		if sub == nil {
+			return
+		}
+		cmd = sub
+	}
+
+	if cmd.setup == nil {
+		var names []string
+		for _, sub := range cmd.commands {
+			names = append(names, sub.name)
+		}
+		printCandidates(a, names, current)
+		return
+	}
+	if strings.HasPrefix(current, "-") {
+		if fs == nil {
+			fs = flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+			new(globals).register(fs)
+			cmd.setup(fs)
+		}
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) {
+			names = append(names, "-"+f.Name)
+		})
+		printCandidates(a, names, current)
+	}
+}
+
+// flagCandidates returns the values a flag given as word may take, when
+// word is a flag taking a value
+func (a *app) flagCandidates(fs *flag.FlagSet, word string) ([]string, bool) {
+	if !strings.HasPrefix(word, "-") || strings.Contains(word, "=") {
+		return nil, false
+	}
+	name := strings.TrimLeft(word, "-")
+	f := fs.Lookup(name)
+	if f == nil {
+		return nil, false
+	}
+	if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+		return nil, false
+	}
+	if name == "profile" {
+		cfg, err := a.loadConfig()
+		if err != nil {
+			return nil, true
+		}
+		return sortedKeys(cfg.Profiles), true
+	}
+	// Other values, such as files, are left to the shell
+	return flagValues[name], true
+}
+
+func findCommand(cmd *command, name string) *command {
+	for _, sub := range cmd.commands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+func printCandidates(a *app, candidates []string, prefix string) {
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			fmt.Fprintln(a.stdout, candidate)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"services-api/client"
+)
+
+const (
+	defaultServer = "http://localhost:8080"
+	userAgent     = "servicesctl"
+)
+
+// Environment variables overriding the profile
+const (
+	envConfig  = "SERVICESCTL_CONFIG"
+	envProfile = "SERVICESCTL_PROFILE"
+	envServer  = "SERVICESCTL_SERVER"
+	envAPIKey  = "SERVICESCTL_API_KEY"
+	envToken   = "SERVICESCTL_TOKEN"
+	envOutput  = "SERVICESCTL_OUTPUT"
+)
+
+// Profile holds the settings of an environment
+type Profile struct {
+	Server string `json:"server,omitempty" yaml:"server,omitempty"`
+	APIKey string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	Token  string `json:"token,omitempty" yaml:"token,omitempty"`
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// Config is the configuration file of servicesctl
+type Config struct {
+	CurrentProfile string             `json:"current_profile,omitempty" yaml:"current_profile,omitempty"`
+	Profiles       map[string]Profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// globals are the flags accepted by every command. They take precedence
+// over the environment, which takes precedence over the profile.
+type globals struct {
+	config  string
+	profile string
+	server  string
+	apiKey  string
+	token   string
+	output  string
+}
+
+func (g *globals) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.config, "config", "", "Configuration file (default $"+envConfig+" or the user config dir)")
+	fs.StringVar(&g.profile, "profile", "", "Profile of the configuration file to use (default $"+envProfile+" or the current profile)")
+	fs.StringVar(&g.server, "server", "", "Address of the API (default $"+envServer+", the profile or "+defaultServer+")")
+	fs.StringVar(&g.apiKey, "api-key", "", "API key (default $"+envAPIKey+" or the profile)")
+	fs.StringVar(&g.token, "token", "", "Bearer token, used without API key (default $"+envToken+" or the profile)")
+	fs.StringVar(&g.output, "output", "", "Output format: table, json or yaml (default $"+envOutput+", the profile or table)")
+	fs.StringVar(&g.output, "o", "", "Shorthand for -output")
+}
+
+// app holds the state of an invocation
+type app struct {
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	globals globals
+	api     *client.Client
+}
+
+// configPath returns the path of the configuration file
+func (a *app) configPath() (string, error) {
+	if a.globals.config != "" {
+		return a.globals.config, nil
+	}
+	if path := os.Getenv(envConfig); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the configuration file, set %s: %w", envConfig, err)
+	}
+	return filepath.Join(dir, "servicesctl", "config.yaml"), nil
+}
+
+// loadConfig reads the configuration file; a missing file is an empty configuration
+func (a *app) loadConfig() (*Config, error) {
+	path, err := a.configPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfig writes the configuration file, which holds credentials and is
+// only readable by its owner
+func (a *app) saveConfig(cfg *Config) error {
+	path, err := a.configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// settings resolves the profile in use, overridden by the environment and
+// the flags
+func (a *app) settings() (Profile, error) {
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return Profile{}, err
+	}
+	name := firstNonEmpty(a.globals.profile, os.Getenv(envProfile), cfg.CurrentProfile)
+	profile, ok := cfg.Profiles[name]
+	if name != "" && !ok {
+		return Profile{}, fmt.Errorf("profile %q doesn't exist, create it with \"servicesctl config set %s\"", name, name)
+	}
+	return Profile{
+		Server: firstNonEmpty(a.globals.server, os.Getenv(envServer), profile.Server, defaultServer),
+		APIKey: firstNonEmpty(a.globals.apiKey, os.Getenv(envAPIKey), profile.APIKey),
+		Token:  firstNonEmpty(a.globals.token, os.Getenv(envToken), profile.Token),
+		Output: firstNonEmpty(a.globals.output, os.Getenv(envOutput), profile.Output, outputTable),
+	}, nil
+}
+
+// client returns the API client of the resolved settings
+func (a *app) client() (*client.Client, error) {
+	if a.api != nil {
+		return a.api, nil
+	}
+	settings, err := a.settings()
+	if err != nil {
+		return nil, err
+	}
+	a.api, err = client.New(client.Config{
+		BaseURL:   settings.Server,
+		APIKey:    settings.APIKey,
+		Token:     settings.Token,
+		UserAgent: userAgent,
+	})
+	return a.api, err
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+var configCommand = &command{
+	name:    "config",
+	summary: "Manage the profiles of the configuration file",
+	commands: []*command{
+		{
+			name:    "view",
+			summary: "Show the profiles, without their credentials",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 0 {
+						return errUsage
+					}
+					cfg, err := a.loadConfig()
+					if err != nil {
+						return err
+					}
+					return a.printConfig(cfg)
+				}
+			},
+		},
+		{
+			name:    "set",
+			args:    "<profile>",
+			summary: "Create or update a profile with the -server, -api-key, -token and -output flags given",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				use := fs.Bool("use", false, "Make it the current profile")
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					cfg, err := a.loadConfig()
+					if err != nil {
+						return err
+					}
+					profile := cfg.Profiles[args[0]]
+					fs.Visit(func(f *flag.Flag) {
+						switch f.Name {
+						case "server":
+							profile.Server = a.globals.server
+						case "api-key":
+							profile.APIKey = a.globals.apiKey
+						case "token":
+							profile.Token = a.globals.token
+						case "output", "o":
+							profile.Output = a.globals.output
+						}
+					})
+					if profile.Output != "" && !slices.Contains(outputFormats, profile.Output) {
+						return fmt.Errorf("invalid output %q, must be one of table, json or yaml", profile.Output)
+					}
+					if cfg.Profiles == nil {
+						cfg.Profiles = make(map[string]Profile)
+					}
+					cfg.Profiles[args[0]] = profile
+					if *use || cfg.CurrentProfile == "" {
+						cfg.CurrentProfile = args[0]
+					}
+					return a.saveConfig(cfg)
+				}
+			},
+		},
+		{
+			name:    "use",
+			args:    "<profile>",
+			summary: "Make a profile the current one",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					cfg, err := a.loadConfig()
+					if err != nil {
+						return err
+					}
+					if _, ok := cfg.Profiles[args[0]]; !ok {
+						return fmt.Errorf("profile %q doesn't exist", args[0])
+					}
+					cfg.CurrentProfile = args[0]
+					return a.saveConfig(cfg)
+				}
+			},
+		},
+		{
+			name:    "delete",
+			args:    "<profile>",
+			summary: "Delete a profile",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					cfg, err := a.loadConfig()
+					if err != nil {
+						return err
+					}
+					if _, ok := cfg.Profiles[args[0]]; !ok {
+						return fmt.Errorf("profile %q doesn't exist", args[0])
+					}
+					delete(cfg.Profiles, args[0])
+					if cfg.CurrentProfile == args[0] {
+						cfg.CurrentProfile = ""
+					}
+					return a.saveConfig(cfg)
+				}
+			},
+		},
+	},
+}
+
+// printConfig prints the profiles with their credentials redacted
+func (a *app) printConfig(cfg *Config) error {
+	redacted := Config{CurrentProfile: cfg.CurrentProfile, Profiles: make(map[string]Profile, len(cfg.Profiles))}
+	for name, profile := range cfg.Profiles {
+		if profile.APIKey != "" {
+			profile.APIKey = redactedValue
+		}
+		if profile.Token != "" {
+			profile.Token = redactedValue
+		}
+		redacted.Profiles[name] = profile
+	}
+
+	return a.print(redacted, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "CURRENT\tPROFILE\tSERVER\tAUTH\tOUTPUT")
+		for _, name := range sortedKeys(redacted.Profiles) {
+			profile := redacted.Profiles[name]
+			current := ""
+			if name == redacted.CurrentProfile {
+				current = "*"
+			}
+			auth := "none"
+			if profile.APIKey != "" {
+				auth = "api key"
+			} else if profile.Token != "" {
+				auth = "token"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, name, profile.Server, auth, profile.Output)
+		}
+	})
+}
+
+// redactedValue replaces credentials in printed configurations
+const redactedValue = "<redacted>"
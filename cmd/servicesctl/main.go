@@ -0,0 +1,172 @@
+// Command servicesctl operates the service catalog through the API.
+//
+//	servicesctl services list -selector tier=critical
+//	servicesctl versions create payments 2.1.0 -description "Adds refunds"
+//	servicesctl diff catalog.yaml
+//
+// The server and credentials come from flags, SERVICESCTL_* environment
+// variables or a profile of the configuration file; see "servicesctl config".
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"services-api/client"
+)
+
+// errUsage reports invalid arguments; the usage of the command was printed
+var errUsage = errors.New("invalid usage")
+
+// errDifferences makes diff exit with status 1 when the catalogs differ
+var errDifferences = errors.New("the catalogs differ")
+
+// command is a node of the command tree: either a group of subcommands or
+// a leaf with a run function. setup registers the flags of a leaf and
+// returns the function running it with the remaining arguments.
+type command struct {
+	name     string
+	args     string // Positional arguments shown in the usage, e.g. "<service>"
+	summary  string
+	commands []*command
+	setup    func(fs *flag.FlagSet) func(ctx context.Context, a *app, args []string) error
+}
+
+// commands is the command tree of servicesctl
+var commands = &command{
+	name: "servicesctl",
+	commands: []*command{
+		servicesCommand,
+		versionsCommand,
+		importCommand,
+		exportCommand,
+		diffCommand,
+		configCommand,
+		completionCommand,
+	},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	a := &app{stdin: stdin, stdout: stdout, stderr: stderr}
+	if len(args) > 0 && args[0] == completeCommand {
+		a.complete(args[1:])
+		return 0
+	}
+
+	err := a.execute(ctx, commands, []string{commands.name}, args)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case errors.Is(err, errDifferences):
+		return 1
+	default:
+		fmt.Fprintln(stderr, "Error:", describeError(err))
+		return 1
+	}
+}
+
+// execute walks down the command tree along args and runs the leaf reached
+func (a *app) execute(ctx context.Context, cmd *command, path []string, args []string) error {
+	if cmd.setup == nil {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+			a.printGroupUsage(cmd, path)
+			if len(args) == 0 {
+				return errUsage
+			}
+			return flag.ErrHelp
+		}
+		if sub := findCommand(cmd, args[0]); sub != nil {
+			return a.execute(ctx, sub, append(path, sub.name), args[1:])
+		}
+		fmt.Fprintf(a.stderr, "Unknown command %q for %s\n\n", args[0], strings.Join(path, " "))
+		a.printGroupUsage(cmd, path)
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	a.globals.register(fs)
+	runFn := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: %s [flags] %s\n\n%s\n\nFlags:\n", strings.Join(path, " "), cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	err = runFn(ctx, a, positional)
+	if errors.Is(err, errUsage) {
+		fs.Usage()
+	}
+	return err
+}
+
+// parseInterspersed parses flags placed before, between or after the
+// positional arguments, which the flag package stops at, and returns the
+// positional arguments. Arguments after "--" are all positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		// The flag package consumes "--" and leaves the rest unparsed
+		if parsed := len(args) - len(rest); parsed > 0 && args[parsed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func (a *app) printGroupUsage(cmd *command, path []string) {
+	fmt.Fprintf(a.stderr, "Usage: %s <command> [flags]\n\nCommands:\n", strings.Join(path, " "))
+	for _, sub := range cmd.commands {
+		fmt.Fprintf(a.stderr, "  %-12s %s\n", sub.name, sub.summary)
+	}
+}
+
+// describeError adds the details of API errors to their message
+func describeError(err error) string {
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	message := apiErr.Message
+	if message == "" {
+		message = apiErr.Error()
+	}
+	if details, ok := apiErr.Details.(string); ok && details != "" && details != message {
+		message += ": " + details
+	}
+	if errors.Is(err, client.ErrUnauthorized) {
+		message += " (set an API key with -api-key, SERVICESCTL_API_KEY or the profile)"
+	}
+	return message
+}
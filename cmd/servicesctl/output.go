@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+// outputFormat returns the output format of the resolved settings
+func (a *app) outputFormat() (string, error) {
+	format := firstNonEmpty(a.globals.output, os.Getenv(envOutput))
+	if format == "" {
+		settings, err := a.settings()
+		if err != nil {
+			return "", err
+		}
+		format = settings.Output
+	}
+	if !slices.Contains(outputFormats, format) {
+		return "", fmt.Errorf("invalid output %q, must be one of table, json or yaml", format)
+	}
+	return format, nil
+}
+
+// print writes value as JSON or YAML, or calls table to write it as a table
+func (a *app) print(value any, table func(w *tabwriter.Writer)) error {
+	format, err := a.outputFormat()
+	if err != nil {
+		return err
+	}
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case outputYAML:
+		return writeYAML(a.stdout, value)
+	default:
+		w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	}
+}
+
+// writeYAML writes value as YAML with the field names and order of its
+// JSON encoding, as the API types only have JSON tags. JSON being YAML, the
+// encoding is parsed back as a YAML node and restyled as block YAML.
+func writeYAML(w io.Writer, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatLabels writes labels as a selector, e.g. lang=go,tier=critical
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range sortedKeys(labels) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"services-api/client"
+)
+
+// keyValues is a repeatable key=value flag
+type keyValues map[string]string
+
+func (kv *keyValues) String() string {
+	if kv == nil {
+		return ""
+	}
+	return formatLabels(*kv)
+}
+
+func (kv *keyValues) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("%q must be key=value", value)
+	}
+	if *kv == nil {
+		*kv = make(keyValues)
+	}
+	(*kv)[key] = val
+	return nil
+}
+
+// metadata converts custom field values to JSON values, so that numbers
+// and booleans keep their type; other values are strings
+func (kv keyValues) metadata() map[string]any {
+	if kv == nil {
+		return nil
+	}
+	values := make(map[string]any, len(kv))
+	for key, raw := range kv {
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// resolveService returns the ID of a service given by ID or by name
+func resolveService(ctx context.Context, api *client.Client, ref string) (uint, error) {
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		return uint(id), nil
+	}
+	// The name filter is a partial match, so the exact name is looked for
+	for service, err := range api.AllServices(ctx, client.ListServicesOptions{Name: ref, Fields: []string{"id", "name"}, Limit: 100}) {
+		if err != nil {
+			return 0, err
+		}
+		if service.Name == ref {
+			return service.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("service %q not found", ref)
+}
+
+var servicesCommand = &command{
+	name:    "services",
+	summary: "List, show, create, update and delete services",
+	commands: []*command{
+		{
+			name:    "list",
+			summary: "List the services",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				opts := client.ListServicesOptions{}
+				fs.StringVar(&opts.Name, "name", "", "Only services whose name contains this")
+				fs.StringVar(&opts.Selector, "selector", "", "Label selector, e.g. tier=critical,lang in (go,rust)")
+				fs.StringVar(&opts.Filter, "filter", "", `Filter expression, e.g. version_count>=3 and name~"pay"`)
+				fs.StringVar(&opts.Sort, "sort", "", "Comma-separated sort fields, prefixed with - for descending")
+				limit := fs.Int("limit", 0, "Maximum number of services, all when 0")
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 0 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					opts.Limit = 100
+					if *limit > 0 && *limit < opts.Limit {
+						opts.Limit = *limit
+					}
+					services := []client.ServiceModel{}
+					for service, err := range api.AllServices(ctx, opts) {
+						if err != nil {
+							return err
+						}
+						services = append(services, service)
+						if len(services) == *limit {
+							break
+						}
+					}
+					return a.print(services, func(w *tabwriter.Writer) {
+						fmt.Fprintln(w, "ID\tNAME\tVERSIONS\tLABELS\tUPDATED")
+						for _, service := range services {
+							fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n", service.ID, service.Name, service.VersionCount, formatLabels(service.Labels), formatTime(service.UpdatedAt))
+						}
+					})
+				}
+			},
+		},
+		{
+			name:    "get",
+			args:    "<service>",
+			summary: "Show a service, given by ID or name, with its versions",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					id, err := resolveService(ctx, api, args[0])
+					if err != nil {
+						return err
+					}
+					service, err := api.GetService(ctx, id, client.GetServiceOptions{})
+					if err != nil {
+						return err
+					}
+					return a.print(service, func(w *tabwriter.Writer) {
+						fmt.Fprintf(w, "ID:\t%d\n", service.ID)
+						fmt.Fprintf(w, "Name:\t%s\n", service.Name)
+						fmt.Fprintf(w, "Description:\t%s\n", service.Description)
+						fmt.Fprintf(w, "Labels:\t%s\n", formatLabels(service.Labels))
+						for _, key := range sortedKeys(service.Metadata) {
+							fmt.Fprintf(w, "Metadata %s:\t%v\n", key, service.Metadata[key])
+						}
+						if service.ManagedBy != "" {
+							fmt.Fprintf(w, "Managed by:\t%s\n", service.ManagedBy)
+						}
+						fmt.Fprintf(w, "Created:\t%s\n", formatTime(service.CreatedAt))
+						fmt.Fprintf(w, "Updated:\t%s\n", formatTime(service.UpdatedAt))
+						fmt.Fprintln(w)
+						writeVersions(w, service.Versions)
+					})
+				}
+			},
+		},
+		{
+			name:    "create",
+			args:    "<name>",
+			summary: "Create a service",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				description := fs.String("description", "", "Description of the service")
+				var labels, metadata keyValues
+				fs.Var(&labels, "label", "Label as key=value, repeatable")
+				fs.Var(&metadata, "metadata", "Custom field value as key=value, repeatable; JSON values keep their type")
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					service, err := api.CreateService(ctx, client.ServiceRequest{
+						Name:        args[0],
+						Description: *description,
+						Labels:      labels,
+						Metadata:    metadata.metadata(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.printService(service)
+				}
+			},
+		},
+		{
+			name:    "update",
+			args:    "<service>",
+			summary: "Update the given fields of a service; labels and metadata replace the existing ones",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				name := fs.String("name", "", "New name of the service")
+				description := fs.String("description", "", "New description of the service")
+				var labels, metadata keyValues
+				fs.Var(&labels, "label", "Label as key=value, repeatable; replaces all the labels")
+				fs.Var(&metadata, "metadata", "Custom field value as key=value, repeatable; replaces all the values")
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					id, err := resolveService(ctx, api, args[0])
+					if err != nil {
+						return err
+					}
+					service, err := api.UpdateService(ctx, id, client.ServiceRequest{
+						Name:        *name,
+						Description: *description,
+						Labels:      labels,
+						Metadata:    metadata.metadata(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.printService(service)
+				}
+			},
+		},
+		{
+			name:    "delete",
+			args:    "<service>",
+			summary: "Delete a service with its versions",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				force := fs.Bool("force", false, "Delete the service even if other services depend on it")
+				dryRun := fs.Bool("dry-run", false, "Show what would be deleted or broken without deleting anything")
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					id, err := resolveService(ctx, api, args[0])
+					if err != nil {
+						return err
+					}
+					if *dryRun {
+						report, err := api.PreviewServiceDeletion(ctx, id, client.DeleteOptions{Force: *force})
+						if err != nil {
+							return err
+						}
+						return a.print(report, func(w *tabwriter.Writer) {
+							fmt.Fprintf(w, "Service %s would be deleted with %d versions\n", report.Service.Name, len(report.Versions))
+							for _, dependent := range report.Dependents {
+								fmt.Fprintf(w, "The dependency of %s on it would be removed\n", dependent.ServiceName)
+							}
+							for _, key := range report.APIKeys {
+								fmt.Fprintf(w, "API key %s is scoped to the service\n", key.Name)
+							}
+							if report.Blocked {
+								fmt.Fprintln(w, "The deletion is blocked by its dependents, use -force")
+							}
+						})
+					}
+					if err := api.DeleteService(ctx, id, client.DeleteOptions{Force: *force}); err != nil {
+						return err
+					}
+					fmt.Fprintf(a.stderr, "Deleted service %s\n", args[0])
+					return nil
+				}
+			},
+		},
+	},
+}
+
+func (a *app) printService(service *client.ServiceModel) error {
+	return a.print(service, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tDESCRIPTION\tLABELS")
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", service.ID, service.Name, service.Description, formatLabels(service.Labels))
+	})
+}
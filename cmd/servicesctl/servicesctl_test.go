@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"services-api/client"
+)
+
+// servicesctl runs a command line against server with a configuration file
+// in a temporary directory, returning the exit status, stdout and stderr
+func servicesctl(t *testing.T, server string, args ...string) (int, string, string) {
+	t.Helper()
+	if os.Getenv(envConfig) == "" {
+		t.Setenv(envConfig, filepath.Join(t.TempDir(), "config.yaml"))
+	}
+	if server != "" {
+		t.Setenv(envServer, server)
+	}
+	var stdout, stderr bytes.Buffer
+	status := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// newCatalogServer serves a catalog of a payments service with two versions
+func newCatalogServer(t *testing.T) (*httptest.Server, *[]string) {
+	var calls []string
+	created := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	payments := client.Service{ID: 3, Name: "payments", Description: "Takes payments", Labels: map[string]string{"tier": "critical"}, Versions: []client.Version{
+		{ID: 7, ServiceID: 3, Version: "1.0.0", IsActive: true, CreatedAt: created},
+		{ID: 8, ServiceID: 3, Version: "1.1.0", IsActive: true, CreatedAt: created.Add(time.Hour)},
+	}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/services", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		services := []client.ServiceModel{{ID: 3, Name: "payments", VersionCount: 2, Labels: payments.Labels}}
+		if name := r.URL.Query().Get("name"); name != "" && !strings.Contains("payments", name) {
+			services = nil
+		} else if name == "" {
+			services = append(services, client.ServiceModel{ID: 4, Name: "payments-gateway"})
+		}
+		writeJSON(w, http.StatusOK, client.ServiceResponse{Services: services})
+	})
+	mux.HandleFunc("GET /api/v1/services/3", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		writeJSON(w, http.StatusOK, payments)
+	})
+	mux.HandleFunc("GET /api/v1/services/3/versions", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		pages := 1
+		writeJSON(w, http.StatusOK, client.VersionResponse{Versions: payments.Versions, Pagination: client.Pagination{CurrentPage: 1, TotalPages: &pages}})
+	})
+	mux.HandleFunc("POST /api/v1/services/3/versions/8/deprecate", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		version := payments.Versions[1]
+		version.IsActive = false
+		writeJSON(w, http.StatusOK, version)
+	})
+	mux.HandleFunc("POST /api/v1/import", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		writeJSON(w, http.StatusOK, client.ImportReport{Mode: "merge", DryRun: true, Created: 1, Unchanged: 1, Changes: []client.ImportChange{
+			{Action: client.ImportCreate, Service: "ledger", AddedVersions: []string{"0.1.0"}},
+		}})
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts, &calls
+}
+
+func TestServicesList(t *testing.T) {
+	ts, _ := newCatalogServer(t)
+
+	status, stdout, _ := servicesctl(t, ts.URL, "services", "list")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "ID  NAME")
+	assert.Contains(t, stdout, "3   payments          2         tier=critical")
+
+	status, stdout, _ = servicesctl(t, ts.URL, "services", "list", "-limit", "1", "-o", "json")
+	assert.Equal(t, 0, status)
+	var services []client.ServiceModel
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &services))
+	assert.Len(t, services, 1)
+
+	// YAML keeps the field names of the API
+	status, stdout, _ = servicesctl(t, ts.URL, "services", "list", "-output", "yaml")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "- id: 3\n  name: payments\n")
+	assert.Contains(t, stdout, "version_count: 2")
+}
+
+func TestVersionsDeprecate(t *testing.T) {
+	ts, calls := newCatalogServer(t)
+
+	// The service and version are resolved by name
+	status, stdout, stderr := servicesctl(t, ts.URL, "versions", "deprecate", "payments", "1.1.0")
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, "1.1.0")
+	assert.Contains(t, stdout, "false")
+	assert.Contains(t, *calls, "POST /api/v1/services/3/versions/8/deprecate")
+
+	status, _, stderr = servicesctl(t, ts.URL, "versions", "deprecate", "payments", "9.9.9")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, `version "9.9.9" not found`)
+
+	status, _, stderr = servicesctl(t, ts.URL, "versions", "deprecate", "ledger", "1.0.0")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, `service "ledger" not found`)
+}
+
+func TestVersionsLatest(t *testing.T) {
+	ts, _ := newCatalogServer(t)
+
+	status, stdout, _ := servicesctl(t, ts.URL, "versions", "latest", "3", "-o", "json")
+	assert.Equal(t, 0, status)
+	var version client.Version
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &version))
+	assert.Equal(t, "1.1.0", version.Version)
+}
+
+func TestLatestVersion(t *testing.T) {
+	created := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	versions := []client.Version{
+		{ID: 1, Version: "1.10.0", IsActive: true, CreatedAt: created},
+		{ID: 2, Version: "2.0.0", IsActive: false, CreatedAt: created.Add(2 * time.Hour)},
+		{ID: 3, Version: "1.9.0", IsActive: true, CreatedAt: created.Add(time.Hour)},
+		{ID: 4, Version: "1.10.0-rc.1", IsActive: true, CreatedAt: created.Add(time.Hour)},
+		{ID: 5, Version: "nightly", IsActive: true, CreatedAt: created.Add(3 * time.Hour)},
+	}
+	// 1.9.0 was created later, but 1.10.0 is higher
+	assert.Equal(t, uint(1), latestVersion(versions).ID)
+	assert.Nil(t, latestVersion(versions[1:2]))
+
+	// Versions that aren't semantic versions are picked by creation time
+	assert.Equal(t, uint(5), latestVersion([]client.Version{{ID: 6, Version: "weekly", IsActive: true, CreatedAt: created}, versions[4]}).ID)
+}
+
+func TestDiff(t *testing.T) {
+	ts, calls := newCatalogServer(t)
+	file := filepath.Join(t.TempDir(), "catalog.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"services":[]}`), 0o644))
+
+	// Changes make diff fail like diff(1)
+	status, stdout, _ := servicesctl(t, ts.URL, "diff", file, "-mode", "replace")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stdout, "+ ledger")
+	assert.Contains(t, stdout, "+versions: 0.1.0")
+	assert.Contains(t, stdout, "1 to create, 0 to update, 0 to delete, 1 unchanged, 0 skipped")
+	assert.Contains(t, *calls, "POST /api/v1/import?dry_run=true&format=json&mode=replace")
+}
+
+func TestErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"code": "unauthorized", "message": "Authentication required"})
+	}))
+	defer ts.Close()
+
+	status, _, stderr := servicesctl(t, ts.URL, "services", "get", "3")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "Error: Authentication required (set an API key")
+
+	status, _, stderr = servicesctl(t, ts.URL, "services", "get")
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "Usage: servicesctl services get [flags] <service>")
+
+	status, _, stderr = servicesctl(t, ts.URL, "services", "rename")
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, `Unknown command "rename"`)
+}
+
+func TestProfiles(t *testing.T) {
+	t.Setenv(envConfig, filepath.Join(t.TempDir(), "servicesctl", "config.yaml"))
+	t.Setenv(envServer, "")
+
+	status, _, stderr := servicesctl(t, "", "config", "set", "staging", "-server", "https://staging.example.com", "-api-key", "sk_staging")
+	assert.Equal(t, 0, status, stderr)
+	status, _, _ = servicesctl(t, "", "config", "set", "prod", "-server", "https://prod.example.com", "-o", "json")
+	assert.Equal(t, 0, status)
+
+	// The first profile becomes the current one
+	a := &app{}
+	settings, err := a.settings()
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{Server: "https://staging.example.com", APIKey: "sk_staging", Output: outputTable}, settings)
+
+	status, _, _ = servicesctl(t, "", "config", "use", "prod")
+	assert.Equal(t, 0, status)
+	settings, _ = a.settings()
+	assert.Equal(t, "https://prod.example.com", settings.Server)
+	assert.Equal(t, outputJSON, settings.Output)
+
+	// The environment overrides the profile and flags override both
+	t.Setenv(envProfile, "staging")
+	t.Setenv(envAPIKey, "sk_env")
+	a.globals.apiKey = "sk_flag"
+	settings, _ = a.settings()
+	assert.Equal(t, "https://staging.example.com", settings.Server)
+	assert.Equal(t, "sk_flag", settings.APIKey)
+
+	a.globals.profile = "dev"
+	_, err = a.settings()
+	assert.ErrorContains(t, err, `profile "dev" doesn't exist`)
+
+	// Credentials aren't shown
+	status, stdout, _ := servicesctl(t, "", "config", "view", "-o", "yaml")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "current_profile: prod")
+	assert.Contains(t, stdout, "api_key: <redacted>")
+	assert.NotContains(t, stdout, "sk_staging")
+}
+
+func TestComplete(t *testing.T) {
+	complete := func(words ...string) []string {
+		status, stdout, _ := servicesctl(t, "", append([]string{completeCommand}, words...)...)
+		assert.Equal(t, 0, status)
+		return strings.Fields(stdout)
+	}
+
+	assert.Equal(t, []string{"services"}, complete("se"))
+	assert.Equal(t, []string{"list", "get", "create", "update", "delete"}, complete("services", ""))
+	assert.Equal(t, []string{"-dry-run"}, complete("import", "catalog.yaml", "-d"))
+	assert.Equal(t, []string{"-format"}, complete("export", "-fo"))
+	assert.Equal(t, []string{"table", "json", "yaml"}, complete("versions", "list", "payments", "-o", ""))
+	assert.Equal(t, []string{"merge", "replace"}, complete("diff", "-mode", ""))
+	assert.Empty(t, complete("import", "-dry-run", ""))
+}
+
+func TestParseInterspersed(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "")
+	name := fs.String("name", "", "")
+
+	args, err := parseInterspersed(fs, []string{"payments", "-v", "1.0.0", "-name", "x", "--", "-literal"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payments", "1.0.0", "-literal"}, args)
+	assert.True(t, *verbose)
+	assert.Equal(t, "x", *name)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"services-api/client"
+	"services-api/internal/semver"
+)
+
+// listVersions returns every version of a service, oldest first
+func listVersions(ctx context.Context, api *client.Client, serviceID uint) ([]client.Version, error) {
+	versions := []client.Version{}
+	for version, err := range api.AllVersions(ctx, serviceID, client.ListVersionsOptions{Limit: 100}) {
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// resolveVersion returns the version of a service given by version string
+// or, failing that, by ID
+func resolveVersion(ctx context.Context, api *client.Client, serviceID uint, ref string) (*client.Version, error) {
+	versions, err := listVersions(ctx, api, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		if versions[i].Version == ref {
+			return &versions[i], nil
+		}
+	}
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		for i := range versions {
+			if versions[i].ID == uint(id) {
+				return &versions[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("version %q not found", ref)
+}
+
+// latestVersion returns the highest active version in semantic version
+// order, or nil when every version is deprecated. Versions that aren't
+// semantic versions are only picked when none is, the most recently
+// created first.
+func latestVersion(versions []client.Version) *client.Version {
+	var latest *client.Version
+	for i, version := range versions {
+		if version.IsActive && (latest == nil || newerVersion(version, *latest)) {
+			latest = &versions[i]
+		}
+	}
+	return latest
+}
+
+// newerVersion reports whether a comes after b for latestVersion
+func newerVersion(a, b client.Version) bool {
+	av, aok := semver.Parse(a.Version)
+	bv, bok := semver.Parse(b.Version)
+	if aok != bok {
+		return aok
+	}
+	if aok {
+		if c := av.Compare(bv); c != 0 {
+			return c > 0
+		}
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID > b.ID
+}
+
+var versionsCommand = &command{
+	name:    "versions",
+	summary: "List, create and deprecate the versions of a service",
+	commands: []*command{
+		{
+			name:    "list",
+			args:    "<service>",
+			summary: "List the versions of a service",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				activeOnly := fs.Bool("active", false, "Only list the active versions")
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					serviceID, err := resolveService(ctx, api, args[0])
+					if err != nil {
+						return err
+					}
+					versions, err := listVersions(ctx, api, serviceID)
+					if err != nil {
+						return err
+					}
+					listed := []client.Version{}
+					for _, version := range versions {
+						if version.IsActive || !*activeOnly {
+							listed = append(listed, version)
+						}
+					}
+					return a.print(listed, func(w *tabwriter.Writer) {
+						writeVersions(w, listed)
+					})
+				}
+			},
+		},
+		{
+			name:    "create",
+			args:    "<service> <version>",
+			summary: "Create a version of a service",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				description := fs.String("description", "", "Description of the version")
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 2 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					serviceID, err := resolveService(ctx, api, args[0])
+					if err != nil {
+						return err
+					}
+					version, err := api.CreateVersion(ctx, serviceID, client.VersionRequest{Version: args[1], Description: *description})
+					if err != nil {
+						return err
+					}
+					return a.printVersion(version)
+				}
+			},
+		},
+		{
+			name:    "deprecate",
+			args:    "<service> <version>",
+			summary: "Mark a version, given by version string or ID, as no longer active",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 2 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					serviceID, err := resolveService(ctx, api, args[0])
+					if err != nil {
+						return err
+					}
+					version, err := resolveVersion(ctx, api, serviceID, args[1])
+					if err != nil {
+						return err
+					}
+					deprecated, err := api.DeprecateVersion(ctx, serviceID, version.ID)
+					if err != nil {
+						return err
+					}
+					return a.printVersion(deprecated)
+				}
+			},
+		},
+		{
+			name:    "latest",
+			args:    "<service>",
+			summary: "Show the highest active version of a service in semantic version order",
+			setup: func(fs *flag.FlagSet) func(context.Context, *app, []string) error {
+				return func(ctx context.Context, a *app, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					api, err := a.client()
+					if err != nil {
+						return err
+					}
+					serviceID, err := resolveService(ctx, api, args[0])
+					if err != nil {
+						return err
+					}
+					versions, err := listVersions(ctx, api, serviceID)
+					if err != nil {
+						return err
+					}
+					latest := latestVersion(versions)
+					if latest == nil {
+						return fmt.Errorf("service %s has no active version", args[0])
+					}
+					return a.printVersion(latest)
+				}
+			},
+		},
+	},
+}
+
+func (a *app) printVersion(version *client.Version) error {
+	return a.print(version, func(w *tabwriter.Writer) {
+		writeVersions(w, []client.Version{*version})
+	})
+}
+
+func writeVersions(w *tabwriter.Writer, versions []client.Version) {
+	fmt.Fprintln(w, "ID\tVERSION\tACTIVE\tCREATED\tDESCRIPTION")
+	for _, version := range versions {
+		fmt.Fprintf(w, "%d\t%s\t%t\t%s\t%s\n", version.ID, version.Version, version.IsActive, formatTime(version.CreatedAt), version.Description)
+	}
+}
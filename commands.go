@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"services-api/internal/business"
+	"services-api/internal/config"
+	"services-api/internal/db"
+	"services-api/internal/repository"
+)
+
+// command is a subcommand of the binary. Every command takes the flags and
+// environment variables of the configuration, so jobs can run with the same
+// image and settings as the server.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands lists the subcommands in the order of the usage
+var commands = []command{
+	{"serve", "Run the API server (default)", runServeCommand},
+	{"migrate", "Create or update the database schema", runMigrateCommand},
+	{"seed", "Import the sample catalog or a catalog file", runSeedCommand},
+	{"check-config", "Validate the configuration and the database connection", runCheckConfigCommand},
+	{"create-api-key", "Create an API key and print it", runCreateAPIKeyCommand},
+	{"purge-deleted", "Delete API keys revoked or expired long ago", runPurgeDeletedCommand},
+	{"export", "Write the catalog as YAML, JSON or CSV", runExportCommand},
+	{"sync", "Plan or apply the service files of the sync directory", runSyncCommand},
+	{"config", "Print the effective configuration", runConfigCommand},
+}
+
+// errUsage reports invalid arguments; the usage of the command was printed
+var errUsage = errors.New("invalid usage")
+
+// runCommand runs the command named by the first argument and returns the
+// exit status. Without a command, or when the first argument is a flag, the
+// server is started.
+func runCommand(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runServeCommand(args)
+	}
+	if args[0] == "help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[0])
+	printUsage(os.Stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: services-api [command] [flags]\n\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-15s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun \"services-api <command> -h\" for the flags of a command.")
+}
+
+// newFlagSet creates the flag set of a command. args describes its
+// positional arguments in the usage.
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet("services-api "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: services-api %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// loadCommandConfig parses the flags of a command together with the
+// configuration flags and validates the configuration
+func loadCommandConfig(fs *flag.FlagSet, args []string) (*config.Config, error) {
+	cfg, err := config.LoadFlags(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// openDatabase connects to the database and runs the migrations, like the
+// server does, so a command also works on a fresh database
+func openDatabase(cfg *config.Config) (*gorm.DB, error) {
+	database, err := db.Initialize(cfg.Database.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	if err := db.Migrate(database); err != nil {
+		db.Close(database)
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return database, nil
+}
+
+// newCatalogBusiness creates the catalog business over database. The events
+// of the changes it makes are stored in the outbox, which the server publishes.
+func newCatalogBusiness(database *gorm.DB) business.CatalogBusiness {
+	repositories := repository.Repositories{
+		Services:     repository.NewServiceRepository(database),
+		Versions:     repository.NewVersionRepository(database),
+		Dependencies: repository.NewDependencyRepository(database),
+		CustomFields: repository.NewCustomFieldRepository(database),
+		Outbox:       repository.NewOutboxRepository(database),
+	}
+	return business.NewCatalogBusiness(repository.NewUnitOfWork(database), repositories)
+}
+
+// exitStatus reports the error a command failed with and returns its exit
+// status: 0 for help, 2 for invalid usage and 1 otherwise
+func exitStatus(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+}
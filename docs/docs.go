@@ -15,78 +15,58 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/services": {
+        "/api-keys": {
             "get": {
-                "description": "Get a list of services with optional filtering, sorting, and pagination",
-                "tags": [
-                    "services"
-                ],
-                "summary": "List services",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by service name (case-insensitive, partial match)",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by service description (case-insensitive, partial match)",
-                        "name": "description",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "created_at",
-                        "description": "Sort field (name, created_at)",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "asc",
-                        "description": "Sort order (asc, desc)",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
+                "security": [
                     {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "ApiKeyAuth": []
                     }
                 ],
+                "description": "List all API keys. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "List API keys",
                 "responses": {
                     "200": {
-                        "description": "List of services",
+                        "description": "API keys",
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Failed to list API keys",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new service with the given name and description",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create an API key for a machine client. The plaintext key is only returned in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -94,270 +74,203 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "api-keys"
                 ],
-                "summary": "Create a new service",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "description": "Service details",
-                        "name": "service",
+                        "description": "API key details",
+                        "name": "apiKey",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceRequest"
+                            "$ref": "#/definitions/models.APIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created service",
+                        "description": "Created API key including the plaintext key",
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceModel"
+                            "$ref": "#/definitions/models.APIKeyWithSecret"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Error message",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/services/{sid}": {
-            "get": {
-                "description": "Get details of a service by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "services"
-                ],
-                "summary": "Get a service",
-                "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Service ID",
-                        "name": "sid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Service details",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/models.Service"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "401": {
+                        "description": "Authentication required",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Service not found",
+                    "403": {
+                        "description": "Admin scope required",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Failed to create API key",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api-keys/{kid}": {
             "delete": {
-                "description": "Delete a service with the given ID",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke an API key so it can no longer be used",
                 "tags": [
-                    "services"
+                    "api-keys"
                 ],
-                "summary": "Delete a service",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
-                        "minimum": 1,
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "sid",
+                        "description": "API key ID",
+                        "name": "kid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Service deleted successfully"
+                        "description": "API key revoked"
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid API key ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Service not found",
+                        "description": "API key not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Failed to revoke API key",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update a service with the given ID and details",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api-keys/{kid}/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Replace the secret of an API key. The old key stops working immediately and the new plaintext key is only returned in this response.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "api-keys"
                 ],
-                "summary": "Update a service",
+                "summary": "Rotate an API key",
                 "parameters": [
                     {
-                        "minimum": 1,
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "sid",
+                        "description": "API key ID",
+                        "name": "kid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Service details",
-                        "name": "service",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ServiceRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated service",
+                        "description": "Rotated API key including the new plaintext key",
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceModel"
+                            "$ref": "#/definitions/models.APIKeyWithSecret"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid API key ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Service not found",
+                        "description": "API key not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Failed to rotate API key",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/services/{sid}/versions": {
-            "post": {
-                "description": "Create a new version for a service",
-                "consumes": [
-                    "application/json"
+        "/changes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "List the creations, updates and deletions of services and versions in the order they were committed, for consumers mirroring the catalog. Every change has a sequence number; pass the next_since of a page as since to get the changes after it. Deletions are tombstones without a state, and deleting a service deletes its versions. Changes are kept as long as events (outbox.retention); a since before the oldest change kept returns 410 and the consumer has to reload the services and versions and restart from since=0.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "versions"
+                    "changes"
                 ],
-                "summary": "Create a new version",
+                "summary": "List catalog changes",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "sid",
-                        "in": "path",
-                        "required": true
+                        "default": 0,
+                        "description": "Sequence of the last change received, 0 to start at the oldest change kept",
+                        "name": "since",
+                        "in": "query"
                     },
                     {
-                        "description": "Version details",
-                        "name": "version",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.VersionRequest"
-                        }
+                        "type": "integer",
+                        "description": "Number of changes (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created version",
+                    "200": {
+                        "description": "Page of changes",
                         "schema": {
-                            "$ref": "#/definitions/models.Version"
+                            "$ref": "#/definitions/models.ChangeFeed"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid since",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Changes after since were pruned",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to create version",
+                        "description": "Failed to list changes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -365,9 +278,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/services/{sid}/versions/{vid}": {
+        "/custom-fields": {
             "get": {
-                "description": "Get a version by ID",
+                "description": "List the custom fields services can have in their metadata",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "List custom fields",
+                "responses": {
+                    "200": {
+                        "description": "Custom fields",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CustomField"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Define a custom field of type string, url, enum, number or date. Enum fields list their allowed values in options. Once a field is required, new services must set it and updates that replace metadata must keep it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -375,54 +320,159 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "versions"
+                    "custom-fields"
                 ],
-                "summary": "Get a version by ID",
+                "summary": "Define a custom field",
+                "parameters": [
+                    {
+                        "description": "Custom field details",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created custom field",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A field with that name exists",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/custom-fields/{fid}": {
+            "get": {
+                "description": "Get a custom field definition by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Get a custom field",
                 "parameters": [
                     {
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "sid",
+                        "description": "Custom field ID",
+                        "name": "fid",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Custom field",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a custom field and its value on every service",
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Delete a custom field",
+                "parameters": [
                     {
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "Version ID",
-                        "name": "vid",
+                        "description": "Custom field ID",
+                        "name": "fid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Version details",
+                    "204": {
+                        "description": "Custom field deleted"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
                         "schema": {
-                            "$ref": "#/definitions/models.Version"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid version ID",
+                    "403": {
+                        "description": "Admin scope required",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Version not found",
+                        "description": "Custom field not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to get version",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Update a version by ID",
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the display name, description, required flag or enum options of a custom field. The name and type can't be changed, and enum options still used by services can't be removed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -430,150 +480,3032 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "versions"
+                    "custom-fields"
                 ],
-                "summary": "Update a version",
+                "summary": "Update a custom field",
                 "parameters": [
                     {
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "sid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Version ID",
-                        "name": "vid",
+                        "description": "Custom field ID",
+                        "name": "fid",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Version details",
-                        "name": "version",
+                        "description": "Custom field details",
+                        "name": "field",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.VersionRequest"
+                            "$ref": "#/definitions/models.CustomFieldRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated version",
+                        "description": "Updated custom field",
                         "schema": {
-                            "$ref": "#/definitions/models.Version"
+                            "$ref": "#/definitions/models.CustomField"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Version not found",
+                        "description": "Custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Removed options are in use",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to update version",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a version by ID",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/events/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Push service and version change events as Server-Sent Events as they are committed. Each message has the event type as its name, the position in the event log as its ID and the event as its data. A comment is sent every 15 seconds while idle. Clients reconnecting with the Last-Event-ID header (or last_event_id) first receive the events they missed, as long as they are still in the event log. Otherwise an expired event is sent instead, after which the client should reload the catalog; the stream then goes on with new events. Streams end when the server shuts down.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "versions"
+                    "events"
                 ],
-                "summary": "Delete a version",
+                "summary": "Stream catalog events",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated service IDs to receive events of",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated event types to receive, e.g. version.created,version.deleted",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label selector the service of the event must match, e.g. tier=critical",
+                        "name": "selector",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "sid",
-                        "in": "path",
-                        "required": true
+                        "description": "Replay the events after this ID; the Last-Event-ID header takes precedence",
+                        "name": "last_event_id",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Version ID",
-                        "name": "vid",
-                        "in": "path",
-                        "required": true
+                        "description": "Replay the events after this ID",
+                        "name": "Last-Event-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Version deleted successfully"
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
                     },
                     "400": {
-                        "description": "Invalid version ID",
+                        "description": "Invalid filter or event ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export": {
+            "get": {
+                "description": "Dump every service with its versions, labels and custom field values. Services are identified by name so the dump can be imported into any environment. CSV files have a row per version.",
+                "produces": [
+                    "application/yaml",
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "catalog"
+                ],
+                "summary": "Export the service catalog",
+                "parameters": [
+                    {
+                        "enum": [
+                            "yaml",
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "yaml",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service catalog",
+                        "schema": {
+                            "$ref": "#/definitions/models.Catalog"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Version not found",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/graph": {
+            "get": {
+                "description": "Export all services and their dependencies as JSON, Graphviz DOT or Mermaid",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Export the dependency graph",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "dot",
+                            "mermaid"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dependency graph",
+                        "schema": {
+                            "$ref": "#/definitions/models.Graph"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete version",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "description": "Machine-readable error code",
-                    "type": "string"
+        },
+        "/import": {
+            "post": {
+                "description": "Create and update services from a catalog in the export format, matching services by name and versions by version string. Omitted descriptions, labels and metadata are left unchanged. In replace mode, which requires the admin scope unless dry_run=true, the services and versions missing from the catalog are deleted, except services other services depend on unless force=true. Invalid records and refused deletes are skipped and listed in the report's errors while the others are applied in one transaction. With dry_run=true the report previews the changes without applying them.",
+                "consumes": [
+                    "application/yaml",
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalog"
+                ],
+                "summary": "Import a service catalog",
+                "parameters": [
+                    {
+                        "enum": [
+                            "yaml",
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Input format, taken from the Content-Type when omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "merge",
+                            "replace"
+                        ],
+                        "type": "string",
+                        "default": "merge",
+                        "description": "Import mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report the changes without applying them",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "In replace mode, also delete services other services depend on",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Service catalog",
+                        "name": "catalog",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Catalog"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changes and rejected records",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format, mode or catalog document",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Replace mode without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A service gained dependents while the import was applied",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Catalog too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/labels": {
+            "get": {
+                "description": "List the distinct label keys with their values and the number of services using each, for building facets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List label keys and values",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only return this label key",
+                        "name": "key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Label facets",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LabelFacet"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Full-text search across service names and descriptions, version numbers and descriptions, and labels. Hits are ranked by relevance and matches in the highlight are wrapped in \u003cmark\u003e tags. q supports quoted phrases, \"or\" and \"-\" to exclude words. Facets count the hits of every type regardless of the type filter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text, e.g. payment gateway",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated hit types to return (service, version, label)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Search results",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services": {
+            "get": {
+                "description": "Get a list of services with optional filtering, sorting, and pagination. Pages are selected by page number or, for large catalogs, by the opaque next_cursor/prev_cursor of a previous response, which keep pages stable while services are added. Cursors keep the sort they were created with. The next and prev pages are also linked in an RFC 8288 Link header.",
+                "tags": [
+                    "services"
+                ],
+                "summary": "List services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by service name (case-insensitive, partial match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by service description (case-insensitive, partial match)",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. created_at\u003e2025-01-01 and version_count\u003e=3 and name~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Comma-separated sort fields (name, created_at, updated_at, version_count), prefixed with - for descending, e.g. -updated_at,name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort order of a single sort field without prefix (asc, desc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous response, replaces page, sort and order",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count the matching services; defaults to true with page and false with cursor",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,name,version_count",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resources to embed (versions, dependencies, owner)",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of embedded versions per service",
+                        "name": "versions.limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at",
+                            "updated_at",
+                            "-updated_at",
+                            "version",
+                            "-version"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Order of embedded versions",
+                        "name": "versions.sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label selector, e.g. tier=critical,lang in (go,rust),!deprecated",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact match on the custom field called name, e.g. metadata.sla_tier=gold",
+                        "name": "metadata.name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of services",
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the next and prev pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor, sort, filter expression, label selector or custom field filter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new service with the given name and description",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Create a new service",
+                "parameters": [
+                    {
+                        "description": "Service details",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created service",
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}": {
+            "get": {
+                "description": "Get details of a service by ID. Its first 10 versions are embedded unless expand is given, which embeds only the listed resources. Page through all versions with GET /services/{sid}/versions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get a service",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,name,version_count",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "versions",
+                        "description": "Comma-separated related resources to embed (versions, dependencies, owner); all versions are embedded when absent",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Maximum number of embedded versions, 10 when versions are expanded explicitly",
+                        "name": "versions.limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at",
+                            "updated_at",
+                            "-updated_at",
+                            "version",
+                            "-version"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Order of embedded versions",
+                        "name": "versions.sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a service with the given ID. Services that other services depend on are only deleted with force=true, which also removes those dependencies.",
+                "tags": [
+                    "services"
+                ],
+                "summary": "Delete a service",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete even if other services depend on it",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report what would be deleted without deleting anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run report",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeletionReport"
+                        }
+                    },
+                    "204": {
+                        "description": "Service deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Other services depend on the service",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a service with the given ID and details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Update a service",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Service details",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated service",
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/dependencies": {
+            "get": {
+                "description": "List the services the given service depends on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "List the dependencies of a service",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dependencies of the service",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Dependency"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid service ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Make the service depend on another service, optionally pinned to a version constraint such as \"\u003e=1.2.0, \u003c2.0.0\". Dependencies that would create a cycle are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Add a dependency",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dependency details",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created dependency",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dependency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Dependency exists or would create a cycle",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/dependencies/{did}": {
+            "get": {
+                "description": "Get a dependency of a service by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Get a dependency",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Dependency ID",
+                        "name": "did",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dependency",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dependency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Dependency not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a dependency of a service",
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Remove a dependency",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Dependency ID",
+                        "name": "did",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Dependency removed"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Dependency not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the version constraint of a dependency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Update a dependency",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Dependency ID",
+                        "name": "did",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dependency details",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated dependency",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dependency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Dependency not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/dependents": {
+            "get": {
+                "description": "List the services that depend on the given service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "List the dependents of a service",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dependencies pointing at the service",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Dependency"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid service ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/impact": {
+            "get": {
+                "description": "List every service that depends on the given service, directly or transitively, with the dependency path and its depth",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impact"
+                ],
+                "summary": "Impact of removing a service",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Affected services",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImpactReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid service ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/versions": {
+            "get": {
+                "description": "Get a page of the versions of a service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "List the versions of a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at",
+                            "updated_at",
+                            "-updated_at",
+                            "version",
+                            "-version"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Order of the versions",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of versions",
+                        "schema": {
+                            "$ref": "#/definitions/models.VersionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid service ID or sort",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list versions",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new version for a service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Create a new version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version details",
+                        "name": "version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created version",
+                        "schema": {
+                            "$ref": "#/definitions/models.Version"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create version",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/versions/{vid}": {
+            "get": {
+                "description": "Get a version by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Get a version by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version ID",
+                        "name": "vid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Version details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Version"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Version not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get version",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a version by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Update a version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version ID",
+                        "name": "vid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version details",
+                        "name": "version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated version",
+                        "schema": {
+                            "$ref": "#/definitions/models.Version"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Version not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update version",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a version by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Delete a version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version ID",
+                        "name": "vid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report what would be deleted or left without a matching version without deleting anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run report",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeletionReport"
+                        }
+                    },
+                    "204": {
+                        "description": "Version deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid version ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Version not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete version",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/versions/{vid}/deprecate": {
+            "post": {
+                "description": "Mark a version as no longer active. Deprecated versions are kept but no longer satisfy the version constraints of dependencies in impact analysis.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Deprecate a version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version ID",
+                        "name": "vid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deprecated version",
+                        "schema": {
+                            "$ref": "#/definitions/models.Version"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid service or version ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Version not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to deprecate version",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/versions/{vid}/impact": {
+            "get": {
+                "description": "List every service affected by retiring the given version: the direct dependents whose version constraint it satisfies and the services that transitively depend on them. has_alternative is set when another active version also satisfies a direct dependent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impact"
+                ],
+                "summary": "Impact of retiring a version",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Version ID",
+                        "name": "vid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Affected services",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImpactReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service or version not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{sid}/versions:batch": {
+            "post": {
+                "description": "Apply up to 500 version operations to a service, with the same modes and response as the service batch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "versions"
+                ],
+                "summary": "Create, update and delete versions of a service in bulk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Operations",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VersionBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Every operation succeeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some operations of a best-effort batch failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid batch, or an atomic batch with an invalid operation",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found, or an atomic batch targets a missing version",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services:batch": {
+            "post": {
+                "description": "Apply up to 500 service operations. Every operation is validated before any is applied. In atomic mode (the default) all of them are applied in one transaction or none is; in best_effort mode each valid operation is applied on its own. The response has a result per operation in request order. It is 200 when every operation succeeded, 207 when a best-effort batch partially failed and, for a failed atomic batch, the status of the operation that made it fail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Create, update and delete services in bulk",
+                "parameters": [
+                    {
+                        "description": "Operations",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Every operation succeeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some operations of a best-effort batch failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid batch, or an atomic batch with an invalid operation",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "An atomic batch targets a missing service",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "An atomic batch conflicts with existing services",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List all webhooks, including disabled ones. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhooks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to catalog events. Events are POSTed as JSON signed with HMAC-SHA256 of the body in the X-Webhook-Signature-256 header. A secret is generated when none is given; it is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook details",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created webhook including its secret",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookWithSecret"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin scope required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create webhook",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{wid}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a webhook with its failure count and, when it was disabled, why",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "wid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get webhook",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace the URL, event types and service filter of a webhook. The secret is kept unless a new one is given. Set enabled to true to re-enable a webhook that was disabled after failing deliveries.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Replace a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "wid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook details",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated webhook",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID or request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update webhook",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a webhook and its delivery history",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "wid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted"
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete webhook",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{wid}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the latest deliveries of a webhook, newest first, with the outcome of their last attempt",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "wid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of deliveries (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{wid}/deliveries/{did}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send the event of a delivery again. A new delivery is queued and retried like any other.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "wid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "did",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Queued delivery",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook or delivery ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook or delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to redeliver",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Optional additional details"
+                },
+                "message": {
+                    "description": "Human-readable error message",
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-05-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci-pipeline"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "sk_1a2b3c4d"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write:versions"
+                    ]
+                },
+                "service_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                }
+            }
+        },
+        "models.APIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-05-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci-pipeline"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write:versions"
+                    ]
+                },
+                "service_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.APIKeyWithSecret": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-05-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "key": {
+                    "type": "string",
+                    "example": "sk_1a2b3c4d_Zm9vYmFyYmF6..."
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci-pipeline"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "sk_1a2b3c4d"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write:versions"
+                    ]
+                },
+                "service_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                }
+            }
+        },
+        "models.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "atomic"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "invalid_service"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "op": {
+                    "type": "string",
+                    "example": "create"
+                },
+                "status": {
+                    "description": "HTTP status the operation would have had on its own",
+                    "type": "integer",
+                    "example": 201
+                }
+            }
+        },
+        "models.Catalog": {
+            "type": "object",
+            "properties": {
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogService"
+                    }
+                }
+            }
+        },
+        "models.CatalogService": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Manages user authentication and profiles"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "name": {
+                    "type": "string",
+                    "example": "User Service"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogVersion"
+                    }
+                }
+            }
+        },
+        "models.CatalogVersion": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Initial release"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.0.0"
+                }
+            }
+        },
+        "models.Change": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "entity": {
+                    "type": "string",
+                    "example": "version"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "op": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "seq": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "state": {
+                    "type": "object"
+                }
+            }
+        },
+        "models.ChangeFeed": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Change"
+                    }
+                },
+                "has_more": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "next_since": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.CustomField": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Support level agreed with the service owners"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "SLA tier"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "sla_tier"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "gold",
+                        "silver",
+                        "bronze"
+                    ]
+                },
+                "required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "enum"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                }
+            }
+        },
+        "models.CustomFieldRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Support level agreed with the service owners"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "SLA tier"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "sla_tier"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "gold",
+                        "silver",
+                        "bronze"
+                    ]
+                },
+                "required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "type": "string",
+                    "example": "enum"
+                }
+            }
+        },
+        "models.DeletionReport": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "description": "Active API keys scoped to the service",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIKey"
+                    }
+                },
+                "blocked": {
+                    "description": "The delete would be refused without force=true",
+                    "type": "boolean",
+                    "example": false
+                },
+                "dependencies": {
+                    "description": "Dependencies of the service that would be removed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Dependency"
+                    }
+                },
+                "dependents": {
+                    "description": "Dependencies on the target that would be removed or left unsatisfied",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Dependency"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "service": {
+                    "$ref": "#/definitions/models.GraphNode"
+                },
+                "version": {
+                    "description": "The version being deleted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Version"
+                        }
+                    ]
+                },
+                "versions": {
+                    "description": "Versions deleted with the service",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Version"
+                    }
+                }
+            }
+        },
+        "models.Dependency": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "depends_on_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "depends_on_name": {
+                    "type": "string",
+                    "example": "User Service"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Order Service"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "version_constraint": {
+                    "type": "string",
+                    "example": "\u003e=1.2.0, \u003c2.0.0"
+                }
+            }
+        },
+        "models.DependencyRequest": {
+            "type": "object",
+            "properties": {
+                "depends_on_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "version_constraint": {
+                    "type": "string",
+                    "example": "\u003e=1.2.0, \u003c2.0.0"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "evt_3f2a9c1d5e7b8a04"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "type": "string",
+                    "example": "version.created"
+                }
+            }
+        },
+        "models.Graph": {
+            "type": "object",
+            "properties": {
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GraphEdge"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GraphNode"
+                    }
+                }
+            }
+        },
+        "models.GraphEdge": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "version_constraint": {
+                    "type": "string",
+                    "example": "\u003e=1.2.0, \u003c2.0.0"
+                }
+            }
+        },
+        "models.GraphNode": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "User Service"
+                }
+            }
+        },
+        "models.ImpactReport": {
+            "type": "object",
+            "properties": {
+                "affected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImpactedService"
+                    }
+                },
+                "max_depth": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "service": {
+                    "$ref": "#/definitions/models.GraphNode"
+                },
+                "version": {
+                    "$ref": "#/definitions/models.Version"
+                }
+            }
+        },
+        "models.ImpactedService": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "description": "1 for direct dependents",
+                    "type": "integer",
+                    "example": 1
+                },
+                "has_alternative": {
+                    "description": "Another active version satisfies the constraint",
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Checkout Service"
+                },
+                "path": {
+                    "description": "From the impacted service to the changed one",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GraphNode"
+                    }
+                },
+                "version_constraint": {
+                    "description": "Constraint of direct dependents",
+                    "type": "string",
+                    "example": "\u003e=1.2.0, \u003c2.0.0"
+                }
+            }
+        },
+        "models.ImportChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "added_versions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2.0.0"
+                    ]
+                },
+                "fields": {
+                    "description": "Changed service fields of updates",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "description",
+                        "labels"
+                    ]
+                },
+                "removed_versions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "User Service"
+                },
+                "updated_versions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ImportError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid service: name is required"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "service": {
+                    "type": "string",
+                    "example": "User Service"
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportChange"
+                    }
+                },
+                "created": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "deleted": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportError"
+                    }
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "merge"
+                },
+                "unchanged": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.LabelFacet": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "tier"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LabelValueCount"
+                    }
+                }
+            }
+        },
+        "models.LabelValueCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "value": {
+                    "type": "string",
+                    "example": "critical"
+                }
+            }
+        },
+        "models.Pagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "items_per_page": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzb3J0IjoibmFtZSIsIm9yZGVyIjoiYXNjIiwidmFsdWUiOiJ1c2VycyIsImlkIjo0Mn0"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "models.SearchFacet": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "type": {
+                    "type": "string",
+                    "example": "service"
+                }
+            }
+        },
+        "models.SearchHit": {
+            "type": "object",
+            "properties": {
+                "highlight": {
+                    "type": "string",
+                    "example": "Card \u003cmark\u003epayment\u003c/mark\u003e \u003cmark\u003egateway\u003c/mark\u003e"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.6079271
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "details": {
-                    "description": "Optional additional details"
+                "service_name": {
+                    "type": "string",
+                    "example": "Payments"
                 },
-                "message": {
-                    "description": "Human-readable error message",
-                    "type": "string"
+                "title": {
+                    "type": "string",
+                    "example": "Payments"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "service"
                 }
             }
         },
-        "models.Pagination": {
+        "models.SearchResponse": {
             "type": "object",
             "properties": {
-                "current_page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "items_per_page": {
-                    "type": "integer",
-                    "example": 10
+                "facets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SearchFacet"
+                    }
                 },
-                "total_items": {
-                    "type": "integer",
-                    "example": 50
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SearchHit"
+                    }
                 },
-                "total_pages": {
-                    "type": "integer",
-                    "example": 5
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
                 }
             }
         },
@@ -584,6 +3516,12 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-05-01T00:00:00Z"
                 },
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Dependency"
+                    }
+                },
                 "description": {
                     "type": "string",
                     "example": "Manages user authentication and profiles"
@@ -592,10 +3530,33 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "managed_by": {
+                    "description": "Owner of declaratively managed services, empty for hand-created ones",
+                    "type": "string",
+                    "example": "gitops"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
                 "name": {
                     "type": "string",
                     "example": "User Service"
                 },
+                "owner": {
+                    "description": "Only with expand=owner",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ServiceOwner"
+                        }
+                    ]
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2025-05-01T00:00:00Z"
@@ -612,6 +3573,42 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ServiceBatchOperation": {
+            "type": "object",
+            "properties": {
+                "force": {
+                    "description": "Delete even if other services depend on it",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "op": {
+                    "type": "string",
+                    "example": "create"
+                },
+                "service": {
+                    "$ref": "#/definitions/models.ServiceRequest"
+                }
+            }
+        },
+        "models.ServiceBatchRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "atomic (default) or best_effort",
+                    "type": "string",
+                    "example": "atomic"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServiceBatchOperation"
+                    }
+                }
+            }
+        },
         "models.ServiceModel": {
             "type": "object",
             "properties": {
@@ -619,6 +3616,13 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-05-01T00:00:00Z"
                 },
+                "dependencies": {
+                    "description": "Only with expand=dependencies",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Dependency"
+                    }
+                },
                 "description": {
                     "type": "string",
                     "example": "Manages user authentication and profiles"
@@ -627,10 +3631,32 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "managed_by": {
+                    "type": "string",
+                    "example": "gitops"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
                 "name": {
                     "type": "string",
                     "example": "User Service"
                 },
+                "owner": {
+                    "description": "Only with expand=owner",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ServiceOwner"
+                        }
+                    ]
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2025-05-01T00:00:00Z"
@@ -638,6 +3664,26 @@ const docTemplate = `{
                 "version_count": {
                     "type": "integer",
                     "example": 1
+                },
+                "versions": {
+                    "description": "Only with expand=versions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Version"
+                    }
+                }
+            }
+        },
+        "models.ServiceOwner": {
+            "type": "object",
+            "properties": {
+                "managed_by": {
+                    "type": "string",
+                    "example": "gitops"
+                },
+                "team": {
+                    "type": "string",
+                    "example": "identity"
                 }
             }
         },
@@ -648,6 +3694,18 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Manages user authentication and profiles"
                 },
+                "labels": {
+                    "description": "Replaces all labels when set",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "metadata": {
+                    "description": "Custom field values, replaces all values when set",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
                 "name": {
                     "type": "string",
                     "example": "User Service"
@@ -701,6 +3759,38 @@ const docTemplate = `{
                 }
             }
         },
+        "models.VersionBatchOperation": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "op": {
+                    "type": "string",
+                    "example": "create"
+                },
+                "version": {
+                    "$ref": "#/definitions/models.VersionRequest"
+                }
+            }
+        },
+        "models.VersionBatchRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "atomic (default) or best_effort",
+                    "type": "string",
+                    "example": "atomic"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VersionBatchOperation"
+                    }
+                }
+            }
+        },
         "models.VersionRequest": {
             "type": "object",
             "properties": {
@@ -713,6 +3803,224 @@ const docTemplate = `{
                     "example": "1.0.0"
                 }
             }
+        },
+        "models.VersionResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Version"
+                    }
+                }
+            }
+        },
+        "models.Webhook": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "description": "Deliveries that failed every attempt since the last success",
+                    "type": "integer",
+                    "example": 0
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string",
+                    "example": "5 consecutive deliveries failed"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "version.created",
+                        "service.deleted"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_ids": {
+                    "description": "Only deliver events of these services",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://deploy.example.com/hooks/catalog"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "unexpected status 502"
+                },
+                "event_id": {
+                    "type": "string",
+                    "example": "evt_3f2a9c1d5e7b8a04"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "version.created"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "redelivery_of": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "response_status": {
+                    "type": "integer",
+                    "example": 502
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "webhook_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.WebhookRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Re-enables a disabled webhook or disables it",
+                    "type": "boolean",
+                    "example": true
+                },
+                "events": {
+                    "description": "Event types, or \"*\" for all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "version.created",
+                        "service.deleted"
+                    ]
+                },
+                "secret": {
+                    "description": "Generated when omitted on creation, kept when omitted on update",
+                    "type": "string"
+                },
+                "service_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://deploy.example.com/hooks/catalog"
+                }
+            }
+        },
+        "models.WebhookWithSecret": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "description": "Deliveries that failed every attempt since the last success",
+                    "type": "integer",
+                    "example": 0
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string",
+                    "example": "5 consecutive deliveries failed"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "version.created",
+                        "service.deleted"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_9c1d5e7b8a043f2a..."
+                },
+                "service_ids": {
+                    "description": "Only deliver events of these services",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-05-01T00:00:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://deploy.example.com/hooks/catalog"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
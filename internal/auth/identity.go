@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidCredentials is returned when presented credentials are unknown,
+// expired or revoked
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// APIKeyPrefix marks API keys so they can be told apart from JWTs
+const APIKeyPrefix = "sk_"
+
+// Authentication methods
+const (
+	MethodClientCert = "client_cert"
+	MethodJWT        = "jwt"
+	MethodAPIKey     = "api_key"
+)
+
+// Scopes grant access to groups of operations
+const (
+	ScopeRead          = "read"
+	ScopeWriteServices = "write:services"
+	ScopeWriteVersions = "write:versions"
+	ScopeAdmin         = "admin" // Grants every other scope
+)
+
+// Scopes lists every known scope
+var Scopes = []string{ScopeRead, ScopeWriteServices, ScopeWriteVersions, ScopeAdmin}
+
+// Identity describes an authenticated caller
+type Identity struct {
+	Subject    string   `json:"subject"`               // Stable identifier of the caller
+	Method     string   `json:"method"`                // How the caller authenticated
+	Scopes     []string `json:"scopes"`                // Granted scopes
+	ServiceIDs []uint   `json:"service_ids,omitempty"` // Services the caller is restricted to, empty for all
+}
+
+// HasScope reports whether the identity was granted scope
+func (i *Identity) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+// CanAccessService reports whether the identity may act on the service
+func (i *Identity) CanAccessService(id uint) bool {
+	return len(i.ServiceIDs) == 0 || slices.Contains(i.ServiceIDs, id)
+}
+
+// IsAPIKey reports whether a credential looks like an API key rather than a JWT
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// APIKeyVerifier resolves a plaintext API key to the identity it belongs to
+type APIKeyVerifier interface {
+	// VerifyAPIKey returns the identity for key, or an error wrapping
+	// ErrInvalidCredentials if the key is unknown, expired or revoked
+	VerifyAPIKey(ctx context.Context, key string) (*Identity, error)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the identity
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the identity stored in ctx, if any
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(*Identity)
+	return identity, ok && identity != nil
+}
+
+// FromTLS returns the identity of a client that presented a certificate
+// which was verified against the configured client CA. Unverified
+// certificates are ignored. The identity has no scopes; they are granted by
+// the auth layer.
+func FromTLS(state *tls.ConnectionState) (*Identity, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+
+	subject := certificateSubject(state.VerifiedChains[0][0])
+	if subject == "" {
+		return nil, false
+	}
+	return &Identity{Subject: subject, Method: MethodClientCert}, true
+}
+
+// certificateSubject prefers a URI SAN (e.g. a SPIFFE ID) and falls back to
+// the common name, then the first DNS SAN.
+func certificateSubject(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return ""
+}
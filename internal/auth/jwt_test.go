@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseJWT(t *testing.T) {
+	now := time.Now()
+	token, err := SignJWT(Claims{Subject: "deploy-bot", Scope: "read write:versions", ExpiresAt: now.Add(time.Hour).Unix()}, "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	identity, err := ParseJWT(token, "secret", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if identity.Subject != "deploy-bot" || identity.Method != MethodJWT {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+	if !identity.HasScope(ScopeWriteVersions) || identity.HasScope(ScopeWriteServices) {
+		t.Errorf("unexpected scopes: %v", identity.Scopes)
+	}
+}
+
+func TestParseJWT_Invalid(t *testing.T) {
+	now := time.Now()
+	valid, _ := SignJWT(Claims{Subject: "deploy-bot"}, "secret")
+	expired, _ := SignJWT(Claims{Subject: "deploy-bot", ExpiresAt: now.Add(-time.Minute).Unix()}, "secret")
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"wrong secret", valid, ErrInvalidToken},
+		{"malformed", "not-a-jwt", ErrInvalidToken},
+		{"tampered", valid[:len(valid)-2] + "xx", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := "secret"
+			if tt.name == "wrong secret" {
+				secret = "other"
+			}
+			if _, err := ParseJWT(tt.token, secret, now); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := ParseJWT(expired, "secret", now); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestIdentity_AdminHasAllScopes(t *testing.T) {
+	identity := &Identity{Scopes: []string{ScopeAdmin}, ServiceIDs: []uint{1}}
+	for _, scope := range Scopes {
+		if !identity.HasScope(scope) {
+			t.Errorf("admin should have scope %q", scope)
+		}
+	}
+	if !identity.CanAccessService(1) || identity.CanAccessService(2) {
+		t.Error("service restriction not applied")
+	}
+}
@@ -0,0 +1,234 @@
+package business
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"services-api/internal/auth"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+var (
+	// ErrAPIKeyNotFound is returned when a requested API key doesn't exist
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrInvalidAPIKey is returned when a presented API key is unknown, expired or revoked
+	ErrInvalidAPIKey = fmt.Errorf("invalid api key: %w", auth.ErrInvalidCredentials)
+
+	// ErrInvalidAPIKeyRequest is returned when an API key request fails validation
+	ErrInvalidAPIKeyRequest = errors.New("invalid api key request")
+)
+
+// lastUsedResolution limits how often the last used timestamp is written
+const lastUsedResolution = time.Minute
+
+// APIKeyBusiness interface defines API key business logic operations
+type APIKeyBusiness interface {
+	auth.APIKeyVerifier
+
+	// CreateAPIKey creates a new API key
+	// Returns the key together with its plaintext, which is not stored.
+	CreateAPIKey(ctx context.Context, req models.APIKeyRequest) (*models.APIKeyWithSecret, error)
+
+	// ListAPIKeys returns all API keys without their secrets
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+
+	// RotateAPIKey replaces the secret of an API key, invalidating the old one
+	// Returns the key with its new plaintext or ErrAPIKeyNotFound.
+	RotateAPIKey(ctx context.Context, id uint) (*models.APIKeyWithSecret, error)
+
+	// RevokeAPIKey revokes an API key
+	// Returns ErrAPIKeyNotFound if the key doesn't exist or is already revoked.
+	RevokeAPIKey(ctx context.Context, id uint) error
+
+	// PurgeAPIKeys deletes the keys revoked or expired longer than olderThan ago
+	// Returns the number of keys deleted.
+	PurgeAPIKeys(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type apiKeyBusinessImpl struct {
+	repo repository.APIKeyRepository
+	now  func() time.Time
+}
+
+// NewAPIKeyBusiness creates a new business logic implementation
+// with the provided repository.
+func NewAPIKeyBusiness(repo repository.APIKeyRepository) APIKeyBusiness {
+	return &apiKeyBusinessImpl{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// CreateAPIKey validates the request and creates a new API key
+func (b *apiKeyBusinessImpl) CreateAPIKey(ctx context.Context, req models.APIKeyRequest) (*models.APIKeyWithSecret, error) {
+	if err := b.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	prefix, plaintext, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := b.repo.CreateAPIKey(ctx, models.APIKey{
+		Name:       strings.TrimSpace(req.Name),
+		Prefix:     prefix,
+		KeyHash:    hashAPIKey(plaintext),
+		Scopes:     req.Scopes,
+		ServiceIDs: req.ServiceIDs,
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyWithSecret{APIKey: *key, Key: plaintext}, nil
+}
+
+// ListAPIKeys returns all API keys without their secrets
+func (b *apiKeyBusinessImpl) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return b.repo.ListAPIKeys(ctx)
+}
+
+// RotateAPIKey replaces the secret of an API key
+func (b *apiKeyBusinessImpl) RotateAPIKey(ctx context.Context, id uint) (*models.APIKeyWithSecret, error) {
+	prefix, plaintext, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := b.repo.UpdateAPIKeySecret(ctx, id, prefix, hashAPIKey(plaintext))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &models.APIKeyWithSecret{APIKey: *key, Key: plaintext}, nil
+}
+
+// RevokeAPIKey revokes an API key
+func (b *apiKeyBusinessImpl) RevokeAPIKey(ctx context.Context, id uint) error {
+	err := b.repo.RevokeAPIKey(ctx, id, b.now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// PurgeAPIKeys deletes the keys revoked or expired longer than olderThan
+// ago. They can no longer be used, but are kept for a while to show when
+// they stopped working.
+func (b *apiKeyBusinessImpl) PurgeAPIKeys(ctx context.Context, olderThan time.Duration) (int64, error) {
+	if olderThan < 0 {
+		return 0, fmt.Errorf("%w: the age of purged keys must not be negative", ErrInvalidAPIKeyRequest)
+	}
+	return b.repo.DeleteAPIKeysInvalidBefore(ctx, b.now().Add(-olderThan))
+}
+
+// VerifyAPIKey resolves a plaintext key to the identity it grants and
+// records when it was last used
+func (b *apiKeyBusinessImpl) VerifyAPIKey(ctx context.Context, plaintext string) (*auth.Identity, error) {
+	prefix, ok := parseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := b.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(plaintext))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := b.now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := b.repo.TouchAPIKey(ctx, key.ID, now); err != nil {
+			slog.Warn("Failed to record API key usage", "api_key_id", key.ID, "error", err)
+		}
+	}
+
+	return &auth.Identity{
+		Subject:    strconv.FormatUint(uint64(key.ID), 10),
+		Method:     auth.MethodAPIKey,
+		Scopes:     key.Scopes,
+		ServiceIDs: key.ServiceIDs,
+	}, nil
+}
+
+func (b *apiKeyBusinessImpl) validateRequest(req models.APIKeyRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAPIKeyRequest)
+	}
+	if len(req.Scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKeyRequest)
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return fmt.Errorf("%w: unknown scope %q, must be one of %v", ErrInvalidAPIKeyRequest, scope, auth.Scopes)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(b.now()) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidAPIKeyRequest)
+	}
+	return nil
+}
+
+// generateAPIKey returns a new plaintext key of the form sk_<id>_<secret>
+// and its public prefix sk_<id>
+func generateAPIKey() (prefix, plaintext string, err error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	prefix = auth.APIKeyPrefix + hex.EncodeToString(id)
+	return prefix, prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// parseAPIKeyPrefix extracts the public prefix from a plaintext key
+func parseAPIKeyPrefix(plaintext string) (string, bool) {
+	if !auth.IsAPIKey(plaintext) {
+		return "", false
+	}
+	id, secret, found := strings.Cut(strings.TrimPrefix(plaintext, auth.APIKeyPrefix), "_")
+	if !found || id == "" || secret == "" {
+		return "", false
+	}
+	return auth.APIKeyPrefix + id, true
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
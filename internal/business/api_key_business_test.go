@@ -0,0 +1,238 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"services-api/internal/auth"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// memoryAPIKeyRepo is an in-memory APIKeyRepository
+type memoryAPIKeyRepo struct {
+	keys    map[uint]*models.APIKey
+	nextID  uint
+	touched int
+}
+
+func newMemoryAPIKeyRepo() *memoryAPIKeyRepo {
+	return &memoryAPIKeyRepo{keys: make(map[uint]*models.APIKey), nextID: 1}
+}
+
+func (m *memoryAPIKeyRepo) CreateAPIKey(ctx context.Context, key models.APIKey) (*models.APIKey, error) {
+	key.ID = m.nextID
+	m.nextID++
+	m.keys[key.ID] = &key
+	return &key, nil
+}
+func (m *memoryAPIKeyRepo) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	for _, key := range m.keys {
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+func (m *memoryAPIKeyRepo) GetAPIKey(ctx context.Context, id uint) (*models.APIKey, error) {
+	if key, ok := m.keys[id]; ok {
+		return key, nil
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memoryAPIKeyRepo) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	for _, key := range m.keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memoryAPIKeyRepo) UpdateAPIKeySecret(ctx context.Context, id uint, prefix, keyHash string) (*models.APIKey, error) {
+	key, ok := m.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	key.Prefix, key.KeyHash = prefix, keyHash
+	return key, nil
+}
+func (m *memoryAPIKeyRepo) RevokeAPIKey(ctx context.Context, id uint, revokedAt time.Time) error {
+	key, ok := m.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return repository.ErrNotFound
+	}
+	key.RevokedAt = &revokedAt
+	return nil
+}
+func (m *memoryAPIKeyRepo) TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error {
+	m.touched++
+	m.keys[id].LastUsedAt = &usedAt
+	return nil
+}
+
+func (m *memoryAPIKeyRepo) DeleteAPIKeysInvalidBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for id, key := range m.keys {
+		if (key.RevokedAt != nil && key.RevokedAt.Before(before)) || (key.ExpiresAt != nil && key.ExpiresAt.Before(before)) {
+			delete(m.keys, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestCreateAPIKey_StoresOnlyHash(t *testing.T) {
+	repo := newMemoryAPIKeyRepo()
+	b := NewAPIKeyBusiness(repo)
+
+	created, err := b.CreateAPIKey(context.Background(), models.APIKeyRequest{
+		Name:   "ci-pipeline",
+		Scopes: []string{auth.ScopeRead, auth.ScopeWriteVersions},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(created.Key, created.Prefix+"_") {
+		t.Errorf("key %q does not start with prefix %q", created.Key, created.Prefix)
+	}
+	stored := repo.keys[created.ID]
+	if stored.KeyHash == created.Key || strings.Contains(stored.KeyHash, created.Key) {
+		t.Error("plaintext key must not be stored")
+	}
+}
+
+func TestCreateAPIKey_Validation(t *testing.T) {
+	b := NewAPIKeyBusiness(newMemoryAPIKeyRepo())
+	past := time.Now().Add(-time.Hour)
+
+	tests := []models.APIKeyRequest{
+		{Name: "", Scopes: []string{auth.ScopeRead}},
+		{Name: "ci", Scopes: nil},
+		{Name: "ci", Scopes: []string{"superuser"}},
+		{Name: "ci", Scopes: []string{auth.ScopeRead}, ExpiresAt: &past},
+	}
+	for _, req := range tests {
+		if _, err := b.CreateAPIKey(context.Background(), req); !errors.Is(err, ErrInvalidAPIKeyRequest) {
+			t.Errorf("expected ErrInvalidAPIKeyRequest for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	repo := newMemoryAPIKeyRepo()
+	b := NewAPIKeyBusiness(repo)
+	created, _ := b.CreateAPIKey(context.Background(), models.APIKeyRequest{
+		Name:       "ci-pipeline",
+		Scopes:     []string{auth.ScopeWriteVersions},
+		ServiceIDs: []uint{3},
+	})
+
+	identity, err := b.VerifyAPIKey(context.Background(), created.Key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if identity.Method != auth.MethodAPIKey || !identity.HasScope(auth.ScopeWriteVersions) || !identity.CanAccessService(3) || identity.CanAccessService(4) {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+	if repo.keys[created.ID].LastUsedAt == nil {
+		t.Error("expected last used time to be recorded")
+	}
+
+	// Usage within the resolution window isn't written again
+	b.VerifyAPIKey(context.Background(), created.Key)
+	if repo.touched != 1 {
+		t.Errorf("expected one usage write, got %d", repo.touched)
+	}
+
+	for _, key := range []string{"sk_unknown_secret", created.Key + "x", "not-a-key"} {
+		if _, err := b.VerifyAPIKey(context.Background(), key); !errors.Is(err, auth.ErrInvalidCredentials) {
+			t.Errorf("expected invalid credentials for %q, got %v", key, err)
+		}
+	}
+}
+
+func TestVerifyAPIKey_ExpiredAndRevoked(t *testing.T) {
+	repo := newMemoryAPIKeyRepo()
+	impl := NewAPIKeyBusiness(repo).(*apiKeyBusinessImpl)
+	expiresAt := time.Now().Add(time.Hour)
+
+	expiring, _ := impl.CreateAPIKey(context.Background(), models.APIKeyRequest{Name: "a", Scopes: []string{auth.ScopeRead}, ExpiresAt: &expiresAt})
+	revoked, _ := impl.CreateAPIKey(context.Background(), models.APIKeyRequest{Name: "b", Scopes: []string{auth.ScopeRead}})
+	if err := impl.RevokeAPIKey(context.Background(), revoked.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := impl.VerifyAPIKey(context.Background(), revoked.Key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("expected revoked key to be rejected, got %v", err)
+	}
+
+	impl.now = func() time.Time { return expiresAt.Add(time.Second) }
+	if _, err := impl.VerifyAPIKey(context.Background(), expiring.Key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("expected expired key to be rejected, got %v", err)
+	}
+
+	if err := impl.RevokeAPIKey(context.Background(), revoked.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound when revoking twice, got %v", err)
+	}
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	b := NewAPIKeyBusiness(newMemoryAPIKeyRepo())
+	created, _ := b.CreateAPIKey(context.Background(), models.APIKeyRequest{Name: "ci", Scopes: []string{auth.ScopeRead}})
+
+	rotated, err := b.RotateAPIKey(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated.Key == created.Key || rotated.ID != created.ID {
+		t.Errorf("unexpected rotation result: %+v", rotated)
+	}
+	if _, err := b.VerifyAPIKey(context.Background(), created.Key); err == nil {
+		t.Error("old key must stop working after rotation")
+	}
+	if _, err := b.VerifyAPIKey(context.Background(), rotated.Key); err != nil {
+		t.Errorf("new key should work: %v", err)
+	}
+	if _, err := b.RotateAPIKey(context.Background(), 99); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
+
+func TestPurgeAPIKeys(t *testing.T) {
+	repo := newMemoryAPIKeyRepo()
+	impl := NewAPIKeyBusiness(repo).(*apiKeyBusinessImpl)
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	active, _ := impl.CreateAPIKey(context.Background(), models.APIKeyRequest{Name: "active", Scopes: []string{auth.ScopeRead}})
+	expiring, _ := impl.CreateAPIKey(context.Background(), models.APIKeyRequest{Name: "expiring", Scopes: []string{auth.ScopeRead}, ExpiresAt: &expiresAt})
+	revoked, _ := impl.CreateAPIKey(context.Background(), models.APIKeyRequest{Name: "revoked", Scopes: []string{auth.ScopeRead}})
+	if err := impl.RevokeAPIKey(context.Background(), revoked.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Keys invalid for less than the grace period are kept
+	impl.now = func() time.Time { return now.Add(2 * time.Hour) }
+	deleted, err := impl.PurgeAPIKeys(context.Background(), 24*time.Hour)
+	if err != nil || deleted != 0 {
+		t.Fatalf("expected nothing purged, got %d, %v", deleted, err)
+	}
+
+	impl.now = func() time.Time { return now.Add(48 * time.Hour) }
+	deleted, err = impl.PurgeAPIKeys(context.Background(), 24*time.Hour)
+	if err != nil || deleted != 2 {
+		t.Fatalf("expected 2 keys purged, got %d, %v", deleted, err)
+	}
+	if _, ok := repo.keys[active.ID]; !ok {
+		t.Error("active key must be kept")
+	}
+	if _, ok := repo.keys[expiring.ID]; ok {
+		t.Error("expired key must be purged")
+	}
+
+	if _, err := impl.PurgeAPIKeys(context.Background(), -time.Hour); !errors.Is(err, ErrInvalidAPIKeyRequest) {
+		t.Errorf("expected ErrInvalidAPIKeyRequest, got %v", err)
+	}
+}
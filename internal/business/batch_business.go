@@ -0,0 +1,472 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"services-api/internal/expr"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+var (
+	// ErrInvalidBatch is returned when a batch is empty, too large or has an unknown mode
+	ErrInvalidBatch = errors.New("invalid batch")
+
+	// ErrInvalidOperation is returned for batch operations with an unknown op or a missing or repeated ID
+	ErrInvalidOperation = errors.New("invalid batch operation")
+
+	// ErrInvalidVersion is returned when a version fails validation
+	ErrInvalidVersion = errors.New("invalid version")
+
+	// ErrServiceExists is returned when a service name is already taken
+	ErrServiceExists = errors.New("service already exists")
+
+	// ErrBatchRolledBack is returned for operations of an atomic batch that
+	// succeeded but were rolled back because a later one failed
+	ErrBatchRolledBack = errors.New("rolled back because another operation failed")
+
+	// ErrBatchNotAttempted is returned for operations of an atomic batch that
+	// weren't applied because another one failed
+	ErrBatchNotAttempted = errors.New("not attempted because another operation failed")
+)
+
+// MaxBatchSize is the maximum number of operations in a batch
+const MaxBatchSize = 500
+
+// BatchItem is the outcome of one batch operation. ID is the created,
+// updated or deleted resource and Err is nil when the operation succeeded.
+type BatchItem struct {
+	ID  uint
+	Err error
+}
+
+// BatchBusiness interface defines bulk operations on services and versions
+type BatchBusiness interface {
+	// BatchServices validates and applies service operations. It returns one item per
+	// operation, or ErrInvalidBatch if the batch itself is invalid.
+	BatchServices(ctx context.Context, req models.ServiceBatchRequest) ([]BatchItem, error)
+
+	// BatchVersions validates and applies version operations on a service. It returns one
+	// item per operation, ErrInvalidBatch or ErrServiceNotFound.
+	BatchVersions(ctx context.Context, serviceID uint, req models.VersionBatchRequest) ([]BatchItem, error)
+}
+
+type batchBusinessImpl struct {
+	uow   repository.UnitOfWork
+	repos repository.Repositories
+}
+
+// NewBatchBusiness creates a new business logic implementation. Operations
+// and their events are written in transactions of uow, batches are
+// validated with repos.
+func NewBatchBusiness(uow repository.UnitOfWork, repos repository.Repositories) BatchBusiness {
+	return &batchBusinessImpl{
+		uow:   uow,
+		repos: repos,
+	}
+}
+
+// BatchServices validates every operation before applying any
+func (b *batchBusinessImpl) BatchServices(ctx context.Context, req models.ServiceBatchRequest) ([]BatchItem, error) {
+	mode, err := batchMode(req.Mode, len(req.Operations))
+	if err != nil {
+		return nil, err
+	}
+
+	services, items, err := b.validateServiceOperations(ctx, req.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.run(ctx, mode, items, func(repos repository.Repositories, i int) (uint, error) {
+		op := req.Operations[i]
+		switch op.Op {
+		case models.BatchCreate:
+			created, err := repos.Services.CreateService(ctx, services[i])
+			if err != nil {
+				return 0, err
+			}
+			if err := recordEvent(ctx, repos.Outbox, models.EventServiceCreated, created.ID, created); err != nil {
+				return 0, err
+			}
+			return created.ID, nil
+		case models.BatchUpdate:
+			updated, err := repos.Services.UpdateService(ctx, services[i])
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return 0, ErrServiceNotFound
+				}
+				return 0, err
+			}
+			if err := recordEvent(ctx, repos.Outbox, models.EventServiceUpdated, op.ID, updated); err != nil {
+				return 0, err
+			}
+			return op.ID, nil
+		default:
+			service, err := repos.Services.GetService(ctx, op.ID, models.ServiceExpand{})
+			if err == nil && !op.Force {
+				// Checked again now that dependents can't be added
+				err = checkDependents(ctx, repos, op.ID, nil)
+			}
+			if err == nil {
+				err = repos.Services.DeleteService(ctx, op.ID)
+			}
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return 0, ErrServiceNotFound
+				}
+				return 0, err
+			}
+			if err := recordEvent(ctx, repos.Outbox, models.EventServiceDeleted, op.ID, service); err != nil {
+				return 0, err
+			}
+			return op.ID, nil
+		}
+	})
+}
+
+// BatchVersions validates every operation before applying any
+func (b *batchBusinessImpl) BatchVersions(ctx context.Context, serviceID uint, req models.VersionBatchRequest) ([]BatchItem, error) {
+	mode, err := batchMode(req.Mode, len(req.Operations))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := b.repos.Services.GetService(ctx, serviceID, models.ServiceExpand{}); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+
+	items, err := b.validateVersionOperations(ctx, serviceID, req.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.run(ctx, mode, items, func(repos repository.Repositories, i int) (uint, error) {
+		op := req.Operations[i]
+		version := models.Version{
+			ID:          op.ID,
+			ServiceID:   serviceID,
+			Version:     strings.TrimSpace(op.Version.Version),
+			Description: op.Version.Description,
+		}
+		switch op.Op {
+		case models.BatchCreate:
+			created, err := repos.Versions.CreateVersion(ctx, version)
+			if err != nil {
+				return 0, err
+			}
+			if err := recordEvent(ctx, repos.Outbox, models.EventVersionCreated, serviceID, created); err != nil {
+				return 0, err
+			}
+			return created.ID, nil
+		case models.BatchUpdate:
+			updated, err := repos.Versions.UpdateVersion(ctx, version)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return 0, ErrVersionNotFound
+				}
+				return 0, err
+			}
+			if err := recordEvent(ctx, repos.Outbox, models.EventVersionUpdated, serviceID, updated); err != nil {
+				return 0, err
+			}
+			return op.ID, nil
+		default:
+			deleted, err := repos.Versions.GetVersion(ctx, op.ID, serviceID)
+			if err == nil {
+				err = repos.Versions.DeleteVersion(ctx, op.ID, serviceID)
+			}
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return 0, ErrVersionNotFound
+				}
+				return 0, err
+			}
+			if err := recordEvent(ctx, repos.Outbox, models.EventVersionDeleted, serviceID, deleted); err != nil {
+				return 0, err
+			}
+			return op.ID, nil
+		}
+	})
+}
+
+// run applies the operations that passed validation. Best-effort batches
+// apply each of them in its own transaction. Atomic batches apply nothing when an
+// operation is invalid and otherwise apply all of them in one transaction,
+// which is rolled back when one fails.
+func (b *batchBusinessImpl) run(ctx context.Context, mode string, items []BatchItem, apply func(repos repository.Repositories, i int) (uint, error)) ([]BatchItem, error) {
+	if mode == models.BatchBestEffort {
+		for i := range items {
+			if items[i].Err == nil {
+				var id uint
+				items[i].Err = b.uow.Do(ctx, func(repos repository.Repositories) error {
+					var err error
+					id, err = apply(repos, i)
+					return err
+				})
+				items[i].ID = id
+			}
+		}
+		return items, nil
+	}
+
+	for _, item := range items {
+		if item.Err != nil {
+			for i := range items {
+				if items[i].Err == nil {
+					items[i].Err = ErrBatchNotAttempted
+				}
+			}
+			return items, nil
+		}
+	}
+
+	targets := make([]uint, len(items))
+	for i, item := range items {
+		targets[i] = item.ID
+	}
+	failed := -1
+	err := b.uow.Do(ctx, func(repos repository.Repositories) error {
+		for i := range items {
+			id, err := apply(repos, i)
+			if err != nil {
+				failed = i
+				items[i].Err = err
+				return err
+			}
+			items[i].ID = id
+		}
+		return nil
+	})
+	if err == nil {
+		return items, nil
+	}
+	if failed < 0 {
+		// The operations succeeded but the commit didn't
+		return nil, err
+	}
+	for i := range items {
+		items[i].ID = targets[i]
+		if i < failed {
+			items[i].Err = ErrBatchRolledBack
+		} else if i > failed {
+			items[i].Err = ErrBatchNotAttempted
+		}
+	}
+	return items, nil
+}
+
+// validateServiceOperations checks every operation against the request and
+// the current services. It returns the services to write and an item per
+// operation holding the target ID and validation error, if any.
+func (b *batchBusinessImpl) validateServiceOperations(ctx context.Context, ops []models.ServiceBatchOperation) ([]models.Service, []BatchItem, error) {
+	fields, err := b.repos.CustomFields.ListCustomFields(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	services := make([]models.Service, len(ops))
+	items := make([]BatchItem, len(ops))
+	names := map[string]int{}
+	targets := map[uint]int{}
+	for i, op := range ops {
+		items[i].ID = op.ID
+		service := models.Service{
+			ID:          op.ID,
+			Name:        strings.TrimSpace(op.Service.Name),
+			Description: op.Service.Description,
+			Labels:      op.Service.Labels,
+			Metadata:    op.Service.Metadata,
+		}
+
+		switch op.Op {
+		case models.BatchCreate:
+			service.ID = 0
+			if service.Name == "" {
+				items[i].Err = fmt.Errorf("%w: name is required", ErrInvalidService)
+				continue
+			}
+		case models.BatchUpdate, models.BatchDelete:
+			if op.ID == 0 {
+				items[i].Err = fmt.Errorf("%w: id is required to %s a service", ErrInvalidOperation, op.Op)
+				continue
+			}
+			if first, ok := targets[op.ID]; ok {
+				items[i].Err = fmt.Errorf("%w: service %d is already changed by operation %d", ErrInvalidOperation, op.ID, first)
+				continue
+			}
+			targets[op.ID] = i
+		default:
+			items[i].Err = fmt.Errorf("%w: unknown op %q, expected create, update or delete", ErrInvalidOperation, op.Op)
+			continue
+		}
+
+		if op.Op != models.BatchDelete {
+			if err := validateService(service); err != nil {
+				items[i].Err = err
+				continue
+			}
+			if service.Metadata != nil || op.Op == models.BatchCreate {
+				metadata, err := validateMetadata(fields, service.Metadata, true)
+				if err != nil {
+					items[i].Err = fmt.Errorf("%w: %w", ErrInvalidService, err)
+					continue
+				}
+				service.Metadata = metadata
+			}
+			if service.Name != "" {
+				if first, ok := names[service.Name]; ok {
+					items[i].Err = fmt.Errorf("%w: name %q is already used by operation %d", ErrInvalidOperation, service.Name, first)
+					continue
+				}
+				names[service.Name] = i
+			}
+		}
+		services[i] = service
+	}
+
+	if err := b.checkExistingServices(ctx, ops, services, items); err != nil {
+		return nil, nil, err
+	}
+	return services, items, nil
+}
+
+// checkExistingServices looks up the services targeted by the valid
+// operations and the ones holding their names in a single query, and checks
+// that deleted services have no dependents unless forced. Deletes check their
+// dependents again under the dependency lock when they are applied.
+func (b *batchBusinessImpl) checkExistingServices(ctx context.Context, ops []models.ServiceBatchOperation, services []models.Service, items []BatchItem) error {
+	var names, ids []any
+	for i, op := range ops {
+		if items[i].Err != nil {
+			continue
+		}
+		if op.Op != models.BatchCreate {
+			ids = append(ids, float64(op.ID))
+		}
+		if op.Op != models.BatchDelete && services[i].Name != "" {
+			names = append(names, services[i].Name)
+		}
+	}
+
+	var conditions []expr.Node
+	if len(names) > 0 {
+		conditions = append(conditions, &expr.Comparison{Field: "name", Operator: expr.In, Values: names})
+	}
+	if len(ids) > 0 {
+		conditions = append(conditions, &expr.Comparison{Field: "id", Operator: expr.In, Values: ids})
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	expression := conditions[0]
+	if len(conditions) == 2 {
+		expression = &expr.Logical{Or: true, Left: conditions[0], Right: conditions[1]}
+	}
+
+	existing, _, err := b.repos.Services.ListServices(ctx, models.ServiceFilter{
+		Expression: expression,
+		Page:       1,
+		Limit:      len(names) + len(ids),
+	})
+	if err != nil {
+		return err
+	}
+	byID := map[uint]bool{}
+	byName := map[string]uint{}
+	for _, service := range existing {
+		byID[service.ID] = true
+		byName[service.Name] = service.ID
+	}
+
+	for i, op := range ops {
+		if items[i].Err != nil {
+			continue
+		}
+		if op.Op != models.BatchCreate && !byID[op.ID] {
+			items[i].Err = fmt.Errorf("%w: %d", ErrServiceNotFound, op.ID)
+			continue
+		}
+		if id, ok := byName[services[i].Name]; ok && op.Op != models.BatchDelete && id != op.ID {
+			items[i].Err = fmt.Errorf("%w: %q is service %d", ErrServiceExists, services[i].Name, id)
+			continue
+		}
+		if op.Op == models.BatchDelete && !op.Force {
+			dependents, err := b.repos.Dependencies.ListDependents(ctx, op.ID)
+			if err != nil {
+				return err
+			}
+			if len(dependents) > 0 {
+				names := make([]string, len(dependents))
+				for j, dependent := range dependents {
+					names[j] = dependent.ServiceName
+				}
+				items[i].Err = fmt.Errorf("%w: %s", ErrServiceHasDependents, strings.Join(names, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// validateVersionOperations checks every operation against the request and
+// the current versions of the service
+func (b *batchBusinessImpl) validateVersionOperations(ctx context.Context, serviceID uint, ops []models.VersionBatchOperation) ([]BatchItem, error) {
+	items := make([]BatchItem, len(ops))
+	targets := map[uint]int{}
+	for i, op := range ops {
+		items[i].ID = op.ID
+		switch op.Op {
+		case models.BatchCreate:
+			items[i].ID = 0
+			if strings.TrimSpace(op.Version.Version) == "" {
+				items[i].Err = fmt.Errorf("%w: version is required", ErrInvalidVersion)
+			}
+			continue
+		case models.BatchUpdate, models.BatchDelete:
+			if op.ID == 0 {
+				items[i].Err = fmt.Errorf("%w: id is required to %s a version", ErrInvalidOperation, op.Op)
+				continue
+			}
+			if first, ok := targets[op.ID]; ok {
+				items[i].Err = fmt.Errorf("%w: version %d is already changed by operation %d", ErrInvalidOperation, op.ID, first)
+				continue
+			}
+			targets[op.ID] = i
+		default:
+			items[i].Err = fmt.Errorf("%w: unknown op %q, expected create, update or delete", ErrInvalidOperation, op.Op)
+			continue
+		}
+
+		if _, err := b.repos.Versions.GetVersion(ctx, op.ID, serviceID); err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				return nil, err
+			}
+			items[i].Err = fmt.Errorf("%w: %d", ErrVersionNotFound, op.ID)
+		}
+	}
+	return items, nil
+}
+
+// batchMode checks the size of a batch and returns its mode, atomic by default
+func batchMode(mode string, size int) (string, error) {
+	if size == 0 {
+		return "", fmt.Errorf("%w: no operations", ErrInvalidBatch)
+	}
+	if size > MaxBatchSize {
+		return "", fmt.Errorf("%w: %d operations, at most %d are allowed", ErrInvalidBatch, size, MaxBatchSize)
+	}
+	switch mode {
+	case "", models.BatchAtomic:
+		return models.BatchAtomic, nil
+	case models.BatchBestEffort:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: unknown mode %q, expected atomic or best_effort", ErrInvalidBatch, mode)
+	}
+}
@@ -0,0 +1,239 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+	"services-api/internal/repository/repositorytest"
+)
+
+// memoryUnitOfWork runs units of work on fixed repositories. It only rolls
+// back the events of a memory outbox, so tests check what was attempted.
+type memoryUnitOfWork struct {
+	repos repository.Repositories
+	runs  int
+}
+
+func (u *memoryUnitOfWork) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	u.runs++
+	outbox, _ := u.repos.Outbox.(*memoryOutbox)
+	var appended int
+	if outbox != nil {
+		appended = len(outbox.events)
+	}
+	err := fn(u.repos)
+	if err != nil && outbox != nil {
+		outbox.events = outbox.events[:appended]
+	}
+	return err
+}
+
+// newBatchBusiness returns a batch business over the existing services 1
+// (users) and 2 (orders, which depends on users), recording created names
+func newBatchBusiness(created *[]string) (BatchBusiness, *memoryUnitOfWork) {
+	existing := []models.ServiceModel{{ID: 1, Name: "users"}, {ID: 2, Name: "orders"}}
+	services := &mockRepo{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) ([]models.ServiceModel, int, error) {
+			return existing, len(existing), nil
+		},
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			if id > 2 {
+				return nil, repository.ErrNotFound
+			}
+			return &models.Service{ID: id}, nil
+		},
+		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			if service.Name == "broken" {
+				return nil, errors.New("connection reset")
+			}
+			*created = append(*created, service.Name)
+			service.ID = uint(10 + len(*created))
+			return &service, nil
+		},
+		UpdateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			return &service, nil
+		},
+		DeleteServiceFn: func(ctx context.Context, id uint) error {
+			return nil
+		},
+	}
+	dependencies := newMemoryDependencyRepo(map[uint]string{1: "users", 2: "orders"})
+	dependencies.add(2, 1)
+
+	repos := repository.Repositories{
+		Services:     services,
+		Versions:     &mockVersionRepository{},
+		Dependencies: dependencies,
+		CustomFields: newMemoryCustomFieldRepo(),
+		Outbox:       &memoryOutbox{},
+	}
+	uow := &memoryUnitOfWork{repos: repos}
+	return NewBatchBusiness(uow, repos), uow
+}
+
+func TestBatchServices(t *testing.T) {
+	var created []string
+	b, uow := newBatchBusiness(&created)
+
+	items, err := b.BatchServices(context.Background(), models.ServiceBatchRequest{Operations: []models.ServiceBatchOperation{
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "payments"}},
+		{Op: models.BatchUpdate, ID: 2, Service: models.ServiceRequest{Description: "Order management"}},
+		{Op: models.BatchDelete, ID: 1, Force: true},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, item := range items {
+		if item.Err != nil {
+			t.Errorf("unexpected error for operation %d: %v", i, item.Err)
+		}
+	}
+	if items[0].ID != 11 || items[1].ID != 2 || items[2].ID != 1 {
+		t.Errorf("unexpected IDs: %+v", items)
+	}
+	if uow.runs != 1 || len(created) != 1 {
+		t.Errorf("expected one transaction creating payments, got %d runs and %v", uow.runs, created)
+	}
+}
+
+func TestBatchServices_Validation(t *testing.T) {
+	var created []string
+	b, uow := newBatchBusiness(&created)
+
+	ops := []models.ServiceBatchOperation{
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "payments"}},
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: " "}},
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "users"}},
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "payments"}},
+		{Op: models.BatchUpdate, ID: 7, Service: models.ServiceRequest{Description: "Gone"}},
+		{Op: models.BatchUpdate, Service: models.ServiceRequest{Labels: map[string]string{"Bad Key": "x"}}},
+		{Op: models.BatchDelete, ID: 1},
+		{Op: "upsert"},
+	}
+	want := []error{nil, ErrInvalidService, ErrServiceExists, ErrInvalidOperation, ErrServiceNotFound, ErrInvalidOperation, ErrServiceHasDependents, ErrInvalidOperation}
+
+	items, err := b.BatchServices(context.Background(), models.ServiceBatchRequest{Operations: ops})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(items[0].Err, ErrBatchNotAttempted) {
+		t.Errorf("expected the valid operation not to be attempted, got %v", items[0].Err)
+	}
+	for i := 1; i < len(want); i++ {
+		if !errors.Is(items[i].Err, want[i]) {
+			t.Errorf("operation %d: expected %v, got %v", i, want[i], items[i].Err)
+		}
+	}
+	if uow.runs != 0 || len(created) != 0 {
+		t.Errorf("expected nothing to be written, got %d runs and %v", uow.runs, created)
+	}
+
+	items, err = b.BatchServices(context.Background(), models.ServiceBatchRequest{Mode: models.BatchBestEffort, Operations: ops})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].Err != nil || items[0].ID == 0 {
+		t.Errorf("expected the valid operation to succeed, got %+v", items[0])
+	}
+	if uow.runs != 1 || len(created) != 1 {
+		t.Errorf("expected only payments to be created in its own transaction, got %d runs and %v", uow.runs, created)
+	}
+}
+
+func TestBatchServices_RolledBack(t *testing.T) {
+	var created []string
+	b, _ := newBatchBusiness(&created)
+
+	items, err := b.BatchServices(context.Background(), models.ServiceBatchRequest{Operations: []models.ServiceBatchOperation{
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "payments"}},
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "broken"}},
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "billing"}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(items[0].Err, ErrBatchRolledBack) || items[0].ID != 0 {
+		t.Errorf("expected the first operation to be rolled back, got %+v", items[0])
+	}
+	if items[1].Err == nil || !errors.Is(items[2].Err, ErrBatchNotAttempted) {
+		t.Errorf("unexpected results: %+v", items)
+	}
+}
+
+func TestBatchServices_InvalidBatch(t *testing.T) {
+	var created []string
+	b, _ := newBatchBusiness(&created)
+
+	for _, req := range []models.ServiceBatchRequest{
+		{},
+		{Operations: make([]models.ServiceBatchOperation, MaxBatchSize+1)},
+		{Mode: "eventually", Operations: []models.ServiceBatchOperation{{Op: models.BatchDelete, ID: 2}}},
+	} {
+		if _, err := b.BatchServices(context.Background(), req); !errors.Is(err, ErrInvalidBatch) {
+			t.Errorf("expected ErrInvalidBatch, got %v", err)
+		}
+	}
+}
+
+func TestBatchVersions(t *testing.T) {
+	var created []string
+	b, _ := newBatchBusiness(&created)
+
+	if _, err := b.BatchVersions(context.Background(), 9, models.VersionBatchRequest{Operations: []models.VersionBatchOperation{{Op: models.BatchDelete, ID: 1}}}); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("expected ErrServiceNotFound, got %v", err)
+	}
+
+	items, err := b.BatchVersions(context.Background(), 1, models.VersionBatchRequest{Mode: models.BatchBestEffort, Operations: []models.VersionBatchOperation{
+		{Op: models.BatchCreate, Version: models.VersionRequest{Version: "1.1.0"}},
+		{Op: models.BatchCreate},
+		{Op: models.BatchUpdate, ID: 1, Version: models.VersionRequest{Description: "Stable"}},
+		{Op: models.BatchDelete, ID: 1},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items[0].Err != nil || items[2].Err != nil {
+		t.Errorf("unexpected results: %+v", items)
+	}
+	if !errors.Is(items[1].Err, ErrInvalidVersion) || !errors.Is(items[3].Err, ErrInvalidOperation) {
+		t.Errorf("unexpected errors: %v, %v", items[1].Err, items[3].Err)
+	}
+}
+
+func TestBatchVersions_NotFound(t *testing.T) {
+	var created []string
+	_, uow := newBatchBusiness(&created)
+	// The real repository, so the not found error of the database is mapped
+	uow.repos.Versions = repository.NewVersionRepository(repositorytest.EmptyDB(t))
+	b := NewBatchBusiness(uow, uow.repos)
+
+	for _, mode := range []string{models.BatchAtomic, models.BatchBestEffort} {
+		items, err := b.BatchVersions(context.Background(), 1, models.VersionBatchRequest{Mode: mode, Operations: []models.VersionBatchOperation{
+			{Op: models.BatchUpdate, ID: 7, Version: models.VersionRequest{Description: "Stable"}},
+			{Op: models.BatchDelete, ID: 8},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error in %s mode: %v", mode, err)
+		}
+		if !errors.Is(items[0].Err, ErrVersionNotFound) || !errors.Is(items[1].Err, ErrVersionNotFound) {
+			t.Errorf("expected ErrVersionNotFound per operation in %s mode, got %v, %v", mode, items[0].Err, items[1].Err)
+		}
+	}
+
+	// Versions deleted after the validation, which reads the mock, are
+	// reported when applying
+	b, apply := newBatchBusiness(&created)
+	apply.repos.Versions = uow.repos.Versions
+	items, err := b.BatchVersions(context.Background(), 1, models.VersionBatchRequest{Mode: models.BatchBestEffort, Operations: []models.VersionBatchOperation{
+		{Op: models.BatchDelete, ID: 8},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(items[0].Err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound when applying, got %v", items[0].Err)
+	}
+}
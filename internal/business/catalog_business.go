@@ -0,0 +1,438 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// ErrInvalidImport is returned when the options of an import are invalid
+var ErrInvalidImport = errors.New("invalid import")
+
+// catalogPageSize is the number of services read per query when loading the whole catalog
+const catalogPageSize = 100
+
+// CatalogBusiness interface defines the import and export of the whole service catalog
+type CatalogBusiness interface {
+	// Export returns every service ordered by name with its versions, labels and custom field values.
+	Export(ctx context.Context) (*models.Catalog, error)
+
+	// Import creates and updates the services of a catalog, matched by name, and in replace
+	// mode deletes the services and versions missing from it. Invalid records are skipped and
+	// reported; the others are applied in one transaction unless options.DryRun is set.
+	Import(ctx context.Context, catalog models.Catalog, options models.ImportOptions) (*models.ImportReport, error)
+}
+
+type catalogBusinessImpl struct {
+	uow   repository.UnitOfWork
+	repos repository.Repositories
+}
+
+// NewCatalogBusiness creates a new business logic implementation. Imports
+// are applied with their events in a transaction of uow.
+func NewCatalogBusiness(uow repository.UnitOfWork, repos repository.Repositories) CatalogBusiness {
+	return &catalogBusinessImpl{
+		uow:   uow,
+		repos: repos,
+	}
+}
+
+// Export returns the whole catalog
+func (b *catalogBusinessImpl) Export(ctx context.Context) (*models.Catalog, error) {
+	services, err := listAllServices(ctx, b.repos.Services)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &models.Catalog{Services: make([]models.CatalogService, len(services))}
+	for i, service := range services {
+		catalog.Services[i] = models.CatalogService{
+			Name:        service.Name,
+			Description: service.Description,
+			Labels:      service.Labels,
+			Metadata:    service.Metadata,
+		}
+		for _, version := range service.Versions {
+			catalog.Services[i].Versions = append(catalog.Services[i].Versions, models.CatalogVersion{
+				Version:     version.Version,
+				Description: version.Description,
+			})
+		}
+	}
+	return catalog, nil
+}
+
+// importStep is the planned change of one service
+type importStep struct {
+	change  models.ImportChange
+	service models.Service // Service fields to write, with the ID of existing services
+	add     []models.CatalogVersion
+	update  []models.Version
+	remove  []models.Version
+}
+
+// Import plans the changes against the current services and applies them.
+// A record is the desired state of its service: omitted descriptions,
+// labels and metadata are left unchanged and given ones replace the current.
+func (b *catalogBusinessImpl) Import(ctx context.Context, catalog models.Catalog, options models.ImportOptions) (*models.ImportReport, error) {
+	switch options.Mode {
+	case "":
+		options.Mode = models.ImportMerge
+	case models.ImportMerge, models.ImportReplace:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q, expected merge or replace", ErrInvalidImport, options.Mode)
+	}
+
+	fields, err := b.repos.CustomFields.ListCustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := listAllServices(ctx, b.repos.Services)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.ServiceModel, len(current))
+	for _, service := range current {
+		byName[service.Name] = service
+	}
+
+	report := &models.ImportReport{
+		Mode:    options.Mode,
+		DryRun:  options.DryRun,
+		Changes: []models.ImportChange{},
+		Errors:  []models.ImportError{},
+	}
+	var steps []importStep
+	seen := map[string]bool{}
+	for i, record := range catalog.Services {
+		record.Name = strings.TrimSpace(record.Name)
+		existing, exists := byName[record.Name]
+		step, err := planImport(fields, record, existing, exists, options, seen[record.Name])
+		if record.Name != "" {
+			seen[record.Name] = true
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, models.ImportError{Index: i, Service: record.Name, Error: err.Error()})
+			continue
+		}
+		if step == nil {
+			report.Unchanged++
+			continue
+		}
+		steps = append(steps, *step)
+	}
+
+	if options.Mode == models.ImportReplace {
+		var missing []models.ServiceModel
+		for _, service := range current {
+			if !seen[service.Name] && (options.ManagedBy == "" || service.ManagedBy == options.ManagedBy) {
+				missing = append(missing, service)
+			}
+		}
+		deleted, errs, err := b.planDeletes(ctx, missing, options.Force)
+		if err != nil {
+			return nil, err
+		}
+		report.Errors = append(report.Errors, errs...)
+		for _, service := range missing {
+			if deleted[service.ID] {
+				steps = append(steps, importStep{
+					change: models.ImportChange{Action: models.ImportDelete, Service: service.Name},
+					service: models.Service{
+						ID:          service.ID,
+						Name:        service.Name,
+						Description: service.Description,
+						CreatedAt:   service.CreatedAt,
+						UpdatedAt:   service.UpdatedAt,
+						Labels:      service.Labels,
+						Metadata:    service.Metadata,
+						ManagedBy:   service.ManagedBy,
+					},
+				})
+			}
+		}
+	}
+
+	for _, step := range steps {
+		report.Changes = append(report.Changes, step.change)
+		switch step.change.Action {
+		case models.ImportCreate:
+			report.Created++
+		case models.ImportUpdate:
+			report.Updated++
+		case models.ImportDelete:
+			report.Deleted++
+		}
+	}
+	if options.DryRun || len(steps) == 0 {
+		return report, nil
+	}
+
+	deleted := map[uint]bool{}
+	for _, step := range steps {
+		if step.change.Action == models.ImportDelete {
+			deleted[step.service.ID] = true
+		}
+	}
+	err = b.uow.Do(ctx, func(repos repository.Repositories) error {
+		for _, step := range steps {
+			if step.change.Action == models.ImportDelete && !options.Force {
+				// Checked again now that dependents can't be added
+				if err := checkDependents(ctx, repos, step.service.ID, deleted); err != nil {
+					return fmt.Errorf("%s %q: %w", step.change.Action, step.change.Service, err)
+				}
+			}
+			if err := applyImportStep(ctx, repos, step); err != nil {
+				return fmt.Errorf("%s %q: %w", step.change.Action, step.change.Service, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// planDeletes returns the services to delete among the missing ones.
+// Unless force is set, services keep being refused while other services
+// that aren't deleted depend on them, which are reported with the index -1.
+func (b *catalogBusinessImpl) planDeletes(ctx context.Context, missing []models.ServiceModel, force bool) (map[uint]bool, []models.ImportError, error) {
+	deleted := make(map[uint]bool, len(missing))
+	dependents := make(map[uint][]models.Dependency, len(missing))
+	for _, service := range missing {
+		deleted[service.ID] = true
+		if force {
+			continue
+		}
+		list, err := b.repos.Dependencies.ListDependents(ctx, service.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		dependents[service.ID] = list
+	}
+
+	refused := map[uint]error{}
+	for changed := true; changed; {
+		changed = false
+		for _, service := range missing {
+			if !deleted[service.ID] {
+				continue
+			}
+			if err := dependentsError(dependents[service.ID], deleted); err != nil {
+				deleted[service.ID] = false
+				refused[service.ID] = err
+				changed = true
+			}
+		}
+	}
+
+	var errs []models.ImportError
+	for _, service := range missing {
+		if err, ok := refused[service.ID]; ok {
+			errs = append(errs, models.ImportError{Index: -1, Service: service.Name, Error: err.Error()})
+		}
+	}
+	return deleted, errs, nil
+}
+
+// planImport validates a record and compares it with the existing service.
+// It returns nil when the service is up to date.
+func planImport(fields []models.CustomField, record models.CatalogService, existing models.ServiceModel, exists bool, options models.ImportOptions, duplicate bool) (*importStep, error) {
+	if record.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidService)
+	}
+	if duplicate {
+		return nil, fmt.Errorf("%w: %q appears more than once", ErrInvalidService, record.Name)
+	}
+	if err := validateService(models.Service{Labels: record.Labels}); err != nil {
+		return nil, err
+	}
+	var metadata map[string]any
+	if record.Metadata != nil || !exists {
+		canonical, err := validateMetadata(fields, record.Metadata, true)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidService, err)
+		}
+		metadata = canonical
+	}
+	versions := map[string]models.CatalogVersion{}
+	for _, version := range record.Versions {
+		version.Version = strings.TrimSpace(version.Version)
+		if version.Version == "" {
+			return nil, fmt.Errorf("%w: version is required", ErrInvalidVersion)
+		}
+		if _, ok := versions[version.Version]; ok {
+			return nil, fmt.Errorf("%w: %q appears more than once", ErrInvalidVersion, version.Version)
+		}
+		versions[version.Version] = version
+	}
+
+	if !exists {
+		step := &importStep{
+			change: models.ImportChange{Action: models.ImportCreate, Service: record.Name},
+			service: models.Service{
+				Name:        record.Name,
+				Description: record.Description,
+				Labels:      record.Labels,
+				Metadata:    metadata,
+				ManagedBy:   options.ManagedBy,
+			},
+		}
+		for _, version := range record.Versions {
+			version.Version = strings.TrimSpace(version.Version)
+			step.add = append(step.add, version)
+			step.change.AddedVersions = append(step.change.AddedVersions, version.Version)
+		}
+		return step, nil
+	}
+
+	step := &importStep{
+		change:  models.ImportChange{Action: models.ImportUpdate, Service: record.Name},
+		service: models.Service{ID: existing.ID},
+	}
+	if record.Description != "" && record.Description != existing.Description {
+		step.service.Description = record.Description
+		step.change.Fields = append(step.change.Fields, "description")
+	}
+	if record.Labels != nil && !maps.Equal(record.Labels, existing.Labels) {
+		step.service.Labels = record.Labels
+		step.change.Fields = append(step.change.Fields, "labels")
+	}
+	if metadata != nil && !(len(metadata) == 0 && len(existing.Metadata) == 0) && !reflect.DeepEqual(metadata, existing.Metadata) {
+		step.service.Metadata = metadata
+		step.change.Fields = append(step.change.Fields, "metadata")
+	}
+	if options.ManagedBy != "" && existing.ManagedBy != options.ManagedBy {
+		// Taking over a service created by hand or by another owner must be
+		// asked for, or a name collision would silently change its owner
+		if !options.Adopt {
+			owner := "created by hand"
+			if existing.ManagedBy != "" {
+				owner = fmt.Sprintf("managed by %q", existing.ManagedBy)
+			}
+			return nil, fmt.Errorf("%w: %q is %s", ErrServiceExists, record.Name, owner)
+		}
+		step.service.ManagedBy = options.ManagedBy
+		step.change.Fields = append(step.change.Fields, "managed_by")
+	}
+
+	current := map[string]models.Version{}
+	for _, version := range existing.Versions {
+		if _, ok := current[version.Version]; !ok {
+			current[version.Version] = version
+		}
+	}
+	for _, version := range record.Versions {
+		version.Version = strings.TrimSpace(version.Version)
+		old, ok := current[version.Version]
+		switch {
+		case !ok:
+			step.add = append(step.add, version)
+			step.change.AddedVersions = append(step.change.AddedVersions, version.Version)
+		case version.Description != "" && version.Description != old.Description:
+			step.update = append(step.update, models.Version{ID: old.ID, ServiceID: existing.ID, Description: version.Description})
+			step.change.UpdatedVersions = append(step.change.UpdatedVersions, version.Version)
+		}
+	}
+	if options.Mode == models.ImportReplace {
+		for _, version := range existing.Versions {
+			if _, ok := versions[version.Version]; !ok {
+				step.remove = append(step.remove, version)
+				step.change.RemovedVersions = append(step.change.RemovedVersions, version.Version)
+			}
+		}
+		sort.Strings(step.change.RemovedVersions)
+	}
+
+	if len(step.change.Fields) == 0 && len(step.add) == 0 && len(step.update) == 0 && len(step.remove) == 0 {
+		return nil, nil
+	}
+	return step, nil
+}
+
+// applyImportStep writes the changes of a service and records their events
+func applyImportStep(ctx context.Context, repos repository.Repositories, step importStep) error {
+	serviceID := step.service.ID
+	switch step.change.Action {
+	case models.ImportCreate:
+		created, err := repos.Services.CreateService(ctx, step.service)
+		if err != nil {
+			return err
+		}
+		serviceID = created.ID
+		if err := recordEvent(ctx, repos.Outbox, models.EventServiceCreated, serviceID, created); err != nil {
+			return err
+		}
+	case models.ImportUpdate:
+		if len(step.change.Fields) > 0 {
+			updated, err := repos.Services.UpdateService(ctx, step.service)
+			if err != nil {
+				return err
+			}
+			if err := recordEvent(ctx, repos.Outbox, models.EventServiceUpdated, serviceID, updated); err != nil {
+				return err
+			}
+		}
+	case models.ImportDelete:
+		if err := repos.Services.DeleteService(ctx, serviceID); err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventServiceDeleted, serviceID, step.service)
+	}
+
+	for _, version := range step.add {
+		created, err := repos.Versions.CreateVersion(ctx, models.Version{ServiceID: serviceID, Version: version.Version, Description: version.Description})
+		if err != nil {
+			return err
+		}
+		if err := recordEvent(ctx, repos.Outbox, models.EventVersionCreated, serviceID, created); err != nil {
+			return err
+		}
+	}
+	for _, version := range step.update {
+		updated, err := repos.Versions.UpdateVersion(ctx, version)
+		if err != nil {
+			return err
+		}
+		if err := recordEvent(ctx, repos.Outbox, models.EventVersionUpdated, serviceID, updated); err != nil {
+			return err
+		}
+	}
+	for _, version := range step.remove {
+		if err := repos.Versions.DeleteVersion(ctx, version.ID, serviceID); err != nil {
+			return err
+		}
+		if err := recordEvent(ctx, repos.Outbox, models.EventVersionDeleted, serviceID, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listAllServices loads every service ordered by name with all its versions
+func listAllServices(ctx context.Context, repo repository.ServiceRepository) ([]models.ServiceModel, error) {
+	var services []models.ServiceModel
+	for page := 1; ; page++ {
+		batch, _, err := repo.ListServices(ctx, models.ServiceFilter{
+			Page:       page,
+			Limit:      catalogPageSize,
+			SortFields: []models.SortField{{Field: "name"}},
+			Expand:     models.ServiceExpand{Versions: true, VersionSort: "created_at"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) <= catalogPageSize {
+			return append(services, batch...), nil
+		}
+		services = append(services, batch[:catalogPageSize]...)
+	}
+}
@@ -0,0 +1,231 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// recordingVersionRepo records the version changes it is asked to make
+type recordingVersionRepo struct {
+	mockVersionRepository
+	changes []string
+}
+
+func (r *recordingVersionRepo) CreateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
+	r.changes = append(r.changes, fmt.Sprintf("create %d %s", version.ServiceID, version.Version))
+	return &version, nil
+}
+func (r *recordingVersionRepo) UpdateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
+	r.changes = append(r.changes, fmt.Sprintf("update %d", version.ID))
+	return &version, nil
+}
+func (r *recordingVersionRepo) DeleteVersion(ctx context.Context, versionId uint, serviceId uint) error {
+	r.changes = append(r.changes, fmt.Sprintf("delete %d", versionId))
+	return nil
+}
+
+// newCatalogBusiness returns a catalog business over the services payments,
+// with versions 1.0.0 and 1.1.0, and users. Service changes are recorded.
+func newCatalogBusiness(changes *[]string) (CatalogBusiness, *recordingVersionRepo, *memoryUnitOfWork) {
+	existing := []models.ServiceModel{
+		{ID: 1, Name: "payments", Description: "Payments", Labels: map[string]string{"tier": "critical"}, Versions: []models.Version{
+			{ID: 10, ServiceID: 1, Version: "1.0.0", Description: "Initial release"},
+			{ID: 11, ServiceID: 1, Version: "1.1.0", Description: "Refunds"},
+		}},
+		{ID: 2, Name: "users", Description: "Users"},
+	}
+	services := &mockRepo{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) ([]models.ServiceModel, int, error) {
+			if filter.Page > 1 {
+				return nil, 0, nil
+			}
+			return existing, len(existing), nil
+		},
+		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			*changes = append(*changes, "create "+service.Name)
+			service.ID = 3
+			return &service, nil
+		},
+		UpdateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			*changes = append(*changes, fmt.Sprintf("update %d %q %v", service.ID, service.Description, service.Labels))
+			return &service, nil
+		},
+		DeleteServiceFn: func(ctx context.Context, id uint) error {
+			*changes = append(*changes, fmt.Sprintf("delete %d", id))
+			return nil
+		},
+	}
+	versions := &recordingVersionRepo{}
+	repos := repository.Repositories{
+		Services:     services,
+		Versions:     versions,
+		Dependencies: newMemoryDependencyRepo(nil),
+		CustomFields: newMemoryCustomFieldRepo(),
+		Outbox:       &memoryOutbox{},
+	}
+	uow := &memoryUnitOfWork{repos: repos}
+	return NewCatalogBusiness(uow, repos), versions, uow
+}
+
+func TestExport(t *testing.T) {
+	var changes []string
+	b, _, _ := newCatalogBusiness(&changes)
+
+	catalog, err := b.Export(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(catalog.Services) != 2 || catalog.Services[0].Name != "payments" || len(catalog.Services[0].Versions) != 2 {
+		t.Errorf("unexpected catalog: %+v", catalog)
+	}
+	if catalog.Services[0].Versions[1] != (models.CatalogVersion{Version: "1.1.0", Description: "Refunds"}) {
+		t.Errorf("unexpected version: %+v", catalog.Services[0].Versions[1])
+	}
+}
+
+func TestImport(t *testing.T) {
+	catalog := models.Catalog{Services: []models.CatalogService{
+		{Name: "payments", Labels: map[string]string{"tier": "critical", "pci": "true"}, Versions: []models.CatalogVersion{
+			{Version: "1.1.0", Description: "Refunds and chargebacks"},
+			{Version: "2.0.0"},
+		}},
+		{Name: "users", Description: "Users"},
+		{Name: "billing", Versions: []models.CatalogVersion{{Version: "1.0.0"}}},
+		{Name: ""},
+		{Name: "billing"},
+		{Name: "search", Labels: map[string]string{"Bad Key": "x"}},
+	}}
+
+	var changes []string
+	b, versions, uow := newCatalogBusiness(&changes)
+	report, err := b.Import(context.Background(), catalog, models.ImportOptions{Mode: models.ImportReplace, DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Created != 1 || report.Updated != 1 || report.Deleted != 0 || report.Unchanged != 1 || len(report.Errors) != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	wantChange := models.ImportChange{
+		Action: models.ImportUpdate, Service: "payments", Fields: []string{"labels"},
+		AddedVersions: []string{"2.0.0"}, UpdatedVersions: []string{"1.1.0"}, RemovedVersions: []string{"1.0.0"},
+	}
+	if !reflect.DeepEqual(report.Changes[0], wantChange) {
+		t.Errorf("got change %+v, want %+v", report.Changes[0], wantChange)
+	}
+	if report.Errors[0].Index != 3 || report.Errors[1].Service != "billing" || report.Errors[2].Service != "search" {
+		t.Errorf("unexpected errors: %+v", report.Errors)
+	}
+	if uow.runs != 0 || len(changes) != 0 || len(versions.changes) != 0 {
+		t.Errorf("expected a dry run not to write, got %v %v", changes, versions.changes)
+	}
+
+	report, err = b.Import(context.Background(), models.Catalog{Services: catalog.Services[:3]}, models.ImportOptions{Mode: models.ImportMerge})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantChanges := []string{`update 1 "" map[pci:true tier:critical]`, "create billing"}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("got service changes %v, want %v", changes, wantChanges)
+	}
+	wantVersions := []string{"create 1 2.0.0", "update 11", "create 3 1.0.0"}
+	if !reflect.DeepEqual(versions.changes, wantVersions) {
+		t.Errorf("got version changes %v, want %v", versions.changes, wantVersions)
+	}
+	if uow.runs != 1 || len(report.Errors) != 0 {
+		t.Errorf("expected one transaction without errors, got %d runs and %+v", uow.runs, report.Errors)
+	}
+}
+
+func TestImport_ReplaceDeletes(t *testing.T) {
+	var changes []string
+	b, _, _ := newCatalogBusiness(&changes)
+
+	report, err := b.Import(context.Background(), models.Catalog{Services: []models.CatalogService{{Name: "users"}}}, models.ImportOptions{Mode: models.ImportReplace})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Deleted != 1 || !reflect.DeepEqual(changes, []string{"delete 1"}) {
+		t.Errorf("expected payments to be deleted, got %+v and %v", report, changes)
+	}
+
+	if _, err := b.Import(context.Background(), models.Catalog{}, models.ImportOptions{Mode: "overwrite"}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestImport_ReplaceKeepsDependencies(t *testing.T) {
+	var changes []string
+	b, _, uow := newCatalogBusiness(&changes)
+	deps := uow.repos.Dependencies.(*memoryDependencyRepo)
+	deps.names = map[uint]string{1: "payments", 2: "users"}
+	deps.add(2, 1)
+
+	// payments can't be deleted while users depends on it
+	report, err := b.Import(context.Background(), models.Catalog{Services: []models.CatalogService{{Name: "users"}}}, models.ImportOptions{Mode: models.ImportReplace})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []models.ImportError{{Index: -1, Service: "payments", Error: "service has dependents: users"}}
+	if report.Deleted != 0 || len(changes) != 0 || !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("expected the delete of payments to be refused, got %+v and %v", report, changes)
+	}
+
+	// unless users is deleted as well
+	report, err = b.Import(context.Background(), models.Catalog{}, models.ImportOptions{Mode: models.ImportReplace})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Deleted != 2 || len(report.Errors) != 0 {
+		t.Errorf("expected both services to be deleted, got %+v", report)
+	}
+
+	changes = nil
+	report, err = b.Import(context.Background(), models.Catalog{Services: []models.CatalogService{{Name: "users"}}}, models.ImportOptions{Mode: models.ImportReplace, Force: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Deleted != 1 || !reflect.DeepEqual(changes, []string{"delete 1"}) {
+		t.Errorf("expected a forced delete of payments, got %+v and %v", report, changes)
+	}
+}
+
+func TestImport_ManagedBy(t *testing.T) {
+	var changes []string
+	b, _, _ := newCatalogBusiness(&changes)
+	options := models.ImportOptions{Mode: models.ImportReplace, DryRun: true, ManagedBy: "gitops"}
+
+	// Neither service is managed yet, so users is reported rather than taken over
+	report, err := b.Import(context.Background(), models.Catalog{Services: []models.CatalogService{{Name: "users"}}}, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantErrors := []models.ImportError{{Index: 0, Service: "users", Error: `service already exists: "users" is created by hand`}}
+	if report.Deleted != 0 || len(report.Changes) != 0 || !reflect.DeepEqual(report.Errors, wantErrors) {
+		t.Errorf("expected users to be reported without deletes, got %+v", report)
+	}
+
+	// unless adopting is asked for
+	options.Adopt = true
+	report, err = b.Import(context.Background(), models.Catalog{Services: []models.CatalogService{{Name: "users"}}}, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []models.ImportChange{{Action: models.ImportUpdate, Service: "users", Fields: []string{"managed_by"}}}
+	if report.Deleted != 0 || len(report.Errors) != 0 || !reflect.DeepEqual(report.Changes, want) {
+		t.Errorf("expected users to be adopted without deletes, got %+v", report)
+	}
+	options.Adopt = false
+
+	options.DryRun = false
+	if _, err := b.Import(context.Background(), models.Catalog{Services: []models.CatalogService{{Name: "billing"}}}, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(changes, []string{"create billing"}) {
+		t.Errorf("expected only billing to be created, got %v", changes)
+	}
+}
@@ -0,0 +1,102 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// ErrChangesExpired is returned when changes after the requested sequence
+// were pruned, so the consumer has to reload the catalog
+var ErrChangesExpired = errors.New("changes expired")
+
+// changeOps maps the action of an event type to the operation of its change
+var changeOps = map[string]string{
+	"created": models.ChangeCreate,
+	"updated": models.ChangeUpdate,
+	"deleted": models.ChangeDelete,
+}
+
+// ChangeBusiness interface defines the change feed operations
+type ChangeBusiness interface {
+	// ListChanges returns up to limit changes with a sequence above since,
+	// in the order they were committed. A since of 0 starts at the oldest
+	// change kept. Returns ErrChangesExpired if changes after since were pruned.
+	ListChanges(ctx context.Context, since uint64, limit int) (*models.ChangeFeed, error)
+}
+
+type changeBusinessImpl struct {
+	repo repository.OutboxRepository
+}
+
+// NewChangeBusiness creates a new business logic implementation reading
+// the changes from the event log in the outbox
+func NewChangeBusiness(repo repository.OutboxRepository) ChangeBusiness {
+	return &changeBusinessImpl{repo: repo}
+}
+
+// ListChanges returns a page of the change feed. The sequence of a change
+// is the ID of its event, which follows the commit order.
+func (b *changeBusinessImpl) ListChanges(ctx context.Context, since uint64, limit int) (*models.ChangeFeed, error) {
+	// One more event tells whether there is another page
+	events, err := b.repo.ListEventsAfter(ctx, since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pruning is checked after reading, so events deleted in between are noticed
+	if since > 0 {
+		prunedThrough, err := b.repo.PrunedThrough(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if since < prunedThrough {
+			return nil, fmt.Errorf("%w: changes up to %d were pruned", ErrChangesExpired, prunedThrough)
+		}
+	}
+
+	feed := &models.ChangeFeed{
+		Changes:   make([]models.Change, 0, min(len(events), limit)),
+		NextSince: since,
+		HasMore:   len(events) > limit,
+	}
+	for _, event := range events[:min(len(events), limit)] {
+		change, err := toChange(event)
+		if err != nil {
+			return nil, err
+		}
+		feed.Changes = append(feed.Changes, change)
+		feed.NextSince = change.Seq
+	}
+	return feed, nil
+}
+
+// toChange returns the change an event records
+func toChange(event models.OutboxEvent) (models.Change, error) {
+	entity, action, _ := strings.Cut(event.Type, ".")
+	var entityId struct {
+		ID uint `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(event.Data), &entityId); err != nil {
+		return models.Change{}, fmt.Errorf("failed to decode event %d: %w", event.ID, err)
+	}
+	change := models.Change{
+		Seq:       event.ID,
+		Entity:    entity,
+		Op:        changeOps[action],
+		ID:        entityId.ID,
+		ServiceID: event.ServiceID,
+		ChangedAt: event.OccurredAt,
+	}
+
+	// Deletions are tombstones; their event has the state before the change
+	if change.Op != models.ChangeDelete {
+		change.State = json.RawMessage(event.Data)
+	}
+	return change, nil
+}
@@ -0,0 +1,112 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// newChangeFeed returns a change business over the events of creating,
+// updating and deleting service 7, with a rolled back change in between
+func newChangeFeed(t *testing.T) (ChangeBusiness, *memoryOutbox) {
+	repo := &mockRepo{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			return &models.Service{ID: id, Name: "users"}, nil
+		},
+		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			service.ID = 7
+			return &service, nil
+		},
+		UpdateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			return &service, nil
+		},
+		DeleteServiceFn: func(ctx context.Context, id uint) error {
+			return nil
+		},
+	}
+	uow := serviceUnitOfWork(repo)
+	outbox := uow.repos.Outbox.(*memoryOutbox)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), uow)
+
+	ctx := context.Background()
+	if _, err := bs.CreateService(ctx, models.Service{Name: "users"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A rolled back change leaves a gap in the sequence
+	err := uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := recordEvent(ctx, repos.Outbox, models.EventServiceUpdated, 7, models.Service{ID: 7}); err != nil {
+			return err
+		}
+		return errors.New("connection reset")
+	})
+	if err == nil {
+		t.Fatal("expected the unit of work to fail")
+	}
+
+	if _, err := bs.UpdateService(ctx, models.Service{ID: 7, Description: "Users"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bs.DeleteService(ctx, 7, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewChangeBusiness(outbox), outbox
+}
+
+func TestListChanges(t *testing.T) {
+	changes, _ := newChangeFeed(t)
+
+	feed, err := changes.ListChanges(context.Background(), 0, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed.Changes) != 2 || !feed.HasMore || feed.NextSince != 3 {
+		t.Fatalf("unexpected first page: %+v", feed)
+	}
+	created, updated := feed.Changes[0], feed.Changes[1]
+	if created.Seq != 1 || created.Entity != models.ChangeEntityService || created.Op != models.ChangeCreate || created.ID != 7 || created.State == nil {
+		t.Errorf("unexpected create: %+v", created)
+	}
+	if updated.Seq != 3 || updated.Op != models.ChangeUpdate || string(updated.State) == "" {
+		t.Errorf("unexpected update: %+v", updated)
+	}
+
+	feed, err = changes.ListChanges(context.Background(), feed.NextSince, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed.Changes) != 1 || feed.HasMore || feed.NextSince != 4 {
+		t.Fatalf("unexpected last page: %+v", feed)
+	}
+	if tombstone := feed.Changes[0]; tombstone.Op != models.ChangeDelete || tombstone.ID != 7 || tombstone.State != nil {
+		t.Errorf("expected a tombstone, got %+v", tombstone)
+	}
+
+	// Nothing new keeps the position
+	feed, err = changes.ListChanges(context.Background(), 4, 2)
+	if err != nil || len(feed.Changes) != 0 || feed.NextSince != 4 {
+		t.Errorf("expected an empty page at 4, got %+v, %v", feed, err)
+	}
+}
+
+func TestListChanges_Expired(t *testing.T) {
+	changes, outbox := newChangeFeed(t)
+	outbox.events = outbox.events[1:]
+	outbox.prunedThrough = 1
+
+	if _, err := changes.ListChanges(context.Background(), 0, 10); err != nil {
+		t.Errorf("expected 0 to start at the oldest change kept, got %v", err)
+	}
+	if _, err := changes.ListChanges(context.Background(), 1, 10); err != nil {
+		t.Errorf("expected the changes after the pruned ones to be listed, got %v", err)
+	}
+
+	outbox.events = outbox.events[1:]
+	outbox.prunedThrough = 3
+	if _, err := changes.ListChanges(context.Background(), 1, 10); !errors.Is(err, ErrChangesExpired) {
+		t.Errorf("expected ErrChangesExpired, got %v", err)
+	}
+}
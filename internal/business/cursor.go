@@ -0,0 +1,144 @@
+package business
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"services-api/internal/models"
+)
+
+var (
+	// ErrInvalidCursor is returned when a pagination cursor can't be decoded
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrInvalidSort is returned when a sort names an unknown field
+	ErrInvalidSort = errors.New("invalid sort")
+)
+
+// cursor is the decoded form of the opaque pagination cursor. It carries the
+// sort so that following it keeps the order the list was started with.
+type cursor struct {
+	Sort     string   `json:"sort"`
+	Values   []string `json:"values"`
+	ID       uint     `json:"id"`
+	Backward bool     `json:"backward,omitempty"`
+}
+
+// serviceSorts are the fields services can be sorted by
+var serviceSorts = []string{"name", "created_at", "updated_at", "version_count"}
+
+// parseSort parses a comma-separated sort such as "-updated_at,name" where a
+// "-" prefix sorts descending. A single field without prefix is descending
+// when order is "desc", as before multi-column sorts were supported.
+func parseSort(sort, order string) ([]models.SortField, error) {
+	if sort == "" {
+		sort = "name"
+	}
+	var fields []models.SortField
+	for _, part := range strings.Split(sort, ",") {
+		field := models.SortField{Field: strings.TrimSpace(part)}
+		if name, ok := strings.CutPrefix(field.Field, "-"); ok {
+			field.Field, field.Descending = name, true
+		} else {
+			field.Field = strings.TrimPrefix(field.Field, "+")
+		}
+		if !isServiceSort(field.Field) {
+			return nil, fmt.Errorf("%w: %q isn't sortable, expected %s", ErrInvalidSort, field.Field, strings.Join(serviceSorts, ", "))
+		}
+		for _, previous := range fields {
+			if previous.Field == field.Field {
+				return nil, fmt.Errorf("%w: %q is listed twice", ErrInvalidSort, field.Field)
+			}
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 1 && !strings.HasPrefix(sort, "-") && order == "desc" {
+		fields[0].Descending = true
+	}
+	return fields, nil
+}
+
+func isServiceSort(field string) bool {
+	for _, sort := range serviceSorts {
+		if sort == field {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSort renders sort fields in the syntax parseSort accepts
+func formatSort(fields []models.SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Field
+		if field.Descending {
+			parts[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor returns the cursor of the position of a service in a list
+func encodeCursor(fields []models.SortField, service models.ServiceModel, backward bool) string {
+	c := cursor{Sort: formatSort(fields), ID: service.ID, Backward: backward}
+	for _, field := range fields {
+		switch field.Field {
+		case "created_at":
+			c.Values = append(c.Values, service.CreatedAt.Format(time.RFC3339Nano))
+		case "updated_at":
+			c.Values = append(c.Values, service.UpdatedAt.Format(time.RFC3339Nano))
+		case "version_count":
+			c.Values = append(c.Values, strconv.Itoa(service.VersionCount))
+		default:
+			c.Values = append(c.Values, service.Name)
+		}
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor and returns its sort and keyset
+func decodeCursor(s string) ([]models.SortField, *models.Keyset, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: not base64url", ErrInvalidCursor)
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 {
+		return nil, nil, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+	fields, err := parseSort(c.Sort, "")
+	if err != nil || len(fields) != len(c.Values) {
+		return nil, nil, fmt.Errorf("%w: malformed sort", ErrInvalidCursor)
+	}
+
+	keyset := &models.Keyset{ID: c.ID, Backward: c.Backward}
+	for i, field := range fields {
+		var value any = c.Values[i]
+		switch field.Field {
+		case "created_at", "updated_at":
+			if value, err = time.Parse(time.RFC3339Nano, c.Values[i]); err != nil {
+				return nil, nil, fmt.Errorf("%w: malformed timestamp", ErrInvalidCursor)
+			}
+		case "version_count":
+			if value, err = strconv.Atoi(c.Values[i]); err != nil {
+				return nil, nil, fmt.Errorf("%w: malformed count", ErrInvalidCursor)
+			}
+		}
+		keyset.Values = append(keyset.Values, value)
+	}
+	return fields, keyset, nil
+}
+
+// setTotal fills in the total items and pages of a pagination
+func setTotal(pagination *models.Pagination, total int) {
+	pages := (total + pagination.ItemsPerPage - 1) / pagination.ItemsPerPage
+	pagination.TotalItems = &total
+	pagination.TotalPages = &pages
+}
@@ -0,0 +1,125 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"services-api/internal/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2025, 5, 1, 12, 30, 0, 123456000, time.UTC)
+	service := models.ServiceModel{ID: 42, Name: "users", CreatedAt: created}
+
+	service.VersionCount = 3
+	sort := []models.SortField{{Field: "created_at", Descending: true}, {Field: "version_count"}, {Field: "name"}}
+
+	fields, keyset, err := decodeCursor(encodeCursor(sort, service, true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(fields, sort) || keyset.ID != 42 || !keyset.Backward {
+		t.Errorf("unexpected cursor: %+v %+v", fields, keyset)
+	}
+	if value, ok := keyset.Values[0].(time.Time); !ok || !value.Equal(created) {
+		t.Errorf("expected the creation time, got %v", keyset.Values[0])
+	}
+	if keyset.Values[1] != 3 || keyset.Values[2] != "users" {
+		t.Errorf("unexpected keyset values: %v", keyset.Values)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, c := range []string{
+		"not a cursor!",
+		"bm90IGpzb24", // "not json"
+		"eyJzb3J0IjoiaWQiLCJvcmRlciI6ImFzYyIsImlkIjoxfQ",                                      // sort by id
+		"eyJzb3J0IjoiY3JlYXRlZF9hdCIsIm9yZGVyIjoiYXNjIiwidmFsdWUiOiJ5ZXN0ZXJkYXkiLCJpZCI6MX0", // bad timestamp
+	} {
+		if _, _, err := decodeCursor(c); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v", c, err)
+		}
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort, order, want string
+	}{
+		{"", "", "name"},
+		{"created_at", "desc", "-created_at"},
+		{"-updated_at,name", "", "-updated_at,name"},
+		{"-updated_at, +version_count", "desc", "-updated_at,version_count"},
+	}
+	for _, tt := range tests {
+		fields, err := parseSort(tt.sort, tt.order)
+		if err != nil || formatSort(fields) != tt.want {
+			t.Errorf("parseSort(%q, %q) = %q, %v, want %q", tt.sort, tt.order, formatSort(fields), err, tt.want)
+		}
+	}
+
+	for _, sort := range []string{"id", "name,-name", "name,"} {
+		if _, err := parseSort(sort, ""); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("expected ErrInvalidSort for %q, got %v", sort, err)
+		}
+	}
+}
+
+func TestListServices_Cursor(t *testing.T) {
+	var filter models.ServiceFilter
+	rows := []models.ServiceModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+	repo := &mockRepo{
+		ListServicesFn: func(ctx context.Context, f models.ServiceFilter) ([]models.ServiceModel, int, error) {
+			filter = f
+			return rows, 10, nil
+		},
+	}
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
+
+	// First page in offset mode: a next page, no previous one
+	resp, err := bs.ListServices(context.Background(), models.ServiceFilter{Page: 1, Limit: 2, IncludeTotal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Services) != 2 || resp.Services[1].ID != 2 || resp.Pagination.PrevCursor != "" || *resp.Pagination.TotalItems != 10 {
+		t.Errorf("unexpected first page: %+v", resp)
+	}
+
+	// Following next_cursor reads forward after the last service
+	resp, err = bs.ListServices(context.Background(), models.ServiceFilter{Sort: "created_at", Limit: 2, Cursor: resp.Pagination.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if formatSort(filter.SortFields) != "name" || filter.Keyset.ID != 2 || filter.Keyset.Backward || filter.IncludeTotal {
+		t.Errorf("unexpected repository filter: %+v", filter)
+	}
+	if resp.Pagination.NextCursor == "" || resp.Pagination.PrevCursor == "" || resp.Pagination.TotalItems != nil || resp.Pagination.CurrentPage != 0 {
+		t.Errorf("unexpected pagination: %+v", resp.Pagination)
+	}
+
+	// Following prev_cursor reads backward; the extra row comes first
+	resp, err = bs.ListServices(context.Background(), models.ServiceFilter{Limit: 2, Cursor: resp.Pagination.PrevCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !filter.Keyset.Backward || filter.Keyset.ID != 1 {
+		t.Errorf("unexpected repository filter: %+v", filter)
+	}
+	if resp.Services[0].ID != 2 || resp.Pagination.PrevCursor == "" || resp.Pagination.NextCursor == "" {
+		t.Errorf("unexpected backward page: %+v", resp)
+	}
+
+	// A short last page has no next cursor
+	rows = rows[:1]
+	resp, _ = bs.ListServices(context.Background(), models.ServiceFilter{Page: 3, Limit: 2})
+	if resp.Pagination.NextCursor != "" || resp.Pagination.PrevCursor == "" {
+		t.Errorf("unexpected last page: %+v", resp.Pagination)
+	}
+
+	if _, err := bs.ListServices(context.Background(), models.ServiceFilter{Limit: 2, Cursor: "garbage"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+var (
+	// ErrCustomFieldNotFound is returned when a requested custom field doesn't exist
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+
+	// ErrInvalidCustomField is returned when a custom field request fails validation
+	ErrInvalidCustomField = errors.New("invalid custom field")
+
+	// ErrCustomFieldExists is returned when creating a custom field whose name is taken
+	ErrCustomFieldExists = errors.New("custom field already exists")
+
+	// ErrCustomFieldInUse is returned when removing enum options that services still use
+	ErrCustomFieldInUse = errors.New("custom field option in use")
+)
+
+// maxFieldValueLength is the maximum length of a string or URL field value
+const maxFieldValueLength = 2048
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+var fieldTypes = []string{
+	models.FieldTypeString,
+	models.FieldTypeURL,
+	models.FieldTypeEnum,
+	models.FieldTypeNumber,
+	models.FieldTypeDate,
+}
+
+// CustomFieldBusiness interface defines custom field business logic operations
+type CustomFieldBusiness interface {
+	// ListCustomFields returns all custom field definitions
+	ListCustomFields(ctx context.Context) ([]models.CustomField, error)
+
+	// GetCustomField retrieves a custom field definition
+	// Returns ErrCustomFieldNotFound if it doesn't exist.
+	GetCustomField(ctx context.Context, id uint) (*models.CustomField, error)
+
+	// CreateCustomField defines a new custom field
+	// Returns ErrInvalidCustomField or ErrCustomFieldExists.
+	CreateCustomField(ctx context.Context, req models.CustomFieldRequest) (*models.CustomField, error)
+
+	// UpdateCustomField changes the display name, description, required flag or options of a field
+	// Returns ErrCustomFieldNotFound, ErrInvalidCustomField or ErrCustomFieldInUse.
+	UpdateCustomField(ctx context.Context, id uint, req models.CustomFieldRequest) (*models.CustomField, error)
+
+	// DeleteCustomField deletes a custom field and its value on every service
+	// Returns ErrCustomFieldNotFound if it doesn't exist.
+	DeleteCustomField(ctx context.Context, id uint) error
+}
+
+type customFieldBusinessImpl struct {
+	repo repository.CustomFieldRepository
+}
+
+// NewCustomFieldBusiness creates a new business logic implementation
+// with the provided repository.
+func NewCustomFieldBusiness(repo repository.CustomFieldRepository) CustomFieldBusiness {
+	return &customFieldBusinessImpl{repo: repo}
+}
+
+// ListCustomFields returns all custom field definitions
+func (b *customFieldBusinessImpl) ListCustomFields(ctx context.Context) ([]models.CustomField, error) {
+	return b.repo.ListCustomFields(ctx)
+}
+
+// GetCustomField returns a custom field definition by ID
+func (b *customFieldBusinessImpl) GetCustomField(ctx context.Context, id uint) (*models.CustomField, error) {
+	field, err := b.repo.GetCustomField(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCustomFieldNotFound
+		}
+		return nil, err
+	}
+	return field, nil
+}
+
+// CreateCustomField defines a new custom field
+func (b *customFieldBusinessImpl) CreateCustomField(ctx context.Context, req models.CustomFieldRequest) (*models.CustomField, error) {
+	if !fieldNamePattern.MatchString(req.Name) {
+		return nil, fmt.Errorf("%w: name must start with a lowercase letter and contain at most 63 lowercase letters, digits or '_'", ErrInvalidCustomField)
+	}
+	if !slices.Contains(fieldTypes, req.Type) {
+		return nil, fmt.Errorf("%w: type must be one of %s", ErrInvalidCustomField, strings.Join(fieldTypes, ", "))
+	}
+	if err := validateOptions(req.Type, req.Options); err != nil {
+		return nil, err
+	}
+
+	fields, err := b.repo.ListCustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if field.Name == req.Name {
+			return nil, fmt.Errorf("%w: %s", ErrCustomFieldExists, req.Name)
+		}
+	}
+
+	field := models.CustomField{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Type:        req.Type,
+		Options:     req.Options,
+	}
+	if field.DisplayName == "" {
+		field.DisplayName = req.Name
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	return b.repo.CreateCustomField(ctx, field)
+}
+
+// UpdateCustomField changes the mutable attributes of a custom field
+func (b *customFieldBusinessImpl) UpdateCustomField(ctx context.Context, id uint, req models.CustomFieldRequest) (*models.CustomField, error) {
+	field, err := b.GetCustomField(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if (req.Name != "" && req.Name != field.Name) || (req.Type != "" && req.Type != field.Type) {
+		return nil, fmt.Errorf("%w: the name and type of a field can't be changed", ErrInvalidCustomField)
+	}
+
+	if req.DisplayName != "" {
+		field.DisplayName = req.DisplayName
+	}
+	if req.Description != "" {
+		field.Description = req.Description
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.Options != nil {
+		if err := validateOptions(field.Type, req.Options); err != nil {
+			return nil, err
+		}
+		var removed []string
+		for _, option := range field.Options {
+			if !slices.Contains(req.Options, option) {
+				removed = append(removed, option)
+			}
+		}
+		if len(removed) > 0 {
+			count, err := b.repo.CountFieldValues(ctx, field.ID, removed)
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, fmt.Errorf("%w: %d services use %s", ErrCustomFieldInUse, count, strings.Join(removed, ", "))
+			}
+		}
+		field.Options = req.Options
+	}
+
+	updated, err := b.repo.UpdateCustomField(ctx, *field)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCustomFieldNotFound
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteCustomField deletes a custom field and its values
+func (b *customFieldBusinessImpl) DeleteCustomField(ctx context.Context, id uint) error {
+	err := b.repo.DeleteCustomField(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrCustomFieldNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// validateOptions checks that enum fields have distinct, non-empty options
+// and that other types have none
+func validateOptions(fieldType string, options []string) error {
+	if fieldType != models.FieldTypeEnum {
+		if len(options) > 0 {
+			return fmt.Errorf("%w: only enum fields have options", ErrInvalidCustomField)
+		}
+		return nil
+	}
+	if len(options) == 0 {
+		return fmt.Errorf("%w: enum fields need at least one option", ErrInvalidCustomField)
+	}
+	for i, option := range options {
+		if option == "" || len(option) > maxFieldValueLength {
+			return fmt.Errorf("%w: options must be non-empty and at most %d characters", ErrInvalidCustomField, maxFieldValueLength)
+		}
+		if slices.Contains(options[:i], option) {
+			return fmt.Errorf("%w: duplicate option %q", ErrInvalidCustomField, option)
+		}
+	}
+	return nil
+}
+
+// validateMetadata checks the custom field values of a service against the
+// field definitions and returns them in canonical form: numbers as float64
+// and everything else as strings. When requireAll is set every required
+// field must have a value.
+func validateMetadata(fields []models.CustomField, metadata map[string]any, requireAll bool) (map[string]any, error) {
+	byName := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	canonical := make(map[string]any, len(metadata))
+	for name, value := range metadata {
+		field, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field %q", name)
+		}
+		if value == nil {
+			continue
+		}
+		v, err := canonicalFieldValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		canonical[name] = v
+	}
+
+	if requireAll {
+		for _, field := range fields {
+			if _, ok := canonical[field.Name]; field.Required && !ok {
+				return nil, fmt.Errorf("custom field %q is required", field.Name)
+			}
+		}
+	}
+	return canonical, nil
+}
+
+// canonicalFieldValue validates a single value. Numbers are accepted as JSON
+// numbers or numeric strings so that query parameters can be validated too.
+// NaN and infinities, which YAML and ParseFloat accept, can't be stored as
+// JSON and are rejected.
+func canonicalFieldValue(field models.CustomField, value any) (any, error) {
+	if field.Type == models.FieldTypeNumber {
+		var number float64
+		switch v := value.(type) {
+		case float64:
+			number = v
+		case int:
+			number = float64(v)
+		case string:
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("custom field %q must be a number", field.Name)
+			}
+			number = parsed
+		default:
+			return nil, fmt.Errorf("custom field %q must be a number", field.Name)
+		}
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("custom field %q must be a finite number", field.Name)
+		}
+		return number, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("custom field %q must be a string", field.Name)
+	}
+	if len(s) > maxFieldValueLength {
+		return nil, fmt.Errorf("custom field %q must be at most %d characters", field.Name, maxFieldValueLength)
+	}
+
+	switch field.Type {
+	case models.FieldTypeURL:
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("custom field %q must be an http or https URL", field.Name)
+		}
+	case models.FieldTypeEnum:
+		if !slices.Contains(field.Options, s) {
+			return nil, fmt.Errorf("custom field %q must be one of %s", field.Name, strings.Join(field.Options, ", "))
+		}
+	case models.FieldTypeDate:
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return nil, fmt.Errorf("custom field %q must be a date in YYYY-MM-DD format", field.Name)
+		}
+	}
+	return s, nil
+}
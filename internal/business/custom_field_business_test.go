@@ -0,0 +1,246 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// memoryCustomFieldRepo is an in-memory CustomFieldRepository. values maps
+// field IDs to the stored values of all services.
+type memoryCustomFieldRepo struct {
+	fields map[uint]models.CustomField
+	values map[uint][]string
+	nextID uint
+}
+
+func newMemoryCustomFieldRepo(fields ...models.CustomField) *memoryCustomFieldRepo {
+	r := &memoryCustomFieldRepo{fields: map[uint]models.CustomField{}, values: map[uint][]string{}}
+	for _, field := range fields {
+		r.CreateCustomField(context.Background(), field)
+	}
+	return r
+}
+
+func (r *memoryCustomFieldRepo) ListCustomFields(ctx context.Context) ([]models.CustomField, error) {
+	var fields []models.CustomField
+	for _, field := range r.fields {
+		fields = append(fields, field)
+	}
+	slices.SortFunc(fields, func(a, b models.CustomField) int { return int(a.ID) - int(b.ID) })
+	return fields, nil
+}
+
+func (r *memoryCustomFieldRepo) GetCustomField(ctx context.Context, id uint) (*models.CustomField, error) {
+	field, ok := r.fields[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &field, nil
+}
+
+func (r *memoryCustomFieldRepo) CreateCustomField(ctx context.Context, field models.CustomField) (*models.CustomField, error) {
+	r.nextID++
+	field.ID = r.nextID
+	r.fields[field.ID] = field
+	return &field, nil
+}
+
+func (r *memoryCustomFieldRepo) UpdateCustomField(ctx context.Context, field models.CustomField) (*models.CustomField, error) {
+	if _, ok := r.fields[field.ID]; !ok {
+		return nil, repository.ErrNotFound
+	}
+	r.fields[field.ID] = field
+	return &field, nil
+}
+
+func (r *memoryCustomFieldRepo) DeleteCustomField(ctx context.Context, id uint) error {
+	if _, ok := r.fields[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.fields, id)
+	delete(r.values, id)
+	return nil
+}
+
+func (r *memoryCustomFieldRepo) CountFieldValues(ctx context.Context, fieldID uint, values []string) (int, error) {
+	count := 0
+	for _, value := range r.values[fieldID] {
+		if slices.Contains(values, value) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func sampleFields() []models.CustomField {
+	return []models.CustomField{
+		{Name: "sla_tier", Type: models.FieldTypeEnum, Required: true, Options: []string{"gold", "silver"}},
+		{Name: "runbook", Type: models.FieldTypeURL},
+		{Name: "cost", Type: models.FieldTypeNumber},
+		{Name: "launched", Type: models.FieldTypeDate},
+		{Name: "owner", Type: models.FieldTypeString},
+	}
+}
+
+func TestCreateCustomField(t *testing.T) {
+	b := NewCustomFieldBusiness(newMemoryCustomFieldRepo())
+	required := true
+
+	field, err := b.CreateCustomField(context.Background(), models.CustomFieldRequest{
+		Name: "sla_tier", Type: models.FieldTypeEnum, Required: &required, Options: []string{"gold", "silver"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field.DisplayName != "sla_tier" || !field.Required {
+		t.Errorf("unexpected field: %+v", field)
+	}
+
+	_, err = b.CreateCustomField(context.Background(), models.CustomFieldRequest{Name: "sla_tier", Type: models.FieldTypeString})
+	if !errors.Is(err, ErrCustomFieldExists) {
+		t.Errorf("expected ErrCustomFieldExists, got %v", err)
+	}
+}
+
+func TestCreateCustomField_Invalid(t *testing.T) {
+	b := NewCustomFieldBusiness(newMemoryCustomFieldRepo())
+	for _, req := range []models.CustomFieldRequest{
+		{Name: "SLA", Type: models.FieldTypeString},
+		{Name: "sla tier", Type: models.FieldTypeString},
+		{Name: "sla", Type: "boolean"},
+		{Name: "sla", Type: models.FieldTypeEnum},
+		{Name: "sla", Type: models.FieldTypeEnum, Options: []string{"gold", "gold"}},
+		{Name: "sla", Type: models.FieldTypeString, Options: []string{"gold"}},
+	} {
+		if _, err := b.CreateCustomField(context.Background(), req); !errors.Is(err, ErrInvalidCustomField) {
+			t.Errorf("expected ErrInvalidCustomField for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestUpdateCustomField(t *testing.T) {
+	repo := newMemoryCustomFieldRepo(sampleFields()...)
+	repo.values[1] = []string{"gold"}
+	b := NewCustomFieldBusiness(repo)
+
+	_, err := b.UpdateCustomField(context.Background(), 1, models.CustomFieldRequest{Options: []string{"silver"}})
+	if !errors.Is(err, ErrCustomFieldInUse) {
+		t.Errorf("expected ErrCustomFieldInUse, got %v", err)
+	}
+
+	_, err = b.UpdateCustomField(context.Background(), 1, models.CustomFieldRequest{Type: models.FieldTypeString})
+	if !errors.Is(err, ErrInvalidCustomField) {
+		t.Errorf("expected ErrInvalidCustomField, got %v", err)
+	}
+
+	optional := false
+	field, err := b.UpdateCustomField(context.Background(), 1, models.CustomFieldRequest{
+		DisplayName: "SLA tier", Required: &optional, Options: []string{"gold", "silver", "bronze"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field.DisplayName != "SLA tier" || field.Required || len(field.Options) != 3 {
+		t.Errorf("unexpected field: %+v", field)
+	}
+
+	if _, err := b.UpdateCustomField(context.Background(), 42, models.CustomFieldRequest{}); !errors.Is(err, ErrCustomFieldNotFound) {
+		t.Errorf("expected ErrCustomFieldNotFound, got %v", err)
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	fields := sampleFields()
+
+	metadata, err := validateMetadata(fields, map[string]any{
+		"sla_tier": "gold",
+		"runbook":  "https://wiki.example.com/runbook",
+		"cost":     12.5,
+		"launched": "2024-02-29",
+		"owner":    "team-a",
+	}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata["cost"] != 12.5 || metadata["sla_tier"] != "gold" {
+		t.Errorf("unexpected metadata: %v", metadata)
+	}
+
+	tests := []map[string]any{
+		{"runbook": "https://wiki.example.com"},
+		{"sla_tier": "platinum"},
+		{"sla_tier": "gold", "runbook": "ftp://example.com"},
+		{"sla_tier": "gold", "runbook": "not a url"},
+		{"sla_tier": "gold", "cost": "cheap"},
+		{"sla_tier": "gold", "cost": "NaN"},
+		{"sla_tier": "gold", "cost": "-Inf"},
+		{"sla_tier": "gold", "cost": math.NaN()},
+		{"sla_tier": "gold", "cost": math.Inf(1)},
+		{"sla_tier": "gold", "launched": "2024-02-30"},
+		{"sla_tier": "gold", "owner": 42.0},
+		{"sla_tier": "gold", "unknown": "x"},
+	}
+	for _, tt := range tests {
+		if _, err := validateMetadata(fields, tt, true); err == nil {
+			t.Errorf("expected an error for %v", tt)
+		}
+	}
+
+	if _, err := validateMetadata(fields, map[string]any{"cost": "1.50"}, false); err != nil {
+		t.Errorf("expected numeric strings to be accepted without required fields, got %v", err)
+	}
+}
+
+func TestCreateService_Metadata(t *testing.T) {
+	var stored models.Service
+	repo := &mockRepo{
+		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			stored = service
+			return &service, nil
+		},
+	}
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(sampleFields()...), serviceUnitOfWork(repo))
+
+	_, err := bs.CreateService(context.Background(), models.Service{Name: "users"})
+	if !errors.Is(err, ErrInvalidService) {
+		t.Errorf("expected ErrInvalidService for a missing required field, got %v", err)
+	}
+
+	_, err = bs.CreateService(context.Background(), models.Service{Name: "users", Metadata: map[string]any{"sla_tier": "gold", "cost": 3.0}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Metadata["sla_tier"] != "gold" || stored.Metadata["cost"] != 3.0 {
+		t.Errorf("unexpected stored metadata: %v", stored.Metadata)
+	}
+}
+
+func TestListServices_MetadataFilter(t *testing.T) {
+	var filter models.ServiceFilter
+	repo := &mockRepo{
+		ListServicesFn: func(ctx context.Context, f models.ServiceFilter) ([]models.ServiceModel, int, error) {
+			filter = f
+			return nil, 0, nil
+		},
+	}
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(sampleFields()...), serviceUnitOfWork(repo))
+
+	_, err := bs.ListServices(context.Background(), models.ServiceFilter{Page: 1, Limit: 10, Metadata: map[string]string{"cost": "1.50"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filter.Metadata["cost"] != "1.5" {
+		t.Errorf("expected the canonical number, got %q", filter.Metadata["cost"])
+	}
+
+	_, err = bs.ListServices(context.Background(), models.ServiceFilter{Page: 1, Limit: 10, Metadata: map[string]string{"sla_tier": "platinum"}})
+	if !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expected ErrInvalidFilter, got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+var (
+	// ErrDependencyNotFound is returned when a requested dependency doesn't exist
+	ErrDependencyNotFound = errors.New("dependency not found")
+
+	// ErrDependencyCycle is returned when a new dependency would create a cycle
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+	// ErrDependencyExists is returned when a service already depends on the target
+	ErrDependencyExists = errors.New("dependency already exists")
+
+	// ErrInvalidDependency is returned when a dependency request fails validation
+	ErrInvalidDependency = errors.New("invalid dependency")
+)
+
+// DependencyBusiness interface defines dependency graph business logic operations
+type DependencyBusiness interface {
+	// ListDependencies returns the services a service depends on
+	// Returns ErrServiceNotFound if the service doesn't exist.
+	ListDependencies(ctx context.Context, serviceID uint) ([]models.Dependency, error)
+
+	// ListDependents returns the services depending on a service
+	// Returns ErrServiceNotFound if the service doesn't exist.
+	ListDependents(ctx context.Context, serviceID uint) ([]models.Dependency, error)
+
+	// GetDependency retrieves a dependency of a service
+	// Returns ErrDependencyNotFound if it doesn't exist.
+	GetDependency(ctx context.Context, serviceID, id uint) (*models.Dependency, error)
+
+	// CreateDependency makes a service depend on another one
+	// Returns ErrServiceNotFound, ErrInvalidDependency, ErrDependencyExists or ErrDependencyCycle.
+	CreateDependency(ctx context.Context, serviceID uint, req models.DependencyRequest) (*models.Dependency, error)
+
+	// UpdateDependency changes the version constraint of a dependency
+	// Returns ErrDependencyNotFound or ErrInvalidDependency.
+	UpdateDependency(ctx context.Context, serviceID, id uint, req models.DependencyRequest) (*models.Dependency, error)
+
+	// DeleteDependency removes a dependency of a service
+	// Returns ErrDependencyNotFound if it doesn't exist.
+	DeleteDependency(ctx context.Context, serviceID, id uint) error
+
+	// GetGraph returns the dependency graph of all services
+	GetGraph(ctx context.Context) (*models.Graph, error)
+}
+
+type dependencyBusinessImpl struct {
+	repo     repository.DependencyRepository
+	services repository.ServiceRepository
+}
+
+// NewDependencyBusiness creates a new business logic implementation
+// with the provided repositories.
+func NewDependencyBusiness(repo repository.DependencyRepository, services repository.ServiceRepository) DependencyBusiness {
+	return &dependencyBusinessImpl{
+		repo:     repo,
+		services: services,
+	}
+}
+
+// ListDependencies returns the services a service depends on
+func (b *dependencyBusinessImpl) ListDependencies(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+	if err := b.requireService(ctx, serviceID); err != nil {
+		return nil, err
+	}
+	return b.repo.ListDependencies(ctx, serviceID)
+}
+
+// ListDependents returns the services depending on a service
+func (b *dependencyBusinessImpl) ListDependents(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+	if err := b.requireService(ctx, serviceID); err != nil {
+		return nil, err
+	}
+	return b.repo.ListDependents(ctx, serviceID)
+}
+
+// GetDependency retrieves a dependency of a service
+func (b *dependencyBusinessImpl) GetDependency(ctx context.Context, serviceID, id uint) (*models.Dependency, error) {
+	dependency, err := b.repo.GetDependency(ctx, serviceID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDependencyNotFound
+		}
+		return nil, err
+	}
+	return dependency, nil
+}
+
+// CreateDependency validates the request, rejects cycles and stores the dependency
+func (b *dependencyBusinessImpl) CreateDependency(ctx context.Context, serviceID uint, req models.DependencyRequest) (*models.Dependency, error) {
+	constraint, err := normalizeConstraint(req.VersionConstraint)
+	if err != nil {
+		return nil, err
+	}
+	if req.DependsOnID == 0 {
+		return nil, fmt.Errorf("%w: depends_on_id is required", ErrInvalidDependency)
+	}
+	if err := b.requireService(ctx, serviceID); err != nil {
+		return nil, err
+	}
+
+	dependency := models.Dependency{
+		ServiceID:         serviceID,
+		DependsOnID:       req.DependsOnID,
+		VersionConstraint: constraint,
+	}
+	created, err := b.repo.CreateDependency(ctx, dependency, func(existing []models.Dependency) error {
+		for _, edge := range existing {
+			if edge.ServiceID == serviceID && edge.DependsOnID == req.DependsOnID {
+				return ErrDependencyExists
+			}
+		}
+		if path := findPath(existing, req.DependsOnID, serviceID); path != nil {
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, formatPath(append([]uint{serviceID}, path...)))
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		// The existence of both services is checked while holding the
+		// dependency lock; tell which one is missing
+		if err := b.requireService(ctx, serviceID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: service %d doesn't exist", ErrInvalidDependency, req.DependsOnID)
+	}
+	return created, err
+}
+
+// UpdateDependency changes the version constraint of a dependency
+func (b *dependencyBusinessImpl) UpdateDependency(ctx context.Context, serviceID, id uint, req models.DependencyRequest) (*models.Dependency, error) {
+	constraint, err := normalizeConstraint(req.VersionConstraint)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := b.GetDependency(ctx, serviceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.DependsOnID != 0 && req.DependsOnID != current.DependsOnID {
+		return nil, fmt.Errorf("%w: depends_on_id can't be changed, delete the dependency and create a new one", ErrInvalidDependency)
+	}
+
+	dependency, err := b.repo.UpdateDependency(ctx, models.Dependency{
+		ID:                id,
+		ServiceID:         serviceID,
+		VersionConstraint: constraint,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDependencyNotFound
+		}
+		return nil, err
+	}
+	return dependency, nil
+}
+
+// DeleteDependency removes a dependency of a service
+func (b *dependencyBusinessImpl) DeleteDependency(ctx context.Context, serviceID, id uint) error {
+	err := b.repo.DeleteDependency(ctx, serviceID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrDependencyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetGraph returns the dependency graph of all services
+func (b *dependencyBusinessImpl) GetGraph(ctx context.Context) (*models.Graph, error) {
+	nodes, err := b.repo.ListGraphNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := b.repo.ListAllDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]models.GraphEdge, len(dependencies))
+	for i, dependency := range dependencies {
+		edges[i] = models.GraphEdge{
+			From:              dependency.ServiceID,
+			To:                dependency.DependsOnID,
+			VersionConstraint: dependency.VersionConstraint,
+		}
+	}
+	return &models.Graph{Nodes: nodes, Edges: edges}, nil
+}
+
+func (b *dependencyBusinessImpl) requireService(ctx context.Context, id uint) error {
+	if _, err := b.services.GetService(ctx, id, models.ServiceExpand{}); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrServiceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// findPath returns the services on a dependency path from one service to
+// another, both included, or nil if there is none
+func findPath(edges []models.Dependency, from, to uint) []uint {
+	adjacent := make(map[uint][]uint)
+	for _, edge := range edges {
+		adjacent[edge.ServiceID] = append(adjacent[edge.ServiceID], edge.DependsOnID)
+	}
+
+	// Breadth-first search so the shortest cycle is reported
+	previous := map[uint]uint{from: from}
+	queue := []uint{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			path := []uint{to}
+			for current != from {
+				current = previous[current]
+				path = append([]uint{current}, path...)
+			}
+			return path
+		}
+		for _, next := range adjacent[current] {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+func formatPath(path []uint) string {
+	parts := make([]string, len(path))
+	for i, id := range path {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, " -> ")
+}
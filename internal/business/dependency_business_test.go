@@ -0,0 +1,216 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// memoryDependencyRepo is an in-memory DependencyRepository over a fixed set of services
+type memoryDependencyRepo struct {
+	names        map[uint]string
+	dependencies []models.Dependency
+	nextID       uint
+}
+
+func newMemoryDependencyRepo(names map[uint]string) *memoryDependencyRepo {
+	return &memoryDependencyRepo{names: names, nextID: 1}
+}
+
+func (m *memoryDependencyRepo) add(from, to uint) {
+	m.dependencies = append(m.dependencies, models.Dependency{ID: m.nextID, ServiceID: from, DependsOnID: to})
+	m.nextID++
+}
+
+func (m *memoryDependencyRepo) named(d models.Dependency) models.Dependency {
+	d.ServiceName, d.DependsOnName = m.names[d.ServiceID], m.names[d.DependsOnID]
+	return d
+}
+
+func (m *memoryDependencyRepo) ListDependencies(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+	result := make([]models.Dependency, 0)
+	for _, d := range m.dependencies {
+		if d.ServiceID == serviceID {
+			result = append(result, m.named(d))
+		}
+	}
+	return result, nil
+}
+func (m *memoryDependencyRepo) ListDependents(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+	result := make([]models.Dependency, 0)
+	for _, d := range m.dependencies {
+		if d.DependsOnID == serviceID {
+			result = append(result, m.named(d))
+		}
+	}
+	return result, nil
+}
+func (m *memoryDependencyRepo) ListAllDependencies(ctx context.Context) ([]models.Dependency, error) {
+	return m.dependencies, nil
+}
+func (m *memoryDependencyRepo) GetDependency(ctx context.Context, serviceID, id uint) (*models.Dependency, error) {
+	for _, d := range m.dependencies {
+		if d.ServiceID == serviceID && d.ID == id {
+			named := m.named(d)
+			return &named, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memoryDependencyRepo) LockDependencies(ctx context.Context) error {
+	return nil
+}
+func (m *memoryDependencyRepo) CreateDependency(ctx context.Context, dependency models.Dependency, check repository.EdgeCheck) (*models.Dependency, error) {
+	if _, ok := m.names[dependency.ServiceID]; !ok {
+		return nil, repository.ErrNotFound
+	}
+	if _, ok := m.names[dependency.DependsOnID]; !ok {
+		return nil, repository.ErrNotFound
+	}
+	if err := check(m.dependencies); err != nil {
+		return nil, err
+	}
+	dependency.ID = m.nextID
+	m.nextID++
+	m.dependencies = append(m.dependencies, dependency)
+	named := m.named(dependency)
+	return &named, nil
+}
+func (m *memoryDependencyRepo) UpdateDependency(ctx context.Context, dependency models.Dependency) (*models.Dependency, error) {
+	for i, d := range m.dependencies {
+		if d.ServiceID == dependency.ServiceID && d.ID == dependency.ID {
+			m.dependencies[i].VersionConstraint = dependency.VersionConstraint
+			named := m.named(m.dependencies[i])
+			return &named, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memoryDependencyRepo) DeleteDependency(ctx context.Context, serviceID, id uint) error {
+	for i, d := range m.dependencies {
+		if d.ServiceID == serviceID && d.ID == id {
+			m.dependencies = append(m.dependencies[:i], m.dependencies[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+func (m *memoryDependencyRepo) ListGraphNodes(ctx context.Context) ([]models.GraphNode, error) {
+	nodes := make([]models.GraphNode, 0)
+	for id := uint(1); id <= uint(len(m.names)); id++ {
+		nodes = append(nodes, models.GraphNode{ID: id, Name: m.names[id]})
+	}
+	return nodes, nil
+}
+
+// newDependencyBusiness returns a dependency business over services 1..n
+func newDependencyBusiness(names map[uint]string) (DependencyBusiness, *memoryDependencyRepo) {
+	deps := newMemoryDependencyRepo(names)
+	services := &mockRepo{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			if name, ok := names[id]; ok {
+				return &models.Service{ID: id, Name: name}, nil
+			}
+			return nil, repository.ErrNotFound
+		},
+	}
+	return NewDependencyBusiness(deps, services), deps
+}
+
+func TestCreateDependency(t *testing.T) {
+	b, _ := newDependencyBusiness(map[uint]string{1: "orders", 2: "users"})
+
+	dep, err := b.CreateDependency(context.Background(), 1, models.DependencyRequest{DependsOnID: 2, VersionConstraint: ">=1.2.0,<2.0.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dep.VersionConstraint != ">=1.2.0, <2.0.0" || dep.DependsOnName != "users" {
+		t.Errorf("unexpected dependency: %+v", dep)
+	}
+
+	_, err = b.CreateDependency(context.Background(), 1, models.DependencyRequest{DependsOnID: 2})
+	if !errors.Is(err, ErrDependencyExists) {
+		t.Errorf("expected ErrDependencyExists, got %v", err)
+	}
+}
+
+func TestCreateDependency_Validation(t *testing.T) {
+	b, _ := newDependencyBusiness(map[uint]string{1: "orders", 2: "users"})
+
+	tests := []struct {
+		name      string
+		serviceID uint
+		req       models.DependencyRequest
+		wantErr   error
+	}{
+		{"missing target", 1, models.DependencyRequest{}, ErrInvalidDependency},
+		{"unknown target", 1, models.DependencyRequest{DependsOnID: 9}, ErrInvalidDependency},
+		{"unknown service", 9, models.DependencyRequest{DependsOnID: 1}, ErrServiceNotFound},
+		{"malformed constraint", 1, models.DependencyRequest{DependsOnID: 2, VersionConstraint: "latest"}, ErrInvalidDependency},
+		{"self dependency", 1, models.DependencyRequest{DependsOnID: 1}, ErrDependencyCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := b.CreateDependency(context.Background(), tt.serviceID, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateDependency_RejectsCycle(t *testing.T) {
+	b, deps := newDependencyBusiness(map[uint]string{1: "gateway", 2: "orders", 3: "users"})
+	deps.add(1, 2)
+	deps.add(2, 3)
+
+	_, err := b.CreateDependency(context.Background(), 3, models.DependencyRequest{DependsOnID: 1})
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "3 -> 1 -> 2 -> 3") {
+		t.Errorf("expected the cycle path in the error, got %v", err)
+	}
+
+	// A diamond is not a cycle
+	if _, err := b.CreateDependency(context.Background(), 1, models.DependencyRequest{DependsOnID: 3}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateDependency(t *testing.T) {
+	b, deps := newDependencyBusiness(map[uint]string{1: "orders", 2: "users", 3: "billing"})
+	deps.add(1, 2)
+
+	dep, err := b.UpdateDependency(context.Background(), 1, 1, models.DependencyRequest{VersionConstraint: "^2.0"})
+	if err != nil || dep.VersionConstraint != "^2.0" {
+		t.Errorf("unexpected result: %+v, %v", dep, err)
+	}
+
+	_, err = b.UpdateDependency(context.Background(), 1, 1, models.DependencyRequest{DependsOnID: 3})
+	if !errors.Is(err, ErrInvalidDependency) {
+		t.Errorf("expected ErrInvalidDependency, got %v", err)
+	}
+
+	_, err = b.UpdateDependency(context.Background(), 2, 1, models.DependencyRequest{})
+	if !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("expected ErrDependencyNotFound, got %v", err)
+	}
+}
+
+func TestGetGraph(t *testing.T) {
+	b, deps := newDependencyBusiness(map[uint]string{1: "orders", 2: "users"})
+	deps.add(1, 2)
+
+	graph, err := b.GetGraph(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(graph.Nodes) != 2 || len(graph.Edges) != 1 || graph.Edges[0].From != 1 || graph.Edges[0].To != 2 {
+		t.Errorf("unexpected graph: %+v", graph)
+	}
+}
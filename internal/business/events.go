@@ -0,0 +1,107 @@
+package business
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"services-api/internal/labels"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// recordEvent appends an event about data, the changed service or version,
+// to outbox. It is called in the transaction of the change, so the event is
+// stored if and only if the change is committed.
+func recordEvent(ctx context.Context, outbox repository.OutboxRepository, eventType string, serviceID uint, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	now := time.Now().UTC()
+	return outbox.AppendEvents(ctx, []models.OutboxEvent{{
+		EventID:     newEventID(),
+		Type:        eventType,
+		ServiceID:   serviceID,
+		Data:        string(payload),
+		OccurredAt:  now,
+		AvailableAt: now,
+	}})
+}
+
+func newEventID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		// crypto/rand doesn't fail on supported platforms
+		panic(err)
+	}
+	return "evt_" + hex.EncodeToString(id)
+}
+
+// EventFilter selects the events of a stream by service, type and the labels
+// of their service. The labels of the services are cached for the selector
+// and kept up to date from the service events of the stream, so a filter
+// follows a single stream in order.
+type EventFilter struct {
+	services   BusinessService
+	serviceIDs []uint
+	types      []string
+	selector   labels.Selector
+	labels     map[uint]map[string]string
+}
+
+// NewEventFilter creates a filter passing the events of serviceIDs, of types
+// and of services matching selector; empty criteria pass every event.
+// Services are looked up with services to match their labels.
+func NewEventFilter(services BusinessService, serviceIDs []uint, types []string, selector labels.Selector) *EventFilter {
+	return &EventFilter{
+		services:   services,
+		serviceIDs: serviceIDs,
+		types:      types,
+		selector:   selector,
+		labels:     make(map[uint]map[string]string),
+	}
+}
+
+// Matches reports whether event passes the filter
+func (f *EventFilter) Matches(ctx context.Context, event models.OutboxEvent) bool {
+	if len(f.serviceIDs) > 0 && !slices.Contains(f.serviceIDs, event.ServiceID) {
+		return false
+	}
+	if len(f.types) > 0 && !slices.Contains(f.types, event.Type) {
+		return false
+	}
+	if len(f.selector) == 0 {
+		return true
+	}
+
+	// Service events carry the labels, before the change for deletions
+	if strings.HasPrefix(event.Type, "service.") {
+		var service models.Service
+		if err := json.Unmarshal([]byte(event.Data), &service); err == nil {
+			f.labels[event.ServiceID] = service.Labels
+		}
+	}
+	set, ok := f.labels[event.ServiceID]
+	if !ok {
+		service, err := f.services.GetService(ctx, event.ServiceID, models.ServiceExpand{})
+		if err != nil && !errors.Is(err, ErrServiceNotFound) {
+			return false
+		}
+		if service != nil {
+			set = service.Labels
+		}
+		f.labels[event.ServiceID] = set
+	}
+	matched := f.selector.Matches(set)
+	if event.Type == models.EventServiceDeleted {
+		delete(f.labels, event.ServiceID)
+	}
+	return matched
+}
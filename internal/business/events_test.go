@@ -0,0 +1,244 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"services-api/internal/labels"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// memoryOutbox records the events appended to it. IDs aren't reused after
+// a rollback, like those of a database sequence.
+type memoryOutbox struct {
+	events        []models.OutboxEvent
+	lastID        uint64
+	prunedThrough uint64
+}
+
+func (o *memoryOutbox) AppendEvents(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		o.lastID++
+		event.ID = o.lastID
+		o.events = append(o.events, event)
+	}
+	return nil
+}
+func (o *memoryOutbox) ListEventsAfter(ctx context.Context, after uint64, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	for _, event := range o.events {
+		if event.ID > after && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+func (o *memoryOutbox) LatestEventID(ctx context.Context) (uint64, error) {
+	panic("not used")
+}
+func (o *memoryOutbox) ProcessPending(ctx context.Context, now time.Time, limit int, fn func(events []models.OutboxEvent)) (int, error) {
+	panic("not used")
+}
+func (o *memoryOutbox) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	panic("not used")
+}
+func (o *memoryOutbox) PrunedThrough(ctx context.Context) (uint64, error) {
+	return o.prunedThrough, nil
+}
+
+// types returns the type of every recorded event
+func (o *memoryOutbox) types() []string {
+	var types []string
+	for _, event := range o.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// serviceUnitOfWork returns a unit of work over services, no dependencies
+// and a memory outbox
+func serviceUnitOfWork(services repository.ServiceRepository) *memoryUnitOfWork {
+	return &memoryUnitOfWork{repos: repository.Repositories{Services: services, Dependencies: newMemoryDependencyRepo(nil), Outbox: &memoryOutbox{}}}
+}
+
+// versionUnitOfWork returns a unit of work over versions and a memory outbox
+func versionUnitOfWork(versions repository.VersionRepository) *memoryUnitOfWork {
+	return &memoryUnitOfWork{repos: repository.Repositories{Versions: versions, Outbox: &memoryOutbox{}}}
+}
+
+func TestServiceEvents(t *testing.T) {
+	repo := &mockRepo{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			return &models.Service{ID: id, Name: "users"}, nil
+		},
+		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			service.ID = 7
+			return &service, nil
+		},
+		UpdateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			return &service, nil
+		},
+		DeleteServiceFn: func(ctx context.Context, id uint) error {
+			return nil
+		},
+	}
+	uow := serviceUnitOfWork(repo)
+	outbox := uow.repos.Outbox.(*memoryOutbox)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), uow)
+
+	if _, err := bs.CreateService(context.Background(), models.Service{Name: "users"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bs.UpdateService(context.Background(), models.Service{ID: 7, Description: "Users"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bs.DeleteService(context.Background(), 7, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{models.EventServiceCreated, models.EventServiceUpdated, models.EventServiceDeleted}
+	if !reflect.DeepEqual(outbox.types(), want) {
+		t.Fatalf("got events %v, want %v", outbox.types(), want)
+	}
+	if uow.runs != 3 {
+		t.Errorf("expected every change in its own transaction, got %d", uow.runs)
+	}
+	var deleted models.Service
+	if err := json.Unmarshal([]byte(outbox.events[2].Data), &deleted); err != nil || deleted.Name != "users" {
+		t.Errorf("expected the deleted service in the event data, got %s", outbox.events[2].Data)
+	}
+	for _, event := range outbox.events {
+		if event.ServiceID != 7 || event.EventID == "" || event.OccurredAt.IsZero() || event.AvailableAt.IsZero() || event.PublishedAt != nil {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	}
+}
+
+func TestServiceEvents_RolledBack(t *testing.T) {
+	repo := &mockRepo{
+		UpdateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			return nil, repository.ErrNotFound
+		},
+	}
+	uow := serviceUnitOfWork(repo)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), uow)
+
+	if _, err := bs.UpdateService(context.Background(), models.Service{ID: 7, Description: "Users"}); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+	if events := uow.repos.Outbox.(*memoryOutbox).events; len(events) != 0 {
+		t.Errorf("expected no events for a failed change, got %+v", events)
+	}
+}
+
+func TestVersionEvents(t *testing.T) {
+	uow := versionUnitOfWork(&mockVersionRepository{})
+	outbox := uow.repos.Outbox.(*memoryOutbox)
+	vb := NewVersionBusiness(&mockVersionRepository{}, uow)
+
+	if _, err := vb.CreateVersion(context.Background(), models.Version{ServiceID: 1, Version: "1.0.0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := vb.DeleteVersion(context.Background(), 1, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{models.EventVersionCreated, models.EventVersionDeleted}
+	if !reflect.DeepEqual(outbox.types(), want) {
+		t.Errorf("got events %v, want %v", outbox.types(), want)
+	}
+}
+
+func TestBatchEvents(t *testing.T) {
+	var created []string
+	b, uow := newBatchBusiness(&created)
+	outbox := uow.repos.Outbox.(*memoryOutbox)
+
+	// Nothing is recorded for an atomic batch that is rolled back
+	_, err := b.BatchServices(context.Background(), models.ServiceBatchRequest{Operations: []models.ServiceBatchOperation{
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "payments"}},
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "broken"}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outbox.events) != 0 {
+		t.Errorf("expected no events for a rolled back batch, got %v", outbox.types())
+	}
+
+	_, err = b.BatchServices(context.Background(), models.ServiceBatchRequest{Mode: models.BatchBestEffort, Operations: []models.ServiceBatchOperation{
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "billing"}},
+		{Op: models.BatchCreate, Service: models.ServiceRequest{Name: "broken"}},
+		{Op: models.BatchDelete, ID: 1, Force: true},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{models.EventServiceCreated, models.EventServiceDeleted}
+	if !reflect.DeepEqual(outbox.types(), want) {
+		t.Errorf("got events %v, want %v", outbox.types(), want)
+	}
+}
+
+func TestImportEvents(t *testing.T) {
+	var changes []string
+	b, versions, uow := newCatalogBusiness(&changes)
+	outbox := uow.repos.Outbox.(*memoryOutbox)
+
+	catalog := models.Catalog{Services: []models.CatalogService{
+		{Name: "payments", Versions: []models.CatalogVersion{{Version: "1.1.0"}}},
+		{Name: "billing", Versions: []models.CatalogVersion{{Version: "1.0.0"}}},
+	}}
+	if _, err := b.Import(context.Background(), catalog, models.ImportOptions{Mode: models.ImportReplace, DryRun: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outbox.events) != 0 {
+		t.Errorf("expected no events for a dry run, got %v", outbox.types())
+	}
+
+	if _, err := b.Import(context.Background(), catalog, models.ImportOptions{Mode: models.ImportReplace}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		models.EventVersionDeleted,                             // payments 1.0.0
+		models.EventServiceCreated, models.EventVersionCreated, // billing
+		models.EventServiceDeleted, // users
+	}
+	if !reflect.DeepEqual(outbox.types(), want) {
+		t.Errorf("got events %v, want %v (changes %v %v)", outbox.types(), want, changes, versions.changes)
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	selector, err := labels.Parse("tier=critical")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The service events carry the labels, so no service is looked up
+	filter := NewEventFilter(nil, nil, []string{models.EventServiceCreated, models.EventVersionCreated, models.EventServiceDeleted}, selector)
+
+	events := []struct {
+		event models.OutboxEvent
+		want  bool
+	}{
+		{models.OutboxEvent{Type: models.EventServiceCreated, ServiceID: 1, Data: `{"id":1,"labels":{"tier":"critical"}}`}, true},
+		{models.OutboxEvent{Type: models.EventServiceCreated, ServiceID: 2, Data: `{"id":2,"labels":{"tier":"low"}}`}, false},
+		{models.OutboxEvent{Type: models.EventVersionCreated, ServiceID: 1, Data: `{"id":5}`}, true},
+		{models.OutboxEvent{Type: models.EventVersionCreated, ServiceID: 2, Data: `{"id":6}`}, false},
+		{models.OutboxEvent{Type: models.EventVersionUpdated, ServiceID: 1, Data: `{"id":5}`}, false},
+		{models.OutboxEvent{Type: models.EventServiceDeleted, ServiceID: 1, Data: `{"id":1,"labels":{"tier":"critical"}}`}, true},
+	}
+	for i, tc := range events {
+		if got := filter.Matches(context.Background(), tc.event); got != tc.want {
+			t.Errorf("event %d (%s of service %d): got %v, want %v", i, tc.event.Type, tc.event.ServiceID, got, tc.want)
+		}
+	}
+	if _, ok := filter.labels[1]; ok {
+		t.Errorf("expected the labels of the deleted service to be forgotten")
+	}
+}
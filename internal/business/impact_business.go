@@ -0,0 +1,297 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// ImpactBusiness interface defines impact analysis operations. They answer
+// which services are affected when a service or version goes away, without
+// changing anything.
+type ImpactBusiness interface {
+	// ServiceImpact returns every service that transitively depends on a service
+	// Returns ErrServiceNotFound if the service doesn't exist.
+	ServiceImpact(ctx context.Context, serviceID uint) (*models.ImpactReport, error)
+
+	// VersionImpact returns every service affected by retiring a version: the
+	// direct dependents whose version constraint it satisfies and everything
+	// that transitively depends on them.
+	// Returns ErrServiceNotFound or ErrVersionNotFound.
+	VersionImpact(ctx context.Context, serviceID, versionID uint) (*models.ImpactReport, error)
+
+	// PlanServiceDeletion reports what deleting a service would remove
+	// Returns ErrServiceNotFound if the service doesn't exist.
+	PlanServiceDeletion(ctx context.Context, serviceID uint) (*models.DeletionReport, error)
+
+	// PlanVersionDeletion reports what deleting a version would remove or break
+	// Returns ErrServiceNotFound or ErrVersionNotFound.
+	PlanVersionDeletion(ctx context.Context, serviceID, versionID uint) (*models.DeletionReport, error)
+}
+
+type impactBusinessImpl struct {
+	services     repository.ServiceRepository
+	dependencies repository.DependencyRepository
+	apiKeys      repository.APIKeyRepository
+	now          func() time.Time
+}
+
+// NewImpactBusiness creates a new business logic implementation
+// with the provided repositories.
+func NewImpactBusiness(services repository.ServiceRepository, dependencies repository.DependencyRepository, apiKeys repository.APIKeyRepository) ImpactBusiness {
+	return &impactBusinessImpl{
+		services:     services,
+		dependencies: dependencies,
+		apiKeys:      apiKeys,
+		now:          time.Now,
+	}
+}
+
+// ServiceImpact returns every service that transitively depends on a service
+func (b *impactBusinessImpl) ServiceImpact(ctx context.Context, serviceID uint) (*models.ImpactReport, error) {
+	service, err := b.getService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := b.loadGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	direct := make([]models.ImpactedService, 0)
+	for _, edge := range graph.dependents[serviceID] {
+		direct = append(direct, graph.impacted(edge, serviceID))
+	}
+	return graph.report(service, nil, direct), nil
+}
+
+// VersionImpact returns every service affected by retiring a version
+func (b *impactBusinessImpl) VersionImpact(ctx context.Context, serviceID, versionID uint) (*models.ImpactReport, error) {
+	service, err := b.getService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := findVersion(service, versionID)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := b.loadGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	direct := make([]models.ImpactedService, 0)
+	for _, edge := range graph.dependents[serviceID] {
+		if !matchesConstraint(edge.VersionConstraint, version.Version) {
+			continue
+		}
+		impacted := graph.impacted(edge, serviceID)
+		impacted.HasAlternative = hasAlternative(service, version, edge.VersionConstraint)
+		direct = append(direct, impacted)
+	}
+	return graph.report(service, version, direct), nil
+}
+
+// PlanServiceDeletion reports what deleting a service would remove
+func (b *impactBusinessImpl) PlanServiceDeletion(ctx context.Context, serviceID uint) (*models.DeletionReport, error) {
+	service, err := b.getService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := b.dependencies.ListDependents(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := b.dependencies.ListDependencies(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	apiKeys, err := b.scopedAPIKeys(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DeletionReport{
+		DryRun:       true,
+		Service:      models.GraphNode{ID: service.ID, Name: service.Name},
+		Versions:     service.Versions,
+		Dependents:   dependents,
+		Dependencies: dependencies,
+		APIKeys:      apiKeys,
+		Blocked:      len(dependents) > 0,
+	}, nil
+}
+
+// PlanVersionDeletion reports what deleting a version would remove or break.
+// Dependents are reported when the version satisfies their constraint and
+// no other active version does.
+func (b *impactBusinessImpl) PlanVersionDeletion(ctx context.Context, serviceID, versionID uint) (*models.DeletionReport, error) {
+	service, err := b.getService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := findVersion(service, versionID)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := b.dependencies.ListDependents(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	apiKeys, err := b.scopedAPIKeys(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	broken := make([]models.Dependency, 0)
+	for _, dependent := range dependents {
+		if matchesConstraint(dependent.VersionConstraint, version.Version) && !hasAlternative(service, version, dependent.VersionConstraint) {
+			broken = append(broken, dependent)
+		}
+	}
+
+	return &models.DeletionReport{
+		DryRun:     true,
+		Service:    models.GraphNode{ID: service.ID, Name: service.Name},
+		Version:    version,
+		Dependents: broken,
+		APIKeys:    apiKeys,
+	}, nil
+}
+
+func (b *impactBusinessImpl) getService(ctx context.Context, id uint) (*models.Service, error) {
+	service, err := b.services.GetService(ctx, id, models.ServiceExpand{Versions: true})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+	return service, nil
+}
+
+// scopedAPIKeys returns the active API keys restricted to a service
+func (b *impactBusinessImpl) scopedAPIKeys(ctx context.Context, serviceID uint) ([]models.APIKey, error) {
+	keys, err := b.apiKeys.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := b.now()
+	scoped := make([]models.APIKey, 0)
+	for _, key := range keys {
+		active := key.RevokedAt == nil && (key.ExpiresAt == nil || now.Before(*key.ExpiresAt))
+		if active && slices.Contains(key.ServiceIDs, serviceID) {
+			scoped = append(scoped, key)
+		}
+	}
+	return scoped, nil
+}
+
+func (b *impactBusinessImpl) loadGraph(ctx context.Context) (*impactGraph, error) {
+	nodes, err := b.dependencies.ListGraphNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := b.dependencies.ListAllDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &impactGraph{
+		names:      make(map[uint]string, len(nodes)),
+		dependents: make(map[uint][]models.Dependency),
+	}
+	for _, node := range nodes {
+		graph.names[node.ID] = node.Name
+	}
+	for _, edge := range edges {
+		graph.dependents[edge.DependsOnID] = append(graph.dependents[edge.DependsOnID], edge)
+	}
+	return graph, nil
+}
+
+func findVersion(service *models.Service, versionID uint) (*models.Version, error) {
+	for i := range service.Versions {
+		if service.Versions[i].ID == versionID {
+			return &service.Versions[i], nil
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+// hasAlternative reports whether another active version of the service
+// satisfies the constraint
+func hasAlternative(service *models.Service, retired *models.Version, constraint string) bool {
+	for _, version := range service.Versions {
+		if version.ID != retired.ID && version.IsActive && matchesConstraint(constraint, version.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// impactGraph is the dependency graph indexed by the service depended on
+type impactGraph struct {
+	names      map[uint]string
+	dependents map[uint][]models.Dependency
+}
+
+func (g *impactGraph) node(id uint) models.GraphNode {
+	return models.GraphNode{ID: id, Name: g.names[id]}
+}
+
+// impacted describes the direct dependent of an edge on the target
+func (g *impactGraph) impacted(edge models.Dependency, target uint) models.ImpactedService {
+	return models.ImpactedService{
+		ID:                edge.ServiceID,
+		Name:              g.names[edge.ServiceID],
+		Depth:             1,
+		Path:              []models.GraphNode{g.node(edge.ServiceID), g.node(target)},
+		VersionConstraint: edge.VersionConstraint,
+	}
+}
+
+// report walks the dependents of the direct dependents breadth first, so
+// every service is reported once with its shortest path
+func (g *impactGraph) report(service *models.Service, version *models.Version, direct []models.ImpactedService) *models.ImpactReport {
+	report := &models.ImpactReport{
+		Service:  models.GraphNode{ID: service.ID, Name: service.Name},
+		Version:  version,
+		Affected: make([]models.ImpactedService, 0),
+	}
+
+	seen := map[uint]bool{service.ID: true}
+	queue := make([]models.ImpactedService, 0, len(direct))
+	for _, impacted := range direct {
+		if !seen[impacted.ID] {
+			seen[impacted.ID] = true
+			queue = append(queue, impacted)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		report.Affected = append(report.Affected, current)
+		report.MaxDepth = max(report.MaxDepth, current.Depth)
+
+		for _, edge := range g.dependents[current.ID] {
+			if seen[edge.ServiceID] {
+				continue
+			}
+			seen[edge.ServiceID] = true
+			queue = append(queue, models.ImpactedService{
+				ID:    edge.ServiceID,
+				Name:  g.names[edge.ServiceID],
+				Depth: current.Depth + 1,
+				Path:  append([]models.GraphNode{g.node(edge.ServiceID)}, current.Path...),
+			})
+		}
+	}
+	return report
+}
@@ -0,0 +1,125 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// newImpactBusiness builds a catalog of users <- orders <- checkout <- storefront
+// and billing <- orders, where users has versions 1.0.0 (id 1) and 2.0.0 (id 2)
+func newImpactBusiness(t *testing.T) (ImpactBusiness, *memoryAPIKeyRepo) {
+	t.Helper()
+	names := map[uint]string{1: "users", 2: "orders", 3: "checkout", 4: "storefront", 5: "billing"}
+	deps := newMemoryDependencyRepo(names)
+	deps.dependencies = []models.Dependency{
+		{ID: 1, ServiceID: 2, DependsOnID: 1, VersionConstraint: "^1.0"},
+		{ID: 2, ServiceID: 3, DependsOnID: 2},
+		{ID: 3, ServiceID: 4, DependsOnID: 3},
+		{ID: 4, ServiceID: 5, DependsOnID: 1, VersionConstraint: ">=2.0.0"},
+	}
+	services := &mockRepo{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			name, ok := names[id]
+			if !ok {
+				return nil, repository.ErrNotFound
+			}
+			service := &models.Service{ID: id, Name: name}
+			if id == 1 {
+				service.Versions = []models.Version{
+					{ID: 1, ServiceID: 1, Version: "1.0.0", IsActive: true},
+					{ID: 2, ServiceID: 1, Version: "2.0.0", IsActive: true},
+				}
+			}
+			return service, nil
+		},
+	}
+	apiKeys := newMemoryAPIKeyRepo()
+	return NewImpactBusiness(services, deps, apiKeys), apiKeys
+}
+
+func TestServiceImpact(t *testing.T) {
+	b, _ := newImpactBusiness(t)
+
+	report, err := b.ServiceImpact(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Affected) != 4 || report.MaxDepth != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	storefront := report.Affected[3]
+	if storefront.Name != "storefront" || storefront.Depth != 3 || len(storefront.Path) != 4 {
+		t.Errorf("unexpected entry: %+v", storefront)
+	}
+	if storefront.Path[0].Name != "storefront" || storefront.Path[3].Name != "users" {
+		t.Errorf("expected the path from storefront to users, got %+v", storefront.Path)
+	}
+
+	if _, err := b.ServiceImpact(context.Background(), 9); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("expected ErrServiceNotFound, got %v", err)
+	}
+}
+
+func TestVersionImpact(t *testing.T) {
+	b, _ := newImpactBusiness(t)
+
+	// Only orders accepts 1.x, so billing is not affected
+	report, err := b.VersionImpact(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Affected) != 3 || report.Affected[0].Name != "orders" || report.Affected[0].HasAlternative {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	for _, affected := range report.Affected {
+		if affected.Name == "billing" {
+			t.Error("billing doesn't accept 1.0.0 and must not be affected")
+		}
+	}
+
+	if _, err := b.VersionImpact(context.Background(), 1, 9); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestPlanServiceDeletion(t *testing.T) {
+	b, apiKeys := newImpactBusiness(t)
+	expired := time.Now().Add(-time.Hour)
+	apiKeys.CreateAPIKey(context.Background(), models.APIKey{Name: "users-ci", ServiceIDs: []uint{1}})
+	apiKeys.CreateAPIKey(context.Background(), models.APIKey{Name: "old", ServiceIDs: []uint{1}, ExpiresAt: &expired})
+	apiKeys.CreateAPIKey(context.Background(), models.APIKey{Name: "orders-ci", ServiceIDs: []uint{2}})
+
+	report, err := b.PlanServiceDeletion(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.DryRun || !report.Blocked || len(report.Versions) != 2 || len(report.Dependents) != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.APIKeys) != 1 || report.APIKeys[0].Name != "users-ci" {
+		t.Errorf("expected only the active key scoped to users, got %+v", report.APIKeys)
+	}
+
+	report, err = b.PlanServiceDeletion(context.Background(), 4)
+	if err != nil || report.Blocked {
+		t.Errorf("storefront has no dependents and must not be blocked: %+v, %v", report, err)
+	}
+}
+
+func TestPlanVersionDeletion(t *testing.T) {
+	b, _ := newImpactBusiness(t)
+
+	report, err := b.PlanVersionDeletion(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Version.Version != "2.0.0" || len(report.Dependents) != 1 || report.Dependents[0].ServiceName != "billing" {
+		t.Errorf("expected billing to lose its only matching version, got %+v", report)
+	}
+}
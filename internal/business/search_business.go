@@ -0,0 +1,80 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// ErrInvalidSearch is returned when a search query fails validation
+var ErrInvalidSearch = errors.New("invalid search")
+
+// maxSearchQueryLength bounds the query text passed to the database
+const maxSearchQueryLength = 256
+
+var searchTypes = []string{models.SearchTypeService, models.SearchTypeVersion, models.SearchTypeLabel}
+
+// SearchBusiness interface defines full-text search business logic operations
+type SearchBusiness interface {
+	// Search returns ranked, highlighted hits across services, versions and labels
+	// Returns ErrInvalidSearch if the query is empty, too long or names an unknown type.
+	Search(ctx context.Context, query models.SearchQuery) (*models.SearchResponse, error)
+}
+
+type searchBusinessImpl struct {
+	repo repository.SearchRepository
+}
+
+// NewSearchBusiness creates a new business logic implementation
+// with the provided search repository.
+func NewSearchBusiness(repo repository.SearchRepository) SearchBusiness {
+	return &searchBusinessImpl{repo: repo}
+}
+
+// Search returns a page of hits with facets and pagination details
+func (b *searchBusinessImpl) Search(ctx context.Context, query models.SearchQuery) (*models.SearchResponse, error) {
+	query.Query = strings.TrimSpace(query.Query)
+	if query.Query == "" {
+		return nil, fmt.Errorf("%w: q is required", ErrInvalidSearch)
+	}
+	if len(query.Query) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: q must be at most %d characters", ErrInvalidSearch, maxSearchQueryLength)
+	}
+	for _, t := range query.Types {
+		if !slices.Contains(searchTypes, t) {
+			return nil, fmt.Errorf("%w: type must be one of %s", ErrInvalidSearch, strings.Join(searchTypes, ", "))
+		}
+	}
+	if len(query.Types) == 0 {
+		query.Types = searchTypes
+	}
+
+	hits, facets, err := b.repo.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, facet := range facets {
+		if slices.Contains(query.Types, facet.Type) {
+			total += facet.Count
+		}
+	}
+
+	pagination := models.Pagination{
+		CurrentPage:  query.Page,
+		ItemsPerPage: query.Limit,
+	}
+	setTotal(&pagination, total)
+
+	return &models.SearchResponse{
+		Hits:       hits,
+		Facets:     facets,
+		Pagination: pagination,
+	}, nil
+}
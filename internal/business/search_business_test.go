@@ -0,0 +1,63 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"services-api/internal/models"
+)
+
+type mockSearchRepo struct {
+	query models.SearchQuery
+}
+
+func (m *mockSearchRepo) Search(ctx context.Context, query models.SearchQuery) ([]models.SearchHit, []models.SearchFacet, error) {
+	m.query = query
+	hits := []models.SearchHit{{Type: models.SearchTypeService, ID: 1, Title: "payments"}}
+	facets := []models.SearchFacet{
+		{Type: models.SearchTypeLabel, Count: 4},
+		{Type: models.SearchTypeService, Count: 3},
+		{Type: models.SearchTypeVersion, Count: 12},
+	}
+	return hits, facets, nil
+}
+
+func TestSearch(t *testing.T) {
+	repo := &mockSearchRepo{}
+	b := NewSearchBusiness(repo)
+
+	resp, err := b.Search(context.Background(), models.SearchQuery{Query: "  payment gateway ", Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.query.Query != "payment gateway" || len(repo.query.Types) != 3 {
+		t.Errorf("unexpected repository query: %+v", repo.query)
+	}
+	if *resp.Pagination.TotalItems != 19 || *resp.Pagination.TotalPages != 2 || len(resp.Facets) != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	resp, err = b.Search(context.Background(), models.SearchQuery{Query: "payment", Types: []string{"service", "label"}, Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(repo.query.Types, []string{"service", "label"}) || *resp.Pagination.TotalItems != 7 {
+		t.Errorf("expected the total of the selected types, got %+v", resp.Pagination)
+	}
+}
+
+func TestSearch_Invalid(t *testing.T) {
+	b := NewSearchBusiness(&mockSearchRepo{})
+	for _, query := range []models.SearchQuery{
+		{Query: "   "},
+		{Query: strings.Repeat("x", maxSearchQueryLength+1)},
+		{Query: "payment", Types: []string{"team"}},
+	} {
+		if _, err := b.Search(context.Background(), query); !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("expected ErrInvalidSearch for %+v, got %v", query, err)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package business
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
+	"services-api/internal/labels"
 	"services-api/internal/models"
 	"services-api/internal/repository"
 )
@@ -11,6 +14,15 @@ import (
 var (
 	// ErrServiceNotFound is returned when a requested service doesn't exist
 	ErrServiceNotFound = errors.New("service not found")
+
+	// ErrServiceHasDependents is returned when deleting a service other services depend on
+	ErrServiceHasDependents = errors.New("service has dependents")
+
+	// ErrInvalidService is returned when a service fails validation
+	ErrInvalidService = errors.New("invalid service")
+
+	// ErrInvalidFilter is returned when a service filter fails validation
+	ErrInvalidFilter = errors.New("invalid filter")
 )
 
 // BusinessService interface defines service business logic operations
@@ -20,8 +32,8 @@ type BusinessService interface {
 	ListServices(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error)
 	
 	// GetService retrieves a single service by its ID.
-	// Returns the service with its associated versions or ErrServiceNotFound if not found.
-	GetService(ctx context.Context, id uint) (*models.Service, error)
+	// Returns the service with the related resources selected by expand or ErrServiceNotFound if not found.
+	GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error)
 
 	// CreateService creates a new service
 	// Returns the created service or an error if the service creation fails.
@@ -31,63 +43,164 @@ type BusinessService interface {
 	// Returns the updated service or an error if the service update fails or if the service is not found.
 	UpdateService(ctx context.Context, service models.Service) (*models.Service, error)
 
-	// DeleteService deletes a service and all the versions of the service
-	// Returns an error if the service deletion fails or if the service is not found.
-	DeleteService(ctx context.Context, id uint) error
+	// DeleteService deletes a service, all the versions of the service and its dependencies
+	// Returns ErrServiceHasDependents if other services depend on it, unless force is set,
+	// or an error if the service deletion fails or if the service is not found.
+	DeleteService(ctx context.Context, id uint, force bool) error
+
+	// ListLabelFacets returns the distinct label keys and values with the number of services using them
+	// When key is set only that key is returned.
+	ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error)
 }
 
 type serviceBusinessImpl struct {
-	repo repository.ServiceRepository
+	repo   repository.ServiceRepository
+	fields repository.CustomFieldRepository
+	uow    repository.UnitOfWork
 }
 
 // NewServiceBusiness creates a new business logic implementation
-// with the provided repository.
-func NewServiceBusiness(repo repository.ServiceRepository) BusinessService {
+// with the provided repositories. Changes are written in a transaction
+// of uow together with their events.
+func NewServiceBusiness(repo repository.ServiceRepository, fields repository.CustomFieldRepository, uow repository.UnitOfWork) BusinessService {
 	return &serviceBusinessImpl{
-		repo: repo,
+		repo:   repo,
+		fields: fields,
+		uow:    uow,
 	}
 }
 
-// ListServices returns a paginated list of services
+// ListServices returns a paginated list of services. With a cursor the page
+// is read with keyset conditions and the sort of the cursor is used.
 func (s *serviceBusinessImpl) ListServices(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+	if len(filter.Metadata) > 0 {
+		metadata, err := s.canonicalMetadataFilter(ctx, filter.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		filter.Metadata = metadata
+	}
+
+	if filter.Cursor != "" {
+		sortFields, keyset, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.SortFields, filter.Keyset = sortFields, keyset
+		filter.Page = 0
+	} else {
+		sortFields, err := parseSort(filter.Sort, filter.Order)
+		if err != nil {
+			return nil, err
+		}
+		filter.SortFields = sortFields
+	}
+
 	services, total, err := s.repo.ListServices(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
+	if filter.Expand.Owner {
+		for i := range services {
+			services[i].Owner = models.NewServiceOwner(services[i].Labels, services[i].ManagedBy)
+		}
+	}
 
-	totalPages := (total + filter.Limit - 1) / filter.Limit
+	// The repository returns one row more than requested when there is one,
+	// before the page when reading backward and after it otherwise
+	backward := filter.Keyset != nil && filter.Keyset.Backward
+	more := len(services) > filter.Limit
+	if more && backward {
+		services = services[1:]
+	} else if more {
+		services = services[:filter.Limit]
+	}
+
+	pagination := models.Pagination{
+		CurrentPage:  filter.Page,
+		ItemsPerPage: filter.Limit,
+	}
+	if filter.IncludeTotal {
+		setTotal(&pagination, total)
+	}
+	if len(services) > 0 {
+		hasNext := more || backward
+		hasPrev := (more && backward) || (!backward && (filter.Keyset != nil || filter.Page > 1))
+		if hasNext {
+			pagination.NextCursor = encodeCursor(filter.SortFields, services[len(services)-1], false)
+		}
+		if hasPrev {
+			pagination.PrevCursor = encodeCursor(filter.SortFields, services[0], true)
+		}
+	}
 
 	return &models.ServiceResponse{
-		Services: services,
-		Pagination: models.Pagination{
-			CurrentPage:  filter.Page,
-			TotalPages:   totalPages,
-			TotalItems:   total,
-			ItemsPerPage: filter.Limit,
-		},
+		Services:   services,
+		Pagination: pagination,
 	}, nil
 }
 
 // GetService returns a single service by ID
-func (s *serviceBusinessImpl) GetService(ctx context.Context, id uint) (*models.Service, error) {
-	service, err := s.repo.GetService(ctx, id)
+func (s *serviceBusinessImpl) GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+	service, err := s.repo.GetService(ctx, id, expand)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrServiceNotFound
 		}
 		return nil, err
 	}
+	// The owner is derived from the labels, which are always loaded
+	if expand.Owner {
+		service.Owner = models.NewServiceOwner(service.Labels, service.ManagedBy)
+	}
 	return service, nil
 }
 
 // CreateService creates a new service
 func (s *serviceBusinessImpl) CreateService(ctx context.Context, service models.Service) (*models.Service, error) {
-	return s.repo.CreateService(ctx, service)
+	if err := validateService(service); err != nil {
+		return nil, err
+	}
+	metadata, err := s.validateMetadata(ctx, service.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	service.Metadata = metadata
+	var created *models.Service
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		created, err = repos.Services.CreateService(ctx, service)
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventServiceCreated, created.ID, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
 // UpdateService updates a service
 func (s *serviceBusinessImpl) UpdateService(ctx context.Context, service models.Service) (*models.Service, error) {
-	updatedService, err := s.repo.UpdateService(ctx, service)
+	if err := validateService(service); err != nil {
+		return nil, err
+	}
+	if service.Metadata != nil {
+		metadata, err := s.validateMetadata(ctx, service.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		service.Metadata = metadata
+	}
+	var updatedService *models.Service
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		updatedService, err = repos.Services.UpdateService(ctx, service)
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventServiceUpdated, updatedService.ID, updatedService)
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrServiceNotFound
@@ -98,8 +211,22 @@ func (s *serviceBusinessImpl) UpdateService(ctx context.Context, service models.
 }
 
 // DeleteService deletes a service and all the versions of the service
-func (s *serviceBusinessImpl) DeleteService(ctx context.Context, id uint) error {
-	err := s.repo.DeleteService(ctx, id)
+func (s *serviceBusinessImpl) DeleteService(ctx context.Context, id uint, force bool) error {
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		service, err := repos.Services.GetService(ctx, id, models.ServiceExpand{})
+		if err != nil {
+			return err
+		}
+		if !force {
+			if err := checkDependents(ctx, repos, id, nil); err != nil {
+				return err
+			}
+		}
+		if err := repos.Services.DeleteService(ctx, id); err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventServiceDeleted, service.ID, service)
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrServiceNotFound
@@ -107,4 +234,84 @@ func (s *serviceBusinessImpl) DeleteService(ctx context.Context, id uint) error
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// checkDependents returns ErrServiceHasDependents naming the services that
+// depend on a service, other than the deleted ones. It holds the dependency
+// lock until the transaction of repos ends so that no dependent can be added
+// before the service is deleted.
+func checkDependents(ctx context.Context, repos repository.Repositories, id uint, deleted map[uint]bool) error {
+	if err := repos.Dependencies.LockDependencies(ctx); err != nil {
+		return err
+	}
+	dependents, err := repos.Dependencies.ListDependents(ctx, id)
+	if err != nil {
+		return err
+	}
+	return dependentsError(dependents, deleted)
+}
+
+// dependentsError returns ErrServiceHasDependents naming the dependents
+// other than the deleted ones, or nil if there are none
+func dependentsError(dependents []models.Dependency, deleted map[uint]bool) error {
+	var names []string
+	for _, dependent := range dependents {
+		if !deleted[dependent.ServiceID] {
+			names = append(names, dependent.ServiceName)
+		}
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %s", ErrServiceHasDependents, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// ListLabelFacets returns the distinct label keys and values
+func (s *serviceBusinessImpl) ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error) {
+	return s.repo.ListLabelFacets(ctx, key)
+}
+
+// validateMetadata checks a complete set of custom field values, including
+// that every required field has a value
+func (s *serviceBusinessImpl) validateMetadata(ctx context.Context, metadata map[string]any) (map[string]any, error) {
+	fields, err := s.fields.ListCustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := validateMetadata(fields, metadata, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidService, err)
+	}
+	return canonical, nil
+}
+
+// canonicalMetadataFilter converts custom field filter values to the form
+// they are stored in, so that "1.50" matches a number stored as 1.5
+func (s *serviceBusinessImpl) canonicalMetadataFilter(ctx context.Context, filter map[string]string) (map[string]string, error) {
+	fields, err := s.fields.ListCustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any, len(filter))
+	for name, value := range filter {
+		values[name] = value
+	}
+	canonical, err := validateMetadata(fields, values, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	result := make(map[string]string, len(canonical))
+	for name, value := range canonical {
+		result[name] = fmt.Sprint(value)
+	}
+	return result, nil
+}
+
+// validateService checks the fields of a service that are validated beyond
+// what the database enforces
+func validateService(service models.Service) error {
+	if err := labels.Validate(service.Labels); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidService, err)
+	}
+	return nil
+}
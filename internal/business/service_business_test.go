@@ -5,23 +5,25 @@ import (
 	"errors"
 	"services-api/internal/models"
 	"services-api/internal/repository"
+	"strings"
 	"testing"
 	"time"
 )
 
 type mockRepo struct {
 	ListServicesFn      func(ctx context.Context, filter models.ServiceFilter) ([]models.ServiceModel, int, error)
-	GetServiceFn        func(ctx context.Context, id uint) (*models.Service, error)
+	GetServiceFn        func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error)
 	CreateServiceFn     func(ctx context.Context, service models.Service) (*models.Service, error)
 	UpdateServiceFn     func(ctx context.Context, service models.Service) (*models.Service, error)
 	DeleteServiceFn     func(ctx context.Context, id uint) error
+	ListLabelFacetsFn   func(ctx context.Context, key string) ([]models.LabelFacet, error)
 }
 
 func (m *mockRepo) ListServices(ctx context.Context, filter models.ServiceFilter) ([]models.ServiceModel, int, error) {
 	return m.ListServicesFn(ctx, filter)
 }
-func (m *mockRepo) GetService(ctx context.Context, id uint) (*models.Service, error) {
-	return m.GetServiceFn(ctx, id)
+func (m *mockRepo) GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+	return m.GetServiceFn(ctx, id, expand)
 }
 func (m *mockRepo) CreateService(ctx context.Context, service models.Service) (*models.Service, error) {
 	return m.CreateServiceFn(ctx, service)
@@ -32,6 +34,9 @@ func (m *mockRepo) UpdateService(ctx context.Context, service models.Service) (*
 func (m *mockRepo) DeleteService(ctx context.Context, id uint) error {
 	return m.DeleteServiceFn(ctx, id)
 }
+func (m *mockRepo) ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error) {
+	return m.ListLabelFacetsFn(ctx, key)
+}
 
 func TestListServices(t *testing.T) {
 	repo := &mockRepo{
@@ -39,7 +44,7 @@ func TestListServices(t *testing.T) {
 			return []models.ServiceModel{{ID: 1, Name: "Test Service"}}, 1, nil
 		},
 	}
-	bs := NewServiceBusiness(repo)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
 	resp, err := bs.ListServices(context.Background(), models.ServiceFilter{Page: 1, Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -51,12 +56,12 @@ func TestListServices(t *testing.T) {
 
 func TestGetService_NotFound(t *testing.T) {
 	repo := &mockRepo{
-		GetServiceFn: func(ctx context.Context, id uint) (*models.Service, error) {
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
 			return nil, repository.ErrNotFound
 		},
 	}
-	bs := NewServiceBusiness(repo)
-	_, err := bs.GetService(context.Background(), 1)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
+	_, err := bs.GetService(context.Background(), 1, models.ServiceExpand{})
 	if !errors.Is(err, ErrServiceNotFound) {
 		t.Errorf("expected ErrServiceNotFound, got %v", err)
 	}
@@ -64,24 +69,41 @@ func TestGetService_NotFound(t *testing.T) {
 
 func TestGetService_Success(t *testing.T) {
 	repo := &mockRepo{
-		GetServiceFn: func(ctx context.Context, id uint) (*models.Service, error) {
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
 			return &models.Service{ID: 1, Name: "Test Service", Description: "Test Description", CreatedAt: time.Now(), UpdatedAt: time.Now(), VersionCount: 1}, nil
 		},
 	}
-	bs := NewServiceBusiness(repo)
-	service, err := bs.GetService(context.Background(), 1)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
+	service, err := bs.GetService(context.Background(), 1, models.ServiceExpand{})
 	if err != nil || service.ID != 1 {
 		t.Errorf("unexpected result: %v, %v", service, err)
 	}
 }
 
+func TestGetService_ExpandOwner(t *testing.T) {
+	repo := &mockRepo{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			return &models.Service{ID: 1, Labels: map[string]string{"owner": "identity"}, ManagedBy: "gitops"}, nil
+		},
+	}
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
+
+	service, err := bs.GetService(context.Background(), 1, models.ServiceExpand{Owner: true})
+	if err != nil || service.Owner == nil || *service.Owner != (models.ServiceOwner{Team: "identity", ManagedBy: "gitops"}) {
+		t.Errorf("unexpected owner: %+v, %v", service, err)
+	}
+	if service, _ := bs.GetService(context.Background(), 1, models.ServiceExpand{}); service.Owner != nil {
+		t.Errorf("expected no owner without expand=owner, got %+v", service.Owner)
+	}
+}
+
 func TestCreateService(t *testing.T) {
 	repo := &mockRepo{
 		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
 			return &models.Service{ID: 1, Name: "Test Service"}, nil
 		},
 	}
-	bs := NewServiceBusiness(repo)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
 	service, err := bs.CreateService(context.Background(), models.Service{Name: "Test Service"})
 	if err != nil || service.ID != 1 {
 		t.Errorf("unexpected result: %v, %v", service, err)
@@ -94,7 +116,7 @@ func TestUpdateService(t *testing.T) {
 			return &models.Service{ID: 1, Name: "Updated Service"}, nil
 		},
 	}
-	bs := NewServiceBusiness(repo)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
 	service, err := bs.UpdateService(context.Background(), models.Service{ID: 1, Name: "Updated Service"})
 	if err != nil || service.ID != 1 {
 		t.Errorf("unexpected result: %v, %v", service, err)
@@ -103,13 +125,59 @@ func TestUpdateService(t *testing.T) {
 
 func TestDeleteService(t *testing.T) {
 	repo := &mockRepo{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			return &models.Service{ID: id, Name: "users"}, nil
+		},
 		DeleteServiceFn: func(ctx context.Context, id uint) error {
 			return nil
 		},
 	}
-	bs := NewServiceBusiness(repo)
-	err := bs.DeleteService(context.Background(), 1)
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
+	err := bs.DeleteService(context.Background(), 1, false)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestDeleteService_WithDependents(t *testing.T) {
+	deleted := false
+	repo := &mockRepo{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			return &models.Service{ID: id, Name: "users"}, nil
+		},
+		DeleteServiceFn: func(ctx context.Context, id uint) error {
+			deleted = true
+			return nil
+		},
+	}
+	deps := newMemoryDependencyRepo(map[uint]string{1: "users", 2: "orders"})
+	deps.add(2, 1)
+	uow := serviceUnitOfWork(repo)
+	uow.repos.Dependencies = deps
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), uow)
+
+	err := bs.DeleteService(context.Background(), 1, false)
+	if !errors.Is(err, ErrServiceHasDependents) || !strings.Contains(err.Error(), "orders") {
+		t.Errorf("expected ErrServiceHasDependents naming orders, got %v", err)
+	}
+	if deleted {
+		t.Error("service was deleted despite dependents")
+	}
+
+	if err := bs.DeleteService(context.Background(), 1, true); err != nil || !deleted {
+		t.Errorf("expected forced delete to succeed, got %v", err)
+	}
+}
+func TestCreateService_InvalidLabels(t *testing.T) {
+	repo := &mockRepo{
+		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			t.Error("invalid service must not be stored")
+			return &service, nil
+		},
+	}
+	bs := NewServiceBusiness(repo, newMemoryCustomFieldRepo(), serviceUnitOfWork(repo))
+	_, err := bs.CreateService(context.Background(), models.Service{Name: "users", Labels: map[string]string{"tier": "not valid"}})
+	if !errors.Is(err, ErrInvalidService) {
+		t.Errorf("expected ErrInvalidService, got %v", err)
+	}
+}
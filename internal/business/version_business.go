@@ -28,24 +28,48 @@ type VersionBusiness interface {
 	// DeleteVersion deletes a version
 	// Returns an error if the version deletion fails or if the version is not found.
 	DeleteVersion(ctx context.Context, versionId uint, serviceId uint) error
+
+	// DeprecateVersion marks a version as no longer active, so it no longer
+	// satisfies the version constraints of dependencies
+	// Returns the deprecated version or ErrVersionNotFound if it doesn't exist.
+	DeprecateVersion(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error)
+
+	// ListVersions retrieves a page of the versions of a service
+	// Returns the versions with pagination details or ErrServiceNotFound if the service doesn't exist.
+	ListVersions(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error)
 }
 
 type versionBusinessImpl struct {
 	repo repository.VersionRepository
+	uow  repository.UnitOfWork
 }
 
 // NewVersionBusiness creates a new business logic implementation
-// with the provided repository.
-func NewVersionBusiness(repo repository.VersionRepository) VersionBusiness {
+// with the provided repository. Changes are written in a transaction
+// of uow together with their events.
+func NewVersionBusiness(repo repository.VersionRepository, uow repository.UnitOfWork) VersionBusiness {
 	return &versionBusinessImpl{
 		repo: repo,
+		uow:  uow,
 	}
 }
 
 // CreateVersion creates a new version
 // Returns the created version or an error if the version creation fails.
 func (b *versionBusinessImpl) CreateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
-	return b.repo.CreateVersion(ctx, version)
+	var created *models.Version
+	err := b.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		created, err = repos.Versions.CreateVersion(ctx, version)
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventVersionCreated, created.ServiceID, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
 // GetVersion retrieves a version by its ID
@@ -64,7 +88,15 @@ func (b *versionBusinessImpl) GetVersion(ctx context.Context, serviceId uint, ve
 // UpdateVersion updates a version
 // Returns the updated version or an error if the version update fails or if the version is not found.
 func (b *versionBusinessImpl) UpdateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
-	updatedVersion, err := b.repo.UpdateVersion(ctx, version)
+	var updatedVersion *models.Version
+	err := b.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		updatedVersion, err = repos.Versions.UpdateVersion(ctx, version)
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventVersionUpdated, updatedVersion.ServiceID, updatedVersion)
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrVersionNotFound
@@ -77,7 +109,16 @@ func (b *versionBusinessImpl) UpdateVersion(ctx context.Context, version models.
 // DeleteVersion deletes a version
 // Returns an error if the version deletion fails or if the version is not found.
 func (b *versionBusinessImpl) DeleteVersion(ctx context.Context, versionId uint, serviceId uint) error {
-	err := b.repo.DeleteVersion(ctx, versionId, serviceId)
+	err := b.uow.Do(ctx, func(repos repository.Repositories) error {
+		version, err := repos.Versions.GetVersion(ctx, versionId, serviceId)
+		if err != nil {
+			return err
+		}
+		if err := repos.Versions.DeleteVersion(ctx, versionId, serviceId); err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventVersionDeleted, version.ServiceID, version)
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrVersionNotFound
@@ -85,4 +126,47 @@ func (b *versionBusinessImpl) DeleteVersion(ctx context.Context, versionId uint,
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// DeprecateVersion marks a version as no longer active
+// Returns the deprecated version or ErrVersionNotFound if it doesn't exist.
+func (b *versionBusinessImpl) DeprecateVersion(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error) {
+	var deprecated *models.Version
+	err := b.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		deprecated, err = repos.Versions.DeprecateVersion(ctx, versionId, serviceId)
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventVersionUpdated, deprecated.ServiceID, deprecated)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+	return deprecated, nil
+}
+
+// ListVersions returns a page of the versions of a service
+// Returns the versions with pagination details or ErrServiceNotFound if the service doesn't exist.
+func (b *versionBusinessImpl) ListVersions(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error) {
+	versions, total, err := b.repo.ListVersions(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+
+	pagination := models.Pagination{
+		CurrentPage:  filter.Page,
+		ItemsPerPage: filter.Limit,
+	}
+	setTotal(&pagination, total)
+	return &models.VersionResponse{
+		Versions:   versions,
+		Pagination: pagination,
+	}, nil
+}
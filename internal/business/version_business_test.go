@@ -2,9 +2,12 @@ package business
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"services-api/internal/models"
+	"services-api/internal/repository"
+	"services-api/internal/repository/repositorytest"
 )
 
 type mockVersionRepository struct {
@@ -26,10 +29,23 @@ func (m *mockVersionRepository) UpdateVersion(ctx context.Context, version model
 func (m *mockVersionRepository) DeleteVersion(ctx context.Context, versionId uint, serviceId uint) error {
 	return nil
 }
+func (m *mockVersionRepository) DeprecateVersion(ctx context.Context, versionId uint, serviceId uint) (*models.Version, error) {
+	if versionId != 1 {
+		return nil, repository.ErrNotFound
+	}
+	return &models.Version{ID: 1, ServiceID: serviceId, Version: "1.0.0", IsActive: false}, nil
+}
+
+func (m *mockVersionRepository) ListVersions(ctx context.Context, filter models.VersionFilter) ([]models.Version, int, error) {
+	if filter.ServiceID != 1 {
+		return nil, 0, repository.ErrNotFound
+	}
+	return []models.Version{{ID: 3, ServiceID: 1, Version: "1.2.0"}}, 21, nil
+}
 
 func TestCreateVersion(t *testing.T) {
 	repo := &mockVersionRepository{}
-	business := NewVersionBusiness(repo)
+	business := NewVersionBusiness(repo, versionUnitOfWork(repo))
 
 	version := models.Version{
 		Version: "1.0.0",
@@ -51,7 +67,7 @@ func TestCreateVersion(t *testing.T) {
 
 func TestGetVersion(t *testing.T) {
 	repo := &mockVersionRepository{}
-	business := NewVersionBusiness(repo)
+	business := NewVersionBusiness(repo, versionUnitOfWork(repo))
 
 	version, err := business.GetVersion(context.Background(), 1, 1)
 	if err != nil {
@@ -69,7 +85,7 @@ func TestGetVersion(t *testing.T) {
 
 func TestUpdateVersion(t *testing.T) {
 	repo := &mockVersionRepository{}
-	business := NewVersionBusiness(repo)
+	business := NewVersionBusiness(repo, versionUnitOfWork(repo))
 
 	version := models.Version{
 		ID: 1,
@@ -92,10 +108,63 @@ func TestUpdateVersion(t *testing.T) {
 
 func TestDeleteVersion(t *testing.T) {
 	repo := &mockVersionRepository{}
-	business := NewVersionBusiness(repo)
+	business := NewVersionBusiness(repo, versionUnitOfWork(repo))
 
 	err := business.DeleteVersion(context.Background(), 1, 1)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
+
+func TestDeleteVersion_NotFound(t *testing.T) {
+	// The real repository, so the not found error of the database is mapped
+	repo := repository.NewVersionRepository(repositorytest.EmptyDB(t))
+	business := NewVersionBusiness(repo, versionUnitOfWork(repo))
+
+	if err := business.DeleteVersion(context.Background(), 7, 1); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestDeprecateVersion(t *testing.T) {
+	repo := &mockVersionRepository{}
+	uow := versionUnitOfWork(repo)
+	outbox := uow.repos.Outbox.(*memoryOutbox)
+	business := NewVersionBusiness(repo, uow)
+
+	version, err := business.DeprecateVersion(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if version.IsActive {
+		t.Errorf("expected the version to be inactive")
+	}
+	if got := outbox.types(); len(got) != 1 || got[0] != models.EventVersionUpdated {
+		t.Errorf("got events %v, want [%s]", got, models.EventVersionUpdated)
+	}
+
+	if _, err := business.DeprecateVersion(context.Background(), 1, 2); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	repo := &mockVersionRepository{}
+	business := NewVersionBusiness(repo, versionUnitOfWork(repo))
+
+	result, err := business.ListVersions(context.Background(), models.VersionFilter{ServiceID: 1, Sort: "-version", Page: 2, Limit: 10})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Versions) != 1 || result.Versions[0].Version != "1.2.0" {
+		t.Errorf("unexpected versions: %+v", result.Versions)
+	}
+	if result.Pagination.CurrentPage != 2 || *result.Pagination.TotalItems != 21 || *result.Pagination.TotalPages != 3 {
+		t.Errorf("unexpected pagination: %+v", result.Pagination)
+	}
+
+	_, err = business.ListVersions(context.Background(), models.VersionFilter{ServiceID: 2, Page: 1, Limit: 10})
+	if !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("expected ErrServiceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package business
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"services-api/internal/semver"
+)
+
+// constraintPattern matches a single comparison of a version constraint
+// such as "^1.2", "~1.2.3", ">=1.0.0" or "*"
+var constraintPattern = regexp.MustCompile(`^(\*|(\^|~|>=|<=|>|<|=|!=)?\s*v?\d+(\.\d+){0,2}(-[0-9A-Za-z.-]+)?)$`)
+
+// normalizeConstraint validates a comma-separated version constraint and
+// returns it in canonical form; an empty constraint matches any version
+func normalizeConstraint(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return "", nil
+	}
+
+	parts := strings.Split(constraint, ",")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if !constraintPattern.MatchString(part) {
+			return "", fmt.Errorf("%w: malformed version constraint %q", ErrInvalidDependency, part)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// matchesConstraint reports whether a version satisfies a constraint. The
+// constraint is assumed to be normalized. Versions that aren't semantic
+// versions match every constraint, so impact analysis errs on the side of
+// reporting too much rather than too little.
+func matchesConstraint(constraint, version string) bool {
+	if constraint == "" {
+		return true
+	}
+	v, ok := semver.Parse(version)
+	if !ok {
+		return true
+	}
+
+	for _, part := range strings.Split(constraint, ",") {
+		if !matchesComparison(strings.TrimSpace(part), v) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesComparison(comparison string, v semver.Version) bool {
+	if comparison == "*" {
+		return true
+	}
+
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(comparison, candidate) {
+			op = candidate
+			break
+		}
+	}
+	bound, ok := semver.Parse(strings.TrimSpace(strings.TrimPrefix(comparison, op)))
+	if !ok {
+		return true
+	}
+
+	switch op {
+	case ">=":
+		return v.Compare(bound) >= 0
+	case "<=":
+		return v.Compare(bound) <= 0
+	case ">":
+		return v.Compare(bound) > 0
+	case "<":
+		return v.Compare(bound) < 0
+	case "!=":
+		return !matchesPrefix(bound, v)
+	case "^":
+		return v.Compare(bound) >= 0 && v.Compare(caretUpper(bound)) < 0
+	case "~":
+		return v.Compare(bound) >= 0 && v.Compare(tildeUpper(bound)) < 0
+	}
+	return matchesPrefix(bound, v)
+}
+
+// matchesPrefix compares the parts given in bound, so "1.2" matches "1.2.7"
+func matchesPrefix(bound, v semver.Version) bool {
+	for i := 0; i < bound.Given; i++ {
+		if bound.Core[i] != v.Core[i] {
+			return false
+		}
+	}
+	return bound.Given < 3 || bound.Prerelease == v.Prerelease
+}
+
+// caretUpper returns the exclusive upper bound of ^bound: the next version
+// that changes the left-most non-zero part
+func caretUpper(bound semver.Version) semver.Version {
+	switch {
+	case bound.Core[0] > 0 || bound.Given == 1:
+		return semver.Version{Core: [3]int{bound.Core[0] + 1, 0, 0}, Given: 3, Prerelease: "0"}
+	case bound.Core[1] > 0 || bound.Given == 2:
+		return semver.Version{Core: [3]int{0, bound.Core[1] + 1, 0}, Given: 3, Prerelease: "0"}
+	}
+	return semver.Version{Core: [3]int{0, 0, bound.Core[2] + 1}, Given: 3, Prerelease: "0"}
+}
+
+// tildeUpper returns the exclusive upper bound of ~bound: the next minor
+// version, or the next major version if only the major part was given
+func tildeUpper(bound semver.Version) semver.Version {
+	if bound.Given == 1 {
+		return semver.Version{Core: [3]int{bound.Core[0] + 1, 0, 0}, Given: 3, Prerelease: "0"}
+	}
+	return semver.Version{Core: [3]int{bound.Core[0], bound.Core[1] + 1, 0}, Given: 3, Prerelease: "0"}
+}
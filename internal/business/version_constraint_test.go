@@ -0,0 +1,53 @@
+package business
+
+import "testing"
+
+func TestMatchesConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"", "1.0.0", true},
+		{"*", "0.1.0", true},
+		{"1.2", "1.2.7", true},
+		{"1.2", "1.3.0", false},
+		{"=1.2.3", "1.2.3", true},
+		{"!=1.2.3", "1.2.3", false},
+		{">=1.2.0, <2.0.0", "1.9.9", true},
+		{">=1.2.0, <2.0.0", "2.0.0", false},
+		{">=1.2.0, <2.0.0", "1.1.0", false},
+		{">1.0", "1.0.1", true},
+		{"<=1.0", "1.0.0", true},
+		{"^1.2", "1.9.0", true},
+		{"^1.2", "2.0.0", false},
+		{"^1.2", "2.0.0-alpha", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{">=1.0.0", "1.0.0-rc.1", false},
+		{"v1.2", "v1.2.0", true},
+		// Versions that aren't semantic versions are always reported
+		{">=2.0.0", "latest", true},
+	}
+	for _, tt := range tests {
+		if got := matchesConstraint(tt.constraint, tt.version); got != tt.want {
+			t.Errorf("matchesConstraint(%q, %q) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeConstraint(t *testing.T) {
+	got, err := normalizeConstraint(" >=1.2.0,<2 ")
+	if err != nil || got != ">=1.2.0, <2" {
+		t.Errorf("unexpected result: %q, %v", got, err)
+	}
+	for _, invalid := range []string{"latest", ">=", "1.2.3.4", "=>1.0"} {
+		if _, err := normalizeConstraint(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
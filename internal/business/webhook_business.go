@@ -0,0 +1,258 @@
+package business
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+var (
+	// ErrWebhookNotFound is returned when a requested webhook doesn't exist
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	// ErrDeliveryNotFound is returned when a requested webhook delivery doesn't exist
+	ErrDeliveryNotFound = errors.New("delivery not found")
+
+	// ErrInvalidWebhook is returned when a webhook request fails validation
+	ErrInvalidWebhook = errors.New("invalid webhook")
+)
+
+// AllEvents subscribes a webhook to every event type
+const AllEvents = "*"
+
+// minWebhookSecretLength is the shortest secret accepted for signing payloads
+const minWebhookSecretLength = 16
+
+// WebhookBusiness interface defines webhook subscription operations
+type WebhookBusiness interface {
+	// CreateWebhook creates a new webhook
+	// Returns the webhook together with its secret, generated when the request has none.
+	CreateWebhook(ctx context.Context, req models.WebhookRequest) (*models.WebhookWithSecret, error)
+
+	// ListWebhooks returns all webhooks without their secrets
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+
+	// GetWebhook retrieves a webhook by its ID
+	// Returns the webhook or ErrWebhookNotFound if it doesn't exist.
+	GetWebhook(ctx context.Context, id uint) (*models.Webhook, error)
+
+	// UpdateWebhook replaces the subscription of a webhook. The secret is kept unless
+	// the request has one, and enabling a webhook resets its failures.
+	// Returns the updated webhook or ErrWebhookNotFound.
+	UpdateWebhook(ctx context.Context, id uint, req models.WebhookRequest) (*models.Webhook, error)
+
+	// DeleteWebhook deletes a webhook and its deliveries
+	// Returns ErrWebhookNotFound if the webhook doesn't exist.
+	DeleteWebhook(ctx context.Context, id uint) error
+
+	// ListDeliveries returns the latest deliveries of a webhook, newest first
+	// Returns ErrWebhookNotFound if the webhook doesn't exist.
+	ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error)
+
+	// Redeliver queues the event of a delivery to be sent again as a new delivery
+	// Returns the new delivery, ErrWebhookNotFound or ErrDeliveryNotFound.
+	Redeliver(ctx context.Context, webhookID uint, deliveryID uint) (*models.WebhookDelivery, error)
+}
+
+type webhookBusinessImpl struct {
+	repo repository.WebhookRepository
+	now  func() time.Time
+}
+
+// NewWebhookBusiness creates a new business logic implementation
+// with the provided repository.
+func NewWebhookBusiness(repo repository.WebhookRepository) WebhookBusiness {
+	return &webhookBusinessImpl{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// CreateWebhook validates the request and creates a new webhook
+func (b *webhookBusinessImpl) CreateWebhook(ctx context.Context, req models.WebhookRequest) (*models.WebhookWithSecret, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	webhook := models.Webhook{
+		URL:        strings.TrimSpace(req.URL),
+		Events:     req.Events,
+		ServiceIDs: req.ServiceIDs,
+		Secret:     secret,
+	}
+	if req.Enabled != nil && !*req.Enabled {
+		now := b.now()
+		webhook.DisabledAt = &now
+		webhook.DisabledReason = "disabled on creation"
+	}
+
+	created, err := b.repo.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookWithSecret{Webhook: *created, Secret: secret}, nil
+}
+
+// ListWebhooks returns all webhooks
+func (b *webhookBusinessImpl) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return b.repo.ListWebhooks(ctx)
+}
+
+// GetWebhook returns a single webhook by ID
+func (b *webhookBusinessImpl) GetWebhook(ctx context.Context, id uint) (*models.Webhook, error) {
+	webhook, err := b.repo.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook validates the request and replaces the subscription of a webhook
+func (b *webhookBusinessImpl) UpdateWebhook(ctx context.Context, id uint, req models.WebhookRequest) (*models.Webhook, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+	webhook, err := b.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.URL = strings.TrimSpace(req.URL)
+	webhook.Events = req.Events
+	webhook.ServiceIDs = req.ServiceIDs
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Enabled != nil {
+		switch {
+		case *req.Enabled:
+			webhook.DisabledAt = nil
+			webhook.DisabledReason = ""
+			webhook.ConsecutiveFailures = 0
+		case webhook.DisabledAt == nil:
+			now := b.now()
+			webhook.DisabledAt = &now
+			webhook.DisabledReason = "disabled by request"
+		}
+	}
+
+	updated, err := b.repo.UpdateWebhook(ctx, *webhook)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteWebhook deletes a webhook
+func (b *webhookBusinessImpl) DeleteWebhook(ctx context.Context, id uint) error {
+	err := b.repo.DeleteWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a webhook
+func (b *webhookBusinessImpl) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := b.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	return b.repo.ListDeliveries(ctx, webhookID, limit)
+}
+
+// Redeliver copies the event of a delivery into a new pending delivery,
+// which the dispatcher sends like any other. The copy records the delivery
+// it was made from.
+func (b *webhookBusinessImpl) Redeliver(ctx context.Context, webhookID uint, deliveryID uint) (*models.WebhookDelivery, error) {
+	if _, err := b.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	original, err := b.repo.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	now := b.now()
+	deliveries := []models.WebhookDelivery{{
+		WebhookID:     webhookID,
+		RedeliveryOf:  &original.ID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: &now,
+	}}
+	if err := b.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+func validateWebhookRequest(req models.WebhookRequest) error {
+	target, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if len(req.Events) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	for _, event := range req.Events {
+		if event != AllEvents && !slices.Contains(models.EventTypes, event) {
+			return fmt.Errorf("%w: unknown event type %q, must be %q or one of %v", ErrInvalidWebhook, event, AllEvents, models.EventTypes)
+		}
+	}
+	if req.Secret != "" && len(req.Secret) < minWebhookSecretLength {
+		return fmt.Errorf("%w: secret must be at least %d characters", ErrInvalidWebhook, minWebhookSecretLength)
+	}
+	return nil
+}
+
+// WebhookMatches reports whether a webhook subscribes to an event
+func WebhookMatches(webhook models.Webhook, event models.Event) bool {
+	if webhook.DisabledAt != nil {
+		return false
+	}
+	if !slices.Contains(webhook.Events, AllEvents) && !slices.Contains(webhook.Events, event.Type) {
+		return false
+	}
+	return len(webhook.ServiceIDs) == 0 || slices.Contains(webhook.ServiceIDs, event.ServiceID)
+}
+
+// generateWebhookSecret returns a new random signing secret
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
@@ -0,0 +1,211 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// memoryWebhookRepo is an in-memory WebhookRepository
+type memoryWebhookRepo struct {
+	webhooks   map[uint]*models.Webhook
+	deliveries []models.WebhookDelivery
+	nextID     uint
+}
+
+func newMemoryWebhookRepo() *memoryWebhookRepo {
+	return &memoryWebhookRepo{webhooks: make(map[uint]*models.Webhook), nextID: 1}
+}
+
+func (m *memoryWebhookRepo) CreateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error) {
+	webhook.ID = m.nextID
+	m.nextID++
+	m.webhooks[webhook.ID] = &webhook
+	return &webhook, nil
+}
+func (m *memoryWebhookRepo) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	for _, webhook := range m.webhooks {
+		webhooks = append(webhooks, *webhook)
+	}
+	return webhooks, nil
+}
+func (m *memoryWebhookRepo) GetWebhook(ctx context.Context, id uint) (*models.Webhook, error) {
+	if webhook, ok := m.webhooks[id]; ok {
+		copied := *webhook
+		return &copied, nil
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memoryWebhookRepo) UpdateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error) {
+	if _, ok := m.webhooks[webhook.ID]; !ok {
+		return nil, repository.ErrNotFound
+	}
+	m.webhooks[webhook.ID] = &webhook
+	return &webhook, nil
+}
+func (m *memoryWebhookRepo) DeleteWebhook(ctx context.Context, id uint) error {
+	if _, ok := m.webhooks[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+func (m *memoryWebhookRepo) RecordWebhookSuccess(ctx context.Context, id uint) error {
+	return nil
+}
+func (m *memoryWebhookRepo) RecordWebhookFailure(ctx context.Context, id uint, disableAfter int, reason string, at time.Time) (bool, error) {
+	return false, nil
+}
+func (m *memoryWebhookRepo) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	for i := range deliveries {
+		deliveries[i].ID = uint(len(m.deliveries) + 1)
+		m.deliveries = append(m.deliveries, deliveries[i])
+	}
+	return nil
+}
+func (m *memoryWebhookRepo) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+func (m *memoryWebhookRepo) GetDelivery(ctx context.Context, webhookID uint, id uint) (*models.WebhookDelivery, error) {
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID && delivery.ID == id {
+			return &delivery, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memoryWebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *memoryWebhookRepo) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	return nil
+}
+
+func TestCreateWebhook(t *testing.T) {
+	b := NewWebhookBusiness(newMemoryWebhookRepo())
+
+	created, err := b.CreateWebhook(context.Background(), models.WebhookRequest{
+		URL:    "https://deploy.example.com/hooks",
+		Events: []string{models.EventVersionCreated},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") || created.Webhook.Secret != created.Secret {
+		t.Errorf("expected a generated secret, got %q", created.Secret)
+	}
+	if created.DisabledAt != nil {
+		t.Error("expected the webhook to be enabled")
+	}
+
+	tests := []models.WebhookRequest{
+		{URL: "deploy.example.com/hooks", Events: []string{models.EventVersionCreated}},
+		{URL: "ftp://deploy.example.com", Events: []string{models.EventVersionCreated}},
+		{URL: "https://deploy.example.com"},
+		{URL: "https://deploy.example.com", Events: []string{"version.released"}},
+		{URL: "https://deploy.example.com", Events: []string{AllEvents}, Secret: "short"},
+	}
+	for _, req := range tests {
+		if _, err := b.CreateWebhook(context.Background(), req); !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("expected ErrInvalidWebhook for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	repo := newMemoryWebhookRepo()
+	b := NewWebhookBusiness(repo)
+	created, _ := b.CreateWebhook(context.Background(), models.WebhookRequest{
+		URL:    "https://deploy.example.com/hooks",
+		Events: []string{AllEvents},
+	})
+	disabledAt := time.Now()
+	repo.webhooks[created.ID].DisabledAt = &disabledAt
+	repo.webhooks[created.ID].ConsecutiveFailures = 5
+
+	enabled := true
+	updated, err := b.UpdateWebhook(context.Background(), created.ID, models.WebhookRequest{
+		URL:     "https://deploy.example.com/v2/hooks",
+		Events:  []string{models.EventServiceDeleted},
+		Enabled: &enabled,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.DisabledAt != nil || updated.ConsecutiveFailures != 0 || updated.URL != "https://deploy.example.com/v2/hooks" {
+		t.Errorf("expected the webhook to be re-enabled at the new URL, got %+v", updated)
+	}
+	if updated.Secret != created.Secret {
+		t.Error("expected the secret to be kept")
+	}
+
+	_, err = b.UpdateWebhook(context.Background(), 99, models.WebhookRequest{URL: "https://example.com", Events: []string{AllEvents}})
+	if !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	repo := newMemoryWebhookRepo()
+	b := NewWebhookBusiness(repo)
+	created, _ := b.CreateWebhook(context.Background(), models.WebhookRequest{
+		URL:    "https://deploy.example.com/hooks",
+		Events: []string{AllEvents},
+	})
+	repo.CreateDeliveries(context.Background(), []models.WebhookDelivery{{
+		WebhookID: created.ID,
+		EventID:   "evt_1",
+		EventType: models.EventServiceDeleted,
+		Payload:   `{"id":"evt_1"}`,
+		Status:    models.DeliveryFailed,
+		Attempts:  8,
+	}})
+
+	delivery, err := b.Redeliver(context.Background(), created.ID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery.ID != 2 || delivery.Status != models.DeliveryPending || delivery.Attempts != 0 || delivery.Payload != `{"id":"evt_1"}` || delivery.NextAttemptAt == nil ||
+		delivery.RedeliveryOf == nil || *delivery.RedeliveryOf != 1 {
+		t.Errorf("unexpected redelivery: %+v", delivery)
+	}
+
+	if _, err := b.Redeliver(context.Background(), created.ID, 7); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+	}
+	if _, err := b.Redeliver(context.Background(), 99, 1); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	event := models.Event{Type: models.EventVersionCreated, ServiceID: 3}
+	disabledAt := time.Now()
+	tests := []struct {
+		webhook models.Webhook
+		want    bool
+	}{
+		{models.Webhook{Events: []string{AllEvents}}, true},
+		{models.Webhook{Events: []string{models.EventVersionCreated}, ServiceIDs: []uint{1, 3}}, true},
+		{models.Webhook{Events: []string{models.EventVersionDeleted}}, false},
+		{models.Webhook{Events: []string{AllEvents}, ServiceIDs: []uint{1}}, false},
+		{models.Webhook{Events: []string{AllEvents}, DisabledAt: &disabledAt}, false},
+	}
+	for _, tt := range tests {
+		if got := WebhookMatches(tt.webhook, event); got != tt.want {
+			t.Errorf("WebhookMatches(%+v) = %t, want %t", tt.webhook, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package catalog encodes and decodes service catalogs as YAML, JSON or
+// CSV. YAML and JSON documents hold a list of services with their versions;
+// CSV files have a row per version, repeating the service columns, and a
+// row without version for services that have none.
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"services-api/internal/models"
+)
+
+var (
+	// ErrInvalidCatalog is returned when a catalog document can't be decoded
+	ErrInvalidCatalog = errors.New("invalid catalog")
+
+	// ErrUnsupportedFormat is returned for formats other than yaml, json and csv
+	ErrUnsupportedFormat = errors.New("unsupported catalog format")
+)
+
+// Catalog formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// csvColumns are the columns of CSV catalogs. Labels are written as
+// "key=value" pairs separated by commas and metadata as a JSON object.
+var csvColumns = []string{"name", "description", "labels", "metadata", "version", "version_description"}
+
+// ContentType returns the media type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatJSON:
+		return "application/json"
+	case FormatCSV:
+		return "text/csv"
+	default:
+		return "application/yaml"
+	}
+}
+
+// Encode writes a catalog in the given format
+func Encode(w io.Writer, format string, catalog models.Catalog) error {
+	if catalog.Services == nil {
+		catalog.Services = []models.CatalogService{}
+	}
+	switch format {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(catalog); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(catalog)
+	case FormatCSV:
+		return encodeCSV(w, catalog)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// Decode reads a catalog in the given format. Unknown fields are rejected
+// and metadata values are converted to the types the API accepts.
+func Decode(r io.Reader, format string) (*models.Catalog, error) {
+	var catalog models.Catalog
+	switch format {
+	case FormatYAML:
+		decoder := yaml.NewDecoder(r)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&catalog); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: empty document", ErrInvalidCatalog)
+			}
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+		}
+	case FormatJSON:
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&catalog); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: empty document", ErrInvalidCatalog)
+			}
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+		}
+	case FormatCSV:
+		decoded, err := decodeCSV(r)
+		if err != nil {
+			return nil, err
+		}
+		catalog = *decoded
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	for i := range catalog.Services {
+		catalog.Services[i].Metadata = normalizeMetadata(catalog.Services[i].Metadata)
+	}
+	return &catalog, nil
+}
+
+// DecodeService reads a YAML document describing a single service, the
+// format of the service.yaml files reconciled by the sync command.
+func DecodeService(r io.Reader) (*models.CatalogService, error) {
+	var service models.CatalogService
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&service); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty document", ErrInvalidCatalog)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+	}
+	service.Metadata = normalizeMetadata(service.Metadata)
+	return &service, nil
+}
+
+func encodeCSV(w io.Writer, catalog models.Catalog) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, service := range catalog.Services {
+		metadata := ""
+		if len(service.Metadata) > 0 {
+			data, err := json.Marshal(service.Metadata)
+			if err != nil {
+				return err
+			}
+			metadata = string(data)
+		}
+		row := []string{service.Name, service.Description, formatLabels(service.Labels), metadata, "", ""}
+
+		if len(service.Versions) == 0 {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		for _, version := range service.Versions {
+			row[4], row[5] = version.Version, version.Description
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// decodeCSV reads rows into services in order of first appearance. The
+// header selects the columns, of which only name is required; the service
+// columns are taken from the first row of each service.
+func decodeCSV(r io.Reader) (*models.Catalog, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty document", ErrInvalidCatalog)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(csvColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q, expected %s", ErrInvalidCatalog, name, strings.Join(csvColumns, ", "))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: missing the name column", ErrInvalidCatalog)
+	}
+
+	catalog := &models.Catalog{Services: []models.CatalogService{}}
+	positions := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+		}
+		line, _ := reader.FieldPos(0)
+		value := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return record[i]
+			}
+			return ""
+		}
+
+		name := value("name")
+		position, seen := positions[name]
+		if !seen {
+			service := models.CatalogService{Name: name, Description: value("description")}
+			if service.Labels, err = parseLabels(value("labels")); err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidCatalog, line, err)
+			}
+			if metadata := value("metadata"); metadata != "" {
+				if err := json.Unmarshal([]byte(metadata), &service.Metadata); err != nil {
+					return nil, fmt.Errorf("%w: line %d: metadata must be a JSON object: %w", ErrInvalidCatalog, line, err)
+				}
+			}
+			position = len(catalog.Services)
+			positions[name] = position
+			catalog.Services = append(catalog.Services, service)
+		}
+		if version := value("version"); version != "" {
+			catalog.Services[position].Versions = append(catalog.Services[position].Versions, models.CatalogVersion{
+				Version:     version,
+				Description: value("version_description"),
+			})
+		}
+	}
+	return catalog, nil
+}
+
+// formatLabels writes labels as "key=value" pairs sorted by key
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func parseLabels(s string) (map[string]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	labels := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("label %q must be key=value", strings.TrimSpace(pair))
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+// normalizeMetadata converts the integers and timestamps YAML produces to
+// the numbers and date strings custom fields expect
+func normalizeMetadata(metadata map[string]any) map[string]any {
+	for name, value := range metadata {
+		switch v := value.(type) {
+		case int:
+			metadata[name] = float64(v)
+		case int64:
+			metadata[name] = float64(v)
+		case uint64:
+			metadata[name] = float64(v)
+		case time.Time:
+			if v.Equal(v.Truncate(24 * time.Hour)) {
+				metadata[name] = v.Format(time.DateOnly)
+			} else {
+				metadata[name] = v.Format(time.RFC3339)
+			}
+		}
+	}
+	return metadata
+}
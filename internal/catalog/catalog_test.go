@@ -0,0 +1,109 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"services-api/internal/models"
+)
+
+func sampleCatalog() models.Catalog {
+	return models.Catalog{Services: []models.CatalogService{
+		{
+			Name:        "payments",
+			Description: "Card payments, refunds",
+			Labels:      map[string]string{"tier": "critical", "lang": "go"},
+			Metadata:    map[string]any{"cost": 12.5, "sla_tier": "gold"},
+			Versions: []models.CatalogVersion{
+				{Version: "1.0.0", Description: "Initial release"},
+				{Version: "1.1.0", Description: `Adds "refunds"`},
+			},
+		},
+		{Name: "users"},
+	}}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, format := range []string{FormatYAML, FormatJSON, FormatCSV} {
+		var buf bytes.Buffer
+		if err := Encode(&buf, format, sampleCatalog()); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		decoded, err := Decode(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if !reflect.DeepEqual(*decoded, sampleCatalog()) {
+			t.Errorf("%s: round trip changed the catalog: %+v", format, *decoded)
+		}
+	}
+}
+
+func TestEncodeCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, FormatCSV, sampleCatalog()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `name,description,labels,metadata,version,version_description
+payments,"Card payments, refunds","lang=go,tier=critical","{""cost"":12.5,""sla_tier"":""gold""}",1.0.0,Initial release
+payments,"Card payments, refunds","lang=go,tier=critical","{""cost"":12.5,""sla_tier"":""gold""}",1.1.0,"Adds ""refunds"""
+users,,,,,
+`
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDecode_YAMLMetadata(t *testing.T) {
+	catalog, err := Decode(strings.NewReader("services:\n  - name: users\n    metadata:\n      cost: 3\n      launched: 2024-02-29\n"), FormatYAML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	metadata := catalog.Services[0].Metadata
+	if metadata["cost"] != 3.0 || metadata["launched"] != "2024-02-29" {
+		t.Errorf("unexpected metadata: %#v", metadata)
+	}
+}
+
+func TestDecodeService(t *testing.T) {
+	service, err := DecodeService(strings.NewReader("name: users\nmetadata:\n  cost: 3\nversions:\n  - version: 1.0.0\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if service.Name != "users" || service.Metadata["cost"] != 3.0 || service.Versions[0].Version != "1.0.0" {
+		t.Errorf("unexpected service: %+v", service)
+	}
+
+	for _, input := range []string{"", "name: users\nowner: team-a\n", "services: []\n"} {
+		if _, err := DecodeService(strings.NewReader(input)); !errors.Is(err, ErrInvalidCatalog) {
+			t.Errorf("expected ErrInvalidCatalog for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		format string
+		input  string
+	}{
+		{FormatYAML, ""},
+		{FormatYAML, "services:\n  - name: users\n    owner: team-a\n"},
+		{FormatJSON, `{"services": [{"name": "users", "versions": "1.0.0"}]}`},
+		{FormatCSV, "name,owner\nusers,team-a\n"},
+		{FormatCSV, "description\nUsers\n"},
+		{FormatCSV, "name,labels\nusers,tier\n"},
+		{FormatCSV, "name,metadata\nusers,cost=3\n"},
+	}
+	for _, tt := range tests {
+		if _, err := Decode(strings.NewReader(tt.input), tt.format); !errors.Is(err, ErrInvalidCatalog) {
+			t.Errorf("expected ErrInvalidCatalog for %s %q, got %v", tt.format, tt.input, err)
+		}
+	}
+
+	if _, err := Decode(strings.NewReader("{}"), "xml"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
@@ -1,28 +1,549 @@
 package config
 
 import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultJWTSecret is the placeholder secret used when none is configured.
+// It is refused when running in production.
+const DefaultJWTSecret = "your-secret-key"
+
+// Supported deployment environments
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+	EnvTest        = "test"
 )
 
-// Config holds application configuration
+// Config holds application configuration.
+//
+// Values are resolved in the following order, later sources overriding
+// earlier ones: built-in defaults, the configuration file (YAML or JSON),
+// environment variables and finally command-line flags.
 type Config struct {
-	DatabaseURL string
-	JWTSecret   string
-	Environment string
+	Environment string          `yaml:"environment"`
+	Server      ServerConfig    `yaml:"server"`
+	Database    DatabaseConfig  `yaml:"database"`
+	Log         LogConfig       `yaml:"log"`
+	Auth        AuthConfig      `yaml:"auth"`
+	CORS        CORSConfig      `yaml:"cors"`
+	Security    SecurityConfig  `yaml:"security_headers"`
+	RateLimit   RateLimitConfig `yaml:"rate_limit"`
+	Sync        SyncConfig      `yaml:"sync"`
+	Webhooks    WebhookConfig   `yaml:"webhooks"`
+	Outbox      OutboxConfig    `yaml:"outbox"`
+
+	// Features toggles optional behaviour by name and can be changed at runtime
+	Features map[string]bool `yaml:"features"`
+
+	// File is the path of the configuration file that was loaded, if any
+	File string `yaml:"-"`
+}
+
+// ServerConfig holds HTTP server settings
+type ServerConfig struct {
+	Host              string        `yaml:"host"`
+	Port              int           `yaml:"port"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes"`
+	TLS               TLSConfig     `yaml:"tls"`
+	Admin             AdminConfig   `yaml:"admin"`
+	GRPC              GRPCConfig    `yaml:"grpc"`
+}
+
+// TLSConfig holds settings for serving HTTPS and authenticating clients with
+// certificates. Certificate files are reloaded from disk when they change.
+type TLSConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	MinVersion   string `yaml:"min_version"`    // 1.2 or 1.3
+	ClientCAFile string `yaml:"client_ca_file"` // CA bundle used to verify client certificates
+	ClientAuth   string `yaml:"client_auth"`    // none, optional or require
+}
+
+// AdminConfig holds settings for the optional admin listener serving
+// metrics, health and profiling endpoints
+type AdminConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+	Pprof   bool   `yaml:"pprof"`
+}
+
+// GRPCConfig holds settings for the optional gRPC listener serving the
+// services and versions. It uses the TLS settings of the HTTP server.
+type GRPCConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Host       string `yaml:"host"`
+	Port       int    `yaml:"port"`
+	Reflection bool   `yaml:"reflection"` // Let clients such as grpcurl discover the services
+}
+
+// DatabaseConfig holds database connection and pool settings
+type DatabaseConfig struct {
+	URL             string        `yaml:"url"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+// LogConfig holds logging settings
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error
+	Format string `yaml:"format"` // text or json
+}
+
+// AuthConfig holds authentication settings
+type AuthConfig struct {
+	JWTSecret string `yaml:"jwt_secret"`
+
+	// Required rejects anonymous requests. When false, requests without
+	// credentials may read but presented credentials are still verified.
+	Required bool `yaml:"required"`
+
+	// ClientCertScopes are granted to callers authenticated by a client certificate
+	ClientCertScopes []string `yaml:"client_cert_scopes"`
+}
+
+// CORSConfig holds cross-origin resource sharing settings. Allowed origins
+// may be "*", an exact origin or a wildcard subdomain such as
+// "https://*.example.com".
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// SecurityConfig holds the security headers added to every response
+type SecurityConfig struct {
+	Enabled                      bool          `yaml:"enabled"`
+	HSTSMaxAge                   time.Duration `yaml:"hsts_max_age"` // 0 disables Strict-Transport-Security
+	HSTSIncludeSubdomains        bool          `yaml:"hsts_include_subdomains"`
+	FrameOptions                 string        `yaml:"frame_options"`
+	ContentSecurityPolicy        string        `yaml:"content_security_policy"`
+	SwaggerContentSecurityPolicy string        `yaml:"swagger_content_security_policy"`
+}
+
+// RateLimitConfig holds request rate limiting settings. Each client gets a
+// token bucket per limit; write requests use the stricter write limit unless
+// a route override matches.
+type RateLimitConfig struct {
+	Enabled                bool             `yaml:"enabled"`
+	RequestsPerSecond      float64          `yaml:"requests_per_second"`
+	Burst                  int              `yaml:"burst"`
+	WriteRequestsPerSecond float64          `yaml:"write_requests_per_second"`
+	WriteBurst             int              `yaml:"write_burst"`
+	Routes                 []RouteRateLimit `yaml:"routes"`
+}
+
+// SyncConfig holds settings for reconciling the catalog against a directory
+// of service.yaml files. The "sync" subcommand reconciles once; Enabled
+// also reconciles on a schedule while serving.
+type SyncConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Dir       string        `yaml:"dir"`
+	Interval  time.Duration `yaml:"interval"`
+	ManagedBy string        `yaml:"managed_by"` // Marker of the services the reconciler owns and may delete
+	Adopt     bool          `yaml:"adopt"`      // Take over existing services not marked with ManagedBy
+}
+
+// WebhookConfig holds settings for delivering events to webhooks. Failed
+// attempts are retried after RetryBackoff, doubled after every attempt up
+// to MaxBackoff, and a webhook is disabled after DisableAfter deliveries in
+// a row failed every attempt.
+type WebhookConfig struct {
+	Timeout      time.Duration `yaml:"timeout"`
+	MaxAttempts  int           `yaml:"max_attempts"`
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+	MaxBackoff   time.Duration `yaml:"max_backoff"`
+	DisableAfter int           `yaml:"disable_after"`
+}
+
+// Event publishers of the outbox
+const (
+	PublisherWebhook = "webhook" // Queue deliveries to the webhook subscriptions
+	PublisherStdout  = "stdout"  // Print events as JSON lines, for testing
+)
+
+// OutboxConfig holds settings for publishing the events stored in the
+// outbox. Events failing to publish are retried after PollInterval, doubled
+// after every attempt up to MaxBackoff. Published events are kept for
+// Retention.
+type OutboxConfig struct {
+	Publishers   []string      `yaml:"publishers"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size"`
+	MaxBackoff   time.Duration `yaml:"max_backoff"`
+	Retention    time.Duration `yaml:"retention"`
+}
+
+// RouteRateLimit overrides the rate limit for a single route
+type RouteRateLimit struct {
+	Method            string  `yaml:"method"` // HTTP method, empty for any
+	Path              string  `yaml:"path"`   // Route pattern, e.g. /api/v1/services/:sid
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// Addr returns the address the HTTP server listens on
+func (s ServerConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// Addr returns the address the admin listener binds to
+func (a AdminConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", a.Host, a.Port)
+}
+
+// Addr returns the address the gRPC listener binds to
+func (g GRPCConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", g.Host, g.Port)
+}
+
+// IsProduction reports whether the configuration targets production
+func (c *Config) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// FeatureEnabled reports whether the named feature flag is switched on
+func (c *Config) FeatureEnabled(name string) bool {
+	return c.Features[name]
 }
 
-// Load loads configuration from environment variables
-func Load() *Config {
+// Default returns the built-in configuration defaults
+func Default() *Config {
 	return &Config{
-		DatabaseURL: os.Getenv("DATABASE_URL"),
-		JWTSecret:   getEnvOrDefault("JWT_SECRET", "your-secret-key"),
-		Environment: getEnvOrDefault("ENVIRONMENT", "development"),
+		Environment: EnvDevelopment,
+		Server: ServerConfig{
+			Port:              8080,
+			ShutdownTimeout:   10 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			MaxHeaderBytes:    1 << 20,
+			TLS: TLSConfig{
+				MinVersion: "1.2",
+				ClientAuth: "none",
+			},
+			Admin: AdminConfig{
+				Port:  9090,
+				Pprof: true,
+			},
+			GRPC: GRPCConfig{
+				Port:       50051,
+				Reflection: true,
+			},
+		},
+		Database: DatabaseConfig{
+			MaxIdleConns:    10,
+			MaxOpenConns:    100,
+			ConnMaxLifetime: time.Hour,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Auth: AuthConfig{
+			JWTSecret:        DefaultJWTSecret,
+			ClientCertScopes: []string{"read"},
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "X-API-Key"},
+			ExposedHeaders: []string{"Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
+			MaxAge:         12 * time.Hour,
+		},
+		Security: SecurityConfig{
+			Enabled:                      true,
+			FrameOptions:                 "DENY",
+			ContentSecurityPolicy:        "default-src 'none'; frame-ancestors 'none'",
+			SwaggerContentSecurityPolicy: "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'",
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerSecond:      10,
+			Burst:                  20,
+			WriteRequestsPerSecond: 2,
+			WriteBurst:             5,
+		},
+		Sync: SyncConfig{
+			Interval:  5 * time.Minute,
+			ManagedBy: "gitops",
+		},
+		Webhooks: WebhookConfig{
+			Timeout:      10 * time.Second,
+			MaxAttempts:  8,
+			RetryBackoff: 30 * time.Second,
+			MaxBackoff:   time.Hour,
+			DisableAfter: 5,
+		},
+		Outbox: OutboxConfig{
+			Publishers:   []string{PublisherWebhook},
+			PollInterval: time.Second,
+			BatchSize:    100,
+			MaxBackoff:   time.Minute,
+			Retention:    7 * 24 * time.Hour,
+		},
 	}
 }
 
+// setting maps a configuration field to its environment variable and flag
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	apply func(cfg *Config, value string) error
+}
+
+// settings lists every field that can be overridden from the environment or
+// the command line.
+var settings = []setting{
+	{"ENVIRONMENT", "environment", "Deployment environment (development, staging, production, test)", stringVar(func(c *Config) *string { return &c.Environment })},
+	{"HOST", "host", "Interface the HTTP server binds to", stringVar(func(c *Config) *string { return &c.Server.Host })},
+	{"PORT", "port", "Port the HTTP server listens on", intVar(func(c *Config) *int { return &c.Server.Port })},
+	{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "Grace period for in-flight requests on shutdown", durationVar(func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout })},
+	{"READ_TIMEOUT", "read-timeout", "Maximum duration for reading an entire request", durationVar(func(c *Config) *time.Duration { return &c.Server.ReadTimeout })},
+	{"READ_HEADER_TIMEOUT", "read-header-timeout", "Maximum duration for reading request headers", durationVar(func(c *Config) *time.Duration { return &c.Server.ReadHeaderTimeout })},
+	{"WRITE_TIMEOUT", "write-timeout", "Maximum duration before timing out writes of a response", durationVar(func(c *Config) *time.Duration { return &c.Server.WriteTimeout })},
+	{"IDLE_TIMEOUT", "idle-timeout", "Maximum time to wait for the next request on keep-alive connections", durationVar(func(c *Config) *time.Duration { return &c.Server.IdleTimeout })},
+	{"MAX_HEADER_BYTES", "max-header-bytes", "Maximum size of request headers in bytes", intVar(func(c *Config) *int { return &c.Server.MaxHeaderBytes })},
+	{"TLS_ENABLED", "tls-enabled", "Serve HTTPS", boolVar(func(c *Config) *bool { return &c.Server.TLS.Enabled })},
+	{"TLS_CERT_FILE", "tls-cert-file", "Path to the PEM encoded server certificate", stringVar(func(c *Config) *string { return &c.Server.TLS.CertFile })},
+	{"TLS_KEY_FILE", "tls-key-file", "Path to the PEM encoded server private key", stringVar(func(c *Config) *string { return &c.Server.TLS.KeyFile })},
+	{"TLS_MIN_VERSION", "tls-min-version", "Minimum TLS version (1.2, 1.3)", stringVar(func(c *Config) *string { return &c.Server.TLS.MinVersion })},
+	{"TLS_CLIENT_CA_FILE", "tls-client-ca-file", "Path to the PEM encoded CA bundle for client certificates", stringVar(func(c *Config) *string { return &c.Server.TLS.ClientCAFile })},
+	{"TLS_CLIENT_AUTH", "tls-client-auth", "Client certificate policy (none, optional, require)", stringVar(func(c *Config) *string { return &c.Server.TLS.ClientAuth })},
+	{"ADMIN_ENABLED", "admin-enabled", "Serve metrics, health and pprof on a separate listener", boolVar(func(c *Config) *bool { return &c.Server.Admin.Enabled })},
+	{"ADMIN_HOST", "admin-host", "Interface the admin listener binds to", stringVar(func(c *Config) *string { return &c.Server.Admin.Host })},
+	{"ADMIN_PORT", "admin-port", "Port the admin listener listens on", intVar(func(c *Config) *int { return &c.Server.Admin.Port })},
+	{"ADMIN_PPROF", "admin-pprof", "Expose pprof on the admin listener", boolVar(func(c *Config) *bool { return &c.Server.Admin.Pprof })},
+	{"GRPC_ENABLED", "grpc-enabled", "Serve the gRPC API on a separate listener", boolVar(func(c *Config) *bool { return &c.Server.GRPC.Enabled })},
+	{"GRPC_HOST", "grpc-host", "Interface the gRPC listener binds to", stringVar(func(c *Config) *string { return &c.Server.GRPC.Host })},
+	{"GRPC_PORT", "grpc-port", "Port the gRPC listener listens on", intVar(func(c *Config) *int { return &c.Server.GRPC.Port })},
+	{"GRPC_REFLECTION", "grpc-reflection", "Serve gRPC reflection", boolVar(func(c *Config) *bool { return &c.Server.GRPC.Reflection })},
+	{"DATABASE_URL", "database-url", "PostgreSQL connection URL", stringVar(func(c *Config) *string { return &c.Database.URL })},
+	{"DB_MAX_IDLE_CONNS", "db-max-idle-conns", "Maximum number of idle database connections", intVar(func(c *Config) *int { return &c.Database.MaxIdleConns })},
+	{"DB_MAX_OPEN_CONNS", "db-max-open-conns", "Maximum number of open database connections", intVar(func(c *Config) *int { return &c.Database.MaxOpenConns })},
+	{"DB_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "Maximum lifetime of a database connection", durationVar(func(c *Config) *time.Duration { return &c.Database.ConnMaxLifetime })},
+	{"LOG_LEVEL", "log-level", "Log level (debug, info, warn, error)", stringVar(func(c *Config) *string { return &c.Log.Level })},
+	{"LOG_FORMAT", "log-format", "Log format (text, json)", stringVar(func(c *Config) *string { return &c.Log.Format })},
+	{"JWT_SECRET", "jwt-secret", "Secret used to sign and verify JWTs", stringVar(func(c *Config) *string { return &c.Auth.JWTSecret })},
+	{"AUTH_REQUIRED", "auth-required", "Reject requests without valid credentials", boolVar(func(c *Config) *bool { return &c.Auth.Required })},
+	{"AUTH_CLIENT_CERT_SCOPES", "auth-client-cert-scopes", "Comma-separated scopes granted to client certificate callers", listVar(func(c *Config) *[]string { return &c.Auth.ClientCertScopes })},
+	{"CORS_ALLOWED_ORIGINS", "cors-allowed-origins", "Comma-separated list of allowed CORS origins", listVar(func(c *Config) *[]string { return &c.CORS.AllowedOrigins })},
+	{"CORS_ALLOWED_METHODS", "cors-allowed-methods", "Comma-separated list of allowed CORS methods", listVar(func(c *Config) *[]string { return &c.CORS.AllowedMethods })},
+	{"CORS_ALLOWED_HEADERS", "cors-allowed-headers", "Comma-separated list of allowed CORS request headers", listVar(func(c *Config) *[]string { return &c.CORS.AllowedHeaders })},
+	{"CORS_EXPOSED_HEADERS", "cors-exposed-headers", "Comma-separated list of response headers exposed to browsers", listVar(func(c *Config) *[]string { return &c.CORS.ExposedHeaders })},
+	{"CORS_ALLOW_CREDENTIALS", "cors-allow-credentials", "Allow credentialed CORS requests", boolVar(func(c *Config) *bool { return &c.CORS.AllowCredentials })},
+	{"CORS_MAX_AGE", "cors-max-age", "How long browsers may cache preflight responses", durationVar(func(c *Config) *time.Duration { return &c.CORS.MaxAge })},
+	{"SECURITY_HEADERS_ENABLED", "security-headers-enabled", "Add security headers to responses", boolVar(func(c *Config) *bool { return &c.Security.Enabled })},
+	{"HSTS_MAX_AGE", "hsts-max-age", "Strict-Transport-Security max age, 0 to disable", durationVar(func(c *Config) *time.Duration { return &c.Security.HSTSMaxAge })},
+	{"RATE_LIMIT_ENABLED", "rate-limit-enabled", "Enable request rate limiting", boolVar(func(c *Config) *bool { return &c.RateLimit.Enabled })},
+	{"RATE_LIMIT_RPS", "rate-limit-rps", "Sustained requests per second allowed per client", floatVar(func(c *Config) *float64 { return &c.RateLimit.RequestsPerSecond })},
+	{"RATE_LIMIT_BURST", "rate-limit-burst", "Maximum burst of requests allowed per client", intVar(func(c *Config) *int { return &c.RateLimit.Burst })},
+	{"RATE_LIMIT_WRITE_RPS", "rate-limit-write-rps", "Sustained write requests per second allowed per client", floatVar(func(c *Config) *float64 { return &c.RateLimit.WriteRequestsPerSecond })},
+	{"RATE_LIMIT_WRITE_BURST", "rate-limit-write-burst", "Maximum burst of write requests allowed per client", intVar(func(c *Config) *int { return &c.RateLimit.WriteBurst })},
+	{"SYNC_ENABLED", "sync-enabled", "Reconcile the catalog against the sync directory on a schedule", boolVar(func(c *Config) *bool { return &c.Sync.Enabled })},
+	{"SYNC_DIR", "sync-dir", "Directory of service.yaml files describing the desired catalog", stringVar(func(c *Config) *string { return &c.Sync.Dir })},
+	{"SYNC_INTERVAL", "sync-interval", "Time between scheduled reconciliations", durationVar(func(c *Config) *time.Duration { return &c.Sync.Interval })},
+	{"SYNC_MANAGED_BY", "sync-managed-by", "Marker of the services owned by the reconciler", stringVar(func(c *Config) *string { return &c.Sync.ManagedBy })},
+	{"SYNC_ADOPT", "sync-adopt", "Take over existing services not owned by the reconciler", boolVar(func(c *Config) *bool { return &c.Sync.Adopt })},
+	{"WEBHOOK_TIMEOUT", "webhook-timeout", "Timeout of a webhook delivery attempt", durationVar(func(c *Config) *time.Duration { return &c.Webhooks.Timeout })},
+	{"WEBHOOK_MAX_ATTEMPTS", "webhook-max-attempts", "Attempts before a webhook delivery fails", intVar(func(c *Config) *int { return &c.Webhooks.MaxAttempts })},
+	{"WEBHOOK_RETRY_BACKOFF", "webhook-retry-backoff", "Delay before the first retry of a webhook delivery, doubled after every attempt", durationVar(func(c *Config) *time.Duration { return &c.Webhooks.RetryBackoff })},
+	{"WEBHOOK_MAX_BACKOFF", "webhook-max-backoff", "Longest delay between webhook delivery attempts", durationVar(func(c *Config) *time.Duration { return &c.Webhooks.MaxBackoff })},
+	{"WEBHOOK_DISABLE_AFTER", "webhook-disable-after", "Failed deliveries in a row after which a webhook is disabled", intVar(func(c *Config) *int { return &c.Webhooks.DisableAfter })},
+	{"OUTBOX_PUBLISHERS", "outbox-publishers", "Publishers of the events in the outbox (webhook, stdout)", listVar(func(c *Config) *[]string { return &c.Outbox.Publishers })},
+	{"OUTBOX_POLL_INTERVAL", "outbox-poll-interval", "Time between polls of the outbox for events to publish", durationVar(func(c *Config) *time.Duration { return &c.Outbox.PollInterval })},
+	{"OUTBOX_BATCH_SIZE", "outbox-batch-size", "Events published per poll of the outbox", intVar(func(c *Config) *int { return &c.Outbox.BatchSize })},
+	{"OUTBOX_MAX_BACKOFF", "outbox-max-backoff", "Longest delay before retrying an event that failed to publish", durationVar(func(c *Config) *time.Duration { return &c.Outbox.MaxBackoff })},
+	{"OUTBOX_RETENTION", "outbox-retention", "Time published events are kept in the outbox", durationVar(func(c *Config) *time.Duration { return &c.Outbox.Retention })},
+}
+
+// Load builds the configuration from defaults, the configuration file,
+// environment variables and the given command-line arguments, in that order
+// of precedence. The configuration file is taken from the -config flag or the
+// CONFIG_FILE environment variable.
+//
+// Load does not validate the result; call Validate before using it.
+func Load(args []string) (*Config, error) {
+	return LoadFlags(flag.NewFlagSet("services-api", flag.ContinueOnError), args)
+}
+
+// LoadFlags is Load for commands with flags of their own: the configuration
+// flags are registered on fs next to them before parsing args. The
+// positional arguments are left in fs.Args().
+func LoadFlags(fs *flag.FlagSet, args []string) (*Config, error) {
+	configFile := fs.String("config", getEnvOrDefault("CONFIG_FILE", ""), "Path to a YAML or JSON configuration file (env: CONFIG_FILE)")
+	for _, s := range settings {
+		fs.String(s.flag, "", fmt.Sprintf("%s (env: %s)", s.usage, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+		cfg.File = *configFile
+	}
+
+	for _, s := range settings {
+		if value := os.Getenv(s.env); value != "" {
+			if err := s.apply(cfg, value); err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %w", s.env, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				if err := s.apply(cfg, f.Value.String()); err != nil {
+					flagErr = fmt.Errorf("invalid value for -%s: %w", s.flag, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	return cfg, nil
+}
+
+// loadFile merges the YAML or JSON file at path into the configuration.
+// Unknown keys are rejected so that typos don't go unnoticed.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// YAML is a superset of JSON, so a single decoder handles both formats
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration with secrets masked,
+// suitable for logging or printing.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	if redacted.Auth.JWTSecret != "" {
+		redacted.Auth.JWTSecret = "REDACTED"
+	}
+	if u, err := url.Parse(redacted.Database.URL); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "REDACTED")
+			redacted.Database.URL = u.String()
+		}
+	}
+	return &redacted
+}
+
+// Print writes the redacted configuration to w as YAML
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func stringVar(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+func intVar(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func floatVar(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func boolVar(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func durationVar(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func listVar(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*field(c) = items
+		return nil
+	}
+}
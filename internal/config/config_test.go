@@ -1,8 +1,13 @@
 package config
 
 import (
+	"bytes"
+	"flag"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetEnvOrDefault(t *testing.T) {
@@ -34,14 +39,209 @@ func TestLoad(t *testing.T) {
 	defer os.Unsetenv("JWT_SECRET")
 	defer os.Unsetenv("ENVIRONMENT")
 
-	cfg := Load()
-	if cfg.DatabaseURL != "postgres://test" {
-		t.Errorf("expected DATABASE_URL to be 'postgres://test', got %q", cfg.DatabaseURL)
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if cfg.JWTSecret != "mysecret" {
-		t.Errorf("expected JWT_SECRET to be 'mysecret', got %q", cfg.JWTSecret)
+	if cfg.Database.URL != "postgres://test" {
+		t.Errorf("expected DATABASE_URL to be 'postgres://test', got %q", cfg.Database.URL)
+	}
+	if cfg.Auth.JWTSecret != "mysecret" {
+		t.Errorf("expected JWT_SECRET to be 'mysecret', got %q", cfg.Auth.JWTSecret)
 	}
 	if cfg.Environment != "production" {
 		t.Errorf("expected ENVIRONMENT to be 'production', got %q", cfg.Environment)
 	}
-} 
\ No newline at end of file
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Server.Port != 8080 {
+		t.Errorf("expected default port 8080, got %d", cfg.Server.Port)
+	}
+	if cfg.Database.MaxOpenConns != 100 || cfg.Database.MaxIdleConns != 10 {
+		t.Errorf("unexpected pool defaults: %+v", cfg.Database)
+	}
+	if cfg.Server.ShutdownTimeout != 10*time.Second {
+		t.Errorf("expected default shutdown timeout 10s, got %s", cfg.Server.ShutdownTimeout)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+server:
+  port: 9000
+  shutdown_timeout: 30s
+database:
+  url: postgres://file
+  max_open_conns: 50
+log:
+  level: debug
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("DATABASE_URL", "postgres://env")
+	os.Setenv("LOG_LEVEL", "warn")
+	defer os.Unsetenv("DATABASE_URL")
+	defer os.Unsetenv("LOG_LEVEL")
+
+	cfg, err := Load([]string{"-config", path, "-log-level", "error"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// File overrides defaults
+	if cfg.Server.Port != 9000 || cfg.Server.ShutdownTimeout != 30*time.Second || cfg.Database.MaxOpenConns != 50 {
+		t.Errorf("file values not applied: %+v", cfg)
+	}
+	// Environment overrides the file
+	if cfg.Database.URL != "postgres://env" {
+		t.Errorf("expected env to override file, got %q", cfg.Database.URL)
+	}
+	// Flags override the environment
+	if cfg.Log.Level != "error" {
+		t.Errorf("expected flag to override env, got %q", cfg.Log.Level)
+	}
+	if cfg.File != path {
+		t.Errorf("expected File to be %q, got %q", path, cfg.File)
+	}
+}
+
+func TestLoadFlags(t *testing.T) {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "")
+
+	cfg, err := LoadFlags(fs, []string{"-dry-run", "-port", "9000", "catalog.yaml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !*dryRun || cfg.Server.Port != 9000 {
+		t.Errorf("flags not applied: dry-run %t, port %d", *dryRun, cfg.Server.Port)
+	}
+	if args := fs.Args(); len(args) != 1 || args[0] != "catalog.yaml" {
+		t.Errorf("unexpected positional arguments: %v", args)
+	}
+}
+
+func TestLoad_JSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"server": {"port": 7000}, "cors": {"allowed_origins": ["https://portal.example.com"]}}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Server.Port != 7000 {
+		t.Errorf("expected port 7000, got %d", cfg.Server.Port)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 1 || cfg.CORS.AllowedOrigins[0] != "https://portal.example.com" {
+		t.Errorf("unexpected origins: %v", cfg.CORS.AllowedOrigins)
+	}
+}
+
+func TestLoad_UnknownFileKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  prot: 9000\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load([]string{"-config", path}); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestLoad_InvalidEnvValue(t *testing.T) {
+	os.Setenv("PORT", "not-a-number")
+	defer os.Unsetenv("PORT")
+
+	if _, err := Load(nil); err == nil {
+		t.Error("expected error for invalid PORT")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		cfg := Default()
+		cfg.Database.URL = "postgres://test"
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{"valid", func(c *Config) {}, ""},
+		{"missing database url", func(c *Config) { c.Database.URL = "" }, "database.url"},
+		{"default secret in production", func(c *Config) { c.Environment = EnvProduction }, "jwt_secret"},
+		{"custom secret in production", func(c *Config) { c.Environment = EnvProduction; c.Auth.JWTSecret = "s3cr3t" }, ""},
+		{"invalid port", func(c *Config) { c.Server.Port = 0 }, "server.port"},
+		{"idle exceeds open", func(c *Config) { c.Database.MaxIdleConns = 200 }, "max_idle_conns"},
+		{"invalid log level", func(c *Config) { c.Log.Level = "verbose" }, "log.level"},
+		{"wildcard origin with credentials", func(c *Config) {
+			c.CORS.AllowedOrigins = []string{"*"}
+			c.CORS.AllowCredentials = true
+		}, "allow_credentials"},
+		{"wildcard subdomain origin", func(c *Config) { c.CORS.AllowedOrigins = []string{"https://*.example.com"} }, ""},
+		{"malformed wildcard origin", func(c *Config) { c.CORS.AllowedOrigins = []string{"https://app*.example.com"} }, "cors.allowed_origins"},
+		{"negative hsts max age", func(c *Config) { c.Security.HSTSMaxAge = -1 }, "hsts_max_age"},
+		{"rate limit without burst", func(c *Config) { c.RateLimit.Enabled = true; c.RateLimit.Burst = 0 }, "rate_limit.burst"},
+		{"sync without directory", func(c *Config) { c.Sync.Enabled = true }, "sync.dir"},
+		{"webhook backoff above maximum", func(c *Config) { c.Webhooks.RetryBackoff = 2 * time.Hour }, "webhooks.retry_backoff"},
+		{"unknown outbox publisher", func(c *Config) { c.Outbox.Publishers = []string{"kafka"} }, `unknown publisher "kafka"`},
+		{"grpc on the http port", func(c *Config) { c.Server.GRPC.Enabled = true; c.Server.GRPC.Port = 8080 }, "server.grpc.port"},
+		{"grpc on the admin port", func(c *Config) {
+			c.Server.Admin.Enabled = true
+			c.Server.GRPC.Enabled = true
+			c.Server.GRPC.Port = c.Server.Admin.Port
+		}, "server.grpc.port must differ from server.admin.port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Database.URL = "postgres://kong:kong_password@db:5432/kong_db"
+	cfg.Auth.JWTSecret = "supersecret"
+
+	var buf bytes.Buffer
+	if err := cfg.Print(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "kong_password") || strings.Contains(out, "supersecret") {
+		t.Errorf("secrets leaked in output:\n%s", out)
+	}
+	if !strings.Contains(out, "postgres://kong:REDACTED@db:5432/kong_db") {
+		t.Errorf("expected database user and host to be kept:\n%s", out)
+	}
+	if cfg.Auth.JWTSecret != "supersecret" {
+		t.Error("Print must not modify the original configuration")
+	}
+}
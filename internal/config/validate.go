@@ -0,0 +1,189 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	validEnvironments = []string{EnvDevelopment, EnvStaging, EnvProduction, EnvTest}
+	validLogLevels    = []string{"debug", "info", "warn", "error"}
+	validLogFormats   = []string{"text", "json"}
+	validTLSVersions  = []string{"1.2", "1.3"}
+	validClientAuth   = []string{"none", "optional", "require"}
+)
+
+// Validate checks the configuration for missing or inconsistent values.
+// All problems are reported together so they can be fixed in one go.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !slices.Contains(validEnvironments, c.Environment) {
+		errs = append(errs, fmt.Errorf("environment must be one of %v, got %q", validEnvironments, c.Environment))
+	}
+
+	// Server
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		errs = append(errs, errors.New("server timeouts must not be negative"))
+	}
+	if c.Server.MaxHeaderBytes < 0 {
+		errs = append(errs, errors.New("server.max_header_bytes must not be negative"))
+	}
+
+	// TLS
+	tls := c.Server.TLS
+	if !slices.Contains(validTLSVersions, tls.MinVersion) {
+		errs = append(errs, fmt.Errorf("server.tls.min_version must be one of %v, got %q", validTLSVersions, tls.MinVersion))
+	}
+	if !slices.Contains(validClientAuth, tls.ClientAuth) {
+		errs = append(errs, fmt.Errorf("server.tls.client_auth must be one of %v, got %q", validClientAuth, tls.ClientAuth))
+	}
+	if tls.Enabled {
+		if tls.CertFile == "" || tls.KeyFile == "" {
+			errs = append(errs, errors.New("server.tls.cert_file and server.tls.key_file are required when TLS is enabled"))
+		}
+		if tls.ClientAuth != "none" && tls.ClientCAFile == "" {
+			errs = append(errs, errors.New("server.tls.client_ca_file is required when client certificates are verified"))
+		}
+	} else if tls.ClientAuth != "none" {
+		errs = append(errs, errors.New("server.tls.client_auth requires TLS to be enabled"))
+	}
+
+	// Admin listener
+	if c.Server.Admin.Enabled {
+		if c.Server.Admin.Port < 1 || c.Server.Admin.Port > 65535 {
+			errs = append(errs, fmt.Errorf("server.admin.port must be between 1 and 65535, got %d", c.Server.Admin.Port))
+		} else if c.Server.Admin.Port == c.Server.Port {
+			errs = append(errs, errors.New("server.admin.port must differ from server.port"))
+		}
+	}
+
+	// gRPC listener
+	if c.Server.GRPC.Enabled {
+		if c.Server.GRPC.Port < 1 || c.Server.GRPC.Port > 65535 {
+			errs = append(errs, fmt.Errorf("server.grpc.port must be between 1 and 65535, got %d", c.Server.GRPC.Port))
+		} else if c.Server.GRPC.Port == c.Server.Port {
+			errs = append(errs, errors.New("server.grpc.port must differ from server.port"))
+		} else if c.Server.Admin.Enabled && c.Server.GRPC.Port == c.Server.Admin.Port {
+			errs = append(errs, errors.New("server.grpc.port must differ from server.admin.port"))
+		}
+	}
+
+	// Database
+	if c.Database.URL == "" {
+		errs = append(errs, errors.New("database.url is required"))
+	}
+	if c.Database.MaxOpenConns < 1 {
+		errs = append(errs, fmt.Errorf("database.max_open_conns must be at least 1, got %d", c.Database.MaxOpenConns))
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns must be between 0 and max_open_conns, got %d", c.Database.MaxIdleConns))
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		errs = append(errs, errors.New("database.conn_max_lifetime must not be negative"))
+	}
+
+	// Logging
+	if !slices.Contains(validLogLevels, c.Log.Level) {
+		errs = append(errs, fmt.Errorf("log.level must be one of %v, got %q", validLogLevels, c.Log.Level))
+	}
+	if !slices.Contains(validLogFormats, c.Log.Format) {
+		errs = append(errs, fmt.Errorf("log.format must be one of %v, got %q", validLogFormats, c.Log.Format))
+	}
+
+	// Auth
+	if c.Auth.JWTSecret == "" {
+		errs = append(errs, errors.New("auth.jwt_secret is required"))
+	} else if c.IsProduction() && c.Auth.JWTSecret == DefaultJWTSecret {
+		errs = append(errs, errors.New("auth.jwt_secret must be changed from the default in production"))
+	}
+
+	// CORS
+	if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*") {
+		errs = append(errs, errors.New("cors.allow_credentials cannot be combined with the \"*\" origin"))
+	}
+	if c.CORS.MaxAge < 0 {
+		errs = append(errs, errors.New("cors.max_age must not be negative"))
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin != "*" && strings.Count(origin, "*") > 0 && !strings.Contains(origin, "://*.") {
+			errs = append(errs, fmt.Errorf("cors.allowed_origins: wildcard origin %q must have the form scheme://*.domain", origin))
+		}
+	}
+
+	// Security headers
+	if c.Security.HSTSMaxAge < 0 {
+		errs = append(errs, errors.New("security_headers.hsts_max_age must not be negative"))
+	}
+
+	// Rate limiting
+	if c.RateLimit.Enabled {
+		if c.RateLimit.RequestsPerSecond <= 0 {
+			errs = append(errs, errors.New("rate_limit.requests_per_second must be positive"))
+		}
+		if c.RateLimit.Burst < 1 {
+			errs = append(errs, errors.New("rate_limit.burst must be at least 1"))
+		}
+		if c.RateLimit.WriteRequestsPerSecond <= 0 || c.RateLimit.WriteBurst < 1 {
+			errs = append(errs, errors.New("rate_limit.write_requests_per_second must be positive and rate_limit.write_burst at least 1"))
+		}
+		for i, route := range c.RateLimit.Routes {
+			if route.Path == "" || route.RequestsPerSecond <= 0 || route.Burst < 1 {
+				errs = append(errs, fmt.Errorf("rate_limit.routes[%d] needs a path, positive requests_per_second and burst of at least 1", i))
+			}
+		}
+	}
+
+	// Sync
+	if c.Sync.Enabled {
+		if c.Sync.Dir == "" {
+			errs = append(errs, errors.New("sync.dir is required when sync is enabled"))
+		}
+		if c.Sync.Interval <= 0 {
+			errs = append(errs, errors.New("sync.interval must be positive"))
+		}
+	}
+	if c.Sync.ManagedBy == "" {
+		errs = append(errs, errors.New("sync.managed_by is required"))
+	}
+
+	// Webhooks
+	if c.Webhooks.Timeout <= 0 {
+		errs = append(errs, errors.New("webhooks.timeout must be positive"))
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, errors.New("webhooks.max_attempts must be at least 1"))
+	}
+	if c.Webhooks.RetryBackoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.RetryBackoff {
+		errs = append(errs, errors.New("webhooks.retry_backoff must be positive and not exceed webhooks.max_backoff"))
+	}
+	if c.Webhooks.DisableAfter < 1 {
+		errs = append(errs, errors.New("webhooks.disable_after must be at least 1"))
+	}
+
+	// Outbox
+	for _, publisher := range c.Outbox.Publishers {
+		if publisher != PublisherWebhook && publisher != PublisherStdout {
+			errs = append(errs, fmt.Errorf("outbox.publishers: unknown publisher %q", publisher))
+		}
+	}
+	if c.Outbox.PollInterval <= 0 || c.Outbox.MaxBackoff < c.Outbox.PollInterval {
+		errs = append(errs, errors.New("outbox.poll_interval must be positive and not exceed outbox.max_backoff"))
+	}
+	if c.Outbox.BatchSize < 1 {
+		errs = append(errs, errors.New("outbox.batch_size must be at least 1"))
+	}
+	if c.Outbox.Retention <= 0 {
+		errs = append(errs, errors.New("outbox.retention must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// DefaultWatchInterval is how often the configuration file is checked for changes
+const DefaultWatchInterval = 5 * time.Second
+
+// Change describes a single configuration field that differs between two
+// configurations. Secret values are redacted.
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Old, c.New)
+}
+
+// Subscriber is notified with the previous and the new configuration after a
+// successful reload.
+type Subscriber func(old, new *Config)
+
+// Watcher reloads the configuration when the process receives SIGHUP or when
+// the configuration file changes on disk. Invalid configurations are rejected
+// and the current one is kept.
+//
+// Only runtime settings (logging, CORS, rate limits, feature flags) are
+// reloaded. Changes to the environment, server, database and sync sections require
+// a restart and are ignored with a warning.
+type Watcher struct {
+	args        []string
+	interval    time.Duration
+	current     atomic.Pointer[Config]
+	mu          sync.Mutex
+	subscribers []Subscriber
+	modTime     time.Time
+}
+
+// NewWatcher creates a watcher for cfg. The args are the command-line
+// arguments cfg was loaded from; they are re-applied on every reload so the
+// precedence of flags is preserved.
+func NewWatcher(cfg *Config, args []string, interval time.Duration) *Watcher {
+	w := &Watcher{
+		args:     args,
+		interval: interval,
+	}
+	w.current.Store(cfg)
+	w.modTime = fileModTime(cfg.File)
+	return w
+}
+
+// Current returns the active configuration
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called after every successful reload
+func (w *Watcher) Subscribe(fn Subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload loads and validates the configuration again and, if it is valid,
+// swaps it in and notifies subscribers. It returns the applied changes.
+func (w *Watcher) Reload() ([]Change, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	old := w.current.Load()
+	updated, err := Load(w.args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Settings that can't be changed while running keep their current values
+	for _, change := range Diff(old, updated) {
+		if isStaticField(change.Field) {
+			slog.Warn("Config reload: ignoring change, a restart is required", "field", change.Field)
+		}
+	}
+	updated.Environment = old.Environment
+	updated.Server = old.Server
+	updated.Database = old.Database
+	updated.Sync = old.Sync
+	updated.Outbox = old.Outbox
+
+	if err := updated.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	changes := Diff(old, updated)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	w.current.Store(updated)
+	for _, fn := range w.subscribers {
+		fn(old, updated)
+	}
+	return changes, nil
+}
+
+// Run watches for SIGHUP and configuration file changes until ctx is done
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Println("Received SIGHUP, reloading configuration")
+			w.reloadAndLog()
+		case <-ticker.C:
+			file := w.Current().File
+			if file == "" {
+				continue
+			}
+			if modTime := fileModTime(file); !modTime.Equal(w.modTime) {
+				w.modTime = modTime
+				log.Printf("Configuration file %s changed, reloading", file)
+				w.reloadAndLog()
+			}
+		}
+	}
+}
+
+func (w *Watcher) reloadAndLog() {
+	changes, err := w.Reload()
+	if err != nil {
+		slog.Warn("Config reload rejected, keeping current configuration", "error", err)
+		return
+	}
+	if len(changes) == 0 {
+		slog.Debug("Config reload: no changes")
+		return
+	}
+	for _, change := range changes {
+		log.Printf("Config reload: %s", change)
+	}
+}
+
+// Diff returns the fields that differ between old and new. Secrets are
+// compared on their real values but reported redacted.
+func Diff(old, new *Config) []Change {
+	var changes []Change
+	diffStruct("",
+		reflect.ValueOf(*old), reflect.ValueOf(*new),
+		reflect.ValueOf(*old.Redacted()), reflect.ValueOf(*new.Redacted()),
+		&changes)
+	return changes
+}
+
+func diffStruct(prefix string, oldValue, newValue, oldShown, newShown reflect.Value, changes *[]Change) {
+	t := oldValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if !field.IsExported() || name == "-" || name == "" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			diffStruct(name, oldValue.Field(i), newValue.Field(i), oldShown.Field(i), newShown.Field(i), changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			*changes = append(*changes, Change{
+				Field: name,
+				Old:   fmt.Sprint(oldShown.Field(i).Interface()),
+				New:   fmt.Sprint(newShown.Field(i).Interface()),
+			})
+		}
+	}
+}
+
+// isStaticField reports whether a field can only be changed by restarting
+func isStaticField(field string) bool {
+	for _, prefix := range []string{"environment", "server.", "database.", "sync.", "outbox."} {
+		if field == prefix || strings.HasPrefix(field, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestWatcher(t *testing.T, content string) (*Watcher, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, content)
+
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewWatcher(cfg, args, DefaultWatchInterval), path
+}
+
+const baseConfig = `
+database:
+  url: postgres://test
+log:
+  level: info
+`
+
+func TestWatcherReload_AppliesChanges(t *testing.T) {
+	w, path := newTestWatcher(t, baseConfig)
+
+	var notified *Config
+	w.Subscribe(func(old, new *Config) { notified = new })
+
+	writeConfigFile(t, path, `
+database:
+  url: postgres://test
+log:
+  level: debug
+features:
+  search: true
+`)
+
+	changes, err := w.Reload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %v", changes)
+	}
+	if notified == nil || notified.Log.Level != "debug" {
+		t.Errorf("subscriber not notified with new config: %+v", notified)
+	}
+	if w.Current().Log.Level != "debug" || !w.Current().FeatureEnabled("search") {
+		t.Errorf("current config not swapped: %+v", w.Current())
+	}
+}
+
+func TestWatcherReload_RejectsInvalidConfig(t *testing.T) {
+	w, path := newTestWatcher(t, baseConfig)
+	notified := false
+	w.Subscribe(func(old, new *Config) { notified = true })
+
+	writeConfigFile(t, path, `
+database:
+  url: postgres://test
+log:
+  level: verbose
+`)
+
+	if _, err := w.Reload(); err == nil {
+		t.Fatal("expected reload to be rejected")
+	}
+	if notified {
+		t.Error("subscribers must not be notified of a rejected reload")
+	}
+	if w.Current().Log.Level != "info" {
+		t.Errorf("expected old config to be kept, got log level %q", w.Current().Log.Level)
+	}
+}
+
+func TestWatcherReload_IgnoresStaticFields(t *testing.T) {
+	w, path := newTestWatcher(t, baseConfig)
+
+	writeConfigFile(t, path, `
+server:
+  port: 9999
+database:
+  url: postgres://other
+log:
+  level: info
+`)
+
+	changes, err := w.Reload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected static changes to be ignored, got %v", changes)
+	}
+	if w.Current().Server.Port != 8080 || w.Current().Database.URL != "postgres://test" {
+		t.Errorf("static fields changed: %+v", w.Current())
+	}
+}
+
+func TestDiff_RedactsSecrets(t *testing.T) {
+	old := Default()
+	updated := Default()
+	updated.Auth.JWTSecret = "rotated-secret"
+
+	changes := Diff(old, updated)
+	if len(changes) != 1 || changes[0].Field != "auth.jwt_secret" {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	if changes[0].New == "rotated-secret" {
+		t.Error("secret value leaked in diff")
+	}
+}
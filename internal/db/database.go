@@ -41,10 +41,65 @@ func Migrate(db *gorm.DB) error {
 		return fmt.Errorf("database connection is nil")
 	}
 
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&models.Service{},
 		&models.Version{},
+		&models.APIKey{},
+		&models.Dependency{},
+		&models.ServiceLabel{},
+		&models.CustomField{},
+		&models.ServiceFieldValue{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.OutboxEvent{},
+		&models.OutboxPruning{},
 	)
+	if err != nil {
+		return err
+	}
+
+	// Full-text search columns are generated by Postgres, so GORM can't manage them
+	for _, statement := range searchMigrations {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+	for _, statement := range deliveryMigrations {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to create delivery index: %w", err)
+		}
+	}
+	return nil
+}
+
+// deliveryMigrations queue an event at most once per webhook. The unique
+// index leaves out redeliveries, and deliveries queued twice before it
+// existed are turned into redeliveries of the first one so it can be built.
+var deliveryMigrations = []string{
+	`UPDATE webhook_deliveries d SET redelivery_of = first.id
+		FROM (SELECT webhook_id, event_id, min(id) AS id FROM webhook_deliveries
+			WHERE redelivery_of IS NULL GROUP BY webhook_id, event_id HAVING count(*) > 1) first
+		WHERE d.webhook_id = first.webhook_id AND d.event_id = first.event_id
+			AND d.id <> first.id AND d.redelivery_of IS NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event
+		ON webhook_deliveries (webhook_id, event_id) WHERE redelivery_of IS NULL`,
+}
+
+// searchMigrations add the generated tsvector columns and GIN indexes used by
+// full-text search. Names weigh more than descriptions; labels use the simple
+// configuration because keys and values aren't English words.
+var searchMigrations = []string{
+	`ALTER TABLE services ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_services_search ON services USING GIN (search_vector)`,
+	`ALTER TABLE versions ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', coalesce(version, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_versions_search ON versions USING GIN (search_vector)`,
+	`ALTER TABLE service_labels ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		to_tsvector('simple', key || ' ' || value)) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_service_labels_search ON service_labels USING GIN (search_vector)`,
 }
 
 // ConfigureConnectionPool sets up the connection pool settings for the database.
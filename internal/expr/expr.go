@@ -0,0 +1,392 @@
+// Package expr parses filter expressions such as
+//
+//	created_at>2025-01-01 and version_count>=3 and name~"pay"
+//
+// into an AST. Only whitelisted fields are accepted and values are converted
+// to the type of their field, so the AST can be compiled to parameterized SQL
+// without further checks.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidExpression is returned when a filter expression can't be parsed
+var ErrInvalidExpression = errors.New("invalid filter expression")
+
+const (
+	// MaxLength is the maximum length of an expression
+	MaxLength = 2048
+
+	// MaxComparisons bounds the number of comparisons in an expression
+	MaxComparisons = 32
+)
+
+// Type is the type of a filterable field
+type Type int
+
+const (
+	String Type = iota
+	Number
+	Time
+)
+
+// Fields is the whitelist of filterable fields and their types
+type Fields map[string]Type
+
+// Operator is a comparison operator
+type Operator string
+
+const (
+	Equal          Operator = "="
+	NotEqual       Operator = "!="
+	Greater        Operator = ">"
+	GreaterOrEqual Operator = ">="
+	Less           Operator = "<"
+	LessOrEqual    Operator = "<="
+	Contains       Operator = "~"  // Case-insensitive substring match
+	NotContains    Operator = "!~" // Negated Contains
+	In             Operator = "in"
+)
+
+// operatorsByType lists the operators each field type supports
+var operatorsByType = map[Type][]Operator{
+	String: {Equal, NotEqual, Contains, NotContains, In},
+	Number: {Equal, NotEqual, Greater, GreaterOrEqual, Less, LessOrEqual, In},
+	Time:   {Equal, NotEqual, Greater, GreaterOrEqual, Less, LessOrEqual, In},
+}
+
+// Node is a node of the expression AST: *Logical, *Not or *Comparison
+type Node interface {
+	node()
+}
+
+// Logical combines two expressions with "and" or "or"
+type Logical struct {
+	Or          bool
+	Left, Right Node
+}
+
+// Not negates an expression
+type Not struct {
+	Operand Node
+}
+
+// Comparison compares a field with one value, or with a set for In. Values
+// are strings, float64 or time.Time depending on the field type.
+type Comparison struct {
+	Field    string
+	Operator Operator
+	Values   []any
+}
+
+func (*Logical) node()    {}
+func (*Not) node()        {}
+func (*Comparison) node() {}
+
+// Parse parses an expression over the given fields. Keywords (and, or, not,
+// in) are case-insensitive; "and" binds tighter than "or". An empty
+// expression returns a nil node.
+func Parse(input string, fields Fields) (Node, error) {
+	if len(input) > MaxLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidExpression, MaxLength)
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %s", p.peek())
+	}
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenString {
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// isWordChar reports whether r can be part of a bare word: field names,
+// keywords, numbers, dates and timestamps
+func isWordChar(r byte) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '_' || r == '.' || r == '-' || r == ':' || r == '+'
+}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLeftParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRightParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case c == '"':
+			var b strings.Builder
+			start := i
+			for i++; ; i++ {
+				if i >= len(input) {
+					return nil, fmt.Errorf("%w: unterminated string at position %d", ErrInvalidExpression, start)
+				}
+				if input[i] == '\\' && i+1 < len(input) {
+					i++
+				} else if input[i] == '"' {
+					break
+				}
+				b.WriteByte(input[i])
+			}
+			tokens = append(tokens, token{tokenString, b.String(), start})
+			i++
+		case strings.ContainsRune("=!<>~", rune(c)):
+			op := string(c)
+			if i+1 < len(input) && (input[i+1] == '=' || (c == '!' && input[i+1] == '~')) {
+				op += string(input[i+1])
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("%w: unexpected \"!\" at position %d", ErrInvalidExpression, i)
+			}
+			tokens = append(tokens, token{tokenOperator, op, i})
+			i += len(op)
+		case isWordChar(c):
+			start := i
+			for i < len(input) && isWordChar(input[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, input[start:i], start})
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidExpression, c, i)
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens      []token
+	pos         int
+	fields      Fields
+	comparisons int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	position := "end of input"
+	if !p.done() {
+		position = fmt.Sprintf("position %d", p.peek().pos)
+	}
+	return fmt.Errorf("%w: %s at %s", ErrInvalidExpression, fmt.Sprintf(format, args...), position)
+}
+
+// keyword consumes the next token if it is the given keyword
+func (p *parser) keyword(word string) bool {
+	if !p.done() && p.peek().kind == tokenWord && strings.EqualFold(p.peek().text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	if p.done() || p.peek().kind != kind {
+		if p.done() {
+			return token{}, p.errorf("expected %s", what)
+		}
+		return token{}, p.errorf("expected %s, got %s", what, p.peek())
+	}
+	t := p.peek()
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Or: true, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.keyword("not") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: operand}, nil
+	}
+	if !p.done() && p.peek().kind == tokenLeftParen {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightParen, "\")\""); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	field, err := p.expect(tokenWord, "a field")
+	if err != nil {
+		return nil, err
+	}
+	fieldType, ok := p.fields[field.text]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidExpression, field.text, strings.Join(p.fieldNames(), ", "))
+	}
+
+	p.comparisons++
+	if p.comparisons > MaxComparisons {
+		return nil, fmt.Errorf("%w: more than %d comparisons", ErrInvalidExpression, MaxComparisons)
+	}
+
+	comparison := &Comparison{Field: field.text}
+	if p.keyword("in") {
+		comparison.Operator = In
+		if _, err := p.expect(tokenLeftParen, "\"(\""); err != nil {
+			return nil, err
+		}
+		for {
+			value, err := p.parseValue(field.text, fieldType)
+			if err != nil {
+				return nil, err
+			}
+			comparison.Values = append(comparison.Values, value)
+			if !p.done() && p.peek().kind == tokenComma {
+				p.pos++
+				continue
+			}
+			break
+		}
+		if _, err := p.expect(tokenRightParen, "\")\""); err != nil {
+			return nil, err
+		}
+	} else {
+		operator, err := p.expect(tokenOperator, "an operator")
+		if err != nil {
+			return nil, err
+		}
+		comparison.Operator = Operator(operator.text)
+		if comparison.Operator == "==" {
+			comparison.Operator = Equal
+		}
+		value, err := p.parseValue(field.text, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = []any{value}
+	}
+
+	if !slices.Contains(operatorsByType[fieldType], comparison.Operator) {
+		return nil, fmt.Errorf("%w: operator %q isn't supported by field %q", ErrInvalidExpression, comparison.Operator, field.text)
+	}
+	return comparison, nil
+}
+
+// parseValue reads a quoted or bare value and converts it to the field type
+func (p *parser) parseValue(field string, fieldType Type) (any, error) {
+	if p.done() || (p.peek().kind != tokenWord && p.peek().kind != tokenString) {
+		return nil, p.errorf("expected a value for %q", field)
+	}
+	t := p.peek()
+	p.pos++
+
+	switch fieldType {
+	case Number:
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q needs a number, got %s", ErrInvalidExpression, field, t)
+		}
+		return number, nil
+	case Time:
+		for _, layout := range []string{time.DateOnly, time.RFC3339Nano} {
+			if value, err := time.Parse(layout, t.text); err == nil {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %q needs a date (2025-01-01) or RFC 3339 timestamp, got %s", ErrInvalidExpression, field, t)
+	default:
+		return t.text, nil
+	}
+}
+
+func (p *parser) fieldNames() []string {
+	names := make([]string, 0, len(p.fields))
+	for name := range p.fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
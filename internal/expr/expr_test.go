@@ -0,0 +1,85 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFields = Fields{
+	"name":          String,
+	"version_count": Number,
+	"created_at":    Time,
+}
+
+func TestParse(t *testing.T) {
+	node, err := Parse(`created_at>2025-01-01 and version_count>=3 and name~"pay"`, testFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &Logical{
+		Left: &Logical{
+			Left:  &Comparison{Field: "created_at", Operator: Greater, Values: []any{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			Right: &Comparison{Field: "version_count", Operator: GreaterOrEqual, Values: []any{3.0}},
+		},
+		Right: &Comparison{Field: "name", Operator: Contains, Values: []any{"pay"}},
+	}
+	if !reflect.DeepEqual(node, want) {
+		t.Errorf("got %#v, want %#v", node, want)
+	}
+}
+
+func TestParse_Precedence(t *testing.T) {
+	node, err := Parse(`name=a OR not (name in (b, "c d") AND version_count!=0)`, testFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &Logical{
+		Or:   true,
+		Left: &Comparison{Field: "name", Operator: Equal, Values: []any{"a"}},
+		Right: &Not{Operand: &Logical{
+			Left:  &Comparison{Field: "name", Operator: In, Values: []any{"b", "c d"}},
+			Right: &Comparison{Field: "version_count", Operator: NotEqual, Values: []any{0.0}},
+		}},
+	}
+	if !reflect.DeepEqual(node, want) {
+		t.Errorf("got %#v, want %#v", node, want)
+	}
+
+	if node, err := Parse("  ", testFields); node != nil || err != nil {
+		t.Errorf("expected no expression, got %v, %v", node, err)
+	}
+
+	node, _ = Parse(`name="say \"hi\""`, testFields)
+	if value := node.(*Comparison).Values[0]; value != `say "hi"` {
+		t.Errorf("unexpected escaped value %q", value)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{
+		`password="x"`,
+		`name>"a"`,
+		`version_count~3`,
+		`version_count>many`,
+		`created_at<yesterday`,
+		`name="unterminated`,
+		`name=a and`,
+		`(name=a`,
+		`name=a)`,
+		`name in ()`,
+		`name a`,
+		`name=a; drop table services`,
+		`!name=a`,
+		strings.Repeat("name=a or ", MaxComparisons) + "name=a",
+		strings.Repeat("x", MaxLength+1),
+	} {
+		if _, err := Parse(input, testFields); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("expected ErrInvalidExpression for %q, got %v", input, err)
+		}
+	}
+}
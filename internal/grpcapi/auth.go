@@ -0,0 +1,175 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	servicesv1 "services-api/api/services/v1"
+	"services-api/internal/auth"
+	"services-api/internal/middleware"
+)
+
+// methodScopes are the scopes the methods of the registry require, the same
+// as their REST routes. Methods missing from it, such as health checks and
+// reflection, aren't authenticated.
+var methodScopes = map[string]string{
+	servicesv1.ServiceRegistry_ListServices_FullMethodName:     auth.ScopeRead,
+	servicesv1.ServiceRegistry_GetService_FullMethodName:       auth.ScopeRead,
+	servicesv1.ServiceRegistry_CreateService_FullMethodName:    auth.ScopeWriteServices,
+	servicesv1.ServiceRegistry_UpdateService_FullMethodName:    auth.ScopeWriteServices,
+	servicesv1.ServiceRegistry_DeleteService_FullMethodName:    auth.ScopeWriteServices,
+	servicesv1.ServiceRegistry_CreateVersion_FullMethodName:    auth.ScopeWriteVersions,
+	servicesv1.ServiceRegistry_GetVersion_FullMethodName:       auth.ScopeRead,
+	servicesv1.ServiceRegistry_UpdateVersion_FullMethodName:    auth.ScopeWriteVersions,
+	servicesv1.ServiceRegistry_DeleteVersion_FullMethodName:    auth.ScopeWriteVersions,
+	servicesv1.ServiceRegistry_DeprecateVersion_FullMethodName: auth.ScopeWriteVersions,
+	servicesv1.ServiceRegistry_WatchServices_FullMethodName:    auth.ScopeRead,
+}
+
+// serviceRequest is implemented by the requests about a single service
+type serviceRequest interface {
+	GetServiceId() uint32
+}
+
+// UnaryAuthInterceptor authenticates calls with authn, checks the scopes
+// and service restrictions of the caller and applies the rate limits of
+// limiter like the REST API does
+func UnaryAuthInterceptor(authn *middleware.Authenticator, limiter *middleware.RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scope, ok := methodScopes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		ctx, err := authorize(ctx, authn, limiter, scope, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is the UnaryAuthInterceptor of streaming calls.
+// Their request isn't known yet, so service-restricted callers may only read.
+func StreamAuthInterceptor(authn *middleware.Authenticator, limiter *middleware.RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		scope, ok := methodScopes[info.FullMethod]
+		if !ok {
+			return handler(srv, ss)
+		}
+		ctx, err := authorize(ss.Context(), authn, limiter, scope, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize authenticates the caller and takes a token from its bucket.
+// Like on HTTP, failed authentications are limited per IP address before
+// the credentials are checked.
+func authorize(ctx context.Context, authn *middleware.Authenticator, limiter *middleware.RateLimiter, scope string, req any) (context.Context, error) {
+	ip := peerIP(ctx)
+	if result := limiter.CheckAuthFailures(ctx, ip); !result.Allowed {
+		return nil, tooManyRequests(result)
+	}
+	authenticated, err := authenticate(ctx, authn, scope, req)
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			limiter.RecordAuthFailure(ctx, ip)
+		}
+		return nil, err
+	}
+
+	if result := limiter.Allow(authenticated, middleware.ClientKey(authenticated, ip), scope != auth.ScopeRead); !result.Allowed {
+		return nil, tooManyRequests(result)
+	}
+	return authenticated, nil
+}
+
+// authenticate returns ctx with the identity of the caller, or the status
+// rejecting the call
+func authenticate(ctx context.Context, authn *middleware.Authenticator, scope string, req any) (context.Context, error) {
+	// Callers with a verified client certificate are identified by it
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if identity, ok := auth.FromTLS(&info.State); ok {
+				ctx = auth.NewContext(ctx, identity)
+			}
+		}
+	}
+
+	identity, err := authn.Authenticate(ctx, credentialFromMetadata(ctx))
+	if err != nil {
+		if errors.Is(err, middleware.ErrAuthenticationRequired) {
+			return nil, status.Error(codes.Unauthenticated, "authentication is required")
+		}
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			return nil, status.Error(codes.Unauthenticated, "the provided credentials are invalid, expired or revoked")
+		}
+		slog.Error("Failed to verify credentials", "error", err)
+		return nil, status.Error(codes.Internal, "an error occurred while verifying credentials")
+	}
+
+	// Anonymous callers only get here when authentication is optional, and
+	// may only read
+	if identity == nil {
+		if scope != auth.ScopeRead {
+			return nil, status.Error(codes.Unauthenticated, "authentication is required")
+		}
+		return ctx, nil
+	}
+	if !identity.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "the credentials do not grant the %s scope", scope)
+	}
+	if len(identity.ServiceIDs) > 0 && !serviceAllowed(identity, scope, req) {
+		return nil, status.Error(codes.PermissionDenied, "the credentials are restricted to other services")
+	}
+	return auth.NewContext(ctx, identity), nil
+}
+
+// serviceAllowed checks the service restriction of an identity. Calls that
+// aren't about a single service are only allowed for reads.
+func serviceAllowed(identity *auth.Identity, scope string, req any) bool {
+	if req, ok := req.(serviceRequest); ok && req.GetServiceId() != 0 {
+		return identity.CanAccessService(uint(req.GetServiceId()))
+	}
+	return scope == auth.ScopeRead
+}
+
+// credentialFromMetadata returns the API key or bearer token of a call, sent
+// like the headers of the REST API
+func credentialFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if keys := md.Get(strings.ToLower(middleware.APIKeyHeader)); len(keys) > 0 && keys[0] != "" {
+		return strings.TrimSpace(keys[0])
+	}
+	if values := md.Get("authorization"); len(values) > 0 {
+		scheme, token, found := strings.Cut(values[0], " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// authenticatedStream carries the identity of the caller in its context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
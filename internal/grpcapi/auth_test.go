@@ -0,0 +1,145 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	servicesv1 "services-api/api/services/v1"
+	"services-api/internal/auth"
+	"services-api/internal/config"
+	"services-api/internal/middleware"
+	"services-api/internal/models"
+	"services-api/internal/outbox"
+	"services-api/internal/ratelimit"
+)
+
+type stubAPIKeyVerifier map[string]*auth.Identity
+
+func (s stubAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*auth.Identity, error) {
+	if identity, ok := s[key]; ok {
+		return identity, nil
+	}
+	return nil, auth.ErrInvalidCredentials
+}
+
+func newAuthClient(t *testing.T, rateLimit config.RateLimitConfig) servicesv1.ServiceRegistryClient {
+	verifier := stubAPIKeyVerifier{
+		"sk_ci_secret":     {Subject: "1", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeRead, auth.ScopeWriteVersions}, ServiceIDs: []uint{1}},
+		"sk_reader_secret": {Subject: "2", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeRead}},
+	}
+	services := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			return &models.ServiceResponse{}, nil
+		},
+	}
+	versions := &mockVersionBusiness{
+		CreateVersionFn: func(ctx context.Context, version models.Version) (*models.Version, error) {
+			return &version, nil
+		},
+	}
+	authn := middleware.NewAuthenticator(config.AuthConfig{JWTSecret: "secret", Required: true}, verifier)
+	limiter := middleware.NewRateLimiter(rateLimit, ratelimit.NewMemoryStore())
+	return newTestClient(t, NewServer(services, versions, outbox.NewStream(&memoryEventLog{}, 0)), authn, limiter)
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client := newAuthClient(t, config.RateLimitConfig{})
+
+	_, err := client.ListServices(context.Background(), &servicesv1.ListServicesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "credentials are required")
+
+	_, err = client.ListServices(withKey("sk_unknown"), &servicesv1.ListServicesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.ListServices(withKey("sk_reader_secret"), &servicesv1.ListServicesRequest{})
+	assert.NoError(t, err)
+
+	// Bearer API keys are accepted as well
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer sk_ci_secret")
+	_, err = client.CreateVersion(ctx, &servicesv1.CreateVersionRequest{ServiceId: 1, Version: "1.0.0"})
+	assert.NoError(t, err)
+
+	_, err = client.CreateVersion(withKey("sk_reader_secret"), &servicesv1.CreateVersionRequest{ServiceId: 1, Version: "1.0.0"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "the write:versions scope is required")
+
+	_, err = client.CreateVersion(withKey("sk_ci_secret"), &servicesv1.CreateVersionRequest{ServiceId: 2, Version: "1.0.0"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "the key is restricted to service 1")
+
+	// Service-restricted keys may still list
+	_, err = client.ListServices(withKey("sk_ci_secret"), &servicesv1.ListServicesRequest{})
+	assert.NoError(t, err)
+}
+
+func TestAuthInterceptor_OptionalAuth(t *testing.T) {
+	services := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			return &models.ServiceResponse{}, nil
+		},
+		DeleteServiceFn: func(ctx context.Context, id uint, force bool) error {
+			return nil
+		},
+	}
+	versions := &mockVersionBusiness{
+		CreateVersionFn: func(ctx context.Context, version models.Version) (*models.Version, error) {
+			return &version, nil
+		},
+	}
+	client := newAnonymousClient(t, services, versions, outbox.NewStream(&memoryEventLog{}, 0))
+
+	_, err := client.ListServices(context.Background(), &servicesv1.ListServicesRequest{})
+	assert.NoError(t, err, "anonymous callers may read")
+
+	// ...but not write
+	_, err = client.CreateVersion(context.Background(), &servicesv1.CreateVersionRequest{ServiceId: 1, Version: "1.0.0"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.UpdateService(context.Background(), &servicesv1.UpdateServiceRequest{ServiceId: 1, Name: "payments"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.DeleteService(context.Background(), &servicesv1.DeleteServiceRequest{ServiceId: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.DeleteService(withKey(adminKey), &servicesv1.DeleteServiceRequest{ServiceId: 1})
+	assert.NoError(t, err)
+}
+
+func TestAuthInterceptor_Stream(t *testing.T) {
+	client := newAuthClient(t, config.RateLimitConfig{})
+
+	watch, err := client.WatchServices(context.Background(), &servicesv1.WatchServicesRequest{})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthInterceptor_RateLimits(t *testing.T) {
+	client := newAuthClient(t, config.RateLimitConfig{
+		Enabled:                true,
+		RequestsPerSecond:      1,
+		Burst:                  2,
+		WriteRequestsPerSecond: 1,
+		WriteBurst:             1,
+	})
+
+	_, err := client.CreateVersion(withKey("sk_ci_secret"), &servicesv1.CreateVersionRequest{ServiceId: 1, Version: "1.0.0"})
+	assert.NoError(t, err)
+	_, err = client.CreateVersion(withKey("sk_ci_secret"), &servicesv1.CreateVersionRequest{ServiceId: 1, Version: "1.0.1"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "the write limit applies")
+
+	// Failed authentications are limited per IP address, even with valid
+	// credentials once the bucket is empty
+	for i := 0; i < 2; i++ {
+		_, err = client.ListServices(withKey("sk_guess"), &servicesv1.ListServicesRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	_, err = client.ListServices(withKey("sk_reader_secret"), &servicesv1.ListServicesRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -0,0 +1,128 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	servicesv1 "services-api/api/services/v1"
+	"services-api/internal/models"
+)
+
+// serviceToProto converts a service with the versions it was loaded with
+func serviceToProto(service *models.Service) (*servicesv1.Service, error) {
+	metadata, err := metadataToProto(service.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &servicesv1.Service{
+		Id:           uint32(service.ID),
+		Name:         service.Name,
+		Description:  service.Description,
+		Labels:       service.Labels,
+		Metadata:     metadata,
+		VersionCount: int32(service.VersionCount),
+		Versions:     versionsToProto(service.Versions),
+		ManagedBy:    service.ManagedBy,
+		CreatedAt:    timestamppb.New(service.CreatedAt),
+		UpdatedAt:    timestamppb.New(service.UpdatedAt),
+	}, nil
+}
+
+// serviceModelToProto converts a service of a list
+func serviceModelToProto(service models.ServiceModel) (*servicesv1.Service, error) {
+	metadata, err := metadataToProto(service.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &servicesv1.Service{
+		Id:           uint32(service.ID),
+		Name:         service.Name,
+		Description:  service.Description,
+		Labels:       service.Labels,
+		Metadata:     metadata,
+		VersionCount: int32(service.VersionCount),
+		Versions:     versionsToProto(service.Versions),
+		ManagedBy:    service.ManagedBy,
+		CreatedAt:    timestamppb.New(service.CreatedAt),
+		UpdatedAt:    timestamppb.New(service.UpdatedAt),
+	}, nil
+}
+
+func versionToProto(version *models.Version) *servicesv1.Version {
+	return &servicesv1.Version{
+		Id:          uint32(version.ID),
+		ServiceId:   uint32(version.ServiceID),
+		Version:     version.Version,
+		Description: version.Description,
+		IsActive:    version.IsActive,
+		CreatedAt:   timestamppb.New(version.CreatedAt),
+		UpdatedAt:   timestamppb.New(version.UpdatedAt),
+	}
+}
+
+func versionsToProto(versions []models.Version) []*servicesv1.Version {
+	if len(versions) == 0 {
+		return nil
+	}
+	converted := make([]*servicesv1.Version, len(versions))
+	for i := range versions {
+		converted[i] = versionToProto(&versions[i])
+	}
+	return converted
+}
+
+// metadataToProto converts custom field values, nil when there are none
+func metadataToProto(metadata map[string]any) (*structpb.Struct, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	converted, err := structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert metadata: %w", err)
+	}
+	return converted, nil
+}
+
+// metadataFromProto converts custom field values, nil when not set so
+// updates keep the current values
+func metadataFromProto(metadata *structpb.Struct) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+	return metadata.AsMap()
+}
+
+// eventToProto converts a logged event, decoding the service or version it
+// carries
+func eventToProto(event models.OutboxEvent) (*servicesv1.ServiceEvent, error) {
+	converted := &servicesv1.ServiceEvent{
+		Seq:        event.ID,
+		Id:         event.EventID,
+		Type:       event.Type,
+		ServiceId:  uint32(event.ServiceID),
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}
+	if strings.HasPrefix(event.Type, "version.") {
+		var version models.Version
+		if err := json.Unmarshal([]byte(event.Data), &version); err != nil {
+			return nil, fmt.Errorf("failed to decode event %s: %w", event.EventID, err)
+		}
+		converted.Data = &servicesv1.ServiceEvent_Version{Version: versionToProto(&version)}
+		return converted, nil
+	}
+
+	var service models.Service
+	if err := json.Unmarshal([]byte(event.Data), &service); err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", event.EventID, err)
+	}
+	data, err := serviceToProto(&service)
+	if err != nil {
+		return nil, err
+	}
+	converted.Data = &servicesv1.ServiceEvent_Service{Service: data}
+	return converted, nil
+}
@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"services-api/internal/business"
+)
+
+// toStatus converts an error of the business layer to a gRPC status with the
+// code matching the HTTP status the REST API responds with. Unexpected
+// errors are logged and reported as Internal without their details.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, business.ErrServiceNotFound),
+		errors.Is(err, business.ErrVersionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, business.ErrInvalidService),
+		errors.Is(err, business.ErrInvalidFilter),
+		errors.Is(err, business.ErrInvalidSort),
+		errors.Is(err, business.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, business.ErrServiceExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, business.ErrServiceHasDependents):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		slog.Error("gRPC request failed", "error", err)
+		return status.Error(codes.Internal, "an internal error occurred")
+	}
+}
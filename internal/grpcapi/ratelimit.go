@@ -0,0 +1,31 @@
+package grpcapi
+
+import (
+	"context"
+	"math"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"services-api/internal/ratelimit"
+)
+
+// tooManyRequests is the status of calls rejected by the rate limits
+func tooManyRequests(result ratelimit.Result) error {
+	return status.Errorf(codes.ResourceExhausted, "too many requests, retry after %d seconds", int(math.Ceil(result.RetryAfter.Seconds())))
+}
+
+// peerIP returns the IP address of the caller
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
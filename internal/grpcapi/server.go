@@ -0,0 +1,318 @@
+// Package grpcapi serves the services and versions of the catalog over gRPC,
+// alongside the REST API, with the same business logic.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	servicesv1 "services-api/api/services/v1"
+	"services-api/internal/business"
+	"services-api/internal/expr"
+	"services-api/internal/labels"
+	"services-api/internal/models"
+	"services-api/internal/outbox"
+)
+
+const (
+	// defaultPageSize is the page size when none is requested, like the
+	// limit of the REST API
+	defaultPageSize = 10
+
+	// maxPageSize is the largest page size served
+	maxPageSize = 100
+
+	// listVersionLimit is the number of versions of each listed service
+	// included with include_versions, and of the service GetService returns
+	listVersionLimit = 10
+
+	// replayBatchSize is the number of missed events read at once
+	replayBatchSize = 500
+)
+
+// Server implements the ServiceRegistry service
+type Server struct {
+	servicesv1.UnimplementedServiceRegistryServer
+
+	services business.BusinessService
+	versions business.VersionBusiness
+	stream   *outbox.Stream
+}
+
+// NewServer creates a server over the service and version business logic.
+// WatchServices follows stream.
+func NewServer(services business.BusinessService, versions business.VersionBusiness, stream *outbox.Stream) *Server {
+	return &Server{
+		services: services,
+		versions: versions,
+		stream:   stream,
+	}
+}
+
+// ListServices returns a page of services matching the filters
+func (s *Server) ListServices(ctx context.Context, req *servicesv1.ListServicesRequest) (*servicesv1.ListServicesResponse, error) {
+	filter := models.ServiceFilter{
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		Sort:         req.GetSort(),
+		Order:        "asc",
+		Page:         1,
+		Limit:        int(req.GetPageSize()),
+		Cursor:       req.GetPageToken(),
+		Metadata:     req.GetMetadata(),
+		IncludeTotal: req.GetIncludeTotal(),
+	}
+	if filter.Sort == "" {
+		filter.Sort = "name"
+	}
+	if filter.Limit < 1 || filter.Limit > maxPageSize {
+		filter.Limit = defaultPageSize
+	}
+	if req.GetIncludeVersions() {
+		filter.Expand = models.ServiceExpand{Versions: true, VersionLimit: listVersionLimit, VersionSort: "created_at"}
+	}
+
+	selector, err := labels.Parse(req.GetSelector())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid label selector: %v", err)
+	}
+	filter.Selector = selector
+	expression, err := expr.Parse(req.GetFilter(), models.ServiceFilterFields)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid filter expression: %v", err)
+	}
+	filter.Expression = expression
+
+	result, err := s.services.ListServices(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &servicesv1.ListServicesResponse{
+		Services:      make([]*servicesv1.Service, 0, len(result.Services)),
+		NextPageToken: result.Pagination.NextCursor,
+		PrevPageToken: result.Pagination.PrevCursor,
+	}
+	for _, service := range result.Services {
+		converted, err := serviceModelToProto(service)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		resp.Services = append(resp.Services, converted)
+	}
+	if total := result.Pagination.TotalItems; total != nil {
+		totalSize := int32(*total)
+		resp.TotalSize = &totalSize
+	}
+	return resp, nil
+}
+
+// GetService returns a service with its first versions, like the REST API
+func (s *Server) GetService(ctx context.Context, req *servicesv1.GetServiceRequest) (*servicesv1.Service, error) {
+	service, err := s.services.GetService(ctx, uint(req.GetServiceId()), models.ServiceExpand{Versions: true, VersionLimit: listVersionLimit, VersionSort: "created_at"})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(serviceToProto(service))
+}
+
+// CreateService creates a service
+func (s *Server) CreateService(ctx context.Context, req *servicesv1.CreateServiceRequest) (*servicesv1.Service, error) {
+	service, err := s.services.CreateService(ctx, models.Service{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Labels:      req.GetLabels(),
+		Metadata:    metadataFromProto(req.GetMetadata()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(serviceToProto(service))
+}
+
+// UpdateService updates a service, keeping its labels and metadata when
+// they aren't set
+func (s *Server) UpdateService(ctx context.Context, req *servicesv1.UpdateServiceRequest) (*servicesv1.Service, error) {
+	update := models.Service{
+		ID:          uint(req.GetServiceId()),
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Metadata:    metadataFromProto(req.GetMetadata()),
+	}
+	if req.GetLabels() != nil {
+		// An empty map, unlike nil, removes all labels
+		update.Labels = req.GetLabels().GetValues()
+		if update.Labels == nil {
+			update.Labels = map[string]string{}
+		}
+	}
+
+	service, err := s.services.UpdateService(ctx, update)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(serviceToProto(service))
+}
+
+// DeleteService deletes a service with its versions
+func (s *Server) DeleteService(ctx context.Context, req *servicesv1.DeleteServiceRequest) (*emptypb.Empty, error) {
+	if err := s.services.DeleteService(ctx, uint(req.GetServiceId()), req.GetForce()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// CreateVersion adds a version to a service
+func (s *Server) CreateVersion(ctx context.Context, req *servicesv1.CreateVersionRequest) (*servicesv1.Version, error) {
+	version, err := s.versions.CreateVersion(ctx, models.Version{
+		ServiceID:   uint(req.GetServiceId()),
+		Version:     req.GetVersion(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return versionToProto(version), nil
+}
+
+// GetVersion returns a version of a service
+func (s *Server) GetVersion(ctx context.Context, req *servicesv1.GetVersionRequest) (*servicesv1.Version, error) {
+	version, err := s.versions.GetVersion(ctx, uint(req.GetServiceId()), uint(req.GetVersionId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return versionToProto(version), nil
+}
+
+// UpdateVersion updates a version of a service
+func (s *Server) UpdateVersion(ctx context.Context, req *servicesv1.UpdateVersionRequest) (*servicesv1.Version, error) {
+	version, err := s.versions.UpdateVersion(ctx, models.Version{
+		ID:          uint(req.GetVersionId()),
+		ServiceID:   uint(req.GetServiceId()),
+		Version:     req.GetVersion(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return versionToProto(version), nil
+}
+
+// DeleteVersion deletes a version of a service
+func (s *Server) DeleteVersion(ctx context.Context, req *servicesv1.DeleteVersionRequest) (*emptypb.Empty, error) {
+	if err := s.versions.DeleteVersion(ctx, uint(req.GetVersionId()), uint(req.GetServiceId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeprecateVersion marks a version as no longer active
+func (s *Server) DeprecateVersion(ctx context.Context, req *servicesv1.DeprecateVersionRequest) (*servicesv1.Version, error) {
+	version, err := s.versions.DeprecateVersion(ctx, uint(req.GetServiceId()), uint(req.GetVersionId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return versionToProto(version), nil
+}
+
+// WatchServices streams the events matching the request as they are
+// committed, after replaying the logged events following after_seq. Like
+// the SSE stream, watchers falling too far behind are dropped and resume
+// with the seq of the last event they received. When events following
+// after_seq were pruned the watch ends with OutOfRange, and the watcher
+// should reload the catalog and watch again without after_seq.
+func (s *Server) WatchServices(req *servicesv1.WatchServicesRequest, stream grpc.ServerStreamingServer[servicesv1.ServiceEvent]) error {
+	for _, eventType := range req.GetTypes() {
+		if !slices.Contains(models.EventTypes, eventType) {
+			return status.Errorf(codes.InvalidArgument, "unknown event type %q, event types must be one of %s", eventType, strings.Join(models.EventTypes, ", "))
+		}
+	}
+	selector, err := labels.Parse(req.GetSelector())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid label selector: %v", err)
+	}
+	var serviceIDs []uint
+	for _, id := range req.GetServiceIds() {
+		serviceIDs = append(serviceIDs, uint(id))
+	}
+	filter := business.NewEventFilter(s.services, serviceIDs, req.GetTypes(), selector)
+
+	// Subscribe before replaying so nothing committed in between is missed
+	sub := s.stream.Subscribe()
+	defer sub.Close()
+
+	ctx := stream.Context()
+	last := req.GetAfterSeq()
+	if req.AfterSeq != nil {
+		for {
+			events, err := s.stream.Replay(ctx, last, replayBatchSize)
+			if errors.Is(err, outbox.ErrReplayExpired) {
+				return status.Errorf(codes.OutOfRange, "%v, reload the catalog and watch without after_seq", err)
+			}
+			if err != nil {
+				return toStatus(err)
+			}
+			for _, event := range events {
+				if err := s.send(stream, filter, event); err != nil {
+					return err
+				}
+				last = event.ID
+			}
+			if len(events) < replayBatchSize {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-sub.Events():
+			if !ok {
+				return status.Errorf(codes.Unavailable, "the watch fell behind or the server is stopping, resume with after_seq %d", last)
+			}
+			if event.ID <= last {
+				continue
+			}
+			if err := s.send(stream, filter, event); err != nil {
+				return err
+			}
+			last = event.ID
+		}
+	}
+}
+
+// send writes event to the stream if it matches filter
+func (s *Server) send(stream grpc.ServerStreamingServer[servicesv1.ServiceEvent], filter *business.EventFilter, event models.OutboxEvent) error {
+	if !filter.Matches(stream.Context(), event) {
+		return nil
+	}
+	converted, err := eventToProto(event)
+	if err != nil {
+		// Skip the event rather than ending every watch on it
+		slog.Error("Failed to convert event", "seq", event.ID, "error", err)
+		return nil
+	}
+	if err := stream.Send(converted); err != nil {
+		return fmt.Errorf("failed to send event %d: %w", event.ID, err)
+	}
+	return nil
+}
+
+// toProto reports conversion errors as Internal
+func toProto(service *servicesv1.Service, err error) (*servicesv1.Service, error) {
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return service, nil
+}
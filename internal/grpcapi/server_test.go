@@ -0,0 +1,407 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	servicesv1 "services-api/api/services/v1"
+	"services-api/internal/auth"
+	"services-api/internal/business"
+	"services-api/internal/config"
+	"services-api/internal/middleware"
+	"services-api/internal/models"
+	"services-api/internal/outbox"
+	"services-api/internal/ratelimit"
+)
+
+type mockBusinessService struct {
+	ListServicesFn    func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error)
+	GetServiceFn      func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error)
+	CreateServiceFn   func(ctx context.Context, service models.Service) (*models.Service, error)
+	UpdateServiceFn   func(ctx context.Context, service models.Service) (*models.Service, error)
+	DeleteServiceFn   func(ctx context.Context, id uint, force bool) error
+	ListLabelFacetsFn func(ctx context.Context, key string) ([]models.LabelFacet, error)
+}
+
+func (m *mockBusinessService) ListServices(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+	return m.ListServicesFn(ctx, filter)
+}
+func (m *mockBusinessService) GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+	return m.GetServiceFn(ctx, id, expand)
+}
+func (m *mockBusinessService) CreateService(ctx context.Context, service models.Service) (*models.Service, error) {
+	return m.CreateServiceFn(ctx, service)
+}
+func (m *mockBusinessService) UpdateService(ctx context.Context, service models.Service) (*models.Service, error) {
+	return m.UpdateServiceFn(ctx, service)
+}
+func (m *mockBusinessService) DeleteService(ctx context.Context, id uint, force bool) error {
+	return m.DeleteServiceFn(ctx, id, force)
+}
+func (m *mockBusinessService) ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error) {
+	return m.ListLabelFacetsFn(ctx, key)
+}
+
+type mockVersionBusiness struct {
+	CreateVersionFn    func(ctx context.Context, version models.Version) (*models.Version, error)
+	GetVersionFn       func(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error)
+	UpdateVersionFn    func(ctx context.Context, version models.Version) (*models.Version, error)
+	DeleteVersionFn    func(ctx context.Context, versionId uint, serviceId uint) error
+	DeprecateVersionFn func(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error)
+	ListVersionsFn     func(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error)
+}
+
+func (m *mockVersionBusiness) CreateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
+	return m.CreateVersionFn(ctx, version)
+}
+func (m *mockVersionBusiness) GetVersion(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error) {
+	return m.GetVersionFn(ctx, serviceId, versionId)
+}
+func (m *mockVersionBusiness) UpdateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
+	return m.UpdateVersionFn(ctx, version)
+}
+func (m *mockVersionBusiness) DeleteVersion(ctx context.Context, versionId uint, serviceId uint) error {
+	return m.DeleteVersionFn(ctx, versionId, serviceId)
+}
+func (m *mockVersionBusiness) DeprecateVersion(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error) {
+	return m.DeprecateVersionFn(ctx, serviceId, versionId)
+}
+func (m *mockVersionBusiness) ListVersions(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error) {
+	return m.ListVersionsFn(ctx, filter)
+}
+
+// memoryEventLog is an in-memory OutboxRepository for the event stream
+type memoryEventLog struct {
+	mu            sync.Mutex
+	events        []models.OutboxEvent
+	prunedThrough uint64
+}
+
+func (m *memoryEventLog) AppendEvents(ctx context.Context, events []models.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, event := range events {
+		event.ID = uint64(len(m.events) + 1)
+		m.events = append(m.events, event)
+	}
+	return nil
+}
+func (m *memoryEventLog) ListEventsAfter(ctx context.Context, after uint64, limit int) ([]models.OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var events []models.OutboxEvent
+	for _, event := range m.events {
+		if event.ID > after && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+func (m *memoryEventLog) LatestEventID(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return uint64(len(m.events)), nil
+}
+func (m *memoryEventLog) ProcessPending(ctx context.Context, now time.Time, limit int, fn func(events []models.OutboxEvent)) (int, error) {
+	panic("not used")
+}
+func (m *memoryEventLog) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	panic("not used")
+}
+func (m *memoryEventLog) PrunedThrough(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.prunedThrough, nil
+}
+
+// newTestClient serves srv over an in-memory connection with the auth
+// interceptors of authn and limiter and returns a client of it
+func newTestClient(t *testing.T, srv *Server, authn *middleware.Authenticator, limiter *middleware.RateLimiter) servicesv1.ServiceRegistryClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryAuthInterceptor(authn, limiter)),
+		grpc.ChainStreamInterceptor(StreamAuthInterceptor(authn, limiter)),
+	)
+	servicesv1.RegisterServiceRegistryServer(grpcServer, srv)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return servicesv1.NewServiceRegistryClient(conn)
+}
+
+// newAnonymousClient returns a client of a server with optional authentication
+func newAnonymousClient(t *testing.T, services business.BusinessService, versions business.VersionBusiness, stream *outbox.Stream) servicesv1.ServiceRegistryClient {
+	authn := middleware.NewAuthenticator(config.AuthConfig{}, stubAPIKeyVerifier{
+		adminKey: {Subject: "1", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeAdmin}},
+	})
+	limiter := middleware.NewRateLimiter(config.RateLimitConfig{}, ratelimit.NewMemoryStore())
+	return newTestClient(t, NewServer(services, versions, stream), authn, limiter)
+}
+
+// adminKey is accepted by newAnonymousClient, for the calls that need
+// credentials even when authentication is optional
+const adminKey = "sk_admin_secret"
+
+func TestListServices(t *testing.T) {
+	var got models.ServiceFilter
+	total := 42
+	services := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			got = filter
+			return &models.ServiceResponse{
+				Services: []models.ServiceModel{{
+					ID:           1,
+					Name:         "payments",
+					Labels:       map[string]string{"tier": "critical"},
+					Metadata:     map[string]any{"sla_tier": "gold"},
+					VersionCount: 2,
+				}},
+				Pagination: models.Pagination{TotalItems: &total, NextCursor: "n3xt"},
+			}, nil
+		},
+	}
+	client := newAnonymousClient(t, services, &mockVersionBusiness{}, nil)
+
+	resp, err := client.ListServices(context.Background(), &servicesv1.ListServicesRequest{
+		Name:            "pay",
+		Selector:        "tier=critical",
+		Filter:          "version_count>=2",
+		Sort:            "-updated_at",
+		PageSize:        500,
+		PageToken:       "abc",
+		Metadata:        map[string]string{"sla_tier": "gold"},
+		IncludeVersions: true,
+		IncludeTotal:    true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "pay", got.Name)
+	assert.Equal(t, "-updated_at", got.Sort)
+	assert.Equal(t, 10, got.Limit, "page sizes above the maximum fall back to the default")
+	assert.Equal(t, "abc", got.Cursor)
+	assert.Equal(t, map[string]string{"sla_tier": "gold"}, got.Metadata)
+	assert.True(t, got.IncludeTotal)
+	assert.True(t, got.Expand.Versions)
+	assert.Len(t, got.Selector, 1)
+	assert.NotNil(t, got.Expression)
+
+	require.Len(t, resp.Services, 1)
+	assert.Equal(t, "payments", resp.Services[0].Name)
+	assert.Equal(t, "critical", resp.Services[0].Labels["tier"])
+	assert.Equal(t, "gold", resp.Services[0].Metadata.AsMap()["sla_tier"])
+	assert.Equal(t, "n3xt", resp.NextPageToken)
+	assert.Equal(t, int32(42), resp.GetTotalSize())
+}
+
+func TestListServices_InvalidArguments(t *testing.T) {
+	services := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			return nil, fmt.Errorf("%w: malformed", business.ErrInvalidCursor)
+		},
+	}
+	client := newAnonymousClient(t, services, &mockVersionBusiness{}, nil)
+
+	for _, req := range []*servicesv1.ListServicesRequest{
+		{Selector: "tier in (critical"},
+		{Filter: "unknown_field=1"},
+		{PageToken: "bad"},
+	} {
+		_, err := client.ListServices(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "request %v", req)
+	}
+}
+
+func TestGetService_BoundsVersions(t *testing.T) {
+	var got models.ServiceExpand
+	services := &mockBusinessService{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			got = expand
+			return &models.Service{ID: id, Name: "payments", Versions: []models.Version{{ID: 7, ServiceID: id, Version: "1.0.0"}}}, nil
+		},
+	}
+	client := newAnonymousClient(t, services, &mockVersionBusiness{}, nil)
+
+	service, err := client.GetService(context.Background(), &servicesv1.GetServiceRequest{ServiceId: 1})
+	require.NoError(t, err)
+	assert.Len(t, service.Versions, 1)
+	assert.Equal(t, models.ServiceExpand{Versions: true, VersionLimit: listVersionLimit, VersionSort: "created_at"}, got)
+}
+
+func TestUpdateService_KeepsUnsetLabelsAndMetadata(t *testing.T) {
+	var got models.Service
+	services := &mockBusinessService{
+		UpdateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			got = service
+			return &service, nil
+		},
+	}
+	client := newAnonymousClient(t, services, &mockVersionBusiness{}, nil)
+
+	_, err := client.UpdateService(withKey(adminKey), &servicesv1.UpdateServiceRequest{ServiceId: 3, Name: "payments"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), got.ID)
+	assert.Nil(t, got.Labels)
+	assert.Nil(t, got.Metadata)
+
+	metadata, err := structpb.NewStruct(map[string]any{"sla_tier": "gold"})
+	require.NoError(t, err)
+	_, err = client.UpdateService(withKey(adminKey), &servicesv1.UpdateServiceRequest{
+		ServiceId: 3,
+		Name:      "payments",
+		Labels:    &servicesv1.Labels{},
+		Metadata:  metadata,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{}, got.Labels, "empty labels remove all labels")
+	assert.Equal(t, map[string]any{"sla_tier": "gold"}, got.Metadata)
+}
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{business.ErrServiceNotFound, codes.NotFound},
+		{business.ErrVersionNotFound, codes.NotFound},
+		{fmt.Errorf("%w: name is required", business.ErrInvalidService), codes.InvalidArgument},
+		{business.ErrServiceHasDependents, codes.FailedPrecondition},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{errors.New("connection refused"), codes.Internal},
+	}
+	for _, tc := range tests {
+		services := &mockBusinessService{
+			DeleteServiceFn: func(ctx context.Context, id uint, force bool) error { return tc.err },
+		}
+		client := newAnonymousClient(t, services, &mockVersionBusiness{}, nil)
+
+		_, err := client.DeleteService(withKey(adminKey), &servicesv1.DeleteServiceRequest{ServiceId: 1})
+		assert.Equal(t, tc.want, status.Code(err), "error %v", tc.err)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	var calls []string
+	versions := &mockVersionBusiness{
+		GetVersionFn: func(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error) {
+			calls = append(calls, fmt.Sprintf("get %d/%d", serviceId, versionId))
+			return &models.Version{ID: versionId, ServiceID: serviceId, Version: "1.0.0", IsActive: true}, nil
+		},
+		DeleteVersionFn: func(ctx context.Context, versionId uint, serviceId uint) error {
+			calls = append(calls, fmt.Sprintf("delete %d/%d", serviceId, versionId))
+			return business.ErrVersionNotFound
+		},
+		DeprecateVersionFn: func(ctx context.Context, serviceId uint, versionId uint) (*models.Version, error) {
+			calls = append(calls, fmt.Sprintf("deprecate %d/%d", serviceId, versionId))
+			return &models.Version{ID: versionId, ServiceID: serviceId, Version: "1.0.0"}, nil
+		},
+	}
+	client := newAnonymousClient(t, &mockBusinessService{}, versions, nil)
+
+	version, err := client.GetVersion(context.Background(), &servicesv1.GetVersionRequest{ServiceId: 1, VersionId: 7})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(7), version.Id)
+	assert.True(t, version.IsActive)
+
+	_, err = client.DeleteVersion(withKey(adminKey), &servicesv1.DeleteVersionRequest{ServiceId: 1, VersionId: 7})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	version, err = client.DeprecateVersion(withKey(adminKey), &servicesv1.DeprecateVersionRequest{ServiceId: 1, VersionId: 7})
+	require.NoError(t, err)
+	assert.False(t, version.IsActive)
+
+	assert.Equal(t, []string{"get 1/7", "delete 1/7", "deprecate 1/7"}, calls)
+}
+
+func TestWatchServices(t *testing.T) {
+	log := &memoryEventLog{}
+	log.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", Type: models.EventServiceCreated, ServiceID: 1, Data: `{"id":1,"name":"payments"}`},
+		{EventID: "evt_2", Type: models.EventVersionCreated, ServiceID: 1, Data: `{"id":5,"service_id":1,"version":"1.0.0"}`},
+	})
+	stream := outbox.NewStream(log, 5*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	client := newAnonymousClient(t, &mockBusinessService{}, &mockVersionBusiness{}, stream)
+	after := uint64(0)
+	watch, err := client.WatchServices(ctx, &servicesv1.WatchServicesRequest{
+		Types:    []string{models.EventVersionCreated},
+		AfterSeq: &after,
+	})
+	require.NoError(t, err)
+
+	// The logged version is replayed
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), event.Seq)
+	assert.Equal(t, "1.0.0", event.GetVersion().GetVersion())
+
+	// New events are streamed once committed, filtered by type
+	log.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_3", Type: models.EventServiceUpdated, ServiceID: 1, Data: `{"id":1,"name":"payments"}`},
+		{EventID: "evt_4", Type: models.EventVersionCreated, ServiceID: 1, Data: `{"id":6,"service_id":1,"version":"1.1.0"}`},
+	})
+	event, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "evt_4", event.Id)
+	assert.Equal(t, uint32(6), event.GetVersion().GetId())
+}
+
+func TestWatchServices_Expired(t *testing.T) {
+	log := &memoryEventLog{prunedThrough: 2}
+	log.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", Type: models.EventServiceCreated, ServiceID: 1, Data: `{"id":1,"name":"payments"}`},
+		{EventID: "evt_2", Type: models.EventServiceCreated, ServiceID: 2, Data: `{"id":2,"name":"users"}`},
+		{EventID: "evt_3", Type: models.EventServiceCreated, ServiceID: 3, Data: `{"id":3,"name":"billing"}`},
+	})
+	client := newAnonymousClient(t, &mockBusinessService{}, &mockVersionBusiness{}, outbox.NewStream(log, time.Hour))
+
+	after := uint64(1)
+	watch, err := client.WatchServices(context.Background(), &servicesv1.WatchServicesRequest{AfterSeq: &after})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.OutOfRange, status.Code(err), "event 2 was pruned")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	after = 2
+	watch, err = client.WatchServices(ctx, &servicesv1.WatchServicesRequest{AfterSeq: &after})
+	require.NoError(t, err)
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "evt_3", event.Id)
+}
+
+func TestWatchServices_InvalidType(t *testing.T) {
+	stream := outbox.NewStream(&memoryEventLog{}, time.Hour)
+	client := newAnonymousClient(t, &mockBusinessService{}, &mockVersionBusiness{}, stream)
+
+	watch, err := client.WatchServices(context.Background(), &servicesv1.WatchServicesRequest{Types: []string{"service.renamed"}})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+// APIKeyHandler handles API key management requests
+type APIKeyHandler struct {
+	apiKeyBusiness business.APIKeyBusiness
+}
+
+// NewAPIKeyHandler creates a new API key handler with the required business logic.
+func NewAPIKeyHandler(apiKeyBusiness business.APIKeyBusiness) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyBusiness: apiKeyBusiness,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Create an API key for a machine client. The plaintext key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param apiKey body models.APIKeyRequest true "API key details"
+// @Success 201 {object} models.APIKeyWithSecret "Created API key including the plaintext key"
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Admin scope required"
+// @Failure 500 {object} ErrorResponse "Failed to create API key"
+// @Security ApiKeyAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	key, err := h.apiKeyBusiness.CreateAPIKey(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, business.ErrInvalidAPIKeyRequest) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_api_key_request",
+				Message: "Invalid API key request",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to create API key",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List all API keys. Secrets are never returned.
+// @Tags api-keys
+// @Produce json
+// @Success 200 {array} models.APIKey "API keys"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Admin scope required"
+// @Failure 500 {object} ErrorResponse "Failed to list API keys"
+// @Security ApiKeyAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyBusiness.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to list API keys",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Replace the secret of an API key. The old key stops working immediately and the new plaintext key is only returned in this response.
+// @Tags api-keys
+// @Produce json
+// @Param kid path integer true "API key ID"
+// @Success 200 {object} models.APIKeyWithSecret "Rotated API key including the new plaintext key"
+// @Failure 400 {object} ErrorResponse "Invalid API key ID"
+// @Failure 404 {object} ErrorResponse "API key not found"
+// @Failure 500 {object} ErrorResponse "Failed to rotate API key"
+// @Security ApiKeyAuth
+// @Router /api-keys/{kid}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	keyId, err := strconv.ParseUint(c.Param("kid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_api_key_id",
+			Message: "Invalid API key ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	key, err := h.apiKeyBusiness.RotateAPIKey(c.Request.Context(), uint(keyId))
+	if err != nil {
+		if errors.Is(err, business.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:    "api_key_not_found",
+				Message: "API key not found",
+				Details: "The requested API key does not exist or has been revoked",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to rotate API key",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke an API key so it can no longer be used
+// @Tags api-keys
+// @Param kid path integer true "API key ID"
+// @Success 204 "API key revoked"
+// @Failure 400 {object} ErrorResponse "Invalid API key ID"
+// @Failure 404 {object} ErrorResponse "API key not found"
+// @Failure 500 {object} ErrorResponse "Failed to revoke API key"
+// @Security ApiKeyAuth
+// @Router /api-keys/{kid} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyId, err := strconv.ParseUint(c.Param("kid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_api_key_id",
+			Message: "Invalid API key ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	err = h.apiKeyBusiness.RevokeAPIKey(c.Request.Context(), uint(keyId))
+	if err != nil {
+		if errors.Is(err, business.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:    "api_key_not_found",
+				Message: "API key not found",
+				Details: "The requested API key does not exist or has already been revoked",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to revoke API key",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
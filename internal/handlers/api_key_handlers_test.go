@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"services-api/internal/auth"
+	"services-api/internal/business"
+	"services-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAPIKeyBusiness struct {
+	CreateAPIKeyFn func(ctx context.Context, req models.APIKeyRequest) (*models.APIKeyWithSecret, error)
+	ListAPIKeysFn  func(ctx context.Context) ([]models.APIKey, error)
+	RotateAPIKeyFn func(ctx context.Context, id uint) (*models.APIKeyWithSecret, error)
+	RevokeAPIKeyFn func(ctx context.Context, id uint) error
+	VerifyAPIKeyFn func(ctx context.Context, key string) (*auth.Identity, error)
+	PurgeAPIKeysFn func(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+func (m *mockAPIKeyBusiness) CreateAPIKey(ctx context.Context, req models.APIKeyRequest) (*models.APIKeyWithSecret, error) {
+	return m.CreateAPIKeyFn(ctx, req)
+}
+func (m *mockAPIKeyBusiness) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return m.ListAPIKeysFn(ctx)
+}
+func (m *mockAPIKeyBusiness) RotateAPIKey(ctx context.Context, id uint) (*models.APIKeyWithSecret, error) {
+	return m.RotateAPIKeyFn(ctx, id)
+}
+func (m *mockAPIKeyBusiness) RevokeAPIKey(ctx context.Context, id uint) error {
+	return m.RevokeAPIKeyFn(ctx, id)
+}
+func (m *mockAPIKeyBusiness) VerifyAPIKey(ctx context.Context, key string) (*auth.Identity, error) {
+	return m.VerifyAPIKeyFn(ctx, key)
+}
+func (m *mockAPIKeyBusiness) PurgeAPIKeys(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return m.PurgeAPIKeysFn(ctx, olderThan)
+}
+
+func TestCreateAPIKey_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockAPIKeyBusiness{
+		CreateAPIKeyFn: func(ctx context.Context, req models.APIKeyRequest) (*models.APIKeyWithSecret, error) {
+			return &models.APIKeyWithSecret{
+				APIKey: models.APIKey{ID: 1, Name: req.Name, Prefix: "sk_abc", KeyHash: "hash", Scopes: req.Scopes},
+				Key:    "sk_abc_secret",
+			}, nil
+		},
+	}
+	h := NewAPIKeyHandler(mockBiz)
+	r := gin.New()
+	r.POST("/api-keys", h.CreateAPIKey)
+
+	payload := `{"name":"ci-pipeline","scopes":["write:versions"]}`
+	req, _ := http.NewRequest("POST", "/api-keys", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"key":"sk_abc_secret"`)
+	assert.NotContains(t, w.Body.String(), "hash")
+}
+
+func TestCreateAPIKey_InvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockAPIKeyBusiness{
+		CreateAPIKeyFn: func(ctx context.Context, req models.APIKeyRequest) (*models.APIKeyWithSecret, error) {
+			return nil, business.ErrInvalidAPIKeyRequest
+		},
+	}
+	h := NewAPIKeyHandler(mockBiz)
+	r := gin.New()
+	r.POST("/api-keys", h.CreateAPIKey)
+
+	req, _ := http.NewRequest("POST", "/api-keys", bytes.NewBufferString(`{"name":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListAPIKeys_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockAPIKeyBusiness{
+		ListAPIKeysFn: func(ctx context.Context) ([]models.APIKey, error) {
+			return []models.APIKey{{ID: 1, Name: "ci-pipeline", KeyHash: "hash"}}, nil
+		},
+	}
+	h := NewAPIKeyHandler(mockBiz)
+	r := gin.New()
+	r.GET("/api-keys", h.ListAPIKeys)
+
+	req, _ := http.NewRequest("GET", "/api-keys", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "ci-pipeline")
+	assert.NotContains(t, w.Body.String(), "hash")
+}
+
+func TestRotateAPIKey_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockAPIKeyBusiness{
+		RotateAPIKeyFn: func(ctx context.Context, id uint) (*models.APIKeyWithSecret, error) {
+			return nil, business.ErrAPIKeyNotFound
+		},
+	}
+	h := NewAPIKeyHandler(mockBiz)
+	r := gin.New()
+	r.POST("/api-keys/:kid/rotate", h.RotateAPIKey)
+
+	req, _ := http.NewRequest("POST", "/api-keys/9/rotate", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeAPIKey_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockAPIKeyBusiness{
+		RevokeAPIKeyFn: func(ctx context.Context, id uint) error { return nil },
+	}
+	h := NewAPIKeyHandler(mockBiz)
+	r := gin.New()
+	r.DELETE("/api-keys/:kid", h.RevokeAPIKey)
+
+	req, _ := http.NewRequest("DELETE", "/api-keys/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/api-keys/abc", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+// BatchHandler handles bulk operations on services and versions
+type BatchHandler struct {
+	batch business.BatchBusiness
+}
+
+// NewBatchHandler creates a new batch handler with the required batch business logic.
+func NewBatchHandler(batch business.BatchBusiness) *BatchHandler {
+	return &BatchHandler{
+		batch: batch,
+	}
+}
+
+// BatchServices godoc
+// @Summary Create, update and delete services in bulk
+// @Description Apply up to 500 service operations. Every operation is validated before any is applied. In atomic mode (the default) all of them are applied in one transaction or none is; in best_effort mode each valid operation is applied on its own. The response has a result per operation in request order. It is 200 when every operation succeeded, 207 when a best-effort batch partially failed and, for a failed atomic batch, the status of the operation that made it fail.
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param batch body models.ServiceBatchRequest true "Operations"
+// @Success 200 {object} models.BatchResponse "Every operation succeeded"
+// @Success 207 {object} models.BatchResponse "Some operations of a best-effort batch failed"
+// @Failure 400 {object} ErrorResponse "Invalid batch, or an atomic batch with an invalid operation"
+// @Failure 404 {object} models.BatchResponse "An atomic batch targets a missing service"
+// @Failure 409 {object} models.BatchResponse "An atomic batch conflicts with existing services"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services:batch [post]
+func (h *BatchHandler) BatchServices(c *gin.Context) {
+	var req models.ServiceBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	items, err := h.batch.BatchServices(c.Request.Context(), req)
+	if err != nil {
+		writeBatchError(c, err)
+		return
+	}
+
+	ops := make([]string, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = op.Op
+	}
+	writeBatchResponse(c, req.Mode, ops, items)
+}
+
+// BatchVersions godoc
+// @Summary Create, update and delete versions of a service in bulk
+// @Description Apply up to 500 version operations to a service, with the same modes and response as the service batch.
+// @Tags versions
+// @Accept json
+// @Produce json
+// @Param sid path integer true "Service ID"
+// @Param batch body models.VersionBatchRequest true "Operations"
+// @Success 200 {object} models.BatchResponse "Every operation succeeded"
+// @Success 207 {object} models.BatchResponse "Some operations of a best-effort batch failed"
+// @Failure 400 {object} ErrorResponse "Invalid batch, or an atomic batch with an invalid operation"
+// @Failure 404 {object} ErrorResponse "Service not found, or an atomic batch targets a missing version"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/versions:batch [post]
+func (h *BatchHandler) BatchVersions(c *gin.Context) {
+	serviceID, ok := parseServiceID(c)
+	if !ok {
+		return
+	}
+
+	var req models.VersionBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	items, err := h.batch.BatchVersions(c.Request.Context(), serviceID, req)
+	if err != nil {
+		writeBatchError(c, err)
+		return
+	}
+
+	ops := make([]string, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = op.Op
+	}
+	writeBatchResponse(c, req.Mode, ops, items)
+}
+
+// writeBatchResponse writes the per-operation results with the status
+// described on BatchServices. Atomic batches take the status of the first
+// operation that failed on its own, not of the ones rolled back or skipped.
+func writeBatchResponse(c *gin.Context, mode string, ops []string, items []business.BatchItem) {
+	if mode == "" {
+		mode = models.BatchAtomic
+	}
+	resp := models.BatchResponse{
+		Mode:    mode,
+		Results: make([]models.BatchResult, len(items)),
+	}
+	status := http.StatusOK
+	for i, item := range items {
+		result := models.BatchResult{Index: i, Op: ops[i], ID: item.ID}
+		if item.Err == nil {
+			resp.Succeeded++
+			result.Status = batchSuccessStatus(ops[i])
+		} else {
+			resp.Failed++
+			result.Status, result.Code = batchErrorStatus(item.Err)
+			result.Error = item.Err.Error()
+			switch {
+			case mode != models.BatchAtomic:
+				status = http.StatusMultiStatus
+			case status == http.StatusOK && !errors.Is(item.Err, business.ErrBatchRolledBack) && !errors.Is(item.Err, business.ErrBatchNotAttempted):
+				status = result.Status
+			}
+		}
+		resp.Results[i] = result
+	}
+	c.JSON(status, resp)
+}
+
+func batchSuccessStatus(op string) int {
+	switch op {
+	case models.BatchCreate:
+		return http.StatusCreated
+	case models.BatchDelete:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
+
+// batchErrorStatus maps the error of an operation to the status and code the
+// operation would have failed with on its own
+func batchErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, business.ErrInvalidOperation):
+		return http.StatusBadRequest, "invalid_operation"
+	case errors.Is(err, business.ErrInvalidService):
+		return http.StatusBadRequest, "invalid_service"
+	case errors.Is(err, business.ErrInvalidVersion):
+		return http.StatusBadRequest, "invalid_version"
+	case errors.Is(err, business.ErrServiceNotFound):
+		return http.StatusNotFound, "service_not_found"
+	case errors.Is(err, business.ErrVersionNotFound):
+		return http.StatusNotFound, "version_not_found"
+	case errors.Is(err, business.ErrServiceExists):
+		return http.StatusConflict, "service_exists"
+	case errors.Is(err, business.ErrServiceHasDependents):
+		return http.StatusConflict, "service_has_dependents"
+	case errors.Is(err, business.ErrBatchRolledBack):
+		return http.StatusConflict, "rolled_back"
+	case errors.Is(err, business.ErrBatchNotAttempted):
+		return http.StatusFailedDependency, "not_attempted"
+	default:
+		return http.StatusInternalServerError, "internal_error"
+	}
+}
+
+func writeBatchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, business.ErrInvalidBatch):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_batch",
+			Message: "The batch is invalid",
+			Details: err.Error(),
+		})
+	case errors.Is(err, business.ErrServiceNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "service_not_found",
+			Message: "Service not found",
+			Details: "The requested service does not exist",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while applying the batch",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+type mockBatchBusiness struct {
+	business.BatchBusiness
+	BatchServicesFn func(ctx context.Context, req models.ServiceBatchRequest) ([]business.BatchItem, error)
+	BatchVersionsFn func(ctx context.Context, serviceID uint, req models.VersionBatchRequest) ([]business.BatchItem, error)
+}
+
+func (m *mockBatchBusiness) BatchServices(ctx context.Context, req models.ServiceBatchRequest) ([]business.BatchItem, error) {
+	return m.BatchServicesFn(ctx, req)
+}
+
+func (m *mockBatchBusiness) BatchVersions(ctx context.Context, serviceID uint, req models.VersionBatchRequest) ([]business.BatchItem, error) {
+	return m.BatchVersionsFn(ctx, serviceID, req)
+}
+
+func TestBatchServicesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewBatchHandler(&mockBatchBusiness{
+		BatchServicesFn: func(ctx context.Context, req models.ServiceBatchRequest) ([]business.BatchItem, error) {
+			if len(req.Operations) == 0 {
+				return nil, fmt.Errorf("%w: no operations", business.ErrInvalidBatch)
+			}
+			if req.Mode == models.BatchBestEffort {
+				return []business.BatchItem{{ID: 5}, {Err: fmt.Errorf("%w: \"users\" is service 1", business.ErrServiceExists)}}, nil
+			}
+			return []business.BatchItem{{Err: business.ErrBatchNotAttempted}, {Err: fmt.Errorf("%w: 7", business.ErrServiceNotFound), ID: 7}}, nil
+		},
+	})
+	r := gin.New()
+	r.POST("/services:batch", h.BatchServices)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/services:batch", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"mode":"best_effort","operations":[{"op":"create","service":{"name":"payments"}},{"op":"create","service":{"name":"users"}}]}`)
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	assert.Contains(t, w.Body.String(), `"succeeded":1,"failed":1`)
+	assert.Contains(t, w.Body.String(), `{"index":0,"op":"create","status":201,"id":5}`)
+	assert.Contains(t, w.Body.String(), `"status":409,"code":"service_exists"`)
+
+	w = post(`{"operations":[{"op":"create","service":{"name":"payments"}},{"op":"delete","id":7}]}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"mode":"atomic"`)
+	assert.Contains(t, w.Body.String(), `"status":424,"code":"not_attempted"`)
+
+	w = post(`{"operations":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_batch")
+}
+
+func TestBatchVersionsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var serviceID uint
+	h := NewBatchHandler(&mockBatchBusiness{
+		BatchVersionsFn: func(ctx context.Context, id uint, req models.VersionBatchRequest) ([]business.BatchItem, error) {
+			serviceID = id
+			if id == 9 {
+				return nil, business.ErrServiceNotFound
+			}
+			return []business.BatchItem{{ID: 3}, {ID: 1}}, nil
+		},
+	})
+	r := gin.New()
+	r.POST("/services/:sid/versions:batch", h.BatchVersions)
+
+	body := `{"operations":[{"op":"create","version":{"version":"1.1.0"}},{"op":"delete","id":1}]}`
+	req, _ := http.NewRequest("POST", "/services/4/versions:batch", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(4), serviceID)
+	assert.Contains(t, w.Body.String(), `{"index":1,"op":"delete","status":204,"id":1}`)
+
+	req, _ = http.NewRequest("POST", "/services/9/versions:batch", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "service_not_found")
+}
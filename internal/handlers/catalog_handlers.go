@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/catalog"
+	"services-api/internal/models"
+)
+
+// maxImportSize bounds the size of an imported catalog
+const maxImportSize = 10 << 20
+
+// CatalogHandler handles the import and export of the service catalog
+type CatalogHandler struct {
+	catalog business.CatalogBusiness
+}
+
+// NewCatalogHandler creates a new catalog handler with the required catalog business logic.
+func NewCatalogHandler(catalog business.CatalogBusiness) *CatalogHandler {
+	return &CatalogHandler{
+		catalog: catalog,
+	}
+}
+
+// Export godoc
+// @Summary Export the service catalog
+// @Description Dump every service with its versions, labels and custom field values. Services are identified by name so the dump can be imported into any environment. CSV files have a row per version.
+// @Tags catalog
+// @Produce application/yaml
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "Output format" Enums(yaml, json, csv) default(yaml)
+// @Success 200 {object} models.Catalog "Service catalog"
+// @Failure 400 {object} ErrorResponse "Invalid format"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /export [get]
+func (h *CatalogHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", catalog.FormatYAML)
+	if !validCatalogFormat(c, format) {
+		return
+	}
+
+	dump, err := h.catalog.Export(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while exporting the catalog",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var body bytes.Buffer
+	if err := catalog.Encode(&body, format, *dump); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while encoding the catalog",
+			Details: err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, format))
+	c.Data(http.StatusOK, catalog.ContentType(format), body.Bytes())
+}
+
+// Import godoc
+// @Summary Import a service catalog
+// @Description Create and update services from a catalog in the export format, matching services by name and versions by version string. Omitted descriptions, labels and metadata are left unchanged. In replace mode, which requires the admin scope unless dry_run=true, the services and versions missing from the catalog are deleted, except services other services depend on unless force=true. Invalid records and refused deletes are skipped and listed in the report's errors while the others are applied in one transaction. With dry_run=true the report previews the changes without applying them.
+// @Tags catalog
+// @Accept application/yaml
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "Input format, taken from the Content-Type when omitted" Enums(yaml, json, csv)
+// @Param mode query string false "Import mode" Enums(merge, replace) default(merge)
+// @Param dry_run query boolean false "Report the changes without applying them"
+// @Param force query boolean false "In replace mode, also delete services other services depend on"
+// @Param catalog body models.Catalog true "Service catalog"
+// @Success 200 {object} models.ImportReport "Changes and rejected records"
+// @Failure 400 {object} ErrorResponse "Invalid format, mode or catalog document"
+// @Failure 403 {object} ErrorResponse "Replace mode without the admin scope"
+// @Failure 409 {object} ErrorResponse "A service gained dependents while the import was applied"
+// @Failure 413 {object} ErrorResponse "Catalog too large"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /import [post]
+func (h *CatalogHandler) Import(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = formatFromContentType(c.ContentType())
+	}
+	if !validCatalogFormat(c, format) {
+		return
+	}
+	dryRun, ok := parseBoolQuery(c, "dry_run")
+	if !ok {
+		return
+	}
+	force, ok := parseBoolQuery(c, "force")
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Code:    "catalog_too_large",
+			Message: fmt.Sprintf("The catalog must not exceed %d bytes", maxImportSize),
+			Details: err.Error(),
+		})
+		return
+	}
+	dump, err := catalog.Decode(bytes.NewReader(body), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_catalog",
+			Message: "The catalog could not be read",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	report, err := h.catalog.Import(c.Request.Context(), *dump, models.ImportOptions{
+		Mode:   c.DefaultQuery("mode", models.ImportMerge),
+		DryRun: dryRun,
+		Force:  force,
+	})
+	if err != nil {
+		if errors.Is(err, business.ErrInvalidImport) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_import",
+				Message: "The import options are invalid",
+				Details: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, business.ErrServiceHasDependents) {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Code:    "service_has_dependents",
+				Message: "Other services started depending on a deleted service, retry the import or use force=true",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while importing the catalog",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// validCatalogFormat writes a 400 response unless format is yaml, json or csv
+func validCatalogFormat(c *gin.Context, format string) bool {
+	switch format {
+	case catalog.FormatYAML, catalog.FormatJSON, catalog.FormatCSV:
+		return true
+	}
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Code:    "invalid_format",
+		Message: "The format must be yaml, json or csv",
+		Details: fmt.Sprintf("Provided value: %s", format),
+	})
+	return false
+}
+
+// formatFromContentType picks the catalog format of a request body, YAML
+// unless the media type says JSON or CSV
+func formatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json":
+		return catalog.FormatJSON
+	case "text/csv":
+		return catalog.FormatCSV
+	default:
+		return catalog.FormatYAML
+	}
+}
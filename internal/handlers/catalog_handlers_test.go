@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+type mockCatalogBusiness struct {
+	ExportFn func(ctx context.Context) (*models.Catalog, error)
+	ImportFn func(ctx context.Context, catalog models.Catalog, options models.ImportOptions) (*models.ImportReport, error)
+}
+
+func (m *mockCatalogBusiness) Export(ctx context.Context) (*models.Catalog, error) {
+	return m.ExportFn(ctx)
+}
+
+func (m *mockCatalogBusiness) Import(ctx context.Context, catalog models.Catalog, options models.ImportOptions) (*models.ImportReport, error) {
+	return m.ImportFn(ctx, catalog, options)
+}
+
+func TestExportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCatalogHandler(&mockCatalogBusiness{
+		ExportFn: func(ctx context.Context) (*models.Catalog, error) {
+			return &models.Catalog{Services: []models.CatalogService{{Name: "users", Versions: []models.CatalogVersion{{Version: "1.0.0"}}}}}, nil
+		},
+	})
+	r := gin.New()
+	r.GET("/export", h.Export)
+
+	req, _ := http.NewRequest("GET", "/export", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="catalog.yaml"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "services:\n  - name: users\n    versions:\n      - version: 1.0.0\n", w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/export?format=csv", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "name,description,labels,metadata,version,version_description\nusers,,,,1.0.0,\n", w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/export?format=xml", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_format")
+}
+
+func TestImportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.Catalog
+	var options models.ImportOptions
+	h := NewCatalogHandler(&mockCatalogBusiness{
+		ImportFn: func(ctx context.Context, catalog models.Catalog, opts models.ImportOptions) (*models.ImportReport, error) {
+			got, options = catalog, opts
+			if opts.Mode != models.ImportMerge && opts.Mode != models.ImportReplace {
+				return nil, fmt.Errorf("%w: unknown mode %q", business.ErrInvalidImport, opts.Mode)
+			}
+			return &models.ImportReport{Mode: opts.Mode, DryRun: opts.DryRun, Created: 1, Changes: []models.ImportChange{}, Errors: []models.ImportError{}}, nil
+		},
+	})
+	r := gin.New()
+	r.POST("/import", h.Import)
+
+	post := func(url, contentType, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", url, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/import?mode=replace&dry_run=true", "application/json; charset=utf-8", `{"services": [{"name": "users"}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "users", got.Services[0].Name)
+	assert.Equal(t, models.ImportOptions{Mode: models.ImportReplace, DryRun: true}, options)
+	assert.Contains(t, w.Body.String(), `"dry_run":true,"created":1`)
+
+	w = post("/import?format=csv", "application/octet-stream", "name,version\nusers,1.0.0\n")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1.0.0", got.Services[0].Versions[0].Version)
+	assert.Equal(t, models.ImportMerge, options.Mode)
+
+	w = post("/import", "application/yaml", "services: [")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_catalog")
+
+	w = post("/import?mode=overwrite", "application/yaml", "services: []")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_import")
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+)
+
+const (
+	defaultChangeLimit = 100
+	maxChangeLimit     = 1000
+)
+
+// ChangeHandler handles the change feed
+type ChangeHandler struct {
+	changeBusiness business.ChangeBusiness
+}
+
+// NewChangeHandler creates a new change feed handler
+func NewChangeHandler(changeBusiness business.ChangeBusiness) *ChangeHandler {
+	return &ChangeHandler{
+		changeBusiness: changeBusiness,
+	}
+}
+
+// ListChanges godoc
+// @Summary List catalog changes
+// @Description List the creations, updates and deletions of services and versions in the order they were committed, for consumers mirroring the catalog. Every change has a sequence number; pass the next_since of a page as since to get the changes after it. Deletions are tombstones without a state, and deleting a service deletes its versions. Changes are kept as long as events (outbox.retention); a since before the oldest change kept returns 410 and the consumer has to reload the services and versions and restart from since=0.
+// @Tags changes
+// @Produce json
+// @Param since query integer false "Sequence of the last change received, 0 to start at the oldest change kept" default(0)
+// @Param limit query integer false "Number of changes (default 100, max 1000)"
+// @Success 200 {object} models.ChangeFeed "Page of changes"
+// @Failure 400 {object} ErrorResponse "Invalid since"
+// @Failure 410 {object} ErrorResponse "Changes after since were pruned"
+// @Failure 500 {object} ErrorResponse "Failed to list changes"
+// @Security ApiKeyAuth
+// @Router /changes [get]
+func (h *ChangeHandler) ListChanges(c *gin.Context) {
+	var since uint64
+	if value := c.Query("since"); value != "" {
+		var err error
+		since, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_since",
+				Message: "Invalid since",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	limit := parseIntOrDefault(c.Query("limit"), defaultChangeLimit)
+	if limit < 1 || limit > maxChangeLimit {
+		limit = defaultChangeLimit
+	}
+
+	feed, err := h.changeBusiness.ListChanges(c.Request.Context(), since, limit)
+	if err != nil {
+		if errors.Is(err, business.ErrChangesExpired) {
+			c.JSON(http.StatusGone, ErrorResponse{
+				Code:    "changes_expired",
+				Message: "The changes after since are no longer kept; reload the catalog and restart from since=0",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to list changes",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockChangeBusiness struct {
+	ListChangesFn func(ctx context.Context, since uint64, limit int) (*models.ChangeFeed, error)
+}
+
+func (m *mockChangeBusiness) ListChanges(ctx context.Context, since uint64, limit int) (*models.ChangeFeed, error) {
+	return m.ListChangesFn(ctx, since, limit)
+}
+
+func TestListChanges_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var gotSince uint64
+	var gotLimit int
+	mockBiz := &mockChangeBusiness{
+		ListChangesFn: func(ctx context.Context, since uint64, limit int) (*models.ChangeFeed, error) {
+			gotSince, gotLimit = since, limit
+			return &models.ChangeFeed{
+				Changes: []models.Change{
+					{Seq: 43, Entity: models.ChangeEntityVersion, Op: models.ChangeUpdate, ID: 3, ServiceID: 1, State: []byte(`{"id":3}`)},
+					{Seq: 45, Entity: models.ChangeEntityService, Op: models.ChangeDelete, ID: 2, ServiceID: 2},
+				},
+				NextSince: 45,
+				HasMore:   true,
+			}, nil
+		},
+	}
+	h := NewChangeHandler(mockBiz)
+	r := gin.New()
+	r.GET("/changes", h.ListChanges)
+
+	req, _ := http.NewRequest("GET", "/changes?since=42&limit=5000", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint64(42), gotSince)
+	assert.Equal(t, defaultChangeLimit, gotLimit)
+	assert.Contains(t, w.Body.String(), `"state":{"id":3}`)
+	assert.Contains(t, w.Body.String(), `{"seq":45,"entity":"service","op":"delete","id":2,"service_id":2,"changed_at":"0001-01-01T00:00:00Z"}`)
+	assert.Contains(t, w.Body.String(), `"next_since":45,"has_more":true`)
+}
+
+func TestListChanges_InvalidSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewChangeHandler(&mockChangeBusiness{})
+	r := gin.New()
+	r.GET("/changes", h.ListChanges)
+
+	req, _ := http.NewRequest("GET", "/changes?since=-1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_since"`)
+}
+
+func TestListChanges_Expired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockChangeBusiness{
+		ListChangesFn: func(ctx context.Context, since uint64, limit int) (*models.ChangeFeed, error) {
+			return nil, fmt.Errorf("%w: changes up to 50 were pruned", business.ErrChangesExpired)
+		},
+	}
+	h := NewChangeHandler(mockBiz)
+	r := gin.New()
+	r.GET("/changes", h.ListChanges)
+
+	req, _ := http.NewRequest("GET", "/changes?since=10", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"changes_expired"`)
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+// CustomFieldHandler handles custom field definition requests
+type CustomFieldHandler struct {
+	customFieldBusiness business.CustomFieldBusiness
+}
+
+// NewCustomFieldHandler creates a new custom field handler with the required business logic.
+func NewCustomFieldHandler(customFieldBusiness business.CustomFieldBusiness) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		customFieldBusiness: customFieldBusiness,
+	}
+}
+
+// ListCustomFields godoc
+// @Summary List custom fields
+// @Description List the custom fields services can have in their metadata
+// @Tags custom-fields
+// @Produce json
+// @Success 200 {array} models.CustomField "Custom fields"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /custom-fields [get]
+func (h *CustomFieldHandler) ListCustomFields(c *gin.Context) {
+	fields, err := h.customFieldBusiness.ListCustomFields(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusOK, fields)
+}
+
+// GetCustomField godoc
+// @Summary Get a custom field
+// @Description Get a custom field definition by ID
+// @Tags custom-fields
+// @Produce json
+// @Param fid path integer true "Custom field ID" minimum(1)
+// @Success 200 {object} models.CustomField "Custom field"
+// @Failure 400 {object} ErrorResponse "Invalid ID"
+// @Failure 404 {object} ErrorResponse "Custom field not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /custom-fields/{fid} [get]
+func (h *CustomFieldHandler) GetCustomField(c *gin.Context) {
+	fieldId, ok := parseCustomFieldID(c)
+	if !ok {
+		return
+	}
+
+	field, err := h.customFieldBusiness.GetCustomField(c.Request.Context(), fieldId)
+	if err != nil {
+		h.handleError(c, err, fieldId)
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+// CreateCustomField godoc
+// @Summary Define a custom field
+// @Description Define a custom field of type string, url, enum, number or date. Enum fields list their allowed values in options. Once a field is required, new services must set it and updates that replace metadata must keep it.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param field body models.CustomFieldRequest true "Custom field details"
+// @Success 201 {object} models.CustomField "Created custom field"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 403 {object} ErrorResponse "Admin scope required"
+// @Failure 409 {object} ErrorResponse "A field with that name exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /custom-fields [post]
+func (h *CustomFieldHandler) CreateCustomField(c *gin.Context) {
+	var req models.CustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	field, err := h.customFieldBusiness.CreateCustomField(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, field)
+}
+
+// UpdateCustomField godoc
+// @Summary Update a custom field
+// @Description Change the display name, description, required flag or enum options of a custom field. The name and type can't be changed, and enum options still used by services can't be removed.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param fid path integer true "Custom field ID" minimum(1)
+// @Param field body models.CustomFieldRequest true "Custom field details"
+// @Success 200 {object} models.CustomField "Updated custom field"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 403 {object} ErrorResponse "Admin scope required"
+// @Failure 404 {object} ErrorResponse "Custom field not found"
+// @Failure 409 {object} ErrorResponse "Removed options are in use"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /custom-fields/{fid} [patch]
+func (h *CustomFieldHandler) UpdateCustomField(c *gin.Context) {
+	fieldId, ok := parseCustomFieldID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	field, err := h.customFieldBusiness.UpdateCustomField(c.Request.Context(), fieldId, req)
+	if err != nil {
+		h.handleError(c, err, fieldId)
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+// DeleteCustomField godoc
+// @Summary Delete a custom field
+// @Description Delete a custom field and its value on every service
+// @Tags custom-fields
+// @Param fid path integer true "Custom field ID" minimum(1)
+// @Success 204 "Custom field deleted"
+// @Failure 400 {object} ErrorResponse "Invalid ID"
+// @Failure 403 {object} ErrorResponse "Admin scope required"
+// @Failure 404 {object} ErrorResponse "Custom field not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /custom-fields/{fid} [delete]
+func (h *CustomFieldHandler) DeleteCustomField(c *gin.Context) {
+	fieldId, ok := parseCustomFieldID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.customFieldBusiness.DeleteCustomField(c.Request.Context(), fieldId); err != nil {
+		h.handleError(c, err, fieldId)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps custom field errors to responses
+func (h *CustomFieldHandler) handleError(c *gin.Context, err error, fieldId uint) {
+	switch {
+	case errors.Is(err, business.ErrCustomFieldNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "custom_field_not_found",
+			Message: fmt.Sprintf("Custom field with ID %d could not be found", fieldId),
+		})
+	case errors.Is(err, business.ErrInvalidCustomField):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_custom_field",
+			Message: "Invalid custom field",
+			Details: err.Error(),
+		})
+	case errors.Is(err, business.ErrCustomFieldExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Code:    "custom_field_exists",
+			Message: "A custom field with that name already exists",
+			Details: err.Error(),
+		})
+	case errors.Is(err, business.ErrCustomFieldInUse):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Code:    "custom_field_in_use",
+			Message: "The removed options are still used by services",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while processing the custom field",
+			Details: err.Error(),
+		})
+	}
+}
+
+func parseCustomFieldID(c *gin.Context) (uint, bool) {
+	fieldId, err := strconv.ParseUint(c.Param("fid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_custom_field_id",
+			Message: "The custom field ID must be a positive integer",
+			Details: fmt.Sprintf("Provided ID: %s", c.Param("fid")),
+		})
+		return 0, false
+	}
+	return uint(fieldId), true
+}
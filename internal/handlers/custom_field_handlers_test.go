@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+type mockCustomFieldBusiness struct {
+	business.CustomFieldBusiness
+	CreateCustomFieldFn func(ctx context.Context, req models.CustomFieldRequest) (*models.CustomField, error)
+	UpdateCustomFieldFn func(ctx context.Context, id uint, req models.CustomFieldRequest) (*models.CustomField, error)
+	DeleteCustomFieldFn func(ctx context.Context, id uint) error
+}
+
+func (m *mockCustomFieldBusiness) CreateCustomField(ctx context.Context, req models.CustomFieldRequest) (*models.CustomField, error) {
+	return m.CreateCustomFieldFn(ctx, req)
+}
+func (m *mockCustomFieldBusiness) UpdateCustomField(ctx context.Context, id uint, req models.CustomFieldRequest) (*models.CustomField, error) {
+	return m.UpdateCustomFieldFn(ctx, id, req)
+}
+func (m *mockCustomFieldBusiness) DeleteCustomField(ctx context.Context, id uint) error {
+	return m.DeleteCustomFieldFn(ctx, id)
+}
+
+func newCustomFieldRouter(m *mockCustomFieldBusiness) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewCustomFieldHandler(m)
+	r := gin.New()
+	r.POST("/custom-fields", h.CreateCustomField)
+	r.PATCH("/custom-fields/:fid", h.UpdateCustomField)
+	r.DELETE("/custom-fields/:fid", h.DeleteCustomField)
+	return r
+}
+
+func TestCreateCustomFieldHandler(t *testing.T) {
+	r := newCustomFieldRouter(&mockCustomFieldBusiness{
+		CreateCustomFieldFn: func(ctx context.Context, req models.CustomFieldRequest) (*models.CustomField, error) {
+			if req.Type == "boolean" {
+				return nil, fmt.Errorf("%w: unknown type", business.ErrInvalidCustomField)
+			}
+			return &models.CustomField{ID: 1, Name: req.Name, Type: req.Type, Required: *req.Required, Options: req.Options}, nil
+		},
+	})
+
+	req, _ := http.NewRequest("POST", "/custom-fields", bytes.NewBufferString(`{"name":"sla_tier","type":"enum","required":true,"options":["gold","silver"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"options":["gold","silver"]`)
+
+	req, _ = http.NewRequest("POST", "/custom-fields", bytes.NewBufferString(`{"name":"flag","type":"boolean"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_custom_field")
+}
+
+func TestUpdateCustomFieldHandler_InUse(t *testing.T) {
+	r := newCustomFieldRouter(&mockCustomFieldBusiness{
+		UpdateCustomFieldFn: func(ctx context.Context, id uint, req models.CustomFieldRequest) (*models.CustomField, error) {
+			return nil, fmt.Errorf("%w: 2 services use gold", business.ErrCustomFieldInUse)
+		},
+	})
+
+	req, _ := http.NewRequest("PATCH", "/custom-fields/1", bytes.NewBufferString(`{"options":["silver"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "custom_field_in_use")
+}
+
+func TestDeleteCustomFieldHandler(t *testing.T) {
+	r := newCustomFieldRouter(&mockCustomFieldBusiness{
+		DeleteCustomFieldFn: func(ctx context.Context, id uint) error {
+			if id == 2 {
+				return business.ErrCustomFieldNotFound
+			}
+			return nil
+		},
+	})
+
+	req, _ := http.NewRequest("DELETE", "/custom-fields/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/custom-fields/2", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/custom-fields/abc", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_custom_field_id")
+}
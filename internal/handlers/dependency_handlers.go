@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+// DependencyHandler handles service dependency and graph requests
+type DependencyHandler struct {
+	dependencyBusiness business.DependencyBusiness
+}
+
+// NewDependencyHandler creates a new dependency handler with the required business logic.
+func NewDependencyHandler(dependencyBusiness business.DependencyBusiness) *DependencyHandler {
+	return &DependencyHandler{
+		dependencyBusiness: dependencyBusiness,
+	}
+}
+
+// ListDependencies godoc
+// @Summary List the dependencies of a service
+// @Description List the services the given service depends on
+// @Tags dependencies
+// @Produce json
+// @Param sid path integer true "Service ID" minimum(1)
+// @Success 200 {array} models.Dependency "Dependencies of the service"
+// @Failure 400 {object} ErrorResponse "Invalid service ID"
+// @Failure 404 {object} ErrorResponse "Service not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/dependencies [get]
+func (h *DependencyHandler) ListDependencies(c *gin.Context) {
+	serviceId, ok := parseServiceID(c)
+	if !ok {
+		return
+	}
+
+	dependencies, err := h.dependencyBusiness.ListDependencies(c.Request.Context(), serviceId)
+	if err != nil {
+		h.handleError(c, err, serviceId, 0)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// ListDependents godoc
+// @Summary List the dependents of a service
+// @Description List the services that depend on the given service
+// @Tags dependencies
+// @Produce json
+// @Param sid path integer true "Service ID" minimum(1)
+// @Success 200 {array} models.Dependency "Dependencies pointing at the service"
+// @Failure 400 {object} ErrorResponse "Invalid service ID"
+// @Failure 404 {object} ErrorResponse "Service not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/dependents [get]
+func (h *DependencyHandler) ListDependents(c *gin.Context) {
+	serviceId, ok := parseServiceID(c)
+	if !ok {
+		return
+	}
+
+	dependents, err := h.dependencyBusiness.ListDependents(c.Request.Context(), serviceId)
+	if err != nil {
+		h.handleError(c, err, serviceId, 0)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependents)
+}
+
+// GetDependency godoc
+// @Summary Get a dependency
+// @Description Get a dependency of a service by ID
+// @Tags dependencies
+// @Produce json
+// @Param sid path integer true "Service ID" minimum(1)
+// @Param did path integer true "Dependency ID" minimum(1)
+// @Success 200 {object} models.Dependency "Dependency"
+// @Failure 400 {object} ErrorResponse "Invalid ID"
+// @Failure 404 {object} ErrorResponse "Dependency not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/dependencies/{did} [get]
+func (h *DependencyHandler) GetDependency(c *gin.Context) {
+	serviceId, dependencyId, ok := parseDependencyIDs(c)
+	if !ok {
+		return
+	}
+
+	dependency, err := h.dependencyBusiness.GetDependency(c.Request.Context(), serviceId, dependencyId)
+	if err != nil {
+		h.handleError(c, err, serviceId, dependencyId)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependency)
+}
+
+// CreateDependency godoc
+// @Summary Add a dependency
+// @Description Make the service depend on another service, optionally pinned to a version constraint such as ">=1.2.0, <2.0.0". Dependencies that would create a cycle are rejected.
+// @Tags dependencies
+// @Accept json
+// @Produce json
+// @Param sid path integer true "Service ID" minimum(1)
+// @Param dependency body models.DependencyRequest true "Dependency details"
+// @Success 201 {object} models.Dependency "Created dependency"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Service not found"
+// @Failure 409 {object} ErrorResponse "Dependency exists or would create a cycle"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/dependencies [post]
+func (h *DependencyHandler) CreateDependency(c *gin.Context) {
+	serviceId, ok := parseServiceID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	dependency, err := h.dependencyBusiness.CreateDependency(c.Request.Context(), serviceId, req)
+	if err != nil {
+		h.handleError(c, err, serviceId, 0)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dependency)
+}
+
+// UpdateDependency godoc
+// @Summary Update a dependency
+// @Description Change the version constraint of a dependency
+// @Tags dependencies
+// @Accept json
+// @Produce json
+// @Param sid path integer true "Service ID" minimum(1)
+// @Param did path integer true "Dependency ID" minimum(1)
+// @Param dependency body models.DependencyRequest true "Dependency details"
+// @Success 200 {object} models.Dependency "Updated dependency"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 404 {object} ErrorResponse "Dependency not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/dependencies/{did} [patch]
+func (h *DependencyHandler) UpdateDependency(c *gin.Context) {
+	serviceId, dependencyId, ok := parseDependencyIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.DependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	dependency, err := h.dependencyBusiness.UpdateDependency(c.Request.Context(), serviceId, dependencyId, req)
+	if err != nil {
+		h.handleError(c, err, serviceId, dependencyId)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependency)
+}
+
+// DeleteDependency godoc
+// @Summary Remove a dependency
+// @Description Remove a dependency of a service
+// @Tags dependencies
+// @Param sid path integer true "Service ID" minimum(1)
+// @Param did path integer true "Dependency ID" minimum(1)
+// @Success 204 "Dependency removed"
+// @Failure 400 {object} ErrorResponse "Invalid ID"
+// @Failure 404 {object} ErrorResponse "Dependency not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/dependencies/{did} [delete]
+func (h *DependencyHandler) DeleteDependency(c *gin.Context) {
+	serviceId, dependencyId, ok := parseDependencyIDs(c)
+	if !ok {
+		return
+	}
+
+	err := h.dependencyBusiness.DeleteDependency(c.Request.Context(), serviceId, dependencyId)
+	if err != nil {
+		h.handleError(c, err, serviceId, dependencyId)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetGraph godoc
+// @Summary Export the dependency graph
+// @Description Export all services and their dependencies as JSON, Graphviz DOT or Mermaid
+// @Tags dependencies
+// @Produce json
+// @Produce plain
+// @Param format query string false "Output format" Enums(json, dot, mermaid) default(json)
+// @Success 200 {object} models.Graph "Dependency graph"
+// @Failure 400 {object} ErrorResponse "Invalid format"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /graph [get]
+func (h *DependencyHandler) GetGraph(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "dot" && format != "mermaid" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_format",
+			Message: "The format must be one of json, dot or mermaid",
+			Details: fmt.Sprintf("Provided format: %s", format),
+		})
+		return
+	}
+
+	graph, err := h.dependencyBusiness.GetGraph(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while building the dependency graph",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	switch format {
+	case "dot":
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graphToDOT(graph)))
+	case "mermaid":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(graphToMermaid(graph)))
+	default:
+		c.JSON(http.StatusOK, graph)
+	}
+}
+
+func (h *DependencyHandler) handleError(c *gin.Context, err error, serviceId, dependencyId uint) {
+	switch {
+	case errors.Is(err, business.ErrServiceNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "service_not_found",
+			Message: fmt.Sprintf("Service with ID %d could not be found", serviceId),
+		})
+	case errors.Is(err, business.ErrDependencyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "dependency_not_found",
+			Message: fmt.Sprintf("Dependency with ID %d could not be found for service %d", dependencyId, serviceId),
+		})
+	case errors.Is(err, business.ErrInvalidDependency):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_dependency",
+			Message: "Invalid dependency",
+			Details: err.Error(),
+		})
+	case errors.Is(err, business.ErrDependencyExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Code:    "dependency_exists",
+			Message: "The service already depends on the target service",
+		})
+	case errors.Is(err, business.ErrDependencyCycle):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Code:    "dependency_cycle",
+			Message: "The dependency would create a cycle",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while processing the dependency",
+			Details: err.Error(),
+		})
+	}
+}
+
+func parseDependencyIDs(c *gin.Context) (uint, uint, bool) {
+	serviceId, ok := parseServiceID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	dependencyId, err := strconv.ParseUint(c.Param("did"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_dependency_id",
+			Message: "The dependency ID must be a positive integer",
+			Details: fmt.Sprintf("Provided ID: %s", c.Param("did")),
+		})
+		return 0, 0, false
+	}
+	return serviceId, uint(dependencyId), true
+}
+
+// graphToDOT renders the graph in Graphviz DOT format
+func graphToDOT(graph *models.Graph) string {
+	var b strings.Builder
+	b.WriteString("digraph services {\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "  s%d [label=%s];\n", node.ID, strconv.Quote(node.Name))
+	}
+	for _, edge := range graph.Edges {
+		if edge.VersionConstraint != "" {
+			fmt.Fprintf(&b, "  s%d -> s%d [label=%s];\n", edge.From, edge.To, strconv.Quote(edge.VersionConstraint))
+		} else {
+			fmt.Fprintf(&b, "  s%d -> s%d;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// graphToMermaid renders the graph as a Mermaid flowchart
+func graphToMermaid(graph *models.Graph) string {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "  s%d[\"%s\"]\n", node.ID, mermaidEscape(node.Name))
+	}
+	for _, edge := range graph.Edges {
+		if edge.VersionConstraint != "" {
+			fmt.Fprintf(&b, "  s%d -->|\"%s\"| s%d\n", edge.From, mermaidEscape(edge.VersionConstraint), edge.To)
+		} else {
+			fmt.Fprintf(&b, "  s%d --> s%d\n", edge.From, edge.To)
+		}
+	}
+	return b.String()
+}
+
+// mermaidEscape replaces the characters that end a quoted Mermaid label
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;").Replace(s)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+type mockDependencyBusiness struct {
+	business.DependencyBusiness
+	CreateDependencyFn func(ctx context.Context, serviceID uint, req models.DependencyRequest) (*models.Dependency, error)
+	ListDependentsFn   func(ctx context.Context, serviceID uint) ([]models.Dependency, error)
+	GetGraphFn         func(ctx context.Context) (*models.Graph, error)
+}
+
+func (m *mockDependencyBusiness) CreateDependency(ctx context.Context, serviceID uint, req models.DependencyRequest) (*models.Dependency, error) {
+	return m.CreateDependencyFn(ctx, serviceID, req)
+}
+func (m *mockDependencyBusiness) ListDependents(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+	return m.ListDependentsFn(ctx, serviceID)
+}
+func (m *mockDependencyBusiness) GetGraph(ctx context.Context) (*models.Graph, error) {
+	return m.GetGraphFn(ctx)
+}
+
+func newDependencyRouter(m *mockDependencyBusiness) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewDependencyHandler(m)
+	r := gin.New()
+	r.POST("/services/:sid/dependencies", h.CreateDependency)
+	r.GET("/services/:sid/dependents", h.ListDependents)
+	r.GET("/graph", h.GetGraph)
+	return r
+}
+
+func TestCreateDependencyHandler(t *testing.T) {
+	r := newDependencyRouter(&mockDependencyBusiness{
+		CreateDependencyFn: func(ctx context.Context, serviceID uint, req models.DependencyRequest) (*models.Dependency, error) {
+			if req.DependsOnID == 1 {
+				return nil, fmt.Errorf("%w: 2 -> 1 -> 2", business.ErrDependencyCycle)
+			}
+			return &models.Dependency{ID: 1, ServiceID: serviceID, DependsOnID: req.DependsOnID}, nil
+		},
+	})
+
+	req, _ := http.NewRequest("POST", "/services/2/dependencies", bytes.NewBufferString(`{"depends_on_id": 3}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"depends_on_id":3`)
+
+	req, _ = http.NewRequest("POST", "/services/2/dependencies", bytes.NewBufferString(`{"depends_on_id": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "dependency_cycle")
+	// gin escapes ">" in JSON strings
+	assert.Contains(t, w.Body.String(), `2 -\u003e 1 -\u003e 2`)
+}
+
+func TestListDependentsHandler_NotFound(t *testing.T) {
+	r := newDependencyRouter(&mockDependencyBusiness{
+		ListDependentsFn: func(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+			return nil, business.ErrServiceNotFound
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/services/7/dependents", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "service_not_found")
+}
+
+func TestGetGraphHandler_Formats(t *testing.T) {
+	r := newDependencyRouter(&mockDependencyBusiness{
+		GetGraphFn: func(ctx context.Context) (*models.Graph, error) {
+			return &models.Graph{
+				Nodes: []models.GraphNode{{ID: 1, Name: "orders"}, {ID: 2, Name: `users "v2"`}},
+				Edges: []models.GraphEdge{{From: 1, To: 2, VersionConstraint: "^2.0"}},
+			}, nil
+		},
+	})
+
+	tests := []struct {
+		format   string
+		code     int
+		contains string
+	}{
+		{"", http.StatusOK, `"edges":[{"from":1,"to":2,"version_constraint":"^2.0"}]`},
+		{"dot", http.StatusOK, `s1 -> s2 [label="^2.0"];`},
+		{"dot", http.StatusOK, `s2 [label="users \"v2\""];`},
+		{"mermaid", http.StatusOK, `s1 -->|"^2.0"| s2`},
+		{"mermaid", http.StatusOK, `s2["users #quot;v2#quot;"]`},
+		{"svg", http.StatusBadRequest, "invalid_format"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", "/graph?format="+tt.format, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.format)
+		assert.Contains(t, w.Body.String(), tt.contains, tt.format)
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/labels"
+	"services-api/internal/models"
+	"services-api/internal/outbox"
+)
+
+const (
+	// heartbeatInterval is how often a comment is sent on idle streams so
+	// proxies and clients don't close them
+	heartbeatInterval = 15 * time.Second
+
+	// replayBatchSize is the number of missed events read at once
+	replayBatchSize = 500
+)
+
+// EventHandler streams catalog events
+type EventHandler struct {
+	stream          *outbox.Stream
+	businessService business.BusinessService
+}
+
+// NewEventHandler creates a new event handler following stream. Services
+// are looked up to filter version events by label.
+func NewEventHandler(stream *outbox.Stream, businessService business.BusinessService) *EventHandler {
+	return &EventHandler{
+		stream:          stream,
+		businessService: businessService,
+	}
+}
+
+// StreamEvents godoc
+// @Summary Stream catalog events
+// @Description Push service and version change events as Server-Sent Events as they are committed. Each message has the event type as its name, the position in the event log as its ID and the event as its data. A comment is sent every 15 seconds while idle. Clients reconnecting with the Last-Event-ID header (or last_event_id) first receive the events they missed, as long as they are still in the event log. Otherwise an expired event is sent instead, after which the client should reload the catalog; the stream then goes on with new events. Streams end when the server shuts down.
+// @Tags events
+// @Produce text/event-stream
+// @Param service_id query string false "Comma-separated service IDs to receive events of"
+// @Param type query string false "Comma-separated event types to receive, e.g. version.created,version.deleted"
+// @Param selector query string false "Label selector the service of the event must match, e.g. tier=critical"
+// @Param last_event_id query integer false "Replay the events after this ID; the Last-Event-ID header takes precedence"
+// @Param Last-Event-ID header integer false "Replay the events after this ID"
+// @Success 200 {object} models.Event "Stream of events"
+// @Failure 400 {object} ErrorResponse "Invalid filter or event ID"
+// @Security ApiKeyAuth
+// @Router /events/stream [get]
+func (h *EventHandler) StreamEvents(c *gin.Context) {
+	filter, ok := h.parseEventFilter(c)
+	if !ok {
+		return
+	}
+	lastEventId := c.GetHeader("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = c.Query("last_event_id")
+	}
+	var last uint64
+	if lastEventId != "" {
+		var err error
+		last, err = strconv.ParseUint(lastEventId, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_last_event_id",
+				Message: "Invalid last event ID",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	// Subscribe before replaying so nothing committed in between is missed
+	sub := h.stream.Subscribe()
+	defer sub.Close()
+
+	// Streams outlive the write timeout of the server
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	if lastEventId != "" {
+		for {
+			events, err := h.stream.Replay(ctx, last, replayBatchSize)
+			if errors.Is(err, outbox.ErrReplayExpired) {
+				// Replaying again wouldn't help, the client has to reload
+				c.Render(-1, sse.Event{
+					Event: "expired",
+					Data: ErrorResponse{
+						Code:    "events_expired",
+						Message: "The events after the last event ID are no longer kept; reload the catalog",
+						Details: err.Error(),
+					},
+				})
+				c.Writer.Flush()
+				break
+			}
+			if err != nil {
+				// The client reconnects and replays again
+				return
+			}
+			for _, event := range events {
+				h.send(c, filter, event)
+				last = event.ID
+			}
+			if len(events) < replayBatchSize {
+				break
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind or shutting down, the client
+				// reconnects and replays
+				return
+			}
+			if event.ID <= last {
+				continue
+			}
+			h.send(c, filter, event)
+			last = event.ID
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// send writes event to the stream if it matches filter
+func (h *EventHandler) send(c *gin.Context, filter *business.EventFilter, event models.OutboxEvent) {
+	if !filter.Matches(c.Request.Context(), event) {
+		return
+	}
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatUint(event.ID, 10),
+		Event: event.Type,
+		Data:  outbox.ToEvent(event),
+	})
+	c.Writer.Flush()
+}
+
+// parseEventFilter parses the service_id, type and selector parameters,
+// writing a 400 response if they are invalid
+func (h *EventHandler) parseEventFilter(c *gin.Context) (*business.EventFilter, bool) {
+	var serviceIds []uint
+	if value := c.Query("service_id"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			serviceId, err := strconv.ParseUint(part, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Code:    "invalid_service_id",
+					Message: "Invalid service ID",
+					Details: err.Error(),
+				})
+				return nil, false
+			}
+			serviceIds = append(serviceIds, uint(serviceId))
+		}
+	}
+	var types []string
+	if value := c.Query("type"); value != "" {
+		for _, eventType := range strings.Split(value, ",") {
+			if !slices.Contains(models.EventTypes, eventType) {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Code:    "invalid_event_type",
+					Message: fmt.Sprintf("Unknown event type %q", eventType),
+					Details: "Event types must be one of " + strings.Join(models.EventTypes, ", "),
+				})
+				return nil, false
+			}
+			types = append(types, eventType)
+		}
+	}
+	selector, err := labels.Parse(c.Query("selector"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_selector",
+			Message: "The label selector is invalid",
+			Details: err.Error(),
+		})
+		return nil, false
+	}
+	return business.NewEventFilter(h.businessService, serviceIds, types, selector), true
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"services-api/internal/models"
+	"services-api/internal/outbox"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryEventLog is an in-memory OutboxRepository for the event stream
+type memoryEventLog struct {
+	mu            sync.Mutex
+	events        []models.OutboxEvent
+	prunedThrough uint64
+}
+
+func (m *memoryEventLog) AppendEvents(ctx context.Context, events []models.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, event := range events {
+		event.ID = uint64(len(m.events) + 1)
+		m.events = append(m.events, event)
+	}
+	return nil
+}
+func (m *memoryEventLog) ListEventsAfter(ctx context.Context, after uint64, limit int) ([]models.OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var events []models.OutboxEvent
+	for _, event := range m.events {
+		if event.ID > after && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+func (m *memoryEventLog) LatestEventID(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return uint64(len(m.events)), nil
+}
+func (m *memoryEventLog) ProcessPending(ctx context.Context, now time.Time, limit int, fn func(events []models.OutboxEvent)) (int, error) {
+	panic("not used")
+}
+func (m *memoryEventLog) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	panic("not used")
+}
+func (m *memoryEventLog) PrunedThrough(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.prunedThrough, nil
+}
+
+// streamEvents serves a stream request until timeout and returns the response
+func streamEvents(h *EventHandler, target string, header http.Header, timeout time.Duration) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.GET("/events/stream", h.StreamEvents)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", target, nil)
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestStreamEvents_Replay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &memoryEventLog{}
+	log.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", Type: models.EventServiceCreated, ServiceID: 1, Data: `{"id":1}`},
+		{EventID: "evt_2", Type: models.EventVersionCreated, ServiceID: 1, Data: `{"id":1}`},
+		{EventID: "evt_3", Type: models.EventVersionCreated, ServiceID: 2, Data: `{"id":2}`},
+		{EventID: "evt_4", Type: models.EventServiceCreated, ServiceID: 2, Data: `{"id":2}`},
+	})
+	h := NewEventHandler(outbox.NewStream(log, time.Hour), &mockBusinessService{})
+
+	w := streamEvents(h, "/events/stream?type=version.created", http.Header{"Last-Event-ID": {"1"}}, 50*time.Millisecond)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+	assert.Contains(t, w.Body.String(), "id:2\nevent:version.created\ndata:{\"id\":\"evt_2\"")
+	assert.Contains(t, w.Body.String(), "id:3\nevent:version.created\n")
+	assert.NotContains(t, w.Body.String(), "evt_1")
+	assert.NotContains(t, w.Body.String(), "evt_4")
+}
+
+func TestStreamEvents_ReplayExpired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &memoryEventLog{prunedThrough: 2}
+	log.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", Type: models.EventServiceCreated, ServiceID: 1, Data: `{"id":1}`},
+		{EventID: "evt_2", Type: models.EventServiceCreated, ServiceID: 2, Data: `{"id":2}`},
+		{EventID: "evt_3", Type: models.EventServiceCreated, ServiceID: 3, Data: `{"id":3}`},
+	})
+	h := NewEventHandler(outbox.NewStream(log, time.Hour), &mockBusinessService{})
+
+	w := streamEvents(h, "/events/stream", http.Header{"Last-Event-ID": {"1"}}, 50*time.Millisecond)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "event:expired\ndata:{\"code\":\"events_expired\"")
+	assert.NotContains(t, w.Body.String(), "evt_3", "events after a gap aren't replayed")
+
+	// Nothing is missing after the pruned events
+	w = streamEvents(h, "/events/stream", http.Header{"Last-Event-ID": {"2"}}, 50*time.Millisecond)
+	assert.NotContains(t, w.Body.String(), "event:expired")
+	assert.Contains(t, w.Body.String(), "id:3\n")
+}
+
+func TestStreamEvents_EndsOnClose(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stream := outbox.NewStream(&memoryEventLog{}, time.Hour)
+	h := NewEventHandler(stream, &mockBusinessService{})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- streamEvents(h, "/events/stream", nil, 5*time.Second)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	stream.Close()
+
+	select {
+	case w := <-done:
+		assert.Equal(t, http.StatusOK, w.Code)
+	case <-time.After(time.Second):
+		t.Fatal("expected the stream to end when the stream is closed")
+	}
+}
+
+func TestStreamEvents_LiveWithSelector(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &memoryEventLog{}
+	stream := outbox.NewStream(log, 5*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	mockBiz := &mockBusinessService{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			if id == 1 {
+				return &models.Service{ID: 1, Labels: map[string]string{"tier": "critical"}}, nil
+			}
+			return &models.Service{ID: id, Labels: map[string]string{"tier": "batch"}}, nil
+		},
+	}
+	h := NewEventHandler(stream, mockBiz)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- streamEvents(h, "/events/stream?selector=tier%3Dcritical", nil, 200*time.Millisecond)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	log.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", Type: models.EventVersionCreated, ServiceID: 2, Data: `{}`},
+		{EventID: "evt_2", Type: models.EventVersionCreated, ServiceID: 1, Data: `{}`},
+		// The labels in the event data take precedence over the lookup
+		{EventID: "evt_3", Type: models.EventServiceUpdated, ServiceID: 2, Data: `{"id":2,"labels":{"tier":"critical"}}`},
+	})
+	w := <-done
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "evt_1")
+	assert.Contains(t, w.Body.String(), "id:2\n")
+	assert.Contains(t, w.Body.String(), "id:3\n")
+}
+
+func TestStreamEvents_InvalidParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewEventHandler(outbox.NewStream(&memoryEventLog{}, time.Hour), &mockBusinessService{})
+
+	w := streamEvents(h, "/events/stream?type=service.renamed", nil, time.Second)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_event_type"`)
+
+	w = streamEvents(h, "/events/stream?service_id=abc", nil, time.Second)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_service_id"`)
+
+	w = streamEvents(h, "/events/stream", http.Header{"Last-Event-ID": {"-1"}}, time.Second)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_last_event_id"`)
+}
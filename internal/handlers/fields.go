@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/models"
+)
+
+// serviceFields are the service attributes that can be selected with ?fields=
+var serviceFields = []string{
+	"id", "name", "description", "created_at", "updated_at", "version_count",
+	"labels", "metadata", "versions", "dependencies",
+}
+
+// versionSorts are the accepted values of versions.sort
+var versionSorts = []string{"created_at", "-created_at", "updated_at", "-updated_at", "version", "-version"}
+
+// defaultExpandedVersions is the number of versions embedded by
+// expand=versions without versions.limit
+const defaultExpandedVersions = 10
+
+// maxExpandedVersions bounds versions.limit
+const maxExpandedVersions = 100
+
+// parseFields parses the comma-separated ?fields= parameter, writing a 400
+// response for unknown fields. It returns nil when all fields are wanted.
+func parseFields(c *gin.Context) ([]string, bool) {
+	value := c.Query("fields")
+	if value == "" {
+		return nil, true
+	}
+	fields := strings.Split(value, ",")
+	for _, field := range fields {
+		if !slices.Contains(serviceFields, field) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_fields",
+				Message: fmt.Sprintf("Unknown field %q", field),
+				Details: "Fields must be one of " + strings.Join(serviceFields, ", "),
+			})
+			return nil, false
+		}
+	}
+	return fields, true
+}
+
+// parseExpand parses ?expand=, versions.limit and versions.sort, writing a
+// 400 response if they are invalid. defaults applies when expand is absent
+// and versionLimit when versions are expanded explicitly without
+// versions.limit, so the versions of the defaults stay as bounded as before.
+func parseExpand(c *gin.Context, defaults models.ServiceExpand, versionLimit int) (models.ServiceExpand, bool) {
+	expand := defaults
+	if value, ok := c.GetQuery("expand"); ok {
+		expand = models.ServiceExpand{VersionLimit: versionLimit}
+		for _, name := range strings.Split(value, ",") {
+			switch name {
+			case "":
+			case "versions":
+				expand.Versions = true
+			case "dependencies":
+				expand.Dependencies = true
+			case "owner":
+				expand.Owner = true
+			default:
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Code:    "invalid_expand",
+					Message: fmt.Sprintf("Unknown expansion %q", name),
+					Details: "Expansions must be versions, dependencies or owner",
+				})
+				return expand, false
+			}
+		}
+	}
+	if !expand.Versions {
+		expand.VersionLimit = 0
+		return expand, true
+	}
+
+	if value := c.Query("versions.limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxExpandedVersions {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_versions_limit",
+				Message: fmt.Sprintf("versions.limit must be between 1 and %d", maxExpandedVersions),
+				Details: fmt.Sprintf("Provided value: %s", value),
+			})
+			return expand, false
+		}
+		expand.VersionLimit = limit
+	}
+
+	expand.VersionSort = c.DefaultQuery("versions.sort", "created_at")
+	if !slices.Contains(versionSorts, expand.VersionSort) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_versions_sort",
+			Message: "versions.sort must be one of " + strings.Join(versionSorts, ", "),
+			Details: fmt.Sprintf("Provided value: %s", expand.VersionSort),
+		})
+		return expand, false
+	}
+	return expand, true
+}
+
+// needsShaping reports whether a service response must be reshaped: empty
+// expanded lists are omitted by the models and fields trims attributes
+func needsShaping(fields []string, expand models.ServiceExpand) bool {
+	return fields != nil || expand.Versions || expand.Dependencies || expand.Owner
+}
+
+// shapeService converts a service to a map holding only the requested fields
+// and every expanded resource, with empty expansions rendered as empty lists
+func shapeService(service any, fields []string, expand models.ServiceExpand) (map[string]any, error) {
+	data, err := json.Marshal(service)
+	if err != nil {
+		return nil, err
+	}
+	var shaped map[string]any
+	if err := json.Unmarshal(data, &shaped); err != nil {
+		return nil, err
+	}
+
+	var expanded []string
+	if expand.Versions {
+		expanded = append(expanded, "versions")
+	}
+	if expand.Dependencies {
+		expanded = append(expanded, "dependencies")
+	}
+	for _, name := range expanded {
+		if _, ok := shaped[name]; !ok {
+			shaped[name] = []any{}
+		}
+	}
+	if expand.Owner {
+		expanded = append(expanded, "owner")
+	}
+
+	if fields != nil {
+		for name := range shaped {
+			if !slices.Contains(fields, name) && !slices.Contains(expanded, name) {
+				delete(shaped, name)
+			}
+		}
+	}
+	return shaped, nil
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+)
+
+// ImpactHandler handles impact analysis requests
+type ImpactHandler struct {
+	impactBusiness business.ImpactBusiness
+}
+
+// NewImpactHandler creates a new impact handler with the required business logic.
+func NewImpactHandler(impactBusiness business.ImpactBusiness) *ImpactHandler {
+	return &ImpactHandler{
+		impactBusiness: impactBusiness,
+	}
+}
+
+// ServiceImpact godoc
+// @Summary Impact of removing a service
+// @Description List every service that depends on the given service, directly or transitively, with the dependency path and its depth
+// @Tags impact
+// @Produce json
+// @Param sid path integer true "Service ID" minimum(1)
+// @Success 200 {object} models.ImpactReport "Affected services"
+// @Failure 400 {object} ErrorResponse "Invalid service ID"
+// @Failure 404 {object} ErrorResponse "Service not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/impact [get]
+func (h *ImpactHandler) ServiceImpact(c *gin.Context) {
+	serviceId, ok := parseServiceID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.impactBusiness.ServiceImpact(c.Request.Context(), serviceId)
+	if err != nil {
+		writeImpactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// VersionImpact godoc
+// @Summary Impact of retiring a version
+// @Description List every service affected by retiring the given version: the direct dependents whose version constraint it satisfies and the services that transitively depend on them. has_alternative is set when another active version also satisfies a direct dependent.
+// @Tags impact
+// @Produce json
+// @Param sid path integer true "Service ID" minimum(1)
+// @Param vid path integer true "Version ID" minimum(1)
+// @Success 200 {object} models.ImpactReport "Affected services"
+// @Failure 400 {object} ErrorResponse "Invalid ID"
+// @Failure 404 {object} ErrorResponse "Service or version not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{sid}/versions/{vid}/impact [get]
+func (h *ImpactHandler) VersionImpact(c *gin.Context) {
+	serviceId, ok := parseServiceID(c)
+	if !ok {
+		return
+	}
+	versionId, ok := parseVersionID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.impactBusiness.VersionImpact(c.Request.Context(), serviceId, versionId)
+	if err != nil {
+		writeImpactError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// writeImpactError maps impact analysis errors to responses
+func writeImpactError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, business.ErrServiceNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "service_not_found",
+			Message: "Service not found",
+			Details: "The requested service does not exist",
+		})
+	case errors.Is(err, business.ErrVersionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "version_not_found",
+			Message: "Version not found",
+			Details: "The requested version does not exist",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while analysing the impact",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+type mockImpactBusiness struct {
+	business.ImpactBusiness
+	VersionImpactFn       func(ctx context.Context, serviceID, versionID uint) (*models.ImpactReport, error)
+	PlanServiceDeletionFn func(ctx context.Context, serviceID uint) (*models.DeletionReport, error)
+	PlanVersionDeletionFn func(ctx context.Context, serviceID, versionID uint) (*models.DeletionReport, error)
+}
+
+func (m *mockImpactBusiness) VersionImpact(ctx context.Context, serviceID, versionID uint) (*models.ImpactReport, error) {
+	return m.VersionImpactFn(ctx, serviceID, versionID)
+}
+func (m *mockImpactBusiness) PlanServiceDeletion(ctx context.Context, serviceID uint) (*models.DeletionReport, error) {
+	return m.PlanServiceDeletionFn(ctx, serviceID)
+}
+func (m *mockImpactBusiness) PlanVersionDeletion(ctx context.Context, serviceID, versionID uint) (*models.DeletionReport, error) {
+	return m.PlanVersionDeletionFn(ctx, serviceID, versionID)
+}
+
+func TestVersionImpactHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewImpactHandler(&mockImpactBusiness{
+		VersionImpactFn: func(ctx context.Context, serviceID, versionID uint) (*models.ImpactReport, error) {
+			if versionID != 1 {
+				return nil, business.ErrVersionNotFound
+			}
+			return &models.ImpactReport{
+				Service:  models.GraphNode{ID: serviceID, Name: "users"},
+				Affected: []models.ImpactedService{{ID: 2, Name: "orders", Depth: 1}},
+				MaxDepth: 1,
+			}, nil
+		},
+	})
+	r := gin.New()
+	r.GET("/services/:sid/versions/:vid/impact", h.VersionImpact)
+
+	req, _ := http.NewRequest("GET", "/services/1/versions/1/impact", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"orders","depth":1`)
+
+	req, _ = http.NewRequest("GET", "/services/1/versions/2/impact", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest("GET", "/services/1/versions/x/impact", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteServiceHandler_DryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := &mockBusinessService{
+		DeleteServiceFn: func(ctx context.Context, id uint, force bool) error {
+			t.Error("dry run must not delete the service")
+			return nil
+		},
+	}
+	impact := &mockImpactBusiness{
+		PlanServiceDeletionFn: func(ctx context.Context, serviceID uint) (*models.DeletionReport, error) {
+			return &models.DeletionReport{DryRun: true, Service: models.GraphNode{ID: serviceID}, Blocked: true}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, impact)
+	r := gin.New()
+	r.DELETE("/services/:sid", h.DeleteService)
+
+	req, _ := http.NewRequest("DELETE", "/services/1?dry_run=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	assert.Contains(t, w.Body.String(), `"blocked":true`)
+}
+
+func TestDeleteVersionHandler_DryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	impact := &mockImpactBusiness{
+		PlanVersionDeletionFn: func(ctx context.Context, serviceID, versionID uint) (*models.DeletionReport, error) {
+			return nil, business.ErrVersionNotFound
+		},
+	}
+	h := NewVersionHandler(nil, impact)
+	r := gin.New()
+	r.DELETE("/services/:sid/versions/:vid", h.DeleteVersion)
+
+	req, _ := http.NewRequest("DELETE", "/services/1/versions/9?dry_run=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "version_not_found")
+}
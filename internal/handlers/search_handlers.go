@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+// SearchHandler handles full-text search requests
+type SearchHandler struct {
+	searchBusiness business.SearchBusiness
+}
+
+// NewSearchHandler creates a new search handler with the required business logic.
+func NewSearchHandler(searchBusiness business.SearchBusiness) *SearchHandler {
+	return &SearchHandler{
+		searchBusiness: searchBusiness,
+	}
+}
+
+// Search godoc
+// @Summary Search the catalog
+// @Description Full-text search across service names and descriptions, version numbers and descriptions, and labels. Hits are ranked by relevance and matches in the highlight are wrapped in <mark> tags. q supports quoted phrases, "or" and "-" to exclude words. Facets count the hits of every type regardless of the type filter.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search text, e.g. payment gateway"
+// @Param type query string false "Comma-separated hit types to return (service, version, label)"
+// @Param page query integer false "Page number" minimum(1) default(1)
+// @Param limit query integer false "Items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} models.SearchResponse "Search results"
+// @Failure 400 {object} ErrorResponse "Invalid search"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := models.SearchQuery{
+		Query: c.Query("q"),
+		Page:  parseIntOrDefault(c.Query("page"), 1),
+		Limit: parseIntOrDefault(c.Query("limit"), 10),
+	}
+	if types := c.Query("type"); types != "" {
+		query.Types = strings.Split(types, ",")
+	}
+
+	// Validate pagination parameters
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 10
+	}
+
+	result, err := h.searchBusiness.Search(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, business.ErrInvalidSearch) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_search",
+				Message: "The search query is invalid",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while searching",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
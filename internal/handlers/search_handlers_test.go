@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+type mockSearchBusiness struct {
+	SearchFn func(ctx context.Context, query models.SearchQuery) (*models.SearchResponse, error)
+}
+
+func (m *mockSearchBusiness) Search(ctx context.Context, query models.SearchQuery) (*models.SearchResponse, error) {
+	return m.SearchFn(ctx, query)
+}
+
+func TestSearchHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.SearchQuery
+	h := NewSearchHandler(&mockSearchBusiness{
+		SearchFn: func(ctx context.Context, query models.SearchQuery) (*models.SearchResponse, error) {
+			got = query
+			if query.Query == "" {
+				return nil, fmt.Errorf("%w: q is required", business.ErrInvalidSearch)
+			}
+			return &models.SearchResponse{
+				Hits:   []models.SearchHit{{Type: "version", ID: 4, ServiceID: 1, Title: "2.0.0", Highlight: "2.0.0: new <mark>payment</mark> flow"}},
+				Facets: []models.SearchFacet{{Type: "version", Count: 1}},
+			}, nil
+		},
+	})
+	r := gin.New()
+	r.GET("/search", h.Search)
+
+	req, _ := http.NewRequest("GET", "/search?q=payment&type=service,version&page=2&limit=500", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.SearchQuery{Query: "payment", Types: []string{"service", "version"}, Page: 2, Limit: 10}, got)
+	assert.Contains(t, w.Body.String(), `"facets":[{"type":"version","count":1}]`)
+
+	req, _ = http.NewRequest("GET", "/search", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_search")
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"services-api/internal/business"
+	"services-api/internal/expr"
+	"services-api/internal/labels"
 	"services-api/internal/models"
 )
 
@@ -21,26 +25,40 @@ type ErrorResponse struct {
 // ServiceHandler handles service-related HTTP requests
 type ServiceHandler struct {
 	service business.BusinessService
+	impact  business.ImpactBusiness
 }
 
 // NewServiceHandler creates a new service handler with the required service business logic.
-func NewServiceHandler(service business.BusinessService) *ServiceHandler {
+// impact answers dry run deletes.
+func NewServiceHandler(service business.BusinessService, impact business.ImpactBusiness) *ServiceHandler {
 	return &ServiceHandler{
 		service: service,
+		impact:  impact,
 	}
 }
 
 // ListServices godoc
 // @Summary List services
-// @Description Get a list of services with optional filtering, sorting, and pagination
+// @Description Get a list of services with optional filtering, sorting, and pagination. Pages are selected by page number or, for large catalogs, by the opaque next_cursor/prev_cursor of a previous response, which keep pages stable while services are added. Cursors keep the sort they were created with. The next and prev pages are also linked in an RFC 8288 Link header.
 // @Tags services
 // @Param name query string false "Filter by service name (case-insensitive, partial match)"
 // @Param description query string false "Filter by service description (case-insensitive, partial match)"
-// @Param sort query string false "Sort field (name, created_at)" default(created_at)
-// @Param order query string false "Sort order (asc, desc)" default(asc)
+// @Param filter query string false "Filter expression, e.g. created_at>2025-01-01 and version_count>=3 and name~\"pay\""
+// @Param sort query string false "Comma-separated sort fields (name, created_at, updated_at, version_count), prefixed with - for descending, e.g. -updated_at,name" default(name)
+// @Param order query string false "Sort order of a single sort field without prefix (asc, desc)" default(asc)
 // @Param page query integer false "Page number" minimum(1) default(1)
+// @Param cursor query string false "Cursor from a previous response, replaces page, sort and order"
 // @Param limit query integer false "Items per page" minimum(1) maximum(100) default(10)
+// @Param include_total query boolean false "Count the matching services; defaults to true with page and false with cursor"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,name,version_count"
+// @Param expand query string false "Comma-separated related resources to embed (versions, dependencies, owner)"
+// @Param versions.limit query integer false "Maximum number of embedded versions per service" minimum(1) maximum(100) default(10)
+// @Param versions.sort query string false "Order of embedded versions" Enums(created_at, -created_at, updated_at, -updated_at, version, -version) default(created_at)
+// @Param selector query string false "Label selector, e.g. tier=critical,lang in (go,rust),!deprecated"
+// @Param metadata.name query string false "Exact match on the custom field called name, e.g. metadata.sla_tier=gold"
 // @Success 200 {object} models.ServiceResponse "List of services"
+// @Header 200 {string} Link "Links to the next and prev pages"
+// @Failure 400 {object} ErrorResponse "Invalid cursor, sort, filter expression, label selector or custom field filter"
 // @Failure 500 {object} map[string]string "Error message"
 // @Router /services [get]
 func (h *ServiceHandler) ListServices(c *gin.Context) {
@@ -51,6 +69,55 @@ func (h *ServiceHandler) ListServices(c *gin.Context) {
 		Order:       c.DefaultQuery("order", "asc"),
 		Page:        parseIntOrDefault(c.Query("page"), 1),
 		Limit:       parseIntOrDefault(c.Query("limit"), 10),
+		Cursor:      c.Query("cursor"),
+	}
+	fields, ok := parseFields(c)
+	if !ok {
+		return
+	}
+	if filter.Expand, ok = parseExpand(c, models.ServiceExpand{}, defaultExpandedVersions); !ok {
+		return
+	}
+
+	// Offset mode counts by default for backward compatibility
+	filter.IncludeTotal = filter.Cursor == ""
+	if c.Query("include_total") != "" {
+		includeTotal, ok := parseBoolQuery(c, "include_total")
+		if !ok {
+			return
+		}
+		filter.IncludeTotal = includeTotal
+	}
+
+	selector, err := labels.Parse(c.Query("selector"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_selector",
+			Message: "The label selector is invalid",
+			Details: err.Error(),
+		})
+		return
+	}
+	filter.Selector = selector
+
+	expression, err := expr.Parse(c.Query("filter"), models.ServiceFilterFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_filter",
+			Message: "The filter expression is invalid",
+			Details: err.Error(),
+		})
+		return
+	}
+	filter.Expression = expression
+
+	for key, values := range c.Request.URL.Query() {
+		if name, ok := strings.CutPrefix(key, "metadata."); ok && len(values) > 0 {
+			if filter.Metadata == nil {
+				filter.Metadata = make(map[string]string)
+			}
+			filter.Metadata[name] = values[0]
+		}
 	}
 
 	// Validate pagination parameters
@@ -63,6 +130,30 @@ func (h *ServiceHandler) ListServices(c *gin.Context) {
 
 	result, err := h.service.ListServices(c.Request.Context(), filter)
 	if err != nil {
+		if errors.Is(err, business.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_filter",
+				Message: "The custom field filter is invalid",
+				Details: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, business.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_sort",
+				Message: "The sort is invalid",
+				Details: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, business.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "invalid_cursor",
+				Message: "The cursor is invalid",
+				Details: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Code:    "internal_error",
 			Message: "An error occurred while retrieving services",
@@ -71,16 +162,39 @@ func (h *ServiceHandler) ListServices(c *gin.Context) {
 		return
 	}
 
+	if link := paginationLinks(c, result.Pagination); link != "" {
+		c.Header("Link", link)
+	}
+
+	if needsShaping(fields, filter.Expand) {
+		services := make([]map[string]any, len(result.Services))
+		for i := range result.Services {
+			if services[i], err = shapeService(result.Services[i], fields, filter.Expand); err != nil {
+				c.JSON(http.StatusInternalServerError, ErrorResponse{
+					Code:    "internal_error",
+					Message: "An error occurred while rendering the services",
+					Details: err.Error(),
+				})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"services": services, "pagination": result.Pagination})
+		return
+	}
 	c.JSON(http.StatusOK, result)
 }
 
 // GetService godoc
 // @Summary Get a service
-// @Description Get details of a service by ID
+// @Description Get details of a service by ID. Its first 10 versions are embedded unless expand is given, which embeds only the listed resources. Page through all versions with GET /services/{sid}/versions.
 // @Tags services
 // @Accept json
 // @Produce json
 // @Param sid path integer true "Service ID" minimum(1)
+// @Param fields query string false "Comma-separated fields to return, e.g. id,name,version_count"
+// @Param expand query string false "Comma-separated related resources to embed (versions, dependencies, owner); all versions are embedded when absent" default(versions)
+// @Param versions.limit query integer false "Maximum number of embedded versions, 10 when versions are expanded explicitly" minimum(1) maximum(100)
+// @Param versions.sort query string false "Order of embedded versions" Enums(created_at, -created_at, updated_at, -updated_at, version, -version) default(created_at)
 // @Success 200 {object} models.Service "Service details"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 404 {object} map[string]string "Service not found"
@@ -96,8 +210,16 @@ func (h *ServiceHandler) GetService(c *gin.Context) {
 		})
 		return
 	}
+	fields, ok := parseFields(c)
+	if !ok {
+		return
+	}
+	expand, ok := parseExpand(c, models.ServiceExpand{Versions: true}, defaultExpandedVersions)
+	if !ok {
+		return
+	}
 
-	svc, err := h.service.GetService(c.Request.Context(), uint(id))
+	svc, err := h.service.GetService(c.Request.Context(), uint(id), expand)
 	if err != nil {
 		if err == business.ErrServiceNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{
@@ -114,6 +236,19 @@ func (h *ServiceHandler) GetService(c *gin.Context) {
 		return
 	}
 
+	if needsShaping(fields, expand) {
+		shaped, err := shapeService(svc, fields, expand)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Code:    "internal_error",
+				Message: "An error occurred while rendering the service",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, shaped)
+		return
+	}
 	c.JSON(http.StatusOK, svc)
 }
 
@@ -142,10 +277,16 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 	service := models.Service{
 		Name:        req.Name,
 		Description: req.Description,
+		Labels:      req.Labels,
+		Metadata:    req.Metadata,
 	}
 
 	createdService, err := h.service.CreateService(c.Request.Context(), service)
 	if err != nil {
+		if errors.Is(err, business.ErrInvalidService) {
+			writeInvalidService(c, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Code:    "internal_error",
 			Message: "An error occurred while creating the service",
@@ -195,10 +336,16 @@ func (h *ServiceHandler) UpdateService(c *gin.Context) {
 		ID:          uint(id),
 		Name:        req.Name,
 		Description: req.Description,
+		Labels:      req.Labels,
+		Metadata:    req.Metadata,
 	}
 
 	updatedService, err := h.service.UpdateService(c.Request.Context(), service)
 	if err != nil {
+		if errors.Is(err, business.ErrInvalidService) {
+			writeInvalidService(c, err)
+			return
+		}
 		if err == business.ErrServiceNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{
 				Code:    "service_not_found",
@@ -219,12 +366,16 @@ func (h *ServiceHandler) UpdateService(c *gin.Context) {
 
 // DeleteService godoc
 // @Summary Delete a service
-// @Description Delete a service with the given ID
+// @Description Delete a service with the given ID. Services that other services depend on are only deleted with force=true, which also removes those dependencies.
 // @Tags services
 // @Param sid path integer true "Service ID" minimum(1)
+// @Param force query boolean false "Delete even if other services depend on it"
+// @Param dry_run query boolean false "Report what would be deleted without deleting anything"
+// @Success 200 {object} models.DeletionReport "Dry run report"
 // @Success 204 "Service deleted successfully"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 404 {object} map[string]string "Service not found"
+// @Failure 409 {object} ErrorResponse "Other services depend on the service"
 // @Failure 500 {object} map[string]string "Error message"
 // @Router /services/{sid} [delete]
 func (h *ServiceHandler) DeleteService(c *gin.Context) {
@@ -238,7 +389,26 @@ func (h *ServiceHandler) DeleteService(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteService(c.Request.Context(), uint(id))
+	force, ok := parseBoolQuery(c, "force")
+	if !ok {
+		return
+	}
+	dryRun, ok := parseBoolQuery(c, "dry_run")
+	if !ok {
+		return
+	}
+
+	if dryRun {
+		report, err := h.impact.PlanServiceDeletion(c.Request.Context(), uint(id))
+		if err != nil {
+			writeImpactError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	err = h.service.DeleteService(c.Request.Context(), uint(id), force)
 	if err != nil {
 		if err == business.ErrServiceNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{
@@ -247,6 +417,14 @@ func (h *ServiceHandler) DeleteService(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, business.ErrServiceHasDependents) {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Code:    "service_has_dependents",
+				Message: "Other services depend on this service, use force=true to delete it anyway",
+				Details: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Code:    "internal_error",
 			Message: "An error occurred while deleting the service",
@@ -258,6 +436,105 @@ func (h *ServiceHandler) DeleteService(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ListLabels godoc
+// @Summary List label keys and values
+// @Description List the distinct label keys with their values and the number of services using each, for building facets
+// @Tags services
+// @Produce json
+// @Param key query string false "Only return this label key"
+// @Success 200 {array} models.LabelFacet "Label facets"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /labels [get]
+func (h *ServiceHandler) ListLabels(c *gin.Context) {
+	facets, err := h.service.ListLabelFacets(c.Request.Context(), c.Query("key"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "An error occurred while retrieving labels",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
+}
+
+func writeInvalidService(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Code:    "invalid_service",
+		Message: "The service is invalid",
+		Details: err.Error(),
+	})
+}
+
+// parseServiceID parses the :sid path parameter, writing a 400 response if it's invalid
+func parseServiceID(c *gin.Context) (uint, bool) {
+	serviceId, err := strconv.ParseUint(c.Param("sid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_service_id",
+			Message: "The service ID must be a positive integer",
+			Details: fmt.Sprintf("Provided ID: %s", c.Param("sid")),
+		})
+		return 0, false
+	}
+	return uint(serviceId), true
+}
+
+// parseVersionID parses the :vid path parameter, writing a 400 response if it's invalid
+func parseVersionID(c *gin.Context) (uint, bool) {
+	versionId, err := strconv.ParseUint(c.Param("vid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_version_id",
+			Message: "Invalid version ID",
+			Details: err.Error(),
+		})
+		return 0, false
+	}
+	return uint(versionId), true
+}
+
+// parseBoolQuery parses an optional boolean query parameter, writing a 400
+// response if it's malformed
+func parseBoolQuery(c *gin.Context, name string) (bool, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return false, true
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_" + name,
+			Message: fmt.Sprintf("The %s parameter must be a boolean", name),
+			Details: fmt.Sprintf("Provided value: %s", value),
+		})
+		return false, false
+	}
+	return parsed, true
+}
+
+// paginationLinks builds an RFC 8288 Link header value pointing at the next
+// and previous pages, keeping all other query parameters of the request
+func paginationLinks(c *gin.Context, pagination models.Pagination) string {
+	var links []string
+	for _, link := range []struct{ rel, cursor string }{
+		{"next", pagination.NextCursor},
+		{"prev", pagination.PrevCursor},
+	} {
+		if link.cursor == "" {
+			continue
+		}
+		query := c.Request.URL.Query()
+		query.Del("page")
+		query.Del("sort")
+		query.Del("order")
+		query.Set("cursor", link.cursor)
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, query.Encode(), link.rel))
+	}
+	return strings.Join(links, ", ")
+}
+
 // parseIntOrDefault parses a string into an integer. 
 // If parsing fails, it returns the provided default value.
 func parseIntOrDefault(s string, defaultValue int) int {
@@ -3,12 +3,15 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"services-api/internal/business"
+	"services-api/internal/expr"
 	"services-api/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -17,18 +20,19 @@ import (
 
 type mockBusinessService struct {
 	ListServicesFn        func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error)
-	GetServiceFn          func(ctx context.Context, id uint) (*models.Service, error)
+	GetServiceFn          func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error)
 	GetServiceVersionFn   func(ctx context.Context, serviceID uint, versionID uint) (*models.Version, error)
 	CreateServiceFn       func(ctx context.Context, service models.Service) (*models.Service, error)
 	UpdateServiceFn       func(ctx context.Context, service models.Service) (*models.Service, error)
-	DeleteServiceFn       func(ctx context.Context, id uint) error
+	DeleteServiceFn       func(ctx context.Context, id uint, force bool) error
+	ListLabelFacetsFn     func(ctx context.Context, key string) ([]models.LabelFacet, error)
 }
 
 func (m *mockBusinessService) ListServices(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
 	return m.ListServicesFn(ctx, filter)
 }
-func (m *mockBusinessService) GetService(ctx context.Context, id uint) (*models.Service, error) {
-	return m.GetServiceFn(ctx, id)
+func (m *mockBusinessService) GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+	return m.GetServiceFn(ctx, id, expand)
 }
 func (m *mockBusinessService) GetServiceVersion(ctx context.Context, serviceID uint, versionID uint) (*models.Version, error) {
 	return m.GetServiceVersionFn(ctx, serviceID, versionID)
@@ -39,8 +43,11 @@ func (m *mockBusinessService) CreateService(ctx context.Context, service models.
 func (m *mockBusinessService) UpdateService(ctx context.Context, service models.Service) (*models.Service, error) {
 	return m.UpdateServiceFn(ctx, service)
 }
-func (m *mockBusinessService) DeleteService(ctx context.Context, id uint) error {
-	return m.DeleteServiceFn(ctx, id)
+func (m *mockBusinessService) DeleteService(ctx context.Context, id uint, force bool) error {
+	return m.DeleteServiceFn(ctx, id, force)
+}
+func (m *mockBusinessService) ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error) {
+	return m.ListLabelFacetsFn(ctx, key)
 }
 
 
@@ -51,7 +58,7 @@ func TestListServicesHandler(t *testing.T) {
 			return &models.ServiceResponse{Services: []models.ServiceModel{{ID: 1, Name: "Test Service"}}}, nil
 		},
 	}
-	h := NewServiceHandler(mockSvc)
+	h := NewServiceHandler(mockSvc, nil)
 	r := gin.New()
 	r.GET("/services", h.ListServices)
 
@@ -65,11 +72,11 @@ func TestListServicesHandler(t *testing.T) {
 func TestGetServiceHandler_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := &mockBusinessService{
-		GetServiceFn: func(ctx context.Context, id uint) (*models.Service, error) {
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
 			return nil, business.ErrServiceNotFound
 		},
 	}
-	h := NewServiceHandler(mockSvc)
+	h := NewServiceHandler(mockSvc, nil)
 	r := gin.New()
 	r.GET("/services/:sid", h.GetService)
 
@@ -82,11 +89,11 @@ func TestGetServiceHandler_NotFound(t *testing.T) {
 func TestGetServiceHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := &mockBusinessService{
-		GetServiceFn: func(ctx context.Context, id uint) (*models.Service, error) {
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
 			return &models.Service{ID: 1, Name: "Test Service", Description: "Test Description", CreatedAt: time.Now(), UpdatedAt: time.Now(), VersionCount: 1}, nil
 		},
 	}
-	h := NewServiceHandler(mockSvc)
+	h := NewServiceHandler(mockSvc, nil)
 	r := gin.New()
 	r.GET("/services/:sid", h.GetService)
 
@@ -97,6 +104,120 @@ func TestGetServiceHandler_Success(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Test Service")
 }
 
+func TestGetServiceHandler_FieldsAndExpand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.ServiceExpand
+	mockSvc := &mockBusinessService{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			got = expand
+			return &models.Service{ID: 1, Name: "users", Description: "Users", VersionCount: 0}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/services/:sid", h.GetService)
+
+	// All versions are embedded by default, as an empty list when there are none
+	req, _ := http.NewRequest("GET", "/services/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ServiceExpand{Versions: true, VersionSort: "created_at"}, got)
+	assert.Contains(t, w.Body.String(), `"versions":[]`)
+
+	req, _ = http.NewRequest("GET", "/services/1?versions.limit=3", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, models.ServiceExpand{Versions: true, VersionLimit: 3, VersionSort: "created_at"}, got)
+
+	// Explicitly expanded versions are bounded
+	req, _ = http.NewRequest("GET", "/services/1?expand=versions", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, models.ServiceExpand{Versions: true, VersionLimit: 10, VersionSort: "created_at"}, got)
+
+	req, _ = http.NewRequest("GET", "/services/1?fields=id,name&expand=dependencies", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ServiceExpand{Dependencies: true}, got)
+	assert.JSONEq(t, `{"id":1,"name":"users","dependencies":[]}`, w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/services/1?expand=versions&versions.limit=5&versions.sort=-created_at", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, models.ServiceExpand{Versions: true, VersionLimit: 5, VersionSort: "-created_at"}, got)
+
+	for query, code := range map[string]string{
+		"expand=team":                      "invalid_expand",
+		"fields=id,secret":                 "invalid_fields",
+		"expand=versions&versions.limit=0": "invalid_versions_limit",
+		"versions.sort=name":               "invalid_versions_sort",
+	} {
+		req, _ = http.NewRequest("GET", "/services/1?"+query, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), code, query)
+	}
+}
+
+func TestGetServiceHandler_ExpandOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.ServiceExpand
+	mockSvc := &mockBusinessService{
+		GetServiceFn: func(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
+			got = expand
+			return &models.Service{ID: 1, Name: "users", Owner: &models.ServiceOwner{Team: "identity"}}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/services/:sid", h.GetService)
+
+	req, _ := http.NewRequest("GET", "/services/1?fields=id&expand=owner", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ServiceExpand{Owner: true}, got)
+	assert.JSONEq(t, `{"id":1,"owner":{"team":"identity"}}`, w.Body.String())
+}
+
+func TestListServicesHandler_Expand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.ServiceFilter
+	mockSvc := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			got = filter
+			return &models.ServiceResponse{
+				Services: []models.ServiceModel{
+					{ID: 1, Name: "users", Versions: []models.Version{{ID: 7, Version: "1.0.0"}}},
+					{ID: 2, Name: "orders"},
+				},
+				Pagination: models.Pagination{ItemsPerPage: 10},
+			}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/services", h.ListServices)
+
+	req, _ := http.NewRequest("GET", "/services?expand=versions&fields=name", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ServiceExpand{Versions: true, VersionLimit: 10, VersionSort: "created_at"}, got.Expand)
+	assert.Contains(t, w.Body.String(), `{"name":"orders","versions":[]}`)
+	assert.Contains(t, w.Body.String(), `"version":"1.0.0"`)
+	assert.NotContains(t, w.Body.String(), `"id":1`)
+
+	// Without expand nothing is embedded
+	req, _ = http.NewRequest("GET", "/services", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, models.ServiceExpand{}, got.Expand)
+}
+
 func TestCreateServiceHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := &mockBusinessService{
@@ -104,7 +225,7 @@ func TestCreateServiceHandler(t *testing.T) {
 			return &models.Service{ID: 1, Name: "Test Service"}, nil
 		},
 	}
-	h := NewServiceHandler(mockSvc)
+	h := NewServiceHandler(mockSvc, nil)
 	r := gin.New()
 	r.POST("/services", h.CreateService)
 
@@ -122,7 +243,7 @@ func TestUpdateServiceHandler(t *testing.T) {
 			return &models.Service{ID: 1, Name: "Updated Service"}, nil
 		},
 	}
-	h := NewServiceHandler(mockSvc)
+	h := NewServiceHandler(mockSvc, nil)
 	r := gin.New()
 	r.PATCH("/services/:sid", h.UpdateService)
 
@@ -136,16 +257,228 @@ func TestUpdateServiceHandler(t *testing.T) {
 func TestDeleteServiceHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := &mockBusinessService{
-		DeleteServiceFn: func(ctx context.Context, id uint) error {
+		DeleteServiceFn: func(ctx context.Context, id uint, force bool) error {
 			return nil
 		},
 	}
-	h := NewServiceHandler(mockSvc)
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.DELETE("/services/:sid", h.DeleteService)
+
+	req, _ := http.NewRequest("DELETE", "/services/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestDeleteServiceHandler_Dependents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := &mockBusinessService{
+		DeleteServiceFn: func(ctx context.Context, id uint, force bool) error {
+			if force {
+				return nil
+			}
+			return fmt.Errorf("%w: orders", business.ErrServiceHasDependents)
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
 	r := gin.New()
 	r.DELETE("/services/:sid", h.DeleteService)
 
 	req, _ := http.NewRequest("DELETE", "/services/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "service_has_dependents")
+
+	req, _ = http.NewRequest("DELETE", "/services/1?force=true", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/services/1?force=maybe", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListServicesHandler_Selector(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.ServiceFilter
+	mockSvc := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			got = filter
+			return &models.ServiceResponse{Services: []models.ServiceModel{}}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/services", h.ListServices)
+
+	req, _ := http.NewRequest("GET", "/services?selector="+url.QueryEscape("tier=critical,lang in (go,rust),!deprecated"), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, got.Selector, 3)
+
+	req, _ = http.NewRequest("GET", "/services?selector="+url.QueryEscape("lang in (go"), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_selector")
+}
+
+func TestListServicesHandler_Cursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.ServiceFilter
+	mockSvc := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			got = filter
+			if filter.Cursor == "bad" {
+				return nil, fmt.Errorf("%w: malformed", business.ErrInvalidCursor)
+			}
+			return &models.ServiceResponse{
+				Services:   []models.ServiceModel{},
+				Pagination: models.Pagination{ItemsPerPage: 5, NextCursor: "n3xt", PrevCursor: "pr3v"},
+			}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/services", h.ListServices)
+
+	req, _ := http.NewRequest("GET", "/services?cursor=abc&limit=5&name=pay&page=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abc", got.Cursor)
+	assert.False(t, got.IncludeTotal)
+	assert.Equal(t, `</services?cursor=n3xt&limit=5&name=pay>; rel="next", </services?cursor=pr3v&limit=5&name=pay>; rel="prev"`, w.Header().Get("Link"))
+	assert.NotContains(t, w.Body.String(), "total_items")
+
+	req, _ = http.NewRequest("GET", "/services?cursor=abc&include_total=true", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.True(t, got.IncludeTotal)
+
+	req, _ = http.NewRequest("GET", "/services", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.True(t, got.IncludeTotal, "offset mode counts by default")
+
+	req, _ = http.NewRequest("GET", "/services?include_total=maybe", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_include_total")
+
+	req, _ = http.NewRequest("GET", "/services?cursor=bad", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_cursor")
+}
+
+func TestListServicesHandler_FilterExpression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.ServiceFilter
+	mockSvc := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			got = filter
+			if filter.Sort == "id" {
+				return nil, fmt.Errorf("%w: id isn't sortable", business.ErrInvalidSort)
+			}
+			return &models.ServiceResponse{Services: []models.ServiceModel{}}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/services", h.ListServices)
+
+	req, _ := http.NewRequest("GET", "/services?sort=-updated_at,name&filter="+url.QueryEscape(`created_at>2025-01-01 and version_count>=3 and name~"pay"`), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "-updated_at,name", got.Sort)
+	assert.IsType(t, &expr.Logical{}, got.Expression)
+
+	req, _ = http.NewRequest("GET", "/services?filter="+url.QueryEscape(`owner="me"`), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_filter")
+
+	req, _ = http.NewRequest("GET", "/services?sort=id", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_sort")
+}
+
+func TestListServicesHandler_Metadata(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.ServiceFilter
+	mockSvc := &mockBusinessService{
+		ListServicesFn: func(ctx context.Context, filter models.ServiceFilter) (*models.ServiceResponse, error) {
+			got = filter
+			if filter.Metadata["sla_tier"] == "platinum" {
+				return nil, fmt.Errorf("%w: unknown option", business.ErrInvalidFilter)
+			}
+			return &models.ServiceResponse{Services: []models.ServiceModel{}}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/services", h.ListServices)
+
+	req, _ := http.NewRequest("GET", "/services?metadata.sla_tier=gold&metadata.cost=1.5&name=users", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]string{"sla_tier": "gold", "cost": "1.5"}, got.Metadata)
+
+	req, _ = http.NewRequest("GET", "/services?metadata.sla_tier=platinum", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_filter")
+}
+
+func TestCreateServiceHandler_InvalidLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := &mockBusinessService{
+		CreateServiceFn: func(ctx context.Context, service models.Service) (*models.Service, error) {
+			assert.Equal(t, "not valid", service.Labels["tier"])
+			return nil, fmt.Errorf("%w: bad label", business.ErrInvalidService)
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.POST("/services", h.CreateService)
+
+	req, _ := http.NewRequest("POST", "/services", bytes.NewBufferString(`{"name":"users","labels":{"tier":"not valid"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_service")
+}
+
+func TestListLabelsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := &mockBusinessService{
+		ListLabelFacetsFn: func(ctx context.Context, key string) ([]models.LabelFacet, error) {
+			assert.Equal(t, "tier", key)
+			return []models.LabelFacet{{Key: "tier", Values: []models.LabelValueCount{{Value: "critical", Count: 2}}}}, nil
+		},
+	}
+	h := NewServiceHandler(mockSvc, nil)
+	r := gin.New()
+	r.GET("/labels", h.ListLabels)
+
+	req, _ := http.NewRequest("GET", "/labels?key=tier", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `{"key":"tier","values":[{"value":"critical","count":2}]}`)
 }
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"services-api/internal/business"
 	"services-api/internal/models"
@@ -12,14 +16,82 @@ import (
 
 type VersionHandler struct {
 	versionBusiness business.VersionBusiness
+	impact          business.ImpactBusiness
 }
 
-func NewVersionHandler(versionBusiness business.VersionBusiness) *VersionHandler {
+func NewVersionHandler(versionBusiness business.VersionBusiness, impact business.ImpactBusiness) *VersionHandler {
 	return &VersionHandler{
 		versionBusiness: versionBusiness,
+		impact:          impact,
 	}
 }
 
+// ListVersions godoc
+// @Summary List the versions of a service
+// @Description Get a page of the versions of a service
+// @Tags versions
+// @Produce json
+// @Param sid path integer true "Service ID"
+// @Param sort query string false "Order of the versions" Enums(created_at, -created_at, updated_at, -updated_at, version, -version) default(created_at)
+// @Param page query integer false "Page number" minimum(1) default(1)
+// @Param limit query integer false "Items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} models.VersionResponse "List of versions"
+// @Failure 400 {object} ErrorResponse "Invalid service ID or sort"
+// @Failure 404 {object} ErrorResponse "Service not found"
+// @Failure 500 {object} ErrorResponse "Failed to list versions"
+// @Router /services/{sid}/versions [get]
+func (h *VersionHandler) ListVersions(c *gin.Context) {
+	serviceId, err := strconv.ParseUint(c.Param("sid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_service_id",
+			Message: "Invalid service ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	filter := models.VersionFilter{
+		ServiceID: uint(serviceId),
+		Sort:      c.DefaultQuery("sort", "created_at"),
+		Page:      parseIntOrDefault(c.Query("page"), 1),
+		Limit:     parseIntOrDefault(c.Query("limit"), 10),
+	}
+	if !slices.Contains(versionSorts, filter.Sort) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_sort",
+			Message: "sort must be one of " + strings.Join(versionSorts, ", "),
+			Details: fmt.Sprintf("Provided value: %s", filter.Sort),
+		})
+		return
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 10
+	}
+
+	result, err := h.versionBusiness.ListVersions(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, business.ErrServiceNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:    "service_not_found",
+				Message: fmt.Sprintf("Service with ID %d could not be found", serviceId),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to list versions",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // CreateVersion godoc
 // @Summary Create a new version
 // @Description Create a new version for a service
@@ -200,6 +272,60 @@ func (h *VersionHandler) UpdateVersion(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedVersion)
 }
 
+// DeprecateVersion godoc
+// @Summary Deprecate a version
+// @Description Mark a version as no longer active. Deprecated versions are kept but no longer satisfy the version constraints of dependencies in impact analysis.
+// @Tags versions
+// @Produce json
+// @Param sid path integer true "Service ID"
+// @Param vid path integer true "Version ID"
+// @Success 200 {object} models.Version "Deprecated version"
+// @Failure 400 {object} ErrorResponse "Invalid service or version ID"
+// @Failure 404 {object} ErrorResponse "Version not found"
+// @Failure 500 {object} ErrorResponse "Failed to deprecate version"
+// @Router /services/{sid}/versions/{vid}/deprecate [post]
+func (h *VersionHandler) DeprecateVersion(c *gin.Context) {
+	serviceId, err := strconv.ParseUint(c.Param("sid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_service_id",
+			Message: "Invalid service ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	versionId, err := strconv.ParseUint(c.Param("vid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_version_id",
+			Message: "Invalid version ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	version, err := h.versionBusiness.DeprecateVersion(c.Request.Context(), uint(serviceId), uint(versionId))
+	if err != nil {
+		if err == business.ErrVersionNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:    "version_not_found",
+				Message: "Version not found",
+				Details: "The requested version does not exist",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to deprecate version",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
 // DeleteVersion godoc
 // @Summary Delete a version
 // @Description Delete a version by ID
@@ -208,6 +334,8 @@ func (h *VersionHandler) UpdateVersion(c *gin.Context) {
 // @Produce json
 // @Param sid path integer true "Service ID"
 // @Param vid path integer true "Version ID"
+// @Param dry_run query boolean false "Report what would be deleted or left without a matching version without deleting anything"
+// @Success 200 {object} models.DeletionReport "Dry run report"
 // @Success 204 "Version deleted successfully"
 // @Failure 400 {object} ErrorResponse "Invalid version ID"
 // @Failure 404 {object} ErrorResponse "Version not found"
@@ -234,6 +362,20 @@ func (h *VersionHandler) DeleteVersion(c *gin.Context) {
 		return
 	}
 
+	dryRun, ok := parseBoolQuery(c, "dry_run")
+	if !ok {
+		return
+	}
+	if dryRun {
+		report, err := h.impact.PlanVersionDeletion(c.Request.Context(), uint(serviceId), uint(versionId))
+		if err != nil {
+			writeImpactError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
 	err = h.versionBusiness.DeleteVersion(c.Request.Context(), uint(versionId), uint(serviceId))
 	if err != nil {
 		if err == business.ErrVersionNotFound {
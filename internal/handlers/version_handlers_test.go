@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"services-api/internal/business"
 	"services-api/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -15,10 +16,12 @@ import (
 )
 
 type mockVersionBusiness struct {
-	CreateVersionFn func(ctx context.Context, version models.Version) (*models.Version, error)
-	GetVersionFn    func(ctx context.Context, id uint, serviceId uint) (*models.Version, error)
-	UpdateVersionFn func(ctx context.Context, version models.Version) (*models.Version, error)
-	DeleteVersionFn func(ctx context.Context, id uint, serviceId uint) error
+	CreateVersionFn    func(ctx context.Context, version models.Version) (*models.Version, error)
+	GetVersionFn       func(ctx context.Context, id uint, serviceId uint) (*models.Version, error)
+	UpdateVersionFn    func(ctx context.Context, version models.Version) (*models.Version, error)
+	DeleteVersionFn    func(ctx context.Context, id uint, serviceId uint) error
+	DeprecateVersionFn func(ctx context.Context, serviceId uint, id uint) (*models.Version, error)
+	ListVersionsFn     func(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error)
 }
 
 func (m *mockVersionBusiness) CreateVersion(ctx context.Context, version models.Version) (*models.Version, error) {
@@ -33,6 +36,44 @@ func (m *mockVersionBusiness) UpdateVersion(ctx context.Context, version models.
 func (m *mockVersionBusiness) DeleteVersion(ctx context.Context, id uint, serviceId uint) error {
 	return m.DeleteVersionFn(ctx, id, serviceId)
 }
+func (m *mockVersionBusiness) DeprecateVersion(ctx context.Context, serviceId uint, id uint) (*models.Version, error) {
+	return m.DeprecateVersionFn(ctx, serviceId, id)
+}
+
+func (m *mockVersionBusiness) ListVersions(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error) {
+	return m.ListVersionsFn(ctx, filter)
+}
+
+func TestListVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got models.VersionFilter
+	mockBiz := &mockVersionBusiness{
+		ListVersionsFn: func(ctx context.Context, filter models.VersionFilter) (*models.VersionResponse, error) {
+			got = filter
+			if filter.ServiceID != 1 {
+				return nil, business.ErrServiceNotFound
+			}
+			return &models.VersionResponse{Versions: []models.Version{{ID: 2, ServiceID: 1, Version: "2.0.0"}}}, nil
+		},
+	}
+	h := NewVersionHandler(mockBiz, nil)
+	r := gin.New()
+	r.GET("/services/:sid/versions", h.ListVersions)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/services/1/versions?sort=-version&page=2&limit=500", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "2.0.0")
+	assert.Equal(t, models.VersionFilter{ServiceID: 1, Sort: "-version", Page: 2, Limit: 10}, got)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/services/1/versions?sort=name", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/services/9/versions", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
 
 func TestCreateVersion_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -42,7 +83,7 @@ func TestCreateVersion_Success(t *testing.T) {
 			return &version, nil
 		},
 	}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.POST("/services/:sid/versions", h.CreateVersion)
 
@@ -58,7 +99,7 @@ func TestCreateVersion_Success(t *testing.T) {
 func TestCreateVersion_BadRequest(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockBiz := &mockVersionBusiness{}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.POST("/services/:sid/versions", h.CreateVersion)
 
@@ -76,7 +117,7 @@ func TestGetVersion_Success(t *testing.T) {
 			return &models.Version{ID: id, Version: "1.0.0"}, nil
 		},
 	}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.GET("/services/:sid/versions/:vid", h.GetVersion)
 
@@ -90,7 +131,7 @@ func TestGetVersion_Success(t *testing.T) {
 func TestGetVersion_BadRequest(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockBiz := &mockVersionBusiness{}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.GET("/services/:sid/versions/:vid", h.GetVersion)
 
@@ -107,7 +148,7 @@ func TestGetVersion_InternalError(t *testing.T) {
 			return nil, errors.New("db error")
 		},
 	}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.GET("/services/:sid/versions/:vid", h.GetVersion)
 
@@ -125,7 +166,7 @@ func TestUpdateVersion_Success(t *testing.T) {
 			return &version, nil
 		},
 	}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.PUT("/services/:sid/versions/:vid", h.UpdateVersion)
 
@@ -141,7 +182,7 @@ func TestUpdateVersion_Success(t *testing.T) {
 func TestUpdateVersion_BadRequest(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockBiz := &mockVersionBusiness{}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.PUT("/services/:sid/versions/:vid", h.UpdateVersion)
 
@@ -159,7 +200,7 @@ func TestDeleteVersion_Success(t *testing.T) {
 			return nil
 		},
 	}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.DELETE("/services/:sid/versions/:vid", h.DeleteVersion)
 
@@ -172,7 +213,7 @@ func TestDeleteVersion_Success(t *testing.T) {
 func TestDeleteVersion_BadRequest(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockBiz := &mockVersionBusiness{}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.DELETE("/services/:sid/versions/:vid", h.DeleteVersion)
 
@@ -189,7 +230,7 @@ func TestDeleteVersion_InternalError(t *testing.T) {
 			return errors.New("db error")
 		},
 	}
-	h := NewVersionHandler(mockBiz)
+	h := NewVersionHandler(mockBiz, nil)
 	r := gin.New()
 	r.DELETE("/services/:sid/versions/:vid", h.DeleteVersion)
 
@@ -198,3 +239,39 @@ func TestDeleteVersion_InternalError(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestDeprecateVersion_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockVersionBusiness{
+		DeprecateVersionFn: func(ctx context.Context, serviceId uint, id uint) (*models.Version, error) {
+			return &models.Version{ID: id, ServiceID: serviceId, Version: "1.0.0", IsActive: false}, nil
+		},
+	}
+	h := NewVersionHandler(mockBiz, nil)
+	r := gin.New()
+	r.POST("/services/:sid/versions/:vid/deprecate", h.DeprecateVersion)
+
+	req, _ := http.NewRequest("POST", "/services/1/versions/2/deprecate", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"is_active":false`)
+}
+
+func TestDeprecateVersion_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockVersionBusiness{
+		DeprecateVersionFn: func(ctx context.Context, serviceId uint, id uint) (*models.Version, error) {
+			return nil, business.ErrVersionNotFound
+		},
+	}
+	h := NewVersionHandler(mockBiz, nil)
+	r := gin.New()
+	r.POST("/services/:sid/versions/:vid/deprecate", h.DeprecateVersion)
+
+	req, _ := http.NewRequest("POST", "/services/1/versions/2/deprecate", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "version_not_found")
+}
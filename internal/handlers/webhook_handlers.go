@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+)
+
+const (
+	// defaultDeliveryLimit is the number of deliveries listed when no limit is given
+	defaultDeliveryLimit = 20
+
+	// maxDeliveryLimit is the largest number of deliveries listed at once
+	maxDeliveryLimit = 100
+)
+
+// WebhookHandler handles webhook subscription requests
+type WebhookHandler struct {
+	webhookBusiness business.WebhookBusiness
+}
+
+// NewWebhookHandler creates a new webhook handler with the required business logic.
+func NewWebhookHandler(webhookBusiness business.WebhookBusiness) *WebhookHandler {
+	return &WebhookHandler{
+		webhookBusiness: webhookBusiness,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook
+// @Description Subscribe a URL to catalog events. Events are POSTed as JSON signed with HMAC-SHA256 of the body in the X-Webhook-Signature-256 header. A secret is generated when none is given; it is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body models.WebhookRequest true "Webhook details"
+// @Success 201 {object} models.WebhookWithSecret "Created webhook including its secret"
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Admin scope required"
+// @Failure 500 {object} ErrorResponse "Failed to create webhook"
+// @Security ApiKeyAuth
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.webhookBusiness.CreateWebhook(c.Request.Context(), req)
+	if err != nil {
+		writeWebhookError(c, err, "Failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description List all webhooks, including disabled ones. Secrets are never returned.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.Webhook "Webhooks"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Admin scope required"
+// @Failure 500 {object} ErrorResponse "Failed to list webhooks"
+// @Security ApiKeyAuth
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookBusiness.ListWebhooks(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: "Failed to list webhooks",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Description Get a webhook with its failure count and, when it was disabled, why
+// @Tags webhooks
+// @Produce json
+// @Param wid path integer true "Webhook ID"
+// @Success 200 {object} models.Webhook "Webhook"
+// @Failure 400 {object} ErrorResponse "Invalid webhook ID"
+// @Failure 404 {object} ErrorResponse "Webhook not found"
+// @Failure 500 {object} ErrorResponse "Failed to get webhook"
+// @Security ApiKeyAuth
+// @Router /webhooks/{wid} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	webhookId, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookBusiness.GetWebhook(c.Request.Context(), webhookId)
+	if err != nil {
+		writeWebhookError(c, err, "Failed to get webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Replace a webhook
+// @Description Replace the URL, event types and service filter of a webhook. The secret is kept unless a new one is given. Set enabled to true to re-enable a webhook that was disabled after failing deliveries.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param wid path integer true "Webhook ID"
+// @Param webhook body models.WebhookRequest true "Webhook details"
+// @Success 200 {object} models.Webhook "Updated webhook"
+// @Failure 400 {object} ErrorResponse "Invalid webhook ID or request body"
+// @Failure 404 {object} ErrorResponse "Webhook not found"
+// @Failure 500 {object} ErrorResponse "Failed to update webhook"
+// @Security ApiKeyAuth
+// @Router /webhooks/{wid} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	webhookId, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_request_body",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.webhookBusiness.UpdateWebhook(c.Request.Context(), webhookId, req)
+	if err != nil {
+		writeWebhookError(c, err, "Failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery history
+// @Tags webhooks
+// @Param wid path integer true "Webhook ID"
+// @Success 204 "Webhook deleted"
+// @Failure 400 {object} ErrorResponse "Invalid webhook ID"
+// @Failure 404 {object} ErrorResponse "Webhook not found"
+// @Failure 500 {object} ErrorResponse "Failed to delete webhook"
+// @Security ApiKeyAuth
+// @Router /webhooks/{wid} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhookId, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookBusiness.DeleteWebhook(c.Request.Context(), webhookId); err != nil {
+		writeWebhookError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List the latest deliveries of a webhook, newest first, with the outcome of their last attempt
+// @Tags webhooks
+// @Produce json
+// @Param wid path integer true "Webhook ID"
+// @Param limit query integer false "Number of deliveries (default 20, max 100)"
+// @Success 200 {array} models.WebhookDelivery "Deliveries"
+// @Failure 400 {object} ErrorResponse "Invalid webhook ID"
+// @Failure 404 {object} ErrorResponse "Webhook not found"
+// @Failure 500 {object} ErrorResponse "Failed to list deliveries"
+// @Security ApiKeyAuth
+// @Router /webhooks/{wid}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	webhookId, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	limit := parseIntOrDefault(c.Query("limit"), defaultDeliveryLimit)
+	if limit < 1 || limit > maxDeliveryLimit {
+		limit = defaultDeliveryLimit
+	}
+
+	deliveries, err := h.webhookBusiness.ListDeliveries(c.Request.Context(), webhookId, limit)
+	if err != nil {
+		writeWebhookError(c, err, "Failed to list deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver godoc
+// @Summary Redeliver a webhook event
+// @Description Send the event of a delivery again. A new delivery is queued and retried like any other.
+// @Tags webhooks
+// @Produce json
+// @Param wid path integer true "Webhook ID"
+// @Param did path integer true "Delivery ID"
+// @Success 202 {object} models.WebhookDelivery "Queued delivery"
+// @Failure 400 {object} ErrorResponse "Invalid webhook or delivery ID"
+// @Failure 404 {object} ErrorResponse "Webhook or delivery not found"
+// @Failure 500 {object} ErrorResponse "Failed to redeliver"
+// @Security ApiKeyAuth
+// @Router /webhooks/{wid}/deliveries/{did}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	webhookId, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	deliveryId, err := strconv.ParseUint(c.Param("did"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_delivery_id",
+			Message: "Invalid delivery ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	delivery, err := h.webhookBusiness.Redeliver(c.Request.Context(), webhookId, uint(deliveryId))
+	if err != nil {
+		writeWebhookError(c, err, "Failed to redeliver")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// parseWebhookID parses the :wid path parameter, writing a 400 response if it's invalid
+func parseWebhookID(c *gin.Context) (uint, bool) {
+	webhookId, err := strconv.ParseUint(c.Param("wid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_webhook_id",
+			Message: "Invalid webhook ID",
+			Details: err.Error(),
+		})
+		return 0, false
+	}
+	return uint(webhookId), true
+}
+
+// writeWebhookError maps webhook business errors to responses
+func writeWebhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, business.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "invalid_webhook",
+			Message: "Invalid webhook request",
+			Details: err.Error(),
+		})
+	case errors.Is(err, business.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "webhook_not_found",
+			Message: "Webhook not found",
+			Details: "The requested webhook does not exist",
+		})
+	case errors.Is(err, business.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    "delivery_not_found",
+			Message: "Delivery not found",
+			Details: "The requested delivery does not exist for this webhook",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_server_error",
+			Message: message,
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"services-api/internal/business"
+	"services-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWebhookBusiness struct {
+	CreateWebhookFn  func(ctx context.Context, req models.WebhookRequest) (*models.WebhookWithSecret, error)
+	ListWebhooksFn   func(ctx context.Context) ([]models.Webhook, error)
+	GetWebhookFn     func(ctx context.Context, id uint) (*models.Webhook, error)
+	UpdateWebhookFn  func(ctx context.Context, id uint, req models.WebhookRequest) (*models.Webhook, error)
+	DeleteWebhookFn  func(ctx context.Context, id uint) error
+	ListDeliveriesFn func(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error)
+	RedeliverFn      func(ctx context.Context, webhookID uint, deliveryID uint) (*models.WebhookDelivery, error)
+}
+
+func (m *mockWebhookBusiness) CreateWebhook(ctx context.Context, req models.WebhookRequest) (*models.WebhookWithSecret, error) {
+	return m.CreateWebhookFn(ctx, req)
+}
+func (m *mockWebhookBusiness) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return m.ListWebhooksFn(ctx)
+}
+func (m *mockWebhookBusiness) GetWebhook(ctx context.Context, id uint) (*models.Webhook, error) {
+	return m.GetWebhookFn(ctx, id)
+}
+func (m *mockWebhookBusiness) UpdateWebhook(ctx context.Context, id uint, req models.WebhookRequest) (*models.Webhook, error) {
+	return m.UpdateWebhookFn(ctx, id, req)
+}
+func (m *mockWebhookBusiness) DeleteWebhook(ctx context.Context, id uint) error {
+	return m.DeleteWebhookFn(ctx, id)
+}
+func (m *mockWebhookBusiness) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error) {
+	return m.ListDeliveriesFn(ctx, webhookID, limit)
+}
+func (m *mockWebhookBusiness) Redeliver(ctx context.Context, webhookID uint, deliveryID uint) (*models.WebhookDelivery, error) {
+	return m.RedeliverFn(ctx, webhookID, deliveryID)
+}
+
+func TestCreateWebhook_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockWebhookBusiness{
+		CreateWebhookFn: func(ctx context.Context, req models.WebhookRequest) (*models.WebhookWithSecret, error) {
+			webhook := models.Webhook{ID: 1, URL: req.URL, Events: req.Events, Secret: "whsec_abc"}
+			return &models.WebhookWithSecret{Webhook: webhook, Secret: webhook.Secret}, nil
+		},
+	}
+	h := NewWebhookHandler(mockBiz)
+	r := gin.New()
+	r.POST("/webhooks", h.CreateWebhook)
+
+	payload := `{"url":"https://deploy.example.com/hooks","events":["version.created"]}`
+	req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"secret":"whsec_abc"`)
+	assert.Contains(t, w.Body.String(), `"events":["version.created"]`)
+}
+
+func TestCreateWebhook_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockWebhookBusiness{
+		CreateWebhookFn: func(ctx context.Context, req models.WebhookRequest) (*models.WebhookWithSecret, error) {
+			return nil, business.ErrInvalidWebhook
+		},
+	}
+	h := NewWebhookHandler(mockBiz)
+	r := gin.New()
+	r.POST("/webhooks", h.CreateWebhook)
+
+	req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"url":"ftp://example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_webhook")
+}
+
+func TestGetWebhook_HidesSecret(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockWebhookBusiness{
+		GetWebhookFn: func(ctx context.Context, id uint) (*models.Webhook, error) {
+			return &models.Webhook{ID: id, URL: "https://deploy.example.com/hooks", Secret: "whsec_abc"}, nil
+		},
+	}
+	h := NewWebhookHandler(mockBiz)
+	r := gin.New()
+	r.GET("/webhooks/:wid", h.GetWebhook)
+
+	req, _ := http.NewRequest("GET", "/webhooks/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "whsec_abc")
+}
+
+func TestGetWebhook_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockWebhookBusiness{
+		GetWebhookFn: func(ctx context.Context, id uint) (*models.Webhook, error) {
+			return nil, business.ErrWebhookNotFound
+		},
+	}
+	h := NewWebhookHandler(mockBiz)
+	r := gin.New()
+	r.GET("/webhooks/:wid", h.GetWebhook)
+
+	for path, code := range map[string]int{"/webhooks/9": http.StatusNotFound, "/webhooks/abc": http.StatusBadRequest} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestListDeliveries_Limit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var limits []int
+	mockBiz := &mockWebhookBusiness{
+		ListDeliveriesFn: func(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error) {
+			limits = append(limits, limit)
+			return []models.WebhookDelivery{{ID: 1, WebhookID: webhookID, Payload: `{"id":"evt_1"}`}}, nil
+		},
+	}
+	h := NewWebhookHandler(mockBiz)
+	r := gin.New()
+	r.GET("/webhooks/:wid/deliveries", h.ListDeliveries)
+
+	for _, path := range []string{"/webhooks/1/deliveries?limit=5", "/webhooks/1/deliveries?limit=500"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "evt_1")
+	}
+	assert.Equal(t, []int{5, defaultDeliveryLimit}, limits)
+}
+
+func TestRedeliver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBiz := &mockWebhookBusiness{
+		RedeliverFn: func(ctx context.Context, webhookID uint, deliveryID uint) (*models.WebhookDelivery, error) {
+			if deliveryID != 3 {
+				return nil, business.ErrDeliveryNotFound
+			}
+			return &models.WebhookDelivery{ID: 4, WebhookID: webhookID, Status: models.DeliveryPending}, nil
+		},
+	}
+	h := NewWebhookHandler(mockBiz)
+	r := gin.New()
+	r.POST("/webhooks/:wid/deliveries/:did/redeliver", h.Redeliver)
+
+	req, _ := http.NewRequest("POST", "/webhooks/1/deliveries/3/redeliver", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+
+	req, _ = http.NewRequest("POST", "/webhooks/1/deliveries/8/redeliver", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "delivery_not_found")
+}
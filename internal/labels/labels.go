@@ -0,0 +1,214 @@
+// Package labels validates service labels and parses Kubernetes-style label
+// selectors such as "tier=critical,lang in (go,rust),!deprecated".
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidSelector is returned when a label selector can't be parsed
+var ErrInvalidSelector = errors.New("invalid label selector")
+
+// ErrInvalidLabel is returned when a label key or value is malformed
+var ErrInvalidLabel = errors.New("invalid label")
+
+// MaxValueLength is the maximum length of a label value and of the name part of a key
+const MaxValueLength = 63
+
+var (
+	namePattern   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	prefixPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+)
+
+// Operator is the comparison of a selector requirement
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a single condition of a selector. Values holds one value
+// for Equals and NotEquals, the set for In and NotIn and nothing otherwise.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector is a conjunction of requirements
+type Selector []Requirement
+
+// Matches reports whether a set of labels satisfies every requirement
+func (s Selector) Matches(set map[string]string) bool {
+	for _, r := range s {
+		value, ok := set[r.Key]
+		switch r.Operator {
+		case Equals, In:
+			if !ok || !contains(r.Values, value) {
+				return false
+			}
+		case NotEquals, NotIn:
+			if ok && contains(r.Values, value) {
+				return false
+			}
+		case Exists:
+			if !ok {
+				return false
+			}
+		case DoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ValidateKey checks a label key: an optional DNS prefix followed by "/"
+// and a name of at most 63 alphanumerics, '-', '_' or '.'
+func ValidateKey(key string) error {
+	prefix, name, found := strings.Cut(key, "/")
+	if !found {
+		prefix, name = "", key
+	} else if prefix == "" || len(prefix) > 253 || !prefixPattern.MatchString(prefix) {
+		return fmt.Errorf("%w: key %q has a malformed prefix", ErrInvalidLabel, key)
+	}
+	if len(name) > MaxValueLength || !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: key %q must be at most %d alphanumerics, '-', '_' or '.', starting and ending with an alphanumeric", ErrInvalidLabel, key, MaxValueLength)
+	}
+	return nil
+}
+
+// ValidateValue checks a label value, which may be empty
+func ValidateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > MaxValueLength || !namePattern.MatchString(value) {
+		return fmt.Errorf("%w: value %q must be at most %d alphanumerics, '-', '_' or '.', starting and ending with an alphanumeric", ErrInvalidLabel, value, MaxValueLength)
+	}
+	return nil
+}
+
+// Validate checks every key and value of a label set
+func Validate(set map[string]string) error {
+	for key, value := range set {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+		if err := ValidateValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse parses a selector. An empty selector matches everything.
+func Parse(selector string) (Selector, error) {
+	var result Selector
+	for _, part := range splitRequirements(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if strings.TrimSpace(selector) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("%w: empty requirement", ErrInvalidSelector)
+		}
+		requirement, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, requirement)
+	}
+	return result, nil
+}
+
+// splitRequirements splits on the commas that aren't inside a value set
+func splitRequirements(selector string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, selector[start:])
+}
+
+func parseRequirement(s string) (Requirement, error) {
+	if strings.HasPrefix(s, "!") && !strings.HasPrefix(s, "!=") {
+		key := strings.TrimSpace(s[1:])
+		return requirement(key, DoesNotExist, nil)
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		if key, value, found := strings.Cut(s, op); found {
+			operator := Equals
+			if op == "!=" {
+				operator = NotEquals
+			}
+			return requirement(strings.TrimSpace(key), operator, []string{strings.TrimSpace(value)})
+		}
+	}
+
+	if open := strings.Index(s, "("); open >= 0 {
+		if !strings.HasSuffix(s, ")") {
+			return Requirement{}, fmt.Errorf("%w: missing ')' in %q", ErrInvalidSelector, s)
+		}
+		fields := strings.Fields(s[:open])
+		if len(fields) != 2 || (fields[1] != string(In) && fields[1] != string(NotIn)) {
+			return Requirement{}, fmt.Errorf("%w: expected \"key in (...)\" or \"key notin (...)\", got %q", ErrInvalidSelector, s)
+		}
+		inner := s[open+1 : len(s)-1]
+		if strings.TrimSpace(inner) == "" {
+			return Requirement{}, fmt.Errorf("%w: %s needs at least one value", ErrInvalidSelector, fields[1])
+		}
+		var values []string
+		for _, value := range strings.Split(inner, ",") {
+			values = append(values, strings.TrimSpace(value))
+		}
+		return requirement(fields[0], Operator(fields[1]), values)
+	}
+
+	if strings.ContainsAny(s, " \t()") {
+		return Requirement{}, fmt.Errorf("%w: can't parse %q", ErrInvalidSelector, s)
+	}
+	return requirement(s, Exists, nil)
+}
+
+func requirement(key string, operator Operator, values []string) (Requirement, error) {
+	if err := ValidateKey(key); err != nil {
+		return Requirement{}, fmt.Errorf("%w: %w", ErrInvalidSelector, err)
+	}
+	for _, value := range values {
+		if err := ValidateValue(value); err != nil {
+			return Requirement{}, fmt.Errorf("%w: %w", ErrInvalidSelector, err)
+		}
+	}
+	return Requirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package labels
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	selector, err := Parse("tier=critical, lang in (go, rust),!deprecated,team!=payments,env notin (dev),owner,app.kubernetes.io/name==api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Selector{
+		{Key: "tier", Operator: Equals, Values: []string{"critical"}},
+		{Key: "lang", Operator: In, Values: []string{"go", "rust"}},
+		{Key: "deprecated", Operator: DoesNotExist},
+		{Key: "team", Operator: NotEquals, Values: []string{"payments"}},
+		{Key: "env", Operator: NotIn, Values: []string{"dev"}},
+		{Key: "owner", Operator: Exists},
+		{Key: "app.kubernetes.io/name", Operator: Equals, Values: []string{"api"}},
+	}
+	if !reflect.DeepEqual(selector, want) {
+		t.Errorf("got %+v, want %+v", selector, want)
+	}
+
+	if selector, err := Parse("  "); err != nil || len(selector) != 0 {
+		t.Errorf("expected an empty selector, got %+v, %v", selector, err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, selector := range []string{
+		"tier=critical,,lang=go",
+		"lang in (go,rust",
+		"lang between (1,2)",
+		"lang in ()",
+		"tier=not valid",
+		"-tier=critical",
+		"tier critical",
+	} {
+		if _, err := Parse(selector); !errors.Is(err, ErrInvalidSelector) {
+			t.Errorf("expected ErrInvalidSelector for %q, got %v", selector, err)
+		}
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	selector, _ := Parse("tier=critical,lang in (go,rust),!deprecated,team!=payments")
+
+	tests := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{map[string]string{"tier": "critical", "lang": "go"}, true},
+		{map[string]string{"tier": "critical", "lang": "rust", "team": "search"}, true},
+		{map[string]string{"tier": "critical", "lang": "java"}, false},
+		{map[string]string{"tier": "critical", "lang": "go", "deprecated": ""}, false},
+		{map[string]string{"tier": "critical", "lang": "go", "team": "payments"}, false},
+		{map[string]string{"lang": "go"}, false},
+	}
+	for _, tt := range tests {
+		if got := selector.Matches(tt.labels); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(map[string]string{"tier": "critical", "example.com/owner": "team-a", "deprecated": ""}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, set := range []map[string]string{
+		{"": "x"},
+		{"tier": "has space"},
+		{"/name": "x"},
+		{"Example.com/name": "x"},
+		{"tier": "-critical"},
+	} {
+		if err := Validate(set); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("expected ErrInvalidLabel for %v, got %v", set, err)
+		}
+	}
+}
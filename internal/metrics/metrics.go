@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the request duration histogram
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// requestKey identifies a series of request metrics
+type requestKey struct {
+	method string
+	route  string
+	status int
+}
+
+// requestStats accumulates observations for one series
+type requestStats struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// HTTPMetrics collects request counts and latencies and exposes them in the
+// Prometheus text exposition format.
+type HTTPMetrics struct {
+	mu       sync.Mutex
+	requests map[requestKey]*requestStats
+	inFlight atomic.Int64
+}
+
+// NewHTTPMetrics creates an empty metrics collector
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: make(map[requestKey]*requestStats),
+	}
+}
+
+// Start marks the beginning of a request
+func (m *HTTPMetrics) Start() {
+	m.inFlight.Add(1)
+}
+
+// Observe records a completed request. The route should be the matched route
+// pattern rather than the raw path to keep the number of series bounded.
+func (m *HTTPMetrics) Observe(method, route string, status int, duration time.Duration) {
+	m.inFlight.Add(-1)
+
+	key := requestKey{method: method, route: route, status: status}
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.requests[key]
+	if !ok {
+		stats = &requestStats{buckets: make([]uint64, len(durationBuckets))}
+		m.requests[key] = stats
+	}
+	stats.count++
+	stats.sum += seconds
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+}
+
+// WriteTo writes all metrics in the Prometheus text format
+func (m *HTTPMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	keys := make([]requestKey, 0, len(m.requests))
+	snapshot := make(map[requestKey]requestStats, len(m.requests))
+	for key, stats := range m.requests {
+		keys = append(keys, key)
+		snapshot[key] = requestStats{count: stats.count, sum: stats.sum, buckets: append([]uint64(nil), stats.buckets...)}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	cw := &countingWriter{w: w}
+
+	fmt.Fprintln(cw, "# HELP http_requests_total Total number of HTTP requests.")
+	fmt.Fprintln(cw, "# TYPE http_requests_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(cw, "http_requests_total{%s} %d\n", key.labels(), snapshot[key].count)
+	}
+
+	fmt.Fprintln(cw, "# HELP http_request_duration_seconds HTTP request latencies in seconds.")
+	fmt.Fprintln(cw, "# TYPE http_request_duration_seconds histogram")
+	for _, key := range keys {
+		stats := snapshot[key]
+		for i, bound := range durationBuckets {
+			fmt.Fprintf(cw, "http_request_duration_seconds_bucket{%s,le=%q} %d\n", key.labels(), strconv.FormatFloat(bound, 'g', -1, 64), stats.buckets[i])
+		}
+		fmt.Fprintf(cw, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", key.labels(), stats.count)
+		fmt.Fprintf(cw, "http_request_duration_seconds_sum{%s} %g\n", key.labels(), stats.sum)
+		fmt.Fprintf(cw, "http_request_duration_seconds_count{%s} %d\n", key.labels(), stats.count)
+	}
+
+	fmt.Fprintln(cw, "# HELP http_requests_in_flight Number of HTTP requests currently being served.")
+	fmt.Fprintln(cw, "# TYPE http_requests_in_flight gauge")
+	fmt.Fprintf(cw, "http_requests_in_flight %d\n", m.inFlight.Load())
+
+	fmt.Fprintln(cw, "# HELP go_goroutines Number of goroutines that currently exist.")
+	fmt.Fprintln(cw, "# TYPE go_goroutines gauge")
+	fmt.Fprintf(cw, "go_goroutines %d\n", runtime.NumGoroutine())
+
+	return cw.n, cw.err
+}
+
+// Handler serves the metrics over HTTP
+func (m *HTTPMetrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
+
+func (k requestKey) labels() string {
+	return fmt.Sprintf("method=%q,route=%q,status=\"%d\"", k.method, k.route, k.status)
+}
+
+// countingWriter tracks the bytes written and the first error encountered
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/auth"
+	"services-api/internal/config"
+)
+
+// APIKeyHeader is the header machine clients use to present an API key
+const APIKeyHeader = "X-API-Key"
+
+// Authenticator establishes the identity of callers. Credentials are taken
+// from the X-API-Key header or an "Authorization: Bearer" header holding
+// either an API key or an HS256 JWT. Callers with a verified client
+// certificate are granted the configured client certificate scopes.
+type Authenticator struct {
+	apiKeys auth.APIKeyVerifier
+	cfg     atomic.Pointer[config.AuthConfig]
+	now     func() time.Time
+}
+
+// NewAuthenticator creates an authenticator verifying API keys with apiKeys
+func NewAuthenticator(cfg config.AuthConfig, apiKeys auth.APIKeyVerifier) *Authenticator {
+	authenticator := &Authenticator{
+		apiKeys: apiKeys,
+		now:     time.Now,
+	}
+	authenticator.Update(cfg)
+	return authenticator
+}
+
+// Update replaces the authentication settings
+func (a *Authenticator) Update(cfg config.AuthConfig) {
+	a.cfg.Store(&cfg)
+}
+
+// ErrAuthenticationRequired is returned by Authenticate when a caller
+// without credentials isn't allowed
+var ErrAuthenticationRequired = errors.New("authentication required")
+
+// Authenticate returns the identity granted by credential, an API key or a
+// JWT. Without credential, callers with a verified client certificate in
+// ctx get the client certificate scopes, and others get a nil identity when
+// authentication is optional or ErrAuthenticationRequired. Invalid
+// credentials return an error wrapping auth.ErrInvalidCredentials.
+func (a *Authenticator) Authenticate(ctx context.Context, credential string) (*auth.Identity, error) {
+	cfg := a.cfg.Load()
+	if credential == "" {
+		if identity, ok := auth.FromContext(ctx); ok && identity.Method == auth.MethodClientCert {
+			identity.Scopes = slices.Clone(cfg.ClientCertScopes)
+			return identity, nil
+		}
+		if cfg.Required {
+			return nil, ErrAuthenticationRequired
+		}
+		return nil, nil
+	}
+
+	if auth.IsAPIKey(credential) {
+		return a.apiKeys.VerifyAPIKey(ctx, credential)
+	}
+	return auth.ParseJWT(credential, cfg.JWTSecret, a.now())
+}
+
+// Middleware returns the gin handler authenticating requests
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		identity, err := a.Authenticate(ctx, credentialFromRequest(c.Request))
+		if err != nil {
+			if errors.Is(err, ErrAuthenticationRequired) {
+				abortUnauthorized(c, "authentication_required", "Authentication is required")
+				return
+			}
+			if errors.Is(err, auth.ErrInvalidCredentials) {
+				abortUnauthorized(c, "invalid_credentials", "The provided credentials are invalid, expired or revoked")
+				return
+			}
+			slog.Error("Failed to verify credentials", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{
+				Code:    "internal_server_error",
+				Message: "An error occurred while verifying credentials",
+			})
+			return
+		}
+
+		if identity != nil {
+			c.Request = c.Request.WithContext(auth.NewContext(ctx, identity))
+		}
+		c.Next()
+	}
+}
+
+// RequireScope rejects authenticated callers that lack scope or that are
+// restricted to other services than the one in the :sid path parameter.
+// Anonymous requests only reach this point when authentication is optional,
+// in which case they may read, but every other operation requires
+// credentials so anonymous callers can't do more than a read-only key.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			if scope != auth.ScopeRead {
+				abortUnauthorized(c, "authentication_required", "Authentication is required")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if !identity.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Code:    "insufficient_scope",
+				Message: "The credentials do not grant access to this operation",
+				Details: map[string]string{"required_scope": scope},
+			})
+			return
+		}
+
+		if len(identity.ServiceIDs) > 0 && !serviceAllowed(c, identity) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Code:    "service_forbidden",
+				Message: "The credentials are restricted to other services",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScopeIf applies RequireScope(scope) to the requests matching when,
+// for operations that need more than their route's scope with some parameters
+func RequireScopeIf(scope string, when func(c *gin.Context) bool) gin.HandlerFunc {
+	require := RequireScope(scope)
+	return func(c *gin.Context) {
+		if when(c) {
+			require(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// serviceAllowed checks the service restriction of an identity. Requests
+// that aren't about a single service are only allowed for reads.
+func serviceAllowed(c *gin.Context, identity *auth.Identity) bool {
+	sid := c.Param("sid")
+	if sid == "" {
+		return c.Request.Method == http.MethodGet
+	}
+	id, err := strconv.ParseUint(sid, 10, 32)
+	if err != nil {
+		// Let the handler report the malformed ID
+		return true
+	}
+	return identity.CanAccessService(uint(id))
+}
+
+func credentialFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return strings.TrimSpace(key)
+	}
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func abortUnauthorized(c *gin.Context, code, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="services-api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+		Code:    code,
+		Message: message,
+	})
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/auth"
+	"services-api/internal/config"
+)
+
+type stubAPIKeyVerifier map[string]*auth.Identity
+
+func (s stubAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*auth.Identity, error) {
+	if identity, ok := s[key]; ok {
+		return identity, nil
+	}
+	return nil, auth.ErrInvalidCredentials
+}
+
+func newAuthRouter(cfg config.AuthConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	verifier := stubAPIKeyVerifier{
+		"sk_ci_secret":     {Subject: "1", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeRead, auth.ScopeWriteVersions}, ServiceIDs: []uint{1}},
+		"sk_reader_secret": {Subject: "2", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeRead}},
+	}
+
+	router := gin.New()
+	router.Use(NewAuthenticator(cfg, verifier).Middleware())
+	router.GET("/services", RequireScope(auth.ScopeRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/services/:sid/versions", RequireScope(auth.ScopeWriteVersions), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/api-keys", RequireScope(auth.ScopeAdmin), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func authRequest(router *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticator_APIKeys(t *testing.T) {
+	router := newAuthRouter(config.AuthConfig{JWTSecret: "secret", Required: true})
+
+	w := authRequest(router, "POST", "/services/1/versions", map[string]string{APIKeyHeader: "sk_ci_secret"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Bearer API keys are accepted as well
+	w = authRequest(router, "POST", "/services/1/versions", map[string]string{"Authorization": "Bearer sk_ci_secret"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Restricted to service 1
+	w = authRequest(router, "POST", "/services/2/versions", map[string]string{APIKeyHeader: "sk_ci_secret"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "service_forbidden")
+
+	// Missing scope
+	w = authRequest(router, "POST", "/services/1/versions", map[string]string{APIKeyHeader: "sk_reader_secret"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "insufficient_scope")
+
+	// Unknown key
+	w = authRequest(router, "GET", "/services", map[string]string{APIKeyHeader: "sk_unknown_secret"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// No credentials
+	w = authRequest(router, "GET", "/services", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticator_JWT(t *testing.T) {
+	router := newAuthRouter(config.AuthConfig{JWTSecret: "secret", Required: true})
+
+	token, _ := auth.SignJWT(auth.Claims{Subject: "portal", Scope: "admin", ExpiresAt: time.Now().Add(time.Hour).Unix()}, "secret")
+	w := authRequest(router, "GET", "/api-keys", map[string]string{"Authorization": "Bearer " + token})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	forged, _ := auth.SignJWT(auth.Claims{Subject: "portal", Scope: "admin"}, "other-secret")
+	w = authRequest(router, "GET", "/api-keys", map[string]string{"Authorization": "Bearer " + forged})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticator_OptionalAuth(t *testing.T) {
+	router := newAuthRouter(config.AuthConfig{JWTSecret: "secret"})
+
+	// Anonymous requests are allowed when authentication is optional
+	assert.Equal(t, http.StatusOK, authRequest(router, "GET", "/services", nil).Code)
+
+	// but only to read
+	assert.Equal(t, http.StatusUnauthorized, authRequest(router, "GET", "/api-keys", nil).Code)
+	w := authRequest(router, "POST", "/services/1/versions", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "anonymous callers can't do more than a read-only key")
+	assert.Contains(t, w.Body.String(), "authentication_required")
+
+	// Presented credentials are still verified
+	w = authRequest(router, "GET", "/services", map[string]string{APIKeyHeader: "sk_unknown_secret"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireScopeIf(t *testing.T) {
+	router := newAuthRouter(config.AuthConfig{JWTSecret: "secret", Required: true})
+	replace := RequireScopeIf(auth.ScopeAdmin, func(c *gin.Context) bool { return c.Query("mode") == "replace" })
+	router.POST("/import", RequireScope(auth.ScopeRead), replace, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := authRequest(router, "POST", "/import?mode=merge", map[string]string{APIKeyHeader: "sk_reader_secret"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = authRequest(router, "POST", "/import?mode=replace", map[string]string{APIKeyHeader: "sk_reader_secret"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "insufficient_scope")
+}
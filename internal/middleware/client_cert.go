@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/auth"
+)
+
+// ClientCertificate records the identity of callers that authenticated with
+// a verified TLS client certificate, making it available to the auth layer
+// through auth.FromContext.
+func ClientCertificate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if identity, ok := auth.FromTLS(c.Request.TLS); ok {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), identity))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/config"
+)
+
+// CORS answers preflight requests and adds the cross-origin headers for
+// allowed origins. It has to be registered on the engine rather than a group
+// so that it also sees OPTIONS requests, which don't match any route.
+type CORS struct {
+	cfg atomic.Pointer[config.CORSConfig]
+}
+
+// NewCORS creates the CORS middleware
+func NewCORS(cfg config.CORSConfig) *CORS {
+	cors := &CORS{}
+	cors.Update(cfg)
+	return cors
+}
+
+// Update replaces the CORS settings
+func (m *CORS) Update(cfg config.CORSConfig) {
+	m.cfg.Store(&cfg)
+}
+
+// Middleware returns the gin handler applying the CORS policy
+func (m *CORS) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := m.cfg.Load()
+		origin := c.GetHeader("Origin")
+		if origin == "" || len(cfg.AllowedOrigins) == 0 {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !originAllowed(cfg.AllowedOrigins, origin) {
+			if preflight {
+				c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+					Code:    "cors_origin_forbidden",
+					Message: "The origin is not allowed to access this API",
+				})
+				return
+			}
+			// Browsers block the response without CORS headers
+			c.Next()
+			return
+		}
+
+		if slices.Contains(cfg.AllowedOrigins, "*") && !cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(cfg.ExposedHeaders) > 0 {
+				c.Header("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		method := strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))
+		if !slices.Contains(cfg.AllowedMethods, method) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Code:    "cors_method_forbidden",
+				Message: "The method is not allowed for cross-origin requests",
+				Details: map[string]string{"method": method},
+			})
+			return
+		}
+
+		requested := c.GetHeader("Access-Control-Request-Headers")
+		for _, header := range strings.Split(requested, ",") {
+			header = strings.TrimSpace(header)
+			if header != "" && !headerAllowed(cfg.AllowedHeaders, header) {
+				c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+					Code:    "cors_header_forbidden",
+					Message: "A requested header is not allowed for cross-origin requests",
+					Details: map[string]string{"header": header},
+				})
+				return
+			}
+		}
+
+		c.Header("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+		if slices.Contains(cfg.AllowedHeaders, "*") && requested != "" {
+			c.Header("Access-Control-Allow-Headers", requested)
+		} else if len(cfg.AllowedHeaders) > 0 {
+			c.Header("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+		}
+		if cfg.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// originAllowed matches an origin against exact origins, "*" and wildcard
+// subdomain patterns such as "https://*.example.com"
+func originAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+		scheme, domain, found := strings.Cut(pattern, "://*.")
+		if !found {
+			continue
+		}
+		prefix := strings.ToLower(scheme + "://")
+		suffix := strings.ToLower("." + domain)
+		lower := strings.ToLower(origin)
+		if strings.HasPrefix(lower, prefix) && strings.HasSuffix(lower, suffix) && len(lower) > len(prefix)+len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func headerAllowed(allowed []string, header string) bool {
+	for _, h := range allowed {
+		if h == "*" || strings.EqualFold(h, header) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/config"
+)
+
+func newCORSRouter(cfg config.CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewCORS(cfg).Middleware())
+	router.GET("/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, "/services", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_SimpleRequest(t *testing.T) {
+	router := newCORSRouter(config.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"GET"},
+		ExposedHeaders: []string{"X-RateLimit-Limit"},
+	})
+
+	w := corsRequest(router, "GET", "https://app.example.com", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-RateLimit-Limit", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Contains(t, w.Header().Values("Vary"), "Origin")
+
+	// Disallowed origins are served without CORS headers
+	w = corsRequest(router, "GET", "https://evil.example.org", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Same-origin requests are untouched
+	w = corsRequest(router, "GET", "", nil)
+	assert.Empty(t, w.Header().Get("Vary"))
+}
+
+func TestCORS_WildcardSubdomains(t *testing.T) {
+	router := newCORSRouter(config.CORSConfig{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET"},
+	})
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://a.b.example.com", true},
+		{"https://example.com", false},
+		{"http://app.example.com", false},
+		{"https://app.example.com.evil.org", false},
+		{"https://evilexample.com", false},
+	}
+	for _, tt := range tests {
+		w := corsRequest(router, "GET", tt.origin, nil)
+		if tt.allowed {
+			assert.Equal(t, tt.origin, w.Header().Get("Access-Control-Allow-Origin"), tt.origin)
+		} else {
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), tt.origin)
+		}
+	}
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	router := newCORSRouter(config.CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}})
+	w := corsRequest(router, "GET", "https://anything.test", nil)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	router := newCORSRouter(config.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+
+	// OPTIONS doesn't match a route but the preflight is still answered
+	w := corsRequest(router, "OPTIONS", "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "content-type, authorization",
+	})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	w = corsRequest(router, "OPTIONS", "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method": "DELETE",
+	})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "cors_method_forbidden")
+
+	w = corsRequest(router, "OPTIONS", "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method":  "GET",
+		"Access-Control-Request-Headers": "X-Custom",
+	})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "cors_header_forbidden")
+
+	w = corsRequest(router, "OPTIONS", "https://evil.example.org", map[string]string{
+		"Access-Control-Request-Method": "GET",
+	})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "cors_origin_forbidden")
+}
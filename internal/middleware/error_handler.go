@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +22,7 @@ func ErrorHandler() gin.HandlerFunc {
 		// Handle any errors that occurred during request processing
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last()
-			log.Printf("Error processing request: %v", err)
+			slog.Error("Error processing request", "error", err)
 
 			// Check if a response has already been written
 			if c.Writer.Written() {
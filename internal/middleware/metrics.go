@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/metrics"
+)
+
+// Metrics records the count and latency of every request
+func Metrics(m *metrics.HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.Start()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.Observe(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
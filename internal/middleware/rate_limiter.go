@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/auth"
+	"services-api/internal/config"
+	"services-api/internal/ratelimit"
+)
+
+// RateLimiter throttles requests per client using token buckets. Clients are
+// identified by the authenticated identity (API key, JWT subject or client
+// certificate) when present and by IP address otherwise, so Middleware must
+// run after the authentication middleware. Failed authentications are
+// limited per IP address by AuthFailures, which runs before it.
+type RateLimiter struct {
+	store ratelimit.Store
+	cfg   atomic.Pointer[config.RateLimitConfig]
+}
+
+// NewRateLimiter creates a rate limiter backed by the given store
+func NewRateLimiter(cfg config.RateLimitConfig, store ratelimit.Store) *RateLimiter {
+	limiter := &RateLimiter{store: store}
+	limiter.Update(cfg)
+	return limiter
+}
+
+// Update replaces the rate limit settings; existing buckets are kept
+func (l *RateLimiter) Update(cfg config.RateLimitConfig) {
+	l.cfg.Store(&cfg)
+}
+
+// Middleware returns the gin handler enforcing the limits
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := l.cfg.Load()
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		scope, limit := resolveLimit(cfg, c.Request.Method, c.FullPath())
+		result := l.take(c.Request.Context(), scope+"|"+ClientKey(c.Request.Context(), c.ClientIP()), limit)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter.Seconds())))
+		if !result.Allowed {
+			abortTooManyRequests(c, result)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuthFailures returns the gin handler limiting failed authentications per
+// IP address. It runs before authentication: every 401 response takes a
+// token from the bucket of the caller's IP address, with the default limit,
+// and callers with an empty bucket are rejected before their credentials
+// are checked, so guessing keys or tokens is throttled too.
+func (l *RateLimiter) AuthFailures() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.cfg.Load().Enabled {
+			c.Next()
+			return
+		}
+
+		ip := c.ClientIP()
+		if result := l.CheckAuthFailures(c.Request.Context(), ip); !result.Allowed {
+			abortTooManyRequests(c, result)
+			return
+		}
+		c.Next()
+		if c.Writer.Status() == http.StatusUnauthorized {
+			l.RecordAuthFailure(c.Request.Context(), ip)
+		}
+	}
+}
+
+// Allow takes a token from the bucket of client, a ClientKey, for a call
+// that isn't routed over HTTP, using the write limit for writes
+func (l *RateLimiter) Allow(ctx context.Context, client string, write bool) ratelimit.Result {
+	cfg := l.cfg.Load()
+	if !cfg.Enabled {
+		return ratelimit.Result{Allowed: true}
+	}
+	scope, limit := "default", ratelimit.Limit{Rate: cfg.RequestsPerSecond, Burst: cfg.Burst}
+	if write {
+		scope, limit = "write", ratelimit.Limit{Rate: cfg.WriteRequestsPerSecond, Burst: cfg.WriteBurst}
+	}
+	return l.take(ctx, scope+"|"+client, limit)
+}
+
+// CheckAuthFailures reports whether ip may still attempt to authenticate
+func (l *RateLimiter) CheckAuthFailures(ctx context.Context, ip string) ratelimit.Result {
+	cfg := l.cfg.Load()
+	if !cfg.Enabled {
+		return ratelimit.Result{Allowed: true}
+	}
+	result, err := l.store.Peek(ctx, authFailureKey(ip), ratelimit.Limit{Rate: cfg.RequestsPerSecond, Burst: cfg.Burst})
+	if err != nil {
+		// Fail open so that a broken store doesn't take the API down
+		slog.Error("Rate limit store error", "error", err)
+		return ratelimit.Result{Allowed: true}
+	}
+	return result
+}
+
+// RecordAuthFailure charges a failed authentication to ip
+func (l *RateLimiter) RecordAuthFailure(ctx context.Context, ip string) {
+	cfg := l.cfg.Load()
+	if !cfg.Enabled {
+		return
+	}
+	l.take(ctx, authFailureKey(ip), ratelimit.Limit{Rate: cfg.RequestsPerSecond, Burst: cfg.Burst})
+}
+
+// take consumes a token, allowing the request if the store fails
+func (l *RateLimiter) take(ctx context.Context, key string, limit ratelimit.Limit) ratelimit.Result {
+	result, err := l.store.Take(ctx, key, limit)
+	if err != nil {
+		// Fail open so that a broken store doesn't take the API down
+		slog.Error("Rate limit store error", "error", err)
+		return ratelimit.Result{Allowed: true, Limit: limit.Burst, Remaining: limit.Burst}
+	}
+	return result
+}
+
+func abortTooManyRequests(c *gin.Context, result ratelimit.Result) {
+	retryAfter := ceilSeconds(result.RetryAfter.Seconds())
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{
+		Code:    "rate_limit_exceeded",
+		Message: "Too many requests, please retry later",
+		Details: map[string]int{"retry_after_seconds": retryAfter},
+	})
+}
+
+// resolveLimit picks the limit for a request: a matching route override,
+// the write limit for mutating methods, or the default limit
+func resolveLimit(cfg *config.RateLimitConfig, method, route string) (string, ratelimit.Limit) {
+	for _, override := range cfg.Routes {
+		if override.Path == route && (override.Method == "" || strings.EqualFold(override.Method, method)) {
+			return override.Method + " " + override.Path, ratelimit.Limit{Rate: override.RequestsPerSecond, Burst: override.Burst}
+		}
+	}
+
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return "write", ratelimit.Limit{Rate: cfg.WriteRequestsPerSecond, Burst: cfg.WriteBurst}
+	}
+	return "default", ratelimit.Limit{Rate: cfg.RequestsPerSecond, Burst: cfg.Burst}
+}
+
+// ClientKey identifies the caller for rate limiting purposes: the identity
+// in ctx when authenticated, ip otherwise
+func ClientKey(ctx context.Context, ip string) string {
+	if identity, ok := auth.FromContext(ctx); ok {
+		return identity.Method + ":" + identity.Subject
+	}
+	return "ip:" + ip
+}
+
+func authFailureKey(ip string) string {
+	return "auth_failure|ip:" + ip
+}
+
+func ceilSeconds(seconds float64) int {
+	return int(math.Ceil(seconds))
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/config"
+	"services-api/internal/ratelimit"
+)
+
+func newRateLimitedRouter(limiter *RateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	router.GET("/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/services", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/services/:sid", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func doRequest(router *gin.Engine, method, path, ip string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_ReturnsTooManyRequests(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{
+		Enabled:                true,
+		RequestsPerSecond:      1,
+		Burst:                  2,
+		WriteRequestsPerSecond: 1,
+		WriteBurst:             1,
+	}, ratelimit.NewMemoryStore())
+	router := newRateLimitedRouter(limiter)
+
+	w := doRequest(router, "GET", "/services", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+
+	doRequest(router, "GET", "/services", "10.0.0.1")
+	w = doRequest(router, "GET", "/services", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "rate_limit_exceeded")
+
+	// Clients are limited independently
+	w = doRequest(router, "GET", "/services", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimiter_WriteAndRouteLimits(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{
+		Enabled:                true,
+		RequestsPerSecond:      1,
+		Burst:                  5,
+		WriteRequestsPerSecond: 1,
+		WriteBurst:             1,
+		Routes: []config.RouteRateLimit{
+			{Method: "GET", Path: "/services/:sid", RequestsPerSecond: 1, Burst: 1},
+		},
+	}, ratelimit.NewMemoryStore())
+	router := newRateLimitedRouter(limiter)
+
+	assert.Equal(t, http.StatusCreated, doRequest(router, "POST", "/services", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "POST", "/services", "10.0.0.1").Code)
+
+	// Reads have their own bucket
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/services", "10.0.0.1").Code)
+
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/services/1", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "GET", "/services/2", "10.0.0.1").Code)
+}
+
+func TestRateLimiter_DisabledAndUpdate(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{RequestsPerSecond: 1, Burst: 1}, ratelimit.NewMemoryStore())
+	router := newRateLimitedRouter(limiter)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/services", "10.0.0.1").Code)
+	}
+
+	limiter.Update(config.RateLimitConfig{Enabled: true, RequestsPerSecond: 1, Burst: 1})
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/services", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "GET", "/services", "10.0.0.1").Code)
+}
+
+func TestRateLimiter_AuthFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewRateLimiter(config.RateLimitConfig{
+		Enabled:                true,
+		RequestsPerSecond:      1,
+		Burst:                  2,
+		WriteRequestsPerSecond: 1,
+		WriteBurst:             1,
+	}, ratelimit.NewMemoryStore())
+	router := gin.New()
+	router.Use(limiter.AuthFailures())
+	router.Use(NewAuthenticator(config.AuthConfig{Required: true}, stubAPIKeyVerifier{}).Middleware())
+	router.Use(limiter.Middleware())
+	router.GET("/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Invalid keys are rejected until the failures exhaust the bucket of the IP
+	for i := 0; i < 2; i++ {
+		w := authRequest(router, "GET", "/services", map[string]string{APIKeyHeader: "sk_guess"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	w := authRequest(router, "GET", "/services", map[string]string{APIKeyHeader: "sk_guess"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/config"
+)
+
+// swaggerPathPrefix is served with its own, less strict content security
+// policy because the Swagger UI needs inline scripts and styles
+const swaggerPathPrefix = "/swagger/"
+
+// SecurityHeaders adds the standard security headers to every response
+type SecurityHeaders struct {
+	cfg atomic.Pointer[config.SecurityConfig]
+}
+
+// NewSecurityHeaders creates the security headers middleware
+func NewSecurityHeaders(cfg config.SecurityConfig) *SecurityHeaders {
+	headers := &SecurityHeaders{}
+	headers.Update(cfg)
+	return headers
+}
+
+// Update replaces the security header settings
+func (m *SecurityHeaders) Update(cfg config.SecurityConfig) {
+	m.cfg.Store(&cfg)
+}
+
+// Middleware returns the gin handler setting the headers
+func (m *SecurityHeaders) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := m.cfg.Load()
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+
+		csp := cfg.ContentSecurityPolicy
+		if strings.HasPrefix(c.Request.URL.Path, swaggerPathPrefix) {
+			csp = cfg.SwaggerContentSecurityPolicy
+		}
+		if csp != "" {
+			h.Set("Content-Security-Policy", csp)
+		}
+
+		// Browsers ignore HSTS over plain HTTP, so only send it over TLS or
+		// behind a proxy terminating TLS
+		if cfg.HSTSMaxAge > 0 && (c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https") {
+			value := "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+			if cfg.HSTSIncludeSubdomains {
+				value += "; includeSubDomains"
+			}
+			h.Set("Strict-Transport-Security", value)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/config"
+)
+
+func newSecurityHeadersRouter(headers *SecurityHeaders) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(headers.Middleware())
+	router.GET("/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/swagger/*any", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	cfg := config.Default().Security
+	cfg.HSTSMaxAge = 24 * time.Hour
+	cfg.HSTSIncludeSubdomains = true
+	router := newSecurityHeadersRouter(NewSecurityHeaders(cfg))
+
+	req, _ := http.NewRequest("GET", "/services", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, cfg.ContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	// HSTS is only sent over HTTPS
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+
+	req, _ = http.NewRequest("GET", "/swagger/index.html", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, cfg.SwaggerContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "max-age=86400; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecurityHeaders_Disabled(t *testing.T) {
+	headers := NewSecurityHeaders(config.Default().Security)
+	router := newSecurityHeadersRouter(headers)
+	headers.Update(config.SecurityConfig{Enabled: false})
+
+	req, _ := http.NewRequest("GET", "/services", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+}
@@ -1,18 +1,27 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
+
+	"services-api/internal/expr"
+	"services-api/internal/labels"
 )
 
 // Service represents a service in the organization
 type Service struct {
-	ID          uint      `json:"id" gorm:"primaryKey" example:"1"`
+	ID          uint      `json:"id" gorm:"primaryKey;index:idx_services_created_at_id,priority:2;index:idx_services_updated_at_id,priority:2" example:"1"`
 	Name        string    `json:"name" gorm:"unique;not null;index" example:"User Service"`
 	Description string    `json:"description" example:"Manages user authentication and profiles"`
-	CreatedAt   time.Time `json:"created_at" example:"2025-05-01T00:00:00Z"`
-	UpdatedAt   time.Time `json:"updated_at" example:"2025-05-01T00:00:00Z"`
-	Versions    []Version `json:"versions" gorm:"foreignKey:ServiceID"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index:idx_services_created_at_id,priority:1" example:"2025-05-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"index:idx_services_updated_at_id,priority:1" example:"2025-05-01T00:00:00Z"`
+	Versions    []Version `json:"versions,omitempty" gorm:"foreignKey:ServiceID"`
 	VersionCount int       `json:"version_count" gorm:"-" example:"1"`
+	Labels      map[string]string `json:"labels,omitempty" gorm:"-"`
+	Metadata    map[string]any    `json:"metadata,omitempty" gorm:"-"`
+	Dependencies []Dependency     `json:"dependencies,omitempty" gorm:"-"`
+	ManagedBy   string            `json:"managed_by,omitempty" gorm:"index" example:"gitops"` // Owner of declaratively managed services, empty for hand-created ones
+	Owner       *ServiceOwner     `json:"owner,omitempty" gorm:"-"` // Only with expand=owner
 }
 
 type ServiceModel struct {
@@ -22,6 +31,27 @@ type ServiceModel struct {
 	CreatedAt time.Time `json:"created_at" example:"2025-05-01T00:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2025-05-01T00:00:00Z"`
 	VersionCount int `json:"version_count" example:"1"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Versions []Version `json:"versions,omitempty"` // Only with expand=versions
+	Dependencies []Dependency `json:"dependencies,omitempty"` // Only with expand=dependencies
+	ManagedBy string `json:"managed_by,omitempty" example:"gitops"`
+	Owner *ServiceOwner `json:"owner,omitempty"` // Only with expand=owner
+}
+
+// OwnerLabel is the label naming the team that owns a service
+const OwnerLabel = "owner"
+
+// ServiceOwner is who owns a service: the team in its owner label and, for
+// declaratively managed services, what manages it
+type ServiceOwner struct {
+	Team      string `json:"team,omitempty" example:"identity"`
+	ManagedBy string `json:"managed_by,omitempty" example:"gitops"`
+}
+
+// NewServiceOwner returns the owner of a service with the given labels and managed_by
+func NewServiceOwner(labels map[string]string, managedBy string) *ServiceOwner {
+	return &ServiceOwner{Team: labels[OwnerLabel], ManagedBy: managedBy}
 }
 
 // Version represents a version of a service
@@ -43,6 +73,47 @@ type ServiceFilter struct {
 	Order       string `json:"order" example:"asc"`
 	Page        int    `json:"page" example:"1"`
 	Limit       int    `json:"limit" example:"10"`
+	Selector    labels.Selector `json:"-"`
+	Metadata    map[string]string `json:"-"` // Custom field values that must match exactly
+	Cursor      string `json:"-"` // Opaque cursor from a previous response, replaces Page
+	IncludeTotal bool  `json:"-"` // Count the matching services, which costs a COUNT(*)
+	Keyset      *Keyset `json:"-"` // Decoded cursor position, set by the business layer
+	Expand      ServiceExpand `json:"-"`
+	Expression  expr.Node `json:"-"` // Parsed ?filter= expression over ServiceFilterFields
+	SortFields  []SortField `json:"-"` // Parsed Sort and Order, set by the business layer
+}
+
+// ServiceFilterFields are the fields ?filter= expressions on services can use
+var ServiceFilterFields = expr.Fields{
+	"id":            expr.Number,
+	"name":          expr.String,
+	"description":   expr.String,
+	"created_at":    expr.Time,
+	"updated_at":    expr.Time,
+	"version_count": expr.Number,
+}
+
+// SortField is one column of a multi-column sort
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ServiceExpand selects the related resources loaded with services
+type ServiceExpand struct {
+	Versions     bool
+	VersionLimit int    // Versions per service, all when 0
+	VersionSort  string // created_at, updated_at or version, prefixed with "-" for descending
+	Dependencies bool
+	Owner        bool
+}
+
+// Keyset is a position in a list sorted by SortFields and then by ID, used for
+// cursor pagination. Values holds the sort field values of the row at the position.
+type Keyset struct {
+	Values   []any
+	ID       uint
+	Backward bool // Rows before the position instead of after it
 }
 
 // ServiceResponse represents the response for service list
@@ -51,12 +122,29 @@ type ServiceResponse struct {
 	Pagination Pagination  `json:"pagination"`
 }
 
-// Pagination contains pagination information
+// VersionFilter selects a page of the versions of a service
+type VersionFilter struct {
+	ServiceID uint
+	Sort      string // created_at, updated_at or version, prefixed with "-" for descending
+	Page      int
+	Limit     int
+}
+
+// VersionResponse represents the response for version list
+type VersionResponse struct {
+	Versions   []Version  `json:"versions"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination contains pagination information. The page and totals are only
+// set in offset mode and when the total was requested.
 type Pagination struct {
-	CurrentPage  int `json:"current_page" example:"1"`
-	TotalPages   int `json:"total_pages" example:"5"`
-	TotalItems   int `json:"total_items" example:"50"`
-	ItemsPerPage int `json:"items_per_page" example:"10"`
+	CurrentPage  int    `json:"current_page,omitempty" example:"1"`
+	TotalPages   *int   `json:"total_pages,omitempty" example:"5"`
+	TotalItems   *int   `json:"total_items,omitempty" example:"50"`
+	ItemsPerPage int    `json:"items_per_page" example:"10"`
+	NextCursor   string `json:"next_cursor,omitempty" example:"eyJzb3J0IjoibmFtZSIsIm9yZGVyIjoiYXNjIiwidmFsdWUiOiJ1c2VycyIsImlkIjo0Mn0"`
+	PrevCursor   string `json:"prev_cursor,omitempty"`
 }
 
 // VersionRequest represents the request body for creating/updating a version
@@ -69,4 +157,484 @@ type VersionRequest struct {
 type ServiceRequest struct {
 	Name        string `json:"name" example:"User Service"`
 	Description string `json:"description" example:"Manages user authentication and profiles"`
-}
\ No newline at end of file
+	Labels      map[string]string `json:"labels,omitempty"` // Replaces all labels when set
+	Metadata    map[string]any    `json:"metadata,omitempty"` // Custom field values, replaces all values when set
+}
+// APIKey represents a credential for machine clients such as CI pipelines.
+// Only a hash of the key is stored; the plaintext is returned once at creation.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey" example:"1"`
+	Name       string     `json:"name" gorm:"not null" example:"ci-pipeline"`
+	Prefix     string     `json:"prefix" gorm:"not null;uniqueIndex" example:"sk_1a2b3c4d"`
+	KeyHash    string     `json:"-" gorm:"not null"`
+	Scopes     []string   `json:"scopes" gorm:"serializer:json;not null" example:"read,write:versions"`
+	ServiceIDs []uint     `json:"service_ids,omitempty" gorm:"serializer:json"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2026-05-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2025-05-01T00:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" example:"2025-05-01T00:00:00Z"`
+	UpdatedAt  time.Time  `json:"updated_at" example:"2025-05-01T00:00:00Z"`
+}
+
+// APIKeyRequest represents the request body for creating an API key
+type APIKeyRequest struct {
+	Name       string     `json:"name" example:"ci-pipeline"`
+	Scopes     []string   `json:"scopes" example:"read,write:versions"`
+	ServiceIDs []uint     `json:"service_ids,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2026-05-01T00:00:00Z"`
+}
+
+// APIKeyWithSecret is returned when a key is created or rotated. The
+// plaintext key is only ever shown in this response.
+type APIKeyWithSecret struct {
+	APIKey
+	Key string `json:"key" example:"sk_1a2b3c4d_Zm9vYmFyYmF6..."`
+}
+
+// Dependency records that a service depends on another service, optionally
+// pinned to a version constraint such as ">=1.2.0, <2.0.0"
+type Dependency struct {
+	ID                uint      `json:"id" gorm:"primaryKey" example:"1"`
+	ServiceID         uint      `json:"service_id" gorm:"not null;uniqueIndex:idx_dependency_edge" example:"1"`
+	DependsOnID       uint      `json:"depends_on_id" gorm:"not null;uniqueIndex:idx_dependency_edge;index" example:"2"`
+	VersionConstraint string    `json:"version_constraint,omitempty" example:">=1.2.0, <2.0.0"`
+	ServiceName       string    `json:"service_name,omitempty" gorm:"->;-:migration" example:"Order Service"`
+	DependsOnName     string    `json:"depends_on_name,omitempty" gorm:"->;-:migration" example:"User Service"`
+	CreatedAt         time.Time `json:"created_at" example:"2025-05-01T00:00:00Z"`
+	UpdatedAt         time.Time `json:"updated_at" example:"2025-05-01T00:00:00Z"`
+}
+
+// DependencyRequest represents the request body for creating/updating a dependency
+type DependencyRequest struct {
+	DependsOnID       uint   `json:"depends_on_id" example:"2"`
+	VersionConstraint string `json:"version_constraint" example:">=1.2.0, <2.0.0"`
+}
+
+// GraphNode is a service in the dependency graph
+type GraphNode struct {
+	ID   uint   `json:"id" example:"1"`
+	Name string `json:"name" example:"User Service"`
+}
+
+// GraphEdge is a dependency in the dependency graph
+type GraphEdge struct {
+	From              uint   `json:"from" example:"1"`
+	To                uint   `json:"to" example:"2"`
+	VersionConstraint string `json:"version_constraint,omitempty" example:">=1.2.0, <2.0.0"`
+}
+
+// Graph is the dependency graph of all services
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// ImpactedService is a service affected by a change to another service,
+// directly or through a chain of dependencies
+type ImpactedService struct {
+	ID                uint        `json:"id" example:"3"`
+	Name              string      `json:"name" example:"Checkout Service"`
+	Depth             int         `json:"depth" example:"1"`                                   // 1 for direct dependents
+	Path              []GraphNode `json:"path"`                                                // From the impacted service to the changed one
+	VersionConstraint string      `json:"version_constraint,omitempty" example:">=1.2.0, <2.0.0"` // Constraint of direct dependents
+	HasAlternative    bool        `json:"has_alternative,omitempty" example:"true"`            // Another active version satisfies the constraint
+}
+
+// ImpactReport lists every service affected by retiring a service or one of its versions
+type ImpactReport struct {
+	Service  GraphNode         `json:"service"`
+	Version  *Version          `json:"version,omitempty"`
+	Affected []ImpactedService `json:"affected"`
+	MaxDepth int               `json:"max_depth" example:"2"`
+}
+
+// DeletionReport describes what a delete would remove or break, as returned by dry runs
+type DeletionReport struct {
+	DryRun       bool         `json:"dry_run" example:"true"`
+	Service      GraphNode    `json:"service"`
+	Version      *Version     `json:"version,omitempty"`      // The version being deleted
+	Versions     []Version    `json:"versions,omitempty"`     // Versions deleted with the service
+	Dependents   []Dependency `json:"dependents"`             // Dependencies on the target that would be removed or left unsatisfied
+	Dependencies []Dependency `json:"dependencies,omitempty"` // Dependencies of the service that would be removed
+	APIKeys      []APIKey     `json:"api_keys"`               // Active API keys scoped to the service
+	Blocked      bool         `json:"blocked" example:"false"` // The delete would be refused without force=true
+}
+
+// ServiceLabel is a key/value label of a service. The primary key makes
+// keys unique per service and the key/value index serves label selectors.
+type ServiceLabel struct {
+	ServiceID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Key       string `gorm:"primaryKey;index:idx_service_labels_key_value,priority:1"`
+	Value     string `gorm:"not null;index:idx_service_labels_key_value,priority:2"`
+}
+
+// LabelValueCount is a label value and the number of services that have it
+type LabelValueCount struct {
+	Value string `json:"value" example:"critical"`
+	Count int    `json:"count" example:"4"`
+}
+
+// LabelFacet is a label key with its distinct values
+type LabelFacet struct {
+	Key    string            `json:"key" example:"tier"`
+	Values []LabelValueCount `json:"values"`
+}
+
+// Custom field types
+const (
+	FieldTypeString = "string"
+	FieldTypeURL    = "url"
+	FieldTypeEnum   = "enum"
+	FieldTypeNumber = "number"
+	FieldTypeDate   = "date"
+)
+
+// CustomField is an admin-defined structured field of services, such as a
+// runbook link or an SLA tier
+type CustomField struct {
+	ID          uint      `json:"id" gorm:"primaryKey" example:"1"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex" example:"sla_tier"`
+	DisplayName string    `json:"display_name" example:"SLA tier"`
+	Description string    `json:"description,omitempty" example:"Support level agreed with the service owners"`
+	Type        string    `json:"type" gorm:"not null" example:"enum"`
+	Required    bool      `json:"required" example:"true"`
+	Options     []string  `json:"options,omitempty" gorm:"serializer:json" example:"gold,silver,bronze"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-05-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2025-05-01T00:00:00Z"`
+}
+
+// CustomFieldRequest represents the request body for creating/updating a custom field.
+// The name and type can't be changed after creation; omitted fields are left unchanged on update.
+type CustomFieldRequest struct {
+	Name        string   `json:"name" example:"sla_tier"`
+	DisplayName string   `json:"display_name" example:"SLA tier"`
+	Description string   `json:"description" example:"Support level agreed with the service owners"`
+	Type        string   `json:"type" example:"enum"`
+	Required    *bool    `json:"required,omitempty" example:"true"`
+	Options     []string `json:"options,omitempty" example:"gold,silver,bronze"`
+}
+
+// ServiceFieldValue is the value of a custom field for a service, stored in
+// canonical string form
+type ServiceFieldValue struct {
+	ServiceID uint   `gorm:"primaryKey;autoIncrement:false"`
+	FieldID   uint   `gorm:"primaryKey;autoIncrement:false;index:idx_service_field_values_field_value,priority:1"`
+	Value     string `gorm:"not null;index:idx_service_field_values_field_value,priority:2"`
+}
+
+// Search hit types
+const (
+	SearchTypeService = "service"
+	SearchTypeVersion = "version"
+	SearchTypeLabel   = "label"
+)
+
+// SearchQuery contains the parameters of a full-text search
+type SearchQuery struct {
+	Query string   `json:"q" example:"payment gateway"`
+	Types []string `json:"types,omitempty" example:"service,version"` // Restricts the hits, all types when empty
+	Page  int      `json:"page" example:"1"`
+	Limit int      `json:"limit" example:"10"`
+}
+
+// SearchHit is a single ranked search result. For versions and labels
+// ServiceID and ServiceName identify the service they belong to; labels have
+// no ID of their own, so ID is the service ID.
+type SearchHit struct {
+	Type        string  `json:"type" example:"service"`
+	ID          uint    `json:"id" example:"1"`
+	ServiceID   uint    `json:"service_id" example:"1"`
+	ServiceName string  `json:"service_name" example:"Payments"`
+	Title       string  `json:"title" example:"Payments"`
+	Highlight   string  `json:"highlight" example:"Card <mark>payment</mark> <mark>gateway</mark>"`
+	Rank        float64 `json:"rank" example:"0.6079271"`
+}
+
+// SearchFacet is the number of hits of a type, regardless of the type filter
+type SearchFacet struct {
+	Type  string `json:"type" example:"service"`
+	Count int    `json:"count" example:"3"`
+}
+
+// SearchResponse represents the response of a full-text search
+type SearchResponse struct {
+	Hits       []SearchHit   `json:"hits"`
+	Facets     []SearchFacet `json:"facets"`
+	Pagination Pagination    `json:"pagination"`
+}
+
+// Batch operations and modes
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+
+	BatchAtomic     = "atomic"      // All operations are applied in one transaction or none is
+	BatchBestEffort = "best_effort" // Each valid operation is applied on its own
+)
+
+// ServiceBatchOperation is one operation of a service batch. Service is
+// used by create and update, ID by update and delete.
+type ServiceBatchOperation struct {
+	Op      string         `json:"op" example:"create"`
+	ID      uint           `json:"id,omitempty" example:"1"`
+	Service ServiceRequest `json:"service"`
+	Force   bool           `json:"force,omitempty"` // Delete even if other services depend on it
+}
+
+// ServiceBatchRequest represents the request body of a service batch
+type ServiceBatchRequest struct {
+	Mode       string                  `json:"mode" example:"atomic"` // atomic (default) or best_effort
+	Operations []ServiceBatchOperation `json:"operations"`
+}
+
+// VersionBatchOperation is one operation of a version batch. Version is
+// used by create and update, ID by update and delete.
+type VersionBatchOperation struct {
+	Op      string         `json:"op" example:"create"`
+	ID      uint           `json:"id,omitempty" example:"1"`
+	Version VersionRequest `json:"version"`
+}
+
+// VersionBatchRequest represents the request body of a version batch
+type VersionBatchRequest struct {
+	Mode       string                  `json:"mode" example:"atomic"` // atomic (default) or best_effort
+	Operations []VersionBatchOperation `json:"operations"`
+}
+
+// BatchResult is the outcome of one batch operation, in request order
+type BatchResult struct {
+	Index  int    `json:"index" example:"0"`
+	Op     string `json:"op" example:"create"`
+	Status int    `json:"status" example:"201"` // HTTP status the operation would have had on its own
+	ID     uint   `json:"id,omitempty" example:"1"`
+	Code   string `json:"code,omitempty" example:"invalid_service"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchResponse represents the response of a batch
+type BatchResponse struct {
+	Mode      string        `json:"mode" example:"atomic"`
+	Succeeded int           `json:"succeeded" example:"2"`
+	Failed    int           `json:"failed" example:"0"`
+	Results   []BatchResult `json:"results"`
+}
+
+// Catalog is a portable dump of all services, identified by name rather
+// than ID so it can be kept in git and imported into any environment
+type Catalog struct {
+	Services []CatalogService `json:"services" yaml:"services"`
+}
+
+// CatalogService is a service of a catalog with its versions, labels and custom field values
+type CatalogService struct {
+	Name        string            `json:"name" yaml:"name" example:"User Service"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty" example:"Manages user authentication and profiles"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Metadata    map[string]any    `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Versions    []CatalogVersion  `json:"versions,omitempty" yaml:"versions,omitempty"`
+}
+
+// CatalogVersion is a version of a catalog service, identified by its version string
+type CatalogVersion struct {
+	Version     string `json:"version" yaml:"version" example:"1.0.0"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty" example:"Initial release"`
+}
+
+// Import modes
+const (
+	ImportMerge   = "merge"   // Create and update the imported services, keep the others
+	ImportReplace = "replace" // Also delete the services and versions missing from the import
+)
+
+// ImportOptions controls how a catalog is imported. With ManagedBy the
+// imported services are marked as managed by it and replace mode only
+// deletes the services it manages. Existing services with another owner
+// are reported as errors unless Adopt is set.
+type ImportOptions struct {
+	Mode      string
+	DryRun    bool
+	ManagedBy string
+	Adopt     bool // Take over existing services not managed by ManagedBy
+	Force     bool // Delete services other services depend on in replace mode
+}
+
+// Import change actions
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportDelete = "delete"
+)
+
+// ImportChange is a change an import makes, or would make in a dry run, to a service
+type ImportChange struct {
+	Action          string   `json:"action" example:"update"`
+	Service         string   `json:"service" example:"User Service"`
+	Fields          []string `json:"fields,omitempty" example:"description,labels"` // Changed service fields of updates
+	AddedVersions   []string `json:"added_versions,omitempty" example:"2.0.0"`
+	UpdatedVersions []string `json:"updated_versions,omitempty"`
+	RemovedVersions []string `json:"removed_versions,omitempty"`
+}
+
+// ImportError is a record of an import that was rejected. Index is the
+// position of the service in the catalog, starting at 0, or -1 for a
+// service missing from it that couldn't be deleted.
+type ImportError struct {
+	Index   int    `json:"index" example:"3"`
+	Service string `json:"service,omitempty" example:"User Service"`
+	Error   string `json:"error" example:"invalid service: name is required"`
+}
+
+// ImportReport is the outcome of an import. Rejected records are skipped
+// and listed in Errors while the others are applied.
+type ImportReport struct {
+	Mode      string         `json:"mode" example:"merge"`
+	DryRun    bool           `json:"dry_run" example:"false"`
+	Created   int            `json:"created" example:"2"`
+	Updated   int            `json:"updated" example:"1"`
+	Deleted   int            `json:"deleted" example:"0"`
+	Unchanged int            `json:"unchanged" example:"4"`
+	Changes   []ImportChange `json:"changes"`
+	Errors    []ImportError  `json:"errors"`
+}
+
+// Event types emitted for changes to the catalog
+const (
+	EventServiceCreated = "service.created"
+	EventServiceUpdated = "service.updated"
+	EventServiceDeleted = "service.deleted"
+	EventVersionCreated = "version.created"
+	EventVersionUpdated = "version.updated"
+	EventVersionDeleted = "version.deleted"
+)
+
+// EventTypes lists every event type
+var EventTypes = []string{
+	EventServiceCreated, EventServiceUpdated, EventServiceDeleted,
+	EventVersionCreated, EventVersionUpdated, EventVersionDeleted,
+}
+
+// Event is a change to the catalog. Data is the service or version after
+// the change, or before it for deletions.
+type Event struct {
+	ID         string          `json:"id" example:"evt_3f2a9c1d5e7b8a04"`
+	Type       string          `json:"type" example:"version.created"`
+	ServiceID  uint            `json:"service_id" example:"1"`
+	OccurredAt time.Time       `json:"occurred_at" example:"2025-05-01T00:00:00Z"`
+	Data       json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// OutboxEvent is an event stored in the transaction of the change it
+// describes, until it has been published. Events of the same service are
+// published in ID order.
+type OutboxEvent struct {
+	ID          uint64     `gorm:"primaryKey"`
+	EventID     string     `gorm:"not null;uniqueIndex"`
+	Type        string     `gorm:"not null"`
+	ServiceID   uint       `gorm:"not null;index"`
+	Data        string     `gorm:"type:text;not null"`
+	OccurredAt  time.Time  `gorm:"not null"`
+	PublishedAt *time.Time `gorm:"index"`
+	AvailableAt time.Time  `gorm:"not null"` // Earliest time of the next publish attempt
+	Attempts    int        `gorm:"not null;default:0"`
+	LastError   string
+}
+
+// OutboxPruning records how far the oldest events were deleted from the
+// outbox. There is a single row.
+type OutboxPruning struct {
+	ID            uint   `gorm:"primaryKey"`
+	PrunedThrough uint64 `gorm:"not null"` // ID of the last deleted event
+}
+
+// Entities and operations of the change feed
+const (
+	ChangeEntityService = "service"
+	ChangeEntityVersion = "version"
+
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// Change is a mutation of a service or version in the change feed. State is
+// the entity after the change; it is omitted for deletions, which are
+// tombstones. Deleting a service deletes its versions without a change of
+// their own.
+type Change struct {
+	Seq       uint64          `json:"seq" example:"42"`
+	Entity    string          `json:"entity" example:"version"`
+	Op        string          `json:"op" example:"update"`
+	ID        uint            `json:"id" example:"3"`
+	ServiceID uint            `json:"service_id" example:"1"`
+	ChangedAt time.Time       `json:"changed_at" example:"2025-05-01T00:00:00Z"`
+	State     json.RawMessage `json:"state,omitempty" swaggertype:"object"`
+}
+
+// ChangeFeed is a page of the change feed. NextSince is the since of the
+// next page, and stays the same when there are no new changes.
+type ChangeFeed struct {
+	Changes   []Change `json:"changes"`
+	NextSince uint64   `json:"next_since" example:"42"`
+	HasMore   bool     `json:"has_more" example:"false"`
+}
+
+// Webhook is a subscription delivering events to a URL. Payloads are signed
+// with the secret, which is only returned when the webhook is created.
+type Webhook struct {
+	ID                  uint       `json:"id" gorm:"primaryKey" example:"1"`
+	URL                 string     `json:"url" gorm:"not null" example:"https://deploy.example.com/hooks/catalog"`
+	Events              []string   `json:"events" gorm:"serializer:json;not null" example:"version.created,service.deleted"`
+	ServiceIDs          []uint     `json:"service_ids,omitempty" gorm:"serializer:json"` // Only deliver events of these services
+	Secret              string     `json:"-" gorm:"not null"`
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"0"` // Deliveries that failed every attempt since the last success
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty" example:"5 consecutive deliveries failed"`
+	CreatedAt           time.Time  `json:"created_at" example:"2025-05-01T00:00:00Z"`
+	UpdatedAt           time.Time  `json:"updated_at" example:"2025-05-01T00:00:00Z"`
+}
+
+// WebhookRequest represents the request body for creating or replacing a webhook
+type WebhookRequest struct {
+	URL        string   `json:"url" example:"https://deploy.example.com/hooks/catalog"`
+	Events     []string `json:"events" example:"version.created,service.deleted"` // Event types, or "*" for all
+	ServiceIDs []uint   `json:"service_ids,omitempty"`
+	Secret     string   `json:"secret,omitempty"`                  // Generated when omitted on creation, kept when omitted on update
+	Enabled    *bool    `json:"enabled,omitempty" example:"true"` // Re-enables a disabled webhook or disables it
+}
+
+// WebhookWithSecret is returned when a webhook is created. The secret is
+// only ever shown in this response.
+type WebhookWithSecret struct {
+	Webhook
+	Secret string `json:"secret" example:"whsec_9c1d5e7b8a043f2a..."`
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is an event sent, or to be sent, to a webhook and the
+// outcome of its latest attempt. An event is queued once per webhook;
+// redeliveries are the only other deliveries of it and point at the
+// delivery they copy.
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey" example:"1"`
+	RedeliveryOf   *uint      `json:"redelivery_of,omitempty" example:"1"`
+	WebhookID      uint       `json:"webhook_id" gorm:"not null;index" example:"1"`
+	EventID        string     `json:"event_id" gorm:"not null" example:"evt_3f2a9c1d5e7b8a04"`
+	EventType      string     `json:"event_type" gorm:"not null" example:"version.created"`
+	Payload        string     `json:"-" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"not null;index:idx_webhook_deliveries_due,priority:1" example:"pending"`
+	Attempts       int        `json:"attempts" example:"1"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus int        `json:"response_status,omitempty" example:"502"`
+	Error          string     `json:"error,omitempty" example:"unexpected status 502"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" example:"2025-05-01T00:00:00Z"`
+	UpdatedAt      time.Time  `json:"updated_at" example:"2025-05-01T00:00:00Z"`
+}
@@ -0,0 +1,169 @@
+// Package outbox publishes the events stored in the outbox table. Events
+// are written in the transaction of the change they describe, so none is
+// lost when the process stops right after a change is committed. The
+// dispatcher polls the table and hands the events to a Publisher, at least
+// once and in order for each service. Instances lock the events they
+// publish, so several of them can run against the same database.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"log/slog"
+	"sync"
+	"time"
+
+	"services-api/internal/config"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// pruneInterval is how often published events older than the retention are deleted
+const pruneInterval = time.Hour
+
+// Publisher sends events to a broker or another system; adapters for
+// brokers such as NATS or Kafka implement it. An event may be published
+// more than once, when an instance stops before recording that it was
+// published, so consumers should deduplicate on the event ID.
+type Publisher interface {
+	Publish(ctx context.Context, event models.Event) error
+}
+
+// Multi returns a publisher sending every event to each of publishers in
+// turn. When one fails the event is published to all of them again.
+func Multi(publishers ...Publisher) Publisher {
+	return multiPublisher(publishers)
+}
+
+type multiPublisher []Publisher
+
+func (m multiPublisher) Publish(ctx context.Context, event models.Event) error {
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriterPublisher writes events as JSON lines, for example to stdout when testing
+type WriterPublisher struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriterPublisher creates a publisher writing to w
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{encoder: json.NewEncoder(w)}
+}
+
+// Publish writes event on a line of its own
+func (p *WriterPublisher) Publish(ctx context.Context, event models.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.encoder.Encode(event)
+}
+
+// Dispatcher publishes the events of the outbox
+type Dispatcher struct {
+	repo      repository.OutboxRepository
+	publisher Publisher
+	config    config.OutboxConfig
+	now       func() time.Time
+}
+
+// NewDispatcher creates a dispatcher publishing the events of repo to publisher
+func NewDispatcher(repo repository.OutboxRepository, publisher Publisher, cfg config.OutboxConfig) *Dispatcher {
+	return &Dispatcher{
+		repo:      repo,
+		publisher: publisher,
+		config:    cfg,
+		now:       time.Now,
+	}
+}
+
+// Run publishes pending events every poll interval until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	var pruned time.Time
+	for {
+		for {
+			n, err := d.publishPending(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Error("Failed to publish outbox events", "error", err)
+				}
+				break
+			}
+			if n == 0 {
+				break
+			}
+		}
+		if d.now().Sub(pruned) >= pruneInterval {
+			d.prune(ctx)
+			pruned = d.now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishPending publishes a batch of events and returns how many were
+// processed. Only the oldest pending event of a service is publishable, so
+// when one fails the later events of its service wait for its retry.
+func (d *Dispatcher) publishPending(ctx context.Context) (int, error) {
+	return d.repo.ProcessPending(ctx, d.now(), d.config.BatchSize, func(events []models.OutboxEvent) {
+		for i := range events {
+			event := &events[i]
+			event.Attempts++
+			if err := d.publisher.Publish(ctx, ToEvent(*event)); err != nil {
+				event.LastError = err.Error()
+				event.AvailableAt = d.now().Add(backoff(d.config, event.Attempts))
+				slog.Warn("Failed to publish event", "event_id", event.EventID, "attempt", event.Attempts, "error", err)
+				continue
+			}
+			published := d.now()
+			event.PublishedAt = &published
+		}
+	})
+}
+
+// prune deletes the oldest events published longer than the retention ago
+func (d *Dispatcher) prune(ctx context.Context) {
+	deleted, err := d.repo.DeletePublishedBefore(ctx, d.now().Add(-d.config.Retention))
+	if err != nil {
+		slog.Error("Failed to prune outbox events", "error", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Pruned %d published outbox events", deleted)
+	}
+}
+
+// ToEvent returns the published form of an outbox event
+func ToEvent(event models.OutboxEvent) models.Event {
+	return models.Event{
+		ID:         event.EventID,
+		Type:       event.Type,
+		ServiceID:  event.ServiceID,
+		OccurredAt: event.OccurredAt,
+		Data:       json.RawMessage(event.Data),
+	}
+}
+
+// backoff returns the delay before retrying an event that failed to publish attempts times
+func backoff(cfg config.OutboxConfig, attempts int) time.Duration {
+	delay := cfg.PollInterval
+	for i := 1; i < attempts && delay < cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, cfg.MaxBackoff)
+}
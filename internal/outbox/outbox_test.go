@@ -0,0 +1,206 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"services-api/internal/config"
+	"services-api/internal/models"
+)
+
+// memoryRepo is an in-memory OutboxRepository
+type memoryRepo struct {
+	events        []models.OutboxEvent
+	prunedThrough uint64
+}
+
+func (m *memoryRepo) AppendEvents(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		event.ID = m.prunedThrough + uint64(len(m.events)+1)
+		m.events = append(m.events, event)
+	}
+	return nil
+}
+
+func (m *memoryRepo) ListEventsAfter(ctx context.Context, after uint64, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	for _, event := range m.events {
+		if event.ID > after && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *memoryRepo) LatestEventID(ctx context.Context) (uint64, error) {
+	if len(m.events) == 0 {
+		return 0, nil
+	}
+	return m.events[len(m.events)-1].ID, nil
+}
+
+// ProcessPending picks the oldest pending event of every service, like the
+// database query
+func (m *memoryRepo) ProcessPending(ctx context.Context, now time.Time, limit int, fn func(events []models.OutboxEvent)) (int, error) {
+	var picked []models.OutboxEvent
+	seen := make(map[uint]bool)
+	for _, event := range m.events {
+		if event.PublishedAt != nil || seen[event.ServiceID] {
+			continue
+		}
+		seen[event.ServiceID] = true
+		if !event.AvailableAt.After(now) && len(picked) < limit {
+			picked = append(picked, event)
+		}
+	}
+	if len(picked) == 0 {
+		return 0, nil
+	}
+	fn(picked)
+	for _, event := range picked {
+		m.events[event.ID-m.prunedThrough-1] = event
+	}
+	return len(picked), nil
+}
+
+// DeletePublishedBefore deletes the oldest events published before the
+// given time, like the database query
+func (m *memoryRepo) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	deleted := 0
+	for _, event := range m.events {
+		if event.PublishedAt == nil || !event.PublishedAt.Before(before) {
+			break
+		}
+		m.prunedThrough = event.ID
+		deleted++
+	}
+	m.events = m.events[deleted:]
+	return int64(deleted), nil
+}
+
+func (m *memoryRepo) PrunedThrough(ctx context.Context) (uint64, error) {
+	return m.prunedThrough, nil
+}
+
+// recordingPublisher records the IDs of the events it publishes and fails
+// the events listed in failures once each
+type recordingPublisher struct {
+	published []string
+	failures  map[string]bool
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event models.Event) error {
+	if p.failures[event.ID] {
+		delete(p.failures, event.ID)
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+var testConfig = config.OutboxConfig{
+	PollInterval: time.Second,
+	BatchSize:    10,
+	MaxBackoff:   3 * time.Second,
+	Retention:    time.Hour,
+}
+
+// newTestDispatcher returns a dispatcher whose clock is advanced by the returned function
+func newTestDispatcher(repo *memoryRepo, publisher Publisher) (*Dispatcher, func(time.Duration)) {
+	d := NewDispatcher(repo, publisher, testConfig)
+	now := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	return d, func(elapsed time.Duration) { now = now.Add(elapsed) }
+}
+
+func TestDispatcher_OrdersEventsPerService(t *testing.T) {
+	repo := &memoryRepo{}
+	start := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", Type: models.EventServiceCreated, ServiceID: 1, Data: `{}`, AvailableAt: start},
+		{EventID: "evt_2", Type: models.EventServiceCreated, ServiceID: 2, Data: `{}`, AvailableAt: start},
+		{EventID: "evt_3", Type: models.EventVersionCreated, ServiceID: 1, Data: `{}`, AvailableAt: start},
+	})
+	publisher := &recordingPublisher{failures: map[string]bool{"evt_1": true}}
+	d, advance := newTestDispatcher(repo, publisher)
+	ctx := context.Background()
+
+	if n, err := d.publishPending(ctx); n != 2 || err != nil {
+		t.Fatalf("expected the first event of both services to be processed, got %d: %v", n, err)
+	}
+	if repo.events[0].Attempts != 1 || repo.events[0].LastError != "broker unavailable" || repo.events[0].PublishedAt != nil {
+		t.Errorf("expected the failed event to be retried, got %+v", repo.events[0])
+	}
+
+	// The second event of service 1 waits for the retry of the first
+	if n, _ := d.publishPending(ctx); n != 0 {
+		t.Errorf("expected nothing to publish before the retry, got %d", n)
+	}
+	advance(time.Second)
+	d.publishPending(ctx)
+	d.publishPending(ctx)
+
+	want := []string{"evt_2", "evt_1", "evt_3"}
+	if !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published %v, want %v", publisher.published, want)
+	}
+	for _, event := range repo.events {
+		if event.PublishedAt == nil {
+			t.Errorf("expected %s to be published", event.EventID)
+		}
+	}
+}
+
+func TestDispatcher_Prune(t *testing.T) {
+	repo := &memoryRepo{}
+	old := time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", PublishedAt: &old},
+		{EventID: "evt_2", PublishedAt: &recent},
+		{EventID: "evt_3"},
+		{EventID: "evt_4", PublishedAt: &old},
+	})
+	d, _ := newTestDispatcher(repo, &recordingPublisher{})
+
+	// evt_4 is kept until the events before it are deleted
+	d.prune(context.Background())
+	if len(repo.events) != 3 || repo.events[0].EventID != "evt_2" {
+		t.Errorf("expected only the oldest event published before the retention to be deleted, got %+v", repo.events)
+	}
+	if through, _ := repo.PrunedThrough(context.Background()); through != 1 {
+		t.Errorf("expected events to be pruned through 1, got %d", through)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var out bytes.Buffer
+	failing := &recordingPublisher{failures: map[string]bool{"evt_1": true}}
+	publisher := Multi(NewWriterPublisher(&out), failing)
+
+	event := models.Event{ID: "evt_1", Type: models.EventServiceDeleted, ServiceID: 4, Data: []byte(`{"id":4}`)}
+	if err := publisher.Publish(context.Background(), event); err == nil {
+		t.Error("expected the failure of a publisher to be returned")
+	}
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	line := `{"id":"evt_1","type":"service.deleted","service_id":4,"occurred_at":"0001-01-01T00:00:00Z","data":{"id":4}}` + "\n"
+	if out.String() != line+line {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	for i, delay := range want {
+		if got := backoff(testConfig, i+1); got != delay {
+			t.Errorf("backoff after %d attempts = %s, want %s", i+1, got, delay)
+		}
+	}
+}
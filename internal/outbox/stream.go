@@ -0,0 +1,184 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+const (
+	// subscriberBuffer is the number of events buffered for a subscriber
+	// before it is dropped
+	subscriberBuffer = 256
+
+	// tailBatchSize is the number of events read from the log at once
+	tailBatchSize = 500
+)
+
+// ErrReplayExpired is returned when events after the requested ID were
+// pruned, so the subscriber has to reload the catalog
+var ErrReplayExpired = errors.New("events expired")
+
+// Stream follows the outbox, which doubles as the event log, and fans the
+// events out to subscribers as they are committed. Every instance follows
+// the table, so subscribers see the changes made through any of them.
+type Stream struct {
+	repo         repository.OutboxRepository
+	pollInterval time.Duration
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// Subscription receives the events committed while it is open
+type Subscription struct {
+	stream *Stream
+	events chan models.OutboxEvent
+}
+
+// NewStream creates a stream polling repo every pollInterval
+func NewStream(repo repository.OutboxRepository, pollInterval time.Duration) *Stream {
+	return &Stream{
+		repo:         repo,
+		pollInterval: pollInterval,
+		subscribers:  make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe starts receiving the events committed from now on. Once the
+// stream is closed the events channel of new subscriptions is closed.
+func (s *Stream) Subscribe() *Subscription {
+	sub := &Subscription{stream: s, events: make(chan models.OutboxEvent, subscriberBuffer)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(sub.events)
+		return sub
+	}
+	s.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Close ends every subscription, so the streams following them end when
+// the server shuts down instead of holding it up
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Events returns the events of the subscription in ID order. The channel
+// is closed when the subscription is closed or when the subscriber falls
+// too far behind, after which it should replay what it missed.
+func (sub *Subscription) Events() <-chan models.OutboxEvent {
+	return sub.events
+}
+
+// Close ends the subscription
+func (sub *Subscription) Close() {
+	sub.stream.unsubscribe(sub)
+}
+
+func (s *Stream) unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Replay returns up to limit logged events with an ID above after. An after
+// of 0 starts at the oldest event kept. Returns ErrReplayExpired if events
+// after it were pruned.
+func (s *Stream) Replay(ctx context.Context, after uint64, limit int) ([]models.OutboxEvent, error) {
+	events, err := s.repo.ListEventsAfter(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pruning is checked after reading, so events deleted in between are noticed
+	if after > 0 {
+		prunedThrough, err := s.repo.PrunedThrough(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if after < prunedThrough {
+			return nil, fmt.Errorf("%w: events up to %d were pruned", ErrReplayExpired, prunedThrough)
+		}
+	}
+	return events, nil
+}
+
+// Run follows the log until ctx is done, starting with the events
+// committed after it starts
+func (s *Stream) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var last uint64
+	started := false
+	for {
+		if !started {
+			id, err := s.repo.LatestEventID(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.Error("Failed to read the event log", "error", err)
+			}
+			last, started = id, err == nil
+		} else {
+			last = s.poll(ctx, last)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll broadcasts the events logged after last and returns the ID of the
+// last one
+func (s *Stream) poll(ctx context.Context, last uint64) uint64 {
+	for {
+		events, err := s.repo.ListEventsAfter(ctx, last, tailBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Failed to read the event log", "error", err)
+			}
+			return last
+		}
+		for _, event := range events {
+			s.broadcast(event)
+			last = event.ID
+		}
+		if len(events) < tailBatchSize {
+			return last
+		}
+	}
+}
+
+// broadcast hands event to every subscriber, dropping those whose buffer is full
+func (s *Stream) broadcast(event models.OutboxEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"services-api/internal/models"
+)
+
+func TestStream_Poll(t *testing.T) {
+	repo := &memoryRepo{}
+	stream := NewStream(repo, 0)
+	sub := stream.Subscribe()
+	defer sub.Close()
+
+	repo.AppendEvents(context.Background(), []models.OutboxEvent{
+		{EventID: "evt_1", Type: models.EventServiceCreated, ServiceID: 1},
+		{EventID: "evt_2", Type: models.EventVersionCreated, ServiceID: 1},
+	})
+	if last := stream.poll(context.Background(), 0); last != 2 {
+		t.Errorf("expected to stop at the last event, got %d", last)
+	}
+	if last := stream.poll(context.Background(), 2); last != 2 {
+		t.Errorf("expected nothing new, got %d", last)
+	}
+
+	for _, want := range []string{"evt_1", "evt_2"} {
+		if event := <-sub.Events(); event.EventID != want {
+			t.Errorf("received %s, want %s", event.EventID, want)
+		}
+	}
+	select {
+	case event := <-sub.Events():
+		t.Errorf("unexpected event %s", event.EventID)
+	default:
+	}
+}
+
+func TestStream_ReplayExpired(t *testing.T) {
+	repo := &memoryRepo{prunedThrough: 2}
+	repo.AppendEvents(context.Background(), []models.OutboxEvent{{EventID: "evt_3"}})
+	stream := NewStream(repo, 0)
+
+	events, err := stream.Replay(context.Background(), 2, 10)
+	if err != nil || len(events) != 1 || events[0].ID != 3 {
+		t.Errorf("expected the kept event, got %v and %v", events, err)
+	}
+	if _, err := stream.Replay(context.Background(), 1, 10); !errors.Is(err, ErrReplayExpired) {
+		t.Errorf("expected ErrReplayExpired, got %v", err)
+	}
+	if events, err := stream.Replay(context.Background(), 0, 10); err != nil || len(events) != 1 {
+		t.Errorf("expected a replay from the start to begin at the oldest event kept, got %v and %v", events, err)
+	}
+}
+
+func TestStream_Close(t *testing.T) {
+	stream := NewStream(&memoryRepo{}, 0)
+	open := stream.Subscribe()
+	stream.Close()
+
+	if _, ok := <-open.Events(); ok {
+		t.Error("expected open subscriptions to end")
+	}
+	if _, ok := <-stream.Subscribe().Events(); ok {
+		t.Error("expected new subscriptions to end at once")
+	}
+	open.Close()
+}
+
+func TestStream_DropsSlowSubscribers(t *testing.T) {
+	stream := NewStream(&memoryRepo{}, 0)
+	slow := stream.Subscribe()
+	for i := 0; i <= subscriberBuffer; i++ {
+		stream.broadcast(models.OutboxEvent{ID: uint64(i + 1)})
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("expected the buffered events before the channel is closed, got %d", received)
+	}
+
+	// Closing a dropped subscription is harmless
+	slow.Close()
+	if len(stream.subscribers) != 0 {
+		t.Errorf("expected no subscribers left, got %d", len(stream.subscribers))
+	}
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit describes a token bucket: tokens are added at Rate per second up to
+// a maximum of Burst
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int           // Bucket capacity
+	Remaining  int           // Tokens left after this request
+	RetryAfter time.Duration // Time until a token is available, when not allowed
+	ResetAfter time.Duration // Time until the bucket is full again
+}
+
+// Store keeps the state of the token buckets. The in-process MemoryStore is
+// used by default; a shared implementation (e.g. backed by Redis) can be
+// plugged in so that limits apply across replicas.
+type Store interface {
+	// Take consumes a token from the bucket identified by key, creating the
+	// bucket with the given limit if it doesn't exist yet.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+
+	// Peek reports whether a token is available in the bucket identified by
+	// key without consuming it
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// idleBucketTTL is how long an unused bucket is kept before it is evicted
+const idleBucketTTL = 10 * time.Minute
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	lastSeen time.Time
+}
+
+// MemoryStore is an in-process Store
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return s.use(key, limit, true), nil
+}
+
+// Peek implements Store
+func (s *MemoryStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	return s.use(key, limit, false), nil
+}
+
+// use refills the bucket of key and consumes a token if take is set
+func (s *MemoryStore) use(key string, limit Limit, take bool) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	// Refill the bucket for the time elapsed since the last request
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.updated = now
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		if take {
+			b.tokens--
+		}
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAfter = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+	return result
+}
+
+// sweep evicts buckets that haven't been used recently
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.lastSeen) > idleBucketTTL {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Take(context.Background(), "client", limit)
+		if err != nil || !result.Allowed {
+			t.Fatalf("request %d should be allowed: %+v, %v", i, result, err)
+		}
+	}
+
+	result, _ := store.Take(context.Background(), "client", limit)
+	if result.Allowed {
+		t.Fatal("third request should exceed the burst")
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("expected retry after 1s, got %s", result.RetryAfter)
+	}
+
+	// Other clients have their own bucket
+	if result, _ := store.Take(context.Background(), "other", limit); !result.Allowed {
+		t.Error("other client should not be limited")
+	}
+
+	// Tokens are refilled over time
+	now = now.Add(time.Second)
+	result, _ = store.Take(context.Background(), "client", limit)
+	if !result.Allowed || result.Remaining != 0 {
+		t.Errorf("expected request to be allowed after refill: %+v", result)
+	}
+}
+
+func TestMemoryStore_Peek(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 1}
+
+	for i := 0; i < 3; i++ {
+		if result, _ := store.Peek(context.Background(), "client", limit); !result.Allowed {
+			t.Fatalf("peek %d should not consume the token: %+v", i, result)
+		}
+	}
+	store.Take(context.Background(), "client", limit)
+	if result, _ := store.Peek(context.Background(), "client", limit); result.Allowed {
+		t.Error("expected the bucket to be empty after taking its token")
+	}
+}
+
+func TestMemoryStore_EvictsIdleBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	store.Take(context.Background(), "client", Limit{Rate: 1, Burst: 1})
+	now = now.Add(idleBucketTTL + time.Minute)
+	store.Take(context.Background(), "other", Limit{Rate: 1, Burst: 1})
+
+	if _, ok := store.buckets["client"]; ok {
+		t.Error("expected idle bucket to be evicted")
+	}
+}
@@ -0,0 +1,206 @@
+// Package reconcile keeps the service catalog in sync with a directory of
+// service.yaml files, each describing the desired state of one service.
+// Services created by the reconciler are marked with its managed_by value;
+// services created by hand are never deleted.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"services-api/internal/business"
+	"services-api/internal/catalog"
+	"services-api/internal/models"
+)
+
+// ErrInvalidFile is returned when a service file can't be read
+var ErrInvalidFile = errors.New("invalid service file")
+
+// Result is the outcome of a reconciliation
+type Result struct {
+	Report *models.ImportReport
+	Files  []string // Files[i] is the file the i-th service was read from
+}
+
+// File returns the file a report error refers to, or the service for the
+// deletes that were refused
+func (r *Result) File(e models.ImportError) string {
+	if e.Index < 0 || e.Index >= len(r.Files) {
+		return e.Service
+	}
+	return r.Files[e.Index]
+}
+
+// Reconciler compares the service files of a directory with the catalog
+type Reconciler struct {
+	catalog   business.CatalogBusiness
+	dir       string
+	managedBy string
+	adopt     bool
+}
+
+// New creates a reconciler of the services in dir. The services it creates
+// are marked with managedBy and only those are deleted when their file is removed.
+// Existing services not marked with managedBy are only taken over with adopt.
+func New(catalog business.CatalogBusiness, dir, managedBy string, adopt bool) *Reconciler {
+	return &Reconciler{
+		catalog:   catalog,
+		dir:       dir,
+		managedBy: managedBy,
+		adopt:     adopt,
+	}
+}
+
+// Plan returns the changes that would make the catalog match the directory
+func (r *Reconciler) Plan(ctx context.Context) (*Result, error) {
+	return r.reconcile(ctx, true)
+}
+
+// Apply makes the catalog match the directory and returns the applied changes
+func (r *Reconciler) Apply(ctx context.Context) (*Result, error) {
+	return r.reconcile(ctx, false)
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, dryRun bool) (*Result, error) {
+	desired, files, err := LoadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	report, err := r.catalog.Import(ctx, *desired, models.ImportOptions{
+		Mode:      models.ImportReplace,
+		DryRun:    dryRun,
+		ManagedBy: r.managedBy,
+		Adopt:     r.adopt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Report: report, Files: files}, nil
+}
+
+// Run applies the directory every interval until ctx is done, logging the
+// changes and errors of each pass
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.applyAndLog(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reconciler) applyAndLog(ctx context.Context) {
+	result, err := r.Apply(ctx)
+	if err != nil {
+		slog.Error("Sync failed", "dir", r.dir, "error", err)
+		return
+	}
+	for _, e := range result.Report.Errors {
+		slog.Warn("Sync skipped a file", "file", result.File(e), "error", e.Error)
+	}
+	report := result.Report
+	if report.Created+report.Updated+report.Deleted > 0 {
+		log.Printf("Sync of %s: %d created, %d updated, %d deleted", r.dir, report.Created, report.Updated, report.Deleted)
+	}
+}
+
+// LoadDir reads the *.yaml and *.yml files under dir, skipping hidden files
+// and directories, in lexical order. It returns the desired catalog and the
+// file of each service.
+func LoadDir(dir string) (*models.Catalog, []string, error) {
+	desired := &models.Catalog{Services: []models.CatalogService{}}
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && path != dir {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		service, err := catalog.DecodeService(file)
+		if err != nil {
+			return fmt.Errorf("%w %s: %w", ErrInvalidFile, path, err)
+		}
+		desired.Services = append(desired.Services, *service)
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return desired, files, nil
+}
+
+// WritePlan writes a human-readable summary of a reconciliation, one line
+// per changed service followed by the files that were skipped
+func WritePlan(w io.Writer, result *Result) error {
+	report := result.Report
+	changes := append([]models.ImportChange(nil), report.Changes...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Service < changes[j].Service })
+
+	symbols := map[string]string{models.ImportCreate: "+", models.ImportUpdate: "~", models.ImportDelete: "-"}
+	for _, change := range changes {
+		var details []string
+		if len(change.Fields) > 0 {
+			details = append(details, "fields: "+strings.Join(change.Fields, ", "))
+		}
+		if len(change.AddedVersions) > 0 {
+			details = append(details, "add versions: "+strings.Join(change.AddedVersions, ", "))
+		}
+		if len(change.UpdatedVersions) > 0 {
+			details = append(details, "update versions: "+strings.Join(change.UpdatedVersions, ", "))
+		}
+		if len(change.RemovedVersions) > 0 {
+			details = append(details, "remove versions: "+strings.Join(change.RemovedVersions, ", "))
+		}
+		line := fmt.Sprintf("%s %s %s", symbols[change.Action], change.Action, change.Service)
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, "; ") + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	for _, e := range report.Errors {
+		if _, err := fmt.Fprintf(w, "! skipped %s: %s\n", result.File(e), e.Error); err != nil {
+			return err
+		}
+	}
+
+	summary := "Plan: %d to create, %d to update, %d to delete, %d unchanged, %d skipped\n"
+	if !report.DryRun {
+		summary = "Applied: %d created, %d updated, %d deleted, %d unchanged, %d skipped\n"
+	}
+	_, err := fmt.Fprintf(w, summary, report.Created, report.Updated, report.Deleted, report.Unchanged, len(report.Errors))
+	return err
+}
@@ -0,0 +1,119 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"services-api/internal/models"
+)
+
+// fakeCatalog records the imports it is asked to make
+type fakeCatalog struct {
+	catalog models.Catalog
+	options models.ImportOptions
+	report  models.ImportReport
+}
+
+func (f *fakeCatalog) Export(ctx context.Context) (*models.Catalog, error) {
+	return &f.catalog, nil
+}
+
+func (f *fakeCatalog) Import(ctx context.Context, catalog models.Catalog, options models.ImportOptions) (*models.ImportReport, error) {
+	f.catalog, f.options = catalog, options
+	report := f.report
+	report.DryRun = options.DryRun
+	return &report, nil
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"users/service.yaml":   "name: users\nversions:\n  - version: 1.0.0\n",
+		"payments/service.yml": "name: payments\nlabels:\n  tier: critical\n",
+		"README.md":            "# Services\n",
+		".git/config.yaml":     "not: a service\n",
+		"payments/.draft.yaml": "name: draft\n",
+	})
+
+	desired, files, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(desired.Services) != 2 || desired.Services[0].Name != "payments" || desired.Services[1].Name != "users" {
+		t.Errorf("unexpected services: %+v", desired.Services)
+	}
+	wantFiles := []string{filepath.Join(dir, "payments/service.yml"), filepath.Join(dir, "users/service.yaml")}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("got files %v, want %v", files, wantFiles)
+	}
+
+	dir = writeFiles(t, map[string]string{"users.yaml": "name: users\nowner: team-a\n"})
+	if _, _, err := LoadDir(dir); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("expected ErrInvalidFile, got %v", err)
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"billing.yaml": "name: billing\n",
+		"users.yaml":   "name: users\n",
+	})
+	fake := &fakeCatalog{report: models.ImportReport{
+		Mode:    models.ImportReplace,
+		Created: 1,
+		Deleted: 1,
+		Changes: []models.ImportChange{
+			{Action: models.ImportDelete, Service: "search"},
+			{Action: models.ImportCreate, Service: "billing", AddedVersions: []string{"1.0.0"}},
+		},
+		Errors: []models.ImportError{{Index: 1, Service: "users", Error: "invalid service"}},
+	}}
+	r := New(fake, dir, "gitops", false)
+
+	result, err := r.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.ImportOptions{Mode: models.ImportReplace, DryRun: true, ManagedBy: "gitops"}
+	if fake.options != want || len(fake.catalog.Services) != 2 {
+		t.Errorf("unexpected import %+v of %+v", fake.options, fake.catalog)
+	}
+
+	var out bytes.Buffer
+	if err := WritePlan(&out, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantPlan := "+ create billing (add versions: 1.0.0)\n" +
+		"- delete search\n" +
+		"! skipped " + filepath.Join(dir, "users.yaml") + ": invalid service\n" +
+		"Plan: 1 to create, 0 to update, 1 to delete, 0 unchanged, 1 skipped\n"
+	if out.String() != wantPlan {
+		t.Errorf("got plan\n%s\nwant\n%s", out.String(), wantPlan)
+	}
+
+	if _, err := r.Apply(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.options.DryRun {
+		t.Error("expected Apply to write the changes")
+	}
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"services-api/internal/models"
+)
+
+// APIKeyRepository interface defines data access methods for API keys
+type APIKeyRepository interface {
+	// CreateAPIKey stores a new API key
+	// Returns the created key or an error if the creation fails.
+	CreateAPIKey(ctx context.Context, key models.APIKey) (*models.APIKey, error)
+
+	// ListAPIKeys returns all API keys, including revoked ones
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+
+	// GetAPIKey retrieves an API key by its ID
+	// Returns the key or ErrNotFound if it doesn't exist.
+	GetAPIKey(ctx context.Context, id uint) (*models.APIKey, error)
+
+	// GetAPIKeyByPrefix retrieves an API key by its public prefix
+	// Returns the key or ErrNotFound if it doesn't exist.
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+
+	// UpdateAPIKeySecret replaces the prefix and hash of an API key
+	// Returns the updated key or ErrNotFound if it doesn't exist.
+	UpdateAPIKeySecret(ctx context.Context, id uint, prefix, keyHash string) (*models.APIKey, error)
+
+	// RevokeAPIKey marks an API key as revoked
+	// Returns ErrNotFound if the key doesn't exist or is already revoked.
+	RevokeAPIKey(ctx context.Context, id uint, revokedAt time.Time) error
+
+	// TouchAPIKey records when an API key was last used
+	TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error
+
+	// DeleteAPIKeysInvalidBefore deletes the keys revoked or expired before
+	// the given time. Returns the number of keys deleted.
+	DeleteAPIKeysInvalidBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type apiKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+// with the provided database connection.
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepositoryImpl{db: db}
+}
+
+// CreateAPIKey stores a new API key
+func (r *apiKeyRepositoryImpl) CreateAPIKey(ctx context.Context, key models.APIKey) (*models.APIKey, error) {
+	if err := r.db.WithContext(ctx).Create(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys returns all API keys ordered by creation
+func (r *apiKeyRepositoryImpl) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	keys := make([]models.APIKey, 0)
+	if err := r.db.WithContext(ctx).Order("id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetAPIKey retrieves an API key by its ID
+func (r *apiKeyRepositoryImpl) GetAPIKey(ctx context.Context, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByPrefix retrieves an API key by its public prefix
+func (r *apiKeyRepositoryImpl) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// UpdateAPIKeySecret replaces the prefix and hash of an API key that hasn't been revoked
+func (r *apiKeyRepositoryImpl) UpdateAPIKeySecret(ctx context.Context, id uint, prefix, keyHash string) (*models.APIKey, error) {
+	result := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"prefix": prefix, "key_hash": keyHash})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Check if any rows were affected (record exists)
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return r.GetAPIKey(ctx, id)
+}
+
+// RevokeAPIKey marks an API key as revoked
+func (r *apiKeyRepositoryImpl) RevokeAPIKey(ctx context.Context, id uint, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// Check if any rows were affected (record exists)
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TouchAPIKey records when an API key was last used
+func (r *apiKeyRepositoryImpl) TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+}
+
+// DeleteAPIKeysInvalidBefore deletes the keys revoked or expired before the given time
+func (r *apiKeyRepositoryImpl) DeleteAPIKeysInvalidBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("revoked_at < ? OR expires_at < ?", before, before).
+		Delete(&models.APIKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"services-api/internal/models"
+)
+
+// CustomFieldRepository interface defines data access methods for custom field definitions
+type CustomFieldRepository interface {
+	// ListCustomFields returns all custom fields ordered by name
+	ListCustomFields(ctx context.Context) ([]models.CustomField, error)
+
+	// GetCustomField retrieves a custom field by its ID
+	// Returns the field or ErrNotFound if it doesn't exist.
+	GetCustomField(ctx context.Context, id uint) (*models.CustomField, error)
+
+	// CreateCustomField stores a new custom field
+	CreateCustomField(ctx context.Context, field models.CustomField) (*models.CustomField, error)
+
+	// UpdateCustomField updates the display name, description, required flag and options of a field
+	// Returns the updated field or ErrNotFound if it doesn't exist.
+	UpdateCustomField(ctx context.Context, field models.CustomField) (*models.CustomField, error)
+
+	// DeleteCustomField deletes a custom field and its values on all services
+	// Returns ErrNotFound if it doesn't exist.
+	DeleteCustomField(ctx context.Context, id uint) error
+
+	// CountFieldValues returns how many services have one of the given values for a field
+	CountFieldValues(ctx context.Context, fieldID uint, values []string) (int, error)
+}
+
+type customFieldRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCustomFieldRepository creates a new custom field repository
+// with the provided database connection.
+func NewCustomFieldRepository(db *gorm.DB) CustomFieldRepository {
+	return &customFieldRepositoryImpl{db: db}
+}
+
+// ListCustomFields returns all custom fields ordered by name
+func (r *customFieldRepositoryImpl) ListCustomFields(ctx context.Context) ([]models.CustomField, error) {
+	fields := make([]models.CustomField, 0)
+	if err := r.db.WithContext(ctx).Order("name").Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// GetCustomField retrieves a custom field by its ID
+func (r *customFieldRepositoryImpl) GetCustomField(ctx context.Context, id uint) (*models.CustomField, error) {
+	var field models.CustomField
+	if err := r.db.WithContext(ctx).First(&field, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &field, nil
+}
+
+// CreateCustomField stores a new custom field
+func (r *customFieldRepositoryImpl) CreateCustomField(ctx context.Context, field models.CustomField) (*models.CustomField, error) {
+	if err := r.db.WithContext(ctx).Create(&field).Error; err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// UpdateCustomField updates the mutable attributes of a custom field
+func (r *customFieldRepositoryImpl) UpdateCustomField(ctx context.Context, field models.CustomField) (*models.CustomField, error) {
+	result := r.db.WithContext(ctx).Model(&models.CustomField{ID: field.ID}).
+		Select("display_name", "description", "required", "options").
+		Updates(&field)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Check if any rows were affected (record exists)
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return r.GetCustomField(ctx, field.ID)
+}
+
+// DeleteCustomField deletes a custom field and its values on all services
+func (r *customFieldRepositoryImpl) DeleteCustomField(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("field_id = ?", id).Delete(&models.ServiceFieldValue{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.CustomField{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Check if any rows were affected (record exists)
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// CountFieldValues returns how many services have one of the given values for a field
+func (r *customFieldRepositoryImpl) CountFieldValues(ctx context.Context, fieldID uint, values []string) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ServiceFieldValue{}).
+		Where("field_id = ? AND value IN ?", fieldID, values).
+		Count(&count).Error
+	return int(count), err
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"services-api/internal/models"
+)
+
+// dependencyLockKey is the advisory lock serializing dependency inserts so
+// that concurrent inserts can't create a cycle together, and service deletes
+// so that no dependency is added to a service being deleted
+const dependencyLockKey = 0x64657073 // "deps"
+
+// EdgeCheck validates a new dependency against all existing ones
+type EdgeCheck func(existing []models.Dependency) error
+
+// DependencyRepository interface defines data access methods for service dependencies
+type DependencyRepository interface {
+	// ListDependencies returns the dependencies of a service
+	ListDependencies(ctx context.Context, serviceID uint) ([]models.Dependency, error)
+
+	// ListDependents returns the dependencies pointing at a service
+	ListDependents(ctx context.Context, serviceID uint) ([]models.Dependency, error)
+
+	// ListAllDependencies returns every dependency
+	ListAllDependencies(ctx context.Context) ([]models.Dependency, error)
+
+	// GetDependency retrieves a dependency of a service by its ID
+	// Returns the dependency or ErrNotFound if it doesn't exist.
+	GetDependency(ctx context.Context, serviceID, id uint) (*models.Dependency, error)
+
+	// LockDependencies takes the lock CreateDependency holds until the end
+	// of the transaction, so that no dependency is added in the meantime.
+	// It only lasts beyond the call within a unit of work.
+	LockDependencies(ctx context.Context) error
+
+	// CreateDependency stores a new dependency after check has accepted it.
+	// check runs in the same transaction with all existing dependencies and
+	// its error is returned unchanged.
+	// Returns ErrNotFound if either service doesn't exist.
+	CreateDependency(ctx context.Context, dependency models.Dependency, check EdgeCheck) (*models.Dependency, error)
+
+	// UpdateDependency updates the version constraint of a dependency
+	// Returns the updated dependency or ErrNotFound if it doesn't exist.
+	UpdateDependency(ctx context.Context, dependency models.Dependency) (*models.Dependency, error)
+
+	// DeleteDependency deletes a dependency of a service
+	// Returns ErrNotFound if it doesn't exist.
+	DeleteDependency(ctx context.Context, serviceID, id uint) error
+
+	// ListGraphNodes returns the ID and name of every service
+	ListGraphNodes(ctx context.Context) ([]models.GraphNode, error)
+}
+
+type dependencyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDependencyRepository creates a new dependency repository
+// with the provided database connection.
+func NewDependencyRepository(db *gorm.DB) DependencyRepository {
+	return &dependencyRepositoryImpl{db: db}
+}
+
+// withNames selects dependencies together with the names of both services
+func withNames(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Dependency{}).
+		Select("dependencies.*, s.name AS service_name, d.name AS depends_on_name").
+		Joins("JOIN services s ON s.id = dependencies.service_id").
+		Joins("JOIN services d ON d.id = dependencies.depends_on_id")
+}
+
+// ListDependencies returns the dependencies of a service ordered by name
+func (r *dependencyRepositoryImpl) ListDependencies(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+	dependencies := make([]models.Dependency, 0)
+	err := withNames(r.db.WithContext(ctx)).
+		Where("dependencies.service_id = ?", serviceID).
+		Order("d.name").
+		Find(&dependencies).Error
+	if err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// ListDependents returns the dependencies pointing at a service ordered by name
+func (r *dependencyRepositoryImpl) ListDependents(ctx context.Context, serviceID uint) ([]models.Dependency, error) {
+	dependencies := make([]models.Dependency, 0)
+	err := withNames(r.db.WithContext(ctx)).
+		Where("dependencies.depends_on_id = ?", serviceID).
+		Order("s.name").
+		Find(&dependencies).Error
+	if err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// ListAllDependencies returns every dependency ordered by ID
+func (r *dependencyRepositoryImpl) ListAllDependencies(ctx context.Context) ([]models.Dependency, error) {
+	dependencies := make([]models.Dependency, 0)
+	if err := r.db.WithContext(ctx).Order("id").Find(&dependencies).Error; err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// GetDependency retrieves a dependency of a service by its ID
+func (r *dependencyRepositoryImpl) GetDependency(ctx context.Context, serviceID, id uint) (*models.Dependency, error) {
+	var dependency models.Dependency
+	err := withNames(r.db.WithContext(ctx)).
+		Where("dependencies.service_id = ? AND dependencies.id = ?", serviceID, id).
+		First(&dependency).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// LockDependencies takes the dependency lock for the current transaction
+func (r *dependencyRepositoryImpl) LockDependencies(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(?)", dependencyLockKey).Error
+}
+
+// CreateDependency stores a new dependency while holding the dependency lock
+func (r *dependencyRepositoryImpl) CreateDependency(ctx context.Context, dependency models.Dependency, check EdgeCheck) (*models.Dependency, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", dependencyLockKey).Error; err != nil {
+			return err
+		}
+
+		// Services are deleted under the same lock, so both still exist
+		// when the dependency is stored
+		ids := []uint{dependency.ServiceID}
+		if dependency.DependsOnID != dependency.ServiceID {
+			ids = append(ids, dependency.DependsOnID)
+		}
+		var count int64
+		if err := tx.Model(&models.Service{}).Where("id IN ?", ids).Count(&count).Error; err != nil {
+			return err
+		}
+		if count < int64(len(ids)) {
+			return ErrNotFound
+		}
+
+		var existing []models.Dependency
+		if err := tx.Find(&existing).Error; err != nil {
+			return err
+		}
+		if err := check(existing); err != nil {
+			return err
+		}
+
+		return tx.Create(&dependency).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetDependency(ctx, dependency.ServiceID, dependency.ID)
+}
+
+// UpdateDependency updates the version constraint of a dependency
+func (r *dependencyRepositoryImpl) UpdateDependency(ctx context.Context, dependency models.Dependency) (*models.Dependency, error) {
+	result := r.db.WithContext(ctx).Model(&models.Dependency{}).
+		Where("id = ? AND service_id = ?", dependency.ID, dependency.ServiceID).
+		Update("version_constraint", dependency.VersionConstraint)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Check if any rows were affected (record exists)
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return r.GetDependency(ctx, dependency.ServiceID, dependency.ID)
+}
+
+// DeleteDependency deletes a dependency of a service
+func (r *dependencyRepositoryImpl) DeleteDependency(ctx context.Context, serviceID, id uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND service_id = ?", id, serviceID).
+		Delete(&models.Dependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// Check if any rows were affected (record exists)
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListGraphNodes returns the ID and name of every service ordered by ID
+func (r *dependencyRepositoryImpl) ListGraphNodes(ctx context.Context) ([]models.GraphNode, error) {
+	nodes := make([]models.GraphNode, 0)
+	err := r.db.WithContext(ctx).Model(&models.Service{}).
+		Select("id, name").
+		Order("id").
+		Scan(&nodes).Error
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"services-api/internal/models"
+	"services-api/internal/repository/repositorytest"
+)
+
+func TestDependencyRepository_MissingService(t *testing.T) {
+	repo := NewDependencyRepository(repositorytest.EmptyDB(t))
+
+	checked := false
+	_, err := repo.CreateDependency(context.Background(), models.Dependency{ServiceID: 1, DependsOnID: 2}, func(existing []models.Dependency) error {
+		checked = true
+		return nil
+	})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if checked {
+		t.Error("expected the dependency not to be checked when its services are missing")
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"services-api/internal/models"
+)
+
+// outboxLockKey is the advisory lock serializing the transactions that
+// append events
+const outboxLockKey = 0x6f7574626f78
+
+// OutboxRepository interface defines data access methods for the events outbox
+type OutboxRepository interface {
+	// AppendEvents stores events. Called in the transaction of the change
+	// they describe, events are numbered in commit order.
+	AppendEvents(ctx context.Context, events []models.OutboxEvent) error
+
+	// ListEventsAfter returns up to limit events with an ID above after, in ID order
+	ListEventsAfter(ctx context.Context, after uint64, limit int) ([]models.OutboxEvent, error)
+
+	// LatestEventID returns the ID of the latest event, or 0 when there is none
+	LatestEventID(ctx context.Context) (uint64, error)
+
+	// ProcessPending locks up to limit unpublished events available at now
+	// that are the oldest unpublished event of their service and calls fn
+	// with them. Events locked by another instance are skipped. The
+	// published_at, available_at, attempts and last_error fields fn sets
+	// are stored when it returns. Returns the number of events processed.
+	ProcessPending(ctx context.Context, now time.Time, limit int, fn func(events []models.OutboxEvent)) (int, error)
+
+	// DeletePublishedBefore deletes the oldest events, up to the first one
+	// that is unpublished or was published after the given time, so the
+	// remaining events follow each other. Returns the number of events deleted.
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// PrunedThrough returns the ID of the last event deleted, or 0 when
+	// none has been
+	PrunedThrough(ctx context.Context) (uint64, error)
+}
+
+type outboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+// with the provided database connection.
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepositoryImpl{db: db}
+}
+
+// AppendEvents stores events after taking a transaction-scoped lock.
+// Transactions append their events one at a time and the lock is held
+// until commit, so an event is only visible once every event with a lower
+// ID is, and readers can follow the table by ID without missing any.
+func (r *outboxRepositoryImpl) AppendEvents(ctx context.Context, events []models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	db := r.db.WithContext(ctx)
+	if err := db.Exec("SELECT pg_advisory_xact_lock(?)", outboxLockKey).Error; err != nil {
+		return err
+	}
+	return db.Create(&events).Error
+}
+
+// ListEventsAfter returns up to limit events with an ID above after
+func (r *outboxRepositoryImpl) ListEventsAfter(ctx context.Context, after uint64, limit int) ([]models.OutboxEvent, error) {
+	events := make([]models.OutboxEvent, 0)
+	err := r.db.WithContext(ctx).Where("id > ?", after).Order("id").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LatestEventID returns the highest event ID
+func (r *outboxRepositoryImpl) LatestEventID(ctx context.Context) (uint64, error) {
+	var id uint64
+	err := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+// ProcessPending calls fn with the publishable events in a transaction
+// holding their row locks. If the instance stops before committing, the
+// locks are released and the events are published again.
+func (r *outboxRepositoryImpl) ProcessPending(ctx context.Context, now time.Time, limit int, fn func(events []models.OutboxEvent)) (int, error) {
+	var processed int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		events := make([]models.OutboxEvent, 0)
+		err := tx.Raw(`
+			SELECT * FROM outbox_events o
+			WHERE o.published_at IS NULL AND o.available_at <= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events p
+				WHERE p.service_id = o.service_id AND p.published_at IS NULL AND p.id < o.id
+			)
+			ORDER BY o.id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED`, now, limit).
+			Scan(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		fn(events)
+		for _, event := range events {
+			err := tx.Model(&event).
+				Select("published_at", "available_at", "attempts", "last_error").
+				Updates(&event).Error
+			if err != nil {
+				return err
+			}
+		}
+		processed = len(events)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return processed, nil
+}
+
+// DeletePublishedBefore deletes the oldest events published before the
+// given time and records the ID of the last one. Events appended by
+// uncommitted transactions have higher IDs than every committed event, as
+// AppendEvents holds its lock until commit.
+func (r *outboxRepositoryImpl) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var through uint64
+		err := tx.Raw(`
+			SELECT COALESCE(MAX(id), 0) FROM outbox_events
+			WHERE id < COALESCE(
+				(SELECT MIN(id) FROM outbox_events WHERE published_at IS NULL OR published_at >= ?),
+				(SELECT MAX(id) + 1 FROM outbox_events)
+			)`, before).
+			Scan(&through).Error
+		if err != nil || through == 0 {
+			return err
+		}
+
+		result := tx.Where("id <= ?", through).Delete(&models.OutboxEvent{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return tx.Exec(`
+			INSERT INTO outbox_prunings (id, pruned_through) VALUES (1, ?)
+			ON CONFLICT (id) DO UPDATE
+			SET pruned_through = GREATEST(outbox_prunings.pruned_through, EXCLUDED.pruned_through)`, through).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// PrunedThrough returns the ID of the last deleted event
+func (r *outboxRepositoryImpl) PrunedThrough(ctx context.Context) (uint64, error) {
+	var through uint64
+	err := r.db.WithContext(ctx).Raw("SELECT COALESCE((SELECT pruned_through FROM outbox_prunings WHERE id = 1), 0)").Scan(&through).Error
+	return through, err
+}
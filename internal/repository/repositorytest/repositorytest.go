@@ -0,0 +1,68 @@
+// Package repositorytest provides a database for testing the repositories
+// and the layers above them without a Postgres server.
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// EmptyDB returns a Postgres database without any rows: queries return
+// nothing and statements affect nothing, so lookups fail like they do for
+// missing records
+func EmptyDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	sqlDB := sql.OpenDB(emptyConnector{})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open the empty database: %v", err)
+	}
+	return db
+}
+
+// emptyConnector opens connections to the empty database
+type emptyConnector struct{}
+
+func (emptyConnector) Connect(ctx context.Context) (driver.Conn, error) { return emptyConn{}, nil }
+func (emptyConnector) Driver() driver.Driver                            { return emptyDriver{} }
+
+type emptyDriver struct{}
+
+func (emptyDriver) Open(name string) (driver.Conn, error) { return emptyConn{}, nil }
+
+type emptyConn struct{}
+
+func (emptyConn) Prepare(query string) (driver.Stmt, error) { return emptyStmt{}, nil }
+func (emptyConn) Close() error                              { return nil }
+func (emptyConn) Begin() (driver.Tx, error)                 { return emptyTx{}, nil }
+
+type emptyStmt struct{}
+
+func (emptyStmt) Close() error  { return nil }
+func (emptyStmt) NumInput() int { return -1 }
+func (emptyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+func (emptyStmt) Query(args []driver.Value) (driver.Rows, error) { return emptyRows{}, nil }
+
+type emptyTx struct{}
+
+func (emptyTx) Commit() error   { return nil }
+func (emptyTx) Rollback() error { return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
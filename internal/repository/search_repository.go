@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"services-api/internal/models"
+)
+
+// SearchRepository interface defines full-text search over the catalog.
+// It is the extension point for external search engines; the default
+// implementation uses the Postgres tsvector columns created by db.Migrate.
+type SearchRepository interface {
+	// Search returns a page of hits ordered by rank and the hit count of every type.
+	// query.Types must list the types to return. Highlights are escaped HTML
+	// with the matches wrapped in mark elements.
+	Search(ctx context.Context, query models.SearchQuery) ([]models.SearchHit, []models.SearchFacet, error)
+}
+
+type searchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository creates a new Postgres full-text search repository
+// with the provided database connection.
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepositoryImpl{db: db}
+}
+
+// highlightStart and highlightStop delimit the matches in the headlines.
+// They are private use characters so that the text can be HTML-escaped
+// before they are replaced with mark elements.
+const (
+	highlightStart = "\ue000"
+	highlightStop  = "\ue001"
+)
+
+// searchHits matches services and versions with the English configuration
+// and labels with the simple one, mirroring how their vectors are built
+const searchHits = `
+WITH q AS (
+	SELECT websearch_to_tsquery('english', @q) AS english, websearch_to_tsquery('simple', @q) AS simple
+), hits AS (
+	SELECT 'service' AS type, s.id, s.id AS service_id, s.name AS service_name, s.name AS title,
+		s.name || ': ' || coalesce(s.description, '') AS body, 'english' AS config,
+		ts_rank(s.search_vector, q.english) AS rank
+	FROM services s CROSS JOIN q
+	WHERE s.search_vector @@ q.english
+	UNION ALL
+	SELECT 'version', v.id, v.service_id, s.name, v.version,
+		v.version || ': ' || coalesce(v.description, ''), 'english',
+		ts_rank(v.search_vector, q.english)
+	FROM versions v JOIN services s ON s.id = v.service_id CROSS JOIN q
+	WHERE v.search_vector @@ q.english
+	UNION ALL
+	SELECT 'label', l.service_id, l.service_id, s.name, l.key || '=' || l.value,
+		l.key || ' ' || l.value, 'simple',
+		ts_rank(l.search_vector, q.simple)
+	FROM service_labels l JOIN services s ON s.id = l.service_id CROSS JOIN q
+	WHERE l.search_vector @@ q.simple
+)`
+
+// Search runs a ranked full-text search. Highlights are only computed for
+// the returned page because ts_headline is expensive.
+func (r *searchRepositoryImpl) Search(ctx context.Context, query models.SearchQuery) ([]models.SearchHit, []models.SearchFacet, error) {
+	hits := make([]models.SearchHit, 0)
+	err := r.db.WithContext(ctx).Raw(searchHits+`
+		SELECT h.type, h.id, h.service_id, h.service_name, h.title, h.rank,
+			ts_headline(h.config::regconfig, translate(h.body, @start || @stop, ''), CASE WHEN h.config = 'simple' THEN q.simple ELSE q.english END,
+				'StartSel=' || @start || ', StopSel=' || @stop || ', MinWords=10, MaxWords=30') AS highlight
+		FROM hits h CROSS JOIN q
+		WHERE h.type IN @types
+		ORDER BY h.rank DESC, h.type, h.id, h.title
+		LIMIT @limit OFFSET @offset`,
+		map[string]any{
+			"q":      query.Query,
+			"types":  query.Types,
+			"limit":  query.Limit,
+			"offset": (query.Page - 1) * query.Limit,
+			"start":  highlightStart,
+			"stop":   highlightStop,
+		}).Scan(&hits).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range hits {
+		hits[i].Highlight = highlightHTML(hits[i].Highlight)
+	}
+
+	facets := make([]models.SearchFacet, 0)
+	err = r.db.WithContext(ctx).Raw(searchHits+`
+		SELECT type, COUNT(*) AS count FROM hits GROUP BY type ORDER BY type`,
+		map[string]any{"q": query.Query}).Scan(&facets).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hits, facets, nil
+}
+
+// highlightHTML escapes a headline and marks its matches. Search removes
+// the delimiters from the text beforehand, so the headline only contains
+// the ones ts_headline added.
+func highlightHTML(headline string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(headline))
+}
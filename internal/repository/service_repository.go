@@ -3,9 +3,15 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 
+	"services-api/internal/expr"
+	"services-api/internal/labels"
 	"services-api/internal/models"
 )
 
@@ -17,12 +23,14 @@ var (
 // ServiceRepository interface defines data access methods for service entities
 type ServiceRepository interface {
 	// ListServices retrieves a paginated list of services based on filter criteria.
-	// It returns the matched services, the total count of matches, and any error encountered.
+	// It returns the matched services, the total count of matches when filter.IncludeTotal
+	// is set, and any error encountered. Up to filter.Limit+1 services are returned so
+	// callers can tell whether there are more; with a backward keyset the extra one is first.
 	ListServices(ctx context.Context, filter models.ServiceFilter) ([]models.ServiceModel, int, error)
 	
 	// GetService retrieves a single service by its ID.
-	// It returns the service with its associated versions or an error if not found.
-	GetService(ctx context.Context, id uint) (*models.Service, error)
+	// It returns the service with the related resources selected by expand or an error if not found.
+	GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error)
 	
 	// CreateService creates a new service
 	// It returns the created service or an error if the service creation fails.
@@ -35,6 +43,10 @@ type ServiceRepository interface {
 	// DeleteService deletes a service
 	// It returns an error if the service deletion fails or if the service is not found.
 	DeleteService(ctx context.Context, id uint) error
+
+	// ListLabelFacets returns the distinct label keys with their values and usage counts.
+	// When key is set only that key is returned.
+	ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error)
 }
 
 // serviceRepositoryImpl implements ServiceRepository
@@ -64,35 +76,70 @@ func (r *serviceRepositoryImpl) ListServices(ctx context.Context, filter models.
 	if filter.Description != "" {
 		query = query.Where("description ILIKE ?", "%"+filter.Description+"%")
 	}
+	query = applySelector(query, filter.Selector)
+	if filter.Expression != nil {
+		sql, args, err := compileExpression(filter.Expression)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where(sql, args...)
+	}
+	for name, value := range filter.Metadata {
+		query = query.Where("EXISTS (SELECT 1 FROM service_field_values v JOIN custom_fields f ON f.id = v.field_id WHERE v.service_id = services.id AND f.name = ? AND v.value = ?)", name, value)
+	}
 
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if filter.IncludeTotal {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
 	}
 
-	// Apply sorting
-	sortColumn := "name"
-	if filter.Sort != "" {
-		switch filter.Sort {
-		case "name", "created_at", "updated_at":
-			sortColumn = filter.Sort
+	// Apply sorting on the requested fields with the ID as tie breaker, in
+	// reverse for backward pages, which are flipped after reading
+	backward := filter.Keyset != nil && filter.Keyset.Backward
+	sortFields := filter.SortFields
+	if len(sortFields) == 0 {
+		sortFields = []models.SortField{{Field: "name", Descending: filter.Order == "desc"}}
+	}
+	var columns []string
+	var descending []bool
+	for _, field := range sortFields {
+		column, ok := serviceColumns[field.Field]
+		if !ok {
+			return nil, 0, fmt.Errorf("unsupported sort field %q", field.Field)
 		}
+		columns = append(columns, column)
+		descending = append(descending, field.Descending != backward)
 	}
+	columns = append(columns, "services.id")
+	descending = append(descending, descending[len(descending)-1])
 
-	sortOrder := "ASC"
-	if filter.Order == "desc" {
-		sortOrder = "DESC"
+	order := make([]string, len(columns))
+	for i, column := range columns {
+		order[i] = column + " ASC"
+		if descending[i] {
+			order[i] = column + " DESC"
+		}
 	}
-	query = query.Order(sortColumn + " " + sortOrder)
+	query = query.Order(strings.Join(order, ", "))
 
-	// Apply pagination
-	offset := (filter.Page - 1) * filter.Limit
-	query = query.Limit(filter.Limit).Offset(offset)
+	// Apply pagination, by keyset on the sort fields and ID or by offset
+	if filter.Keyset != nil {
+		sql, args := keysetCondition(columns, descending, append(filter.Keyset.Values, filter.Keyset.ID))
+		query = query.Where(sql, args...)
+	} else {
+		query = query.Offset((filter.Page - 1) * filter.Limit)
+	}
+	query = query.Limit(filter.Limit + 1)
 
 	// Execute query
 	if err := query.Find(&services).Error; err != nil {
 		return nil, 0, err
 	}
+	if backward {
+		slices.Reverse(services)
+	}
 
 	// If we have services, get the version counts efficiently in a single query
 	if len(services) > 0 {
@@ -127,6 +174,30 @@ func (r *serviceRepositoryImpl) ListServices(ctx context.Context, filter models.
 			countMap[count.ServiceID] = count.Count
 		}
 
+		// Get the labels of all services in a single query as well
+		labelMap, err := r.loadLabels(r.db.WithContext(ctx), serviceIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		metadataMap, err := r.loadMetadata(r.db.WithContext(ctx), serviceIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Load the expanded resources of the page only
+		var versionMap map[uint][]models.Version
+		if filter.Expand.Versions {
+			if versionMap, err = r.loadVersions(r.db.WithContext(ctx), serviceIDs, filter.Expand); err != nil {
+				return nil, 0, err
+			}
+		}
+		var dependencyMap map[uint][]models.Dependency
+		if filter.Expand.Dependencies {
+			if dependencyMap, err = r.loadDependencies(r.db.WithContext(ctx), serviceIDs); err != nil {
+				return nil, 0, err
+			}
+		}
+
 		// Update services with their respective version counts
 		for i := range services {
 			var serviceModel models.ServiceModel
@@ -135,6 +206,11 @@ func (r *serviceRepositoryImpl) ListServices(ctx context.Context, filter models.
 			serviceModel.Description = services[i].Description
 			serviceModel.CreatedAt = services[i].CreatedAt
 			serviceModel.UpdatedAt = services[i].UpdatedAt
+			serviceModel.ManagedBy = services[i].ManagedBy
+			serviceModel.Labels = labelMap[services[i].ID]
+			serviceModel.Metadata = metadataMap[services[i].ID]
+			serviceModel.Versions = versionMap[services[i].ID]
+			serviceModel.Dependencies = dependencyMap[services[i].ID]
 			if count, exists := countMap[services[i].ID]; exists {
 				serviceModel.VersionCount = count
 			} else {
@@ -148,12 +224,10 @@ func (r *serviceRepositoryImpl) ListServices(ctx context.Context, filter models.
 }
 
 // GetService returns a single service by ID
-func (r *serviceRepositoryImpl) GetService(ctx context.Context, id uint) (*models.Service, error) {
+func (r *serviceRepositoryImpl) GetService(ctx context.Context, id uint, expand models.ServiceExpand) (*models.Service, error) {
 	var service models.Service
 	
-	err := r.db.WithContext(ctx).
-		Preload("Versions").
-		First(&service, id).Error
+	err := r.db.WithContext(ctx).First(&service, id).Error
 	
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -162,28 +236,81 @@ func (r *serviceRepositoryImpl) GetService(ctx context.Context, id uint) (*model
 		return nil, err
 	}
 
+	if err := r.loadExtras(ctx, &service); err != nil {
+		return nil, err
+	}
+
+	if expand.Versions {
+		versionMap, err := r.loadVersions(r.db.WithContext(ctx), []uint{id}, expand)
+		if err != nil {
+			return nil, err
+		}
+		service.Versions = versionMap[id]
+	}
+	if expand.Dependencies {
+		dependencyMap, err := r.loadDependencies(r.db.WithContext(ctx), []uint{id})
+		if err != nil {
+			return nil, err
+		}
+		service.Dependencies = dependencyMap[id]
+	}
+
 	return &service, nil
 }
 
 // CreateService creates a new service
 // It returns the created service or an error if the service creation fails.
 func (r *serviceRepositoryImpl) CreateService(ctx context.Context, service models.Service) (*models.Service, error) {
-	if err := r.db.WithContext(ctx).Model(&models.Service{}).Create(&service).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Service{}).Create(&service).Error; err != nil {
+			return err
+		}
+		if err := replaceLabels(tx, service.ID, service.Labels); err != nil {
+			return err
+		}
+		return replaceMetadata(tx, service.ID, service.Metadata)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &service, nil
 }
 
-// UpdateService updates a service
+// UpdateService updates a service. Labels and custom field values are replaced
+// when service.Labels or service.Metadata aren't nil.
 func (r *serviceRepositoryImpl) UpdateService(ctx context.Context, service models.Service) (*models.Service, error) {
-	result := r.db.WithContext(ctx).Model(&models.Service{}).Where("id = ?", service.ID).Updates(&service)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	
-	// Check if any rows were affected (record exists)
-	if result.RowsAffected == 0 {
-		return nil, ErrNotFound
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Service{}).Where("id = ?", service.ID).Updates(&service)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Check if any rows were affected (record exists)
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		if service.Labels != nil {
+			if err := tx.Where("service_id = ?", service.ID).Delete(&models.ServiceLabel{}).Error; err != nil {
+				return err
+			}
+			if err := replaceLabels(tx, service.ID, service.Labels); err != nil {
+				return err
+			}
+		}
+
+		if service.Metadata != nil {
+			if err := tx.Where("service_id = ?", service.ID).Delete(&models.ServiceFieldValue{}).Error; err != nil {
+				return err
+			}
+			if err := replaceMetadata(tx, service.ID, service.Metadata); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	
 	// Fetch the updated record to return
@@ -191,28 +318,342 @@ func (r *serviceRepositoryImpl) UpdateService(ctx context.Context, service model
 	if err := r.db.WithContext(ctx).First(&updatedService, service.ID).Error; err != nil {
 		return nil, err
 	}
+	if err := r.loadExtras(ctx, &updatedService); err != nil {
+		return nil, err
+	}
 	
 	return &updatedService, nil
 }
 
-// DeleteService deletes a service by ID and all the versions of the service
+// DeleteService deletes a service by ID, all the versions of the service and
+// every dependency from or to it
 func (r *serviceRepositoryImpl) DeleteService(ctx context.Context, id uint) error {
-	
-	// Delete all versions of the service
-	result := r.db.WithContext(ctx).Where("service_id = ?", id).Delete(&models.Version{})
-	if result.Error != nil {
-		return result.Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Wait for the dependency inserts in progress, which check under
+		// the same lock that their services exist
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", dependencyLockKey).Error; err != nil {
+			return err
+		}
+
+		// Delete the dependencies from and to the service
+		result := tx.Where("service_id = ? OR depends_on_id = ?", id, id).Delete(&models.Dependency{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Delete the labels and custom field values of the service
+		result = tx.Where("service_id = ?", id).Delete(&models.ServiceLabel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		result = tx.Where("service_id = ?", id).Delete(&models.ServiceFieldValue{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Delete all versions of the service
+		result = tx.Where("service_id = ?", id).Delete(&models.Version{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Delete the service
+		result = tx.Delete(&models.Service{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Check if any rows were affected (record exists)
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// ListLabelFacets returns the distinct label keys and values ordered by key and value
+func (r *serviceRepositoryImpl) ListLabelFacets(ctx context.Context, key string) ([]models.LabelFacet, error) {
+	type row struct {
+		Key   string
+		Value string
+		Count int
 	}
-	
-	// Delete the service
-	result = r.db.WithContext(ctx).Delete(&models.Service{}, id)
-	if result.Error != nil {
-		return result.Error
+	var rows []row
+
+	query := r.db.WithContext(ctx).Model(&models.ServiceLabel{}).
+		Select("key, value, COUNT(*) AS count").
+		Group("key, value").
+		Order("key, value")
+	if key != "" {
+		query = query.Where("key = ?", key)
 	}
-	
-	// Check if any rows were affected (record exists)
-	if result.RowsAffected == 0 {
-		return ErrNotFound
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	facets := make([]models.LabelFacet, 0)
+	for _, row := range rows {
+		if len(facets) == 0 || facets[len(facets)-1].Key != row.Key {
+			facets = append(facets, models.LabelFacet{Key: row.Key})
+		}
+		last := &facets[len(facets)-1]
+		last.Values = append(last.Values, models.LabelValueCount{Value: row.Value, Count: row.Count})
+	}
+	return facets, nil
+}
+
+// loadLabels returns the labels of the given services keyed by service ID
+func (r *serviceRepositoryImpl) loadLabels(db *gorm.DB, serviceIDs []uint) (map[uint]map[string]string, error) {
+	var rows []models.ServiceLabel
+	if err := db.Where("service_id IN ?", serviceIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	labelMap := make(map[uint]map[string]string)
+	for _, row := range rows {
+		if labelMap[row.ServiceID] == nil {
+			labelMap[row.ServiceID] = make(map[string]string)
+		}
+		labelMap[row.ServiceID][row.Key] = row.Value
+	}
+	return labelMap, nil
+}
+
+// loadExtras loads the labels and custom field values of a single service
+func (r *serviceRepositoryImpl) loadExtras(ctx context.Context, service *models.Service) error {
+	labelMap, err := r.loadLabels(r.db.WithContext(ctx), []uint{service.ID})
+	if err != nil {
+		return err
+	}
+	metadataMap, err := r.loadMetadata(r.db.WithContext(ctx), []uint{service.ID})
+	if err != nil {
+		return err
 	}
+	service.Labels = labelMap[service.ID]
+	service.Metadata = metadataMap[service.ID]
 	return nil
 }
+
+// loadVersions returns the versions of the given services keyed by service ID,
+// sorted and limited per service as requested
+func (r *serviceRepositoryImpl) loadVersions(db *gorm.DB, serviceIDs []uint, expand models.ServiceExpand) (map[uint][]models.Version, error) {
+	order := versionOrder(expand.VersionSort)
+
+	// Rank the versions of every service so the limit applies per service
+	ranked := db.Model(&models.Version{}).
+		Select("versions.*, ROW_NUMBER() OVER (PARTITION BY service_id ORDER BY " + order + ") AS position").
+		Where("service_id IN ?", serviceIDs)
+	query := db.Table("(?) AS ranked", ranked).Order("service_id, position")
+	if expand.VersionLimit > 0 {
+		query = query.Where("position <= ?", expand.VersionLimit)
+	}
+
+	var versions []models.Version
+	if err := query.Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	versionMap := make(map[uint][]models.Version)
+	for _, id := range serviceIDs {
+		versionMap[id] = make([]models.Version, 0)
+	}
+	for _, version := range versions {
+		versionMap[version.ServiceID] = append(versionMap[version.ServiceID], version)
+	}
+	return versionMap, nil
+}
+
+// loadDependencies returns the dependencies of the given services keyed by service ID
+func (r *serviceRepositoryImpl) loadDependencies(db *gorm.DB, serviceIDs []uint) (map[uint][]models.Dependency, error) {
+	var dependencies []models.Dependency
+	err := withNames(db).
+		Where("dependencies.service_id IN ?", serviceIDs).
+		Order("d.name").
+		Find(&dependencies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dependencyMap := make(map[uint][]models.Dependency)
+	for _, id := range serviceIDs {
+		dependencyMap[id] = make([]models.Dependency, 0)
+	}
+	for _, dependency := range dependencies {
+		dependencyMap[dependency.ServiceID] = append(dependencyMap[dependency.ServiceID], dependency)
+	}
+	return dependencyMap, nil
+}
+
+// loadMetadata returns the custom field values of the given services keyed
+// by service ID. Number fields are returned as numbers, all others as strings.
+func (r *serviceRepositoryImpl) loadMetadata(db *gorm.DB, serviceIDs []uint) (map[uint]map[string]any, error) {
+	type row struct {
+		ServiceID uint
+		Name      string
+		Type      string
+		Value     string
+	}
+	var rows []row
+	err := db.Table("service_field_values v").
+		Select("v.service_id, f.name, f.type, v.value").
+		Joins("JOIN custom_fields f ON f.id = v.field_id").
+		Where("v.service_id IN ?", serviceIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	metadataMap := make(map[uint]map[string]any)
+	for _, row := range rows {
+		if metadataMap[row.ServiceID] == nil {
+			metadataMap[row.ServiceID] = make(map[string]any)
+		}
+		var value any = row.Value
+		if row.Type == models.FieldTypeNumber {
+			if number, err := strconv.ParseFloat(row.Value, 64); err == nil {
+				value = number
+			}
+		}
+		metadataMap[row.ServiceID][row.Name] = value
+	}
+	return metadataMap, nil
+}
+
+// replaceMetadata inserts the custom field values of a service, which must
+// have none. Values are expected in canonical string form.
+func replaceMetadata(tx *gorm.DB, serviceID uint, metadata map[string]any) error {
+	for name, value := range metadata {
+		err := tx.Exec("INSERT INTO service_field_values (service_id, field_id, value) SELECT ?, id, ? FROM custom_fields WHERE name = ?",
+			serviceID, fmt.Sprint(value), name).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceLabels inserts the labels of a service, which must have none
+func replaceLabels(tx *gorm.DB, serviceID uint, set map[string]string) error {
+	if len(set) == 0 {
+		return nil
+	}
+	rows := make([]models.ServiceLabel, 0, len(set))
+	for key, value := range set {
+		rows = append(rows, models.ServiceLabel{ServiceID: serviceID, Key: key, Value: value})
+	}
+	return tx.Create(&rows).Error
+}
+
+// serviceColumns maps the filter and sort fields of services to SQL expressions
+var serviceColumns = map[string]string{
+	"id":            "services.id",
+	"name":          "services.name",
+	"description":   "services.description",
+	"created_at":    "services.created_at",
+	"updated_at":    "services.updated_at",
+	"version_count": "(SELECT COUNT(*) FROM versions WHERE versions.service_id = services.id)",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// compileExpression compiles a filter expression to a parameterized SQL
+// condition. Field names are mapped through serviceColumns and values are
+// always passed as parameters.
+func compileExpression(node expr.Node) (string, []any, error) {
+	switch n := node.(type) {
+	case *expr.Logical:
+		left, leftArgs, err := compileExpression(n.Left)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightArgs, err := compileExpression(n.Right)
+		if err != nil {
+			return "", nil, err
+		}
+		operator := "AND"
+		if n.Or {
+			operator = "OR"
+		}
+		return "(" + left + " " + operator + " " + right + ")", append(leftArgs, rightArgs...), nil
+	case *expr.Not:
+		operand, args, err := compileExpression(n.Operand)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT " + operand, args, nil
+	case *expr.Comparison:
+		column, ok := serviceColumns[n.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported filter field %q", n.Field)
+		}
+		switch n.Operator {
+		case expr.Contains, expr.NotContains:
+			pattern := "%" + likeEscaper.Replace(fmt.Sprint(n.Values[0])) + "%"
+			if n.Operator == expr.NotContains {
+				return "(" + column + " NOT ILIKE ?)", []any{pattern}, nil
+			}
+			return "(" + column + " ILIKE ?)", []any{pattern}, nil
+		case expr.In:
+			return "(" + column + " IN ?)", []any{n.Values}, nil
+		case expr.Equal, expr.NotEqual, expr.Greater, expr.GreaterOrEqual, expr.Less, expr.LessOrEqual:
+			return "(" + column + " " + string(n.Operator) + " ?)", n.Values, nil
+		}
+		return "", nil, fmt.Errorf("unsupported filter operator %q", n.Operator)
+	}
+	return "", nil, fmt.Errorf("unsupported filter expression %T", node)
+}
+
+// keysetCondition returns the condition selecting the rows after a position
+// in a list ordered by columns, each ascending or descending. When all the
+// columns share a direction a row comparison is used, which indexes support.
+func keysetCondition(columns []string, descending []bool, values []any) (string, []any) {
+	sameDirection := true
+	for _, d := range descending {
+		sameDirection = sameDirection && d == descending[0]
+	}
+	operator := func(d bool) string {
+		if d {
+			return "<"
+		}
+		return ">"
+	}
+	if sameDirection {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), operator(descending[0]), placeholders), values
+	}
+
+	// (a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)
+	var terms []string
+	var args []any
+	for i := range columns {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j]+" = ?")
+			args = append(args, values[j])
+		}
+		parts = append(parts, columns[i]+" "+operator(descending[i])+" ?")
+		args = append(args, values[i])
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// applySelector adds a condition per selector requirement. Each one is an
+// EXISTS or NOT EXISTS subquery served by the labels primary key and the
+// key/value index. Missing keys satisfy != and notin, as in Kubernetes.
+func applySelector(query *gorm.DB, selector labels.Selector) *gorm.DB {
+	const label = "SELECT 1 FROM service_labels l WHERE l.service_id = services.id AND l.key = ?"
+	for _, requirement := range selector {
+		switch requirement.Operator {
+		case labels.Equals, labels.In:
+			query = query.Where("EXISTS ("+label+" AND l.value IN ?)", requirement.Key, requirement.Values)
+		case labels.NotEquals, labels.NotIn:
+			query = query.Where("NOT EXISTS ("+label+" AND l.value IN ?)", requirement.Key, requirement.Values)
+		case labels.Exists:
+			query = query.Where("EXISTS ("+label+")", requirement.Key)
+		case labels.DoesNotExist:
+			query = query.Where("NOT EXISTS ("+label+")", requirement.Key)
+		}
+	}
+	return query
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories groups the repositories that can take part in a unit of work
+type Repositories struct {
+	Services     ServiceRepository
+	Versions     VersionRepository
+	Dependencies DependencyRepository
+	CustomFields CustomFieldRepository
+	Outbox       OutboxRepository
+}
+
+// UnitOfWork runs several repository operations in one transaction
+type UnitOfWork interface {
+	// Do calls fn with repositories bound to a new transaction, which is
+	// committed when fn returns nil and rolled back otherwise.
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type unitOfWorkImpl struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork creates a unit of work with the provided database connection.
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWorkImpl{db: db}
+}
+
+// Do runs fn in a transaction. The transactions the repositories open
+// themselves become savepoints of it.
+func (u *unitOfWorkImpl) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Services:     NewServiceRepository(tx),
+			Versions:     NewVersionRepository(tx),
+			Dependencies: NewDependencyRepository(tx),
+			CustomFields: NewCustomFieldRepository(tx),
+			Outbox:       NewOutboxRepository(tx),
+		})
+	})
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"services-api/internal/models"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -24,6 +26,14 @@ type VersionRepository interface {
 	// DeleteVersion deletes a version
 	// Returns an error if the version deletion fails or if the version is not found.
 	DeleteVersion(ctx context.Context, id uint, serviceId uint) error
+
+	// DeprecateVersion marks a version as no longer active
+	// Returns the updated version or ErrNotFound if it doesn't exist.
+	DeprecateVersion(ctx context.Context, id uint, serviceId uint) (*models.Version, error)
+
+	// ListVersions returns a page of the versions of a service and their total count
+	// Returns ErrNotFound if the service doesn't exist.
+	ListVersions(ctx context.Context, filter models.VersionFilter) ([]models.Version, int, error)
 }
 
 type versionRepositoryImpl struct {
@@ -50,6 +60,9 @@ func (r *versionRepositoryImpl) CreateVersion(ctx context.Context, version model
 func (r *versionRepositoryImpl) GetVersion(ctx context.Context, id uint, serviceId uint	) (*models.Version, error) {
 	var version models.Version
 	if err := r.db.WithContext(ctx).Where("id = ? AND service_id = ?", id, serviceId).First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
 	return &version, nil
@@ -91,4 +104,66 @@ func (r *versionRepositoryImpl) DeleteVersion(ctx context.Context, id uint, serv
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// DeprecateVersion marks a version as no longer active
+// Returns the updated version or ErrNotFound if it doesn't exist.
+func (r *versionRepositoryImpl) DeprecateVersion(ctx context.Context, id uint, serviceId uint) (*models.Version, error) {
+	// Update skips zero values of structs, so the column is set by name
+	result := r.db.WithContext(ctx).Model(&models.Version{}).Where("id = ? AND service_id = ?", id, serviceId).Update("is_active", false)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return r.GetVersion(ctx, id, serviceId)
+}
+
+// ListVersions returns a page of the versions of a service and their total count
+// Returns ErrNotFound if the service doesn't exist.
+func (r *versionRepositoryImpl) ListVersions(ctx context.Context, filter models.VersionFilter) ([]models.Version, int, error) {
+	db := r.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Model(&models.Version{}).Where("service_id = ?", filter.ServiceID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		// Tell a service without versions from a missing one
+		var services int64
+		if err := db.Model(&models.Service{}).Where("id = ?", filter.ServiceID).Count(&services).Error; err != nil {
+			return nil, 0, err
+		}
+		if services == 0 {
+			return nil, 0, ErrNotFound
+		}
+	}
+
+	versions := make([]models.Version, 0)
+	err := db.Where("service_id = ?", filter.ServiceID).
+		Order(versionOrder(filter.Sort)).
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&versions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return versions, int(total), nil
+}
+
+// versionOrder returns the ORDER BY clause of a version sort: created_at,
+// updated_at or version, prefixed with "-" for descending. Unknown sorts
+// fall back to created_at.
+func versionOrder(sort string) string {
+	column, direction := strings.TrimPrefix(sort, "-"), "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+	}
+	switch column {
+	case "created_at", "updated_at", "version":
+	default:
+		column = "created_at"
+	}
+	return column + " " + direction + ", id " + direction
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"services-api/internal/repository/repositorytest"
+)
+
+func TestVersionRepository_NotFound(t *testing.T) {
+	repo := NewVersionRepository(repositorytest.EmptyDB(t))
+
+	if _, err := repo.GetVersion(context.Background(), 1, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from GetVersion, got %v", err)
+	}
+	if err := repo.DeleteVersion(context.Background(), 1, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from DeleteVersion, got %v", err)
+	}
+	if _, err := repo.DeprecateVersion(context.Background(), 1, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from DeprecateVersion, got %v", err)
+	}
+}
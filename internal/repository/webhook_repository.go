@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"services-api/internal/models"
+)
+
+// WebhookRepository interface defines data access methods for webhooks and their deliveries
+type WebhookRepository interface {
+	// CreateWebhook stores a new webhook
+	// Returns the created webhook or an error if the creation fails.
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error)
+
+	// ListWebhooks returns all webhooks, including disabled ones
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+
+	// GetWebhook retrieves a webhook by its ID
+	// Returns the webhook or ErrNotFound if it doesn't exist.
+	GetWebhook(ctx context.Context, id uint) (*models.Webhook, error)
+
+	// UpdateWebhook replaces the subscription, secret and disabled state of a webhook
+	// Returns the updated webhook or ErrNotFound if it doesn't exist.
+	UpdateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error)
+
+	// DeleteWebhook deletes a webhook and its deliveries
+	// Returns ErrNotFound if the webhook doesn't exist.
+	DeleteWebhook(ctx context.Context, id uint) error
+
+	// RecordWebhookSuccess resets the consecutive failures of a webhook
+	RecordWebhookSuccess(ctx context.Context, id uint) error
+
+	// RecordWebhookFailure counts a failed delivery and disables the webhook
+	// once disableAfter deliveries in a row have failed.
+	// Returns whether the webhook was disabled.
+	RecordWebhookFailure(ctx context.Context, id uint, disableAfter int, reason string, at time.Time) (bool, error)
+
+	// CreateDeliveries stores new deliveries, skipping events already queued
+	// for their webhook. Skipped deliveries keep a zero ID.
+	CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+
+	// ListDeliveries returns the latest deliveries of a webhook, newest first
+	ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error)
+
+	// GetDelivery retrieves a delivery of a webhook by its ID
+	// Returns the delivery or ErrNotFound if it doesn't exist.
+	GetDelivery(ctx context.Context, webhookID uint, id uint) (*models.WebhookDelivery, error)
+
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now and
+	// postpones them by lease, so other instances don't send them concurrently
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+
+	// UpdateDelivery stores the outcome of a delivery attempt
+	UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+}
+
+type webhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+// with the provided database connection.
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepositoryImpl{db: db}
+}
+
+// CreateWebhook stores a new webhook
+func (r *webhookRepositoryImpl) CreateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error) {
+	if err := r.db.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks returns all webhooks ordered by creation
+func (r *webhookRepositoryImpl) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks := make([]models.Webhook, 0)
+	if err := r.db.WithContext(ctx).Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhook retrieves a webhook by its ID
+func (r *webhookRepositoryImpl) GetWebhook(ctx context.Context, id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.db.WithContext(ctx).First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// UpdateWebhook replaces the subscription, secret and disabled state of a webhook
+func (r *webhookRepositoryImpl) UpdateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error) {
+	result := r.db.WithContext(ctx).Model(&webhook).
+		Select("url", "events", "service_ids", "secret", "consecutive_failures", "disabled_at", "disabled_reason").
+		Updates(&webhook)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Check if any rows were affected (record exists)
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return r.GetWebhook(ctx, webhook.ID)
+}
+
+// DeleteWebhook deletes a webhook and its deliveries
+func (r *webhookRepositoryImpl) DeleteWebhook(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Webhook{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Check if any rows were affected (record exists)
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// RecordWebhookSuccess resets the consecutive failures of a webhook
+func (r *webhookRepositoryImpl) RecordWebhookSuccess(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.Webhook{}).
+		Where("id = ? AND consecutive_failures > 0", id).
+		UpdateColumn("consecutive_failures", 0).Error
+}
+
+// RecordWebhookFailure increments the consecutive failures of a webhook and
+// disables it when they reach disableAfter
+func (r *webhookRepositoryImpl) RecordWebhookFailure(ctx context.Context, id uint, disableAfter int, reason string, at time.Time) (bool, error) {
+	disabled := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Webhook{}).Where("id = ?", id).
+			UpdateColumn("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.Webhook{}).
+			Where("id = ? AND disabled_at IS NULL AND consecutive_failures >= ?", id, disableAfter).
+			UpdateColumns(map[string]any{"disabled_at": at, "disabled_reason": reason})
+		if result.Error != nil {
+			return result.Error
+		}
+		disabled = result.RowsAffected > 0
+		return nil
+	})
+	return disabled, err
+}
+
+// CreateDeliveries stores new deliveries, skipping events already queued
+// for their webhook
+func (r *webhookRepositoryImpl) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// ListDeliveries returns the latest deliveries of a webhook
+func (r *webhookRepositoryImpl) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := make([]models.WebhookDelivery, 0)
+	err := r.db.WithContext(ctx).
+		Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDelivery retrieves a delivery of a webhook by its ID
+func (r *webhookRepositoryImpl) GetDelivery(ctx context.Context, webhookID uint, id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.WithContext(ctx).Where("webhook_id = ?", webhookID).First(&delivery, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ClaimDueDeliveries postpones the due deliveries by lease and returns
+// them. Rows locked by another instance are skipped.
+func (r *webhookRepositoryImpl) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := make([]models.WebhookDelivery, 0)
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), models.DeliveryPending, now, limit).
+		Scan(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *webhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(&delivery).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "error", "delivered_at").
+		Updates(&delivery).Error
+}
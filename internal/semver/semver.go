@@ -0,0 +1,61 @@
+// Package semver parses and orders semantic versions such as "1.2.3",
+// "v1.2" or "2.0.0-rc.1", for version constraints and for finding the
+// latest version of a service.
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed version; parts that weren't given are zero and only
+// the first Given parts are compared for prefix matches
+type Version struct {
+	Core       [3]int
+	Given      int
+	Prerelease string
+}
+
+// Parse parses a version of up to three parts, with an optional "v" prefix
+// and prerelease. It reports false if s isn't a semantic version.
+func Parse(s string) (Version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	// Build metadata doesn't take part in comparisons
+	s, _, _ = strings.Cut(s, "+")
+	core, prerelease, _ := strings.Cut(s, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Version{}, false
+	}
+	v := Version{Given: len(parts), Prerelease: prerelease}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, false
+		}
+		v.Core[i] = n
+	}
+	return v, true
+}
+
+// Compare orders versions; a prerelease sorts before its release
+func (v Version) Compare(o Version) int {
+	for i := range v.Core {
+		if v.Core[i] != o.Core[i] {
+			if v.Core[i] < o.Core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+	return strings.Compare(v.Prerelease, o.Prerelease)
+}
@@ -0,0 +1,44 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Version
+		ok    bool
+	}{
+		{"1.2.3", Version{Core: [3]int{1, 2, 3}, Given: 3}, true},
+		{"v1.2", Version{Core: [3]int{1, 2, 0}, Given: 2}, true},
+		{"2.0.0-rc.1+build.5", Version{Core: [3]int{2, 0, 0}, Given: 3, Prerelease: "rc.1"}, true},
+		{"1.2.3.4", Version{}, false},
+		{"latest", Version{}, false},
+		{"1.-2", Version{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.0", 1},
+		{"1.2", "1.2.0", 0},
+		{"2.0.0-rc.1", "2.0.0", -1},
+		{"2.0.0-alpha", "2.0.0-beta", -1},
+		{"v3.0.0", "3.0.0+build", 0},
+	}
+	for _, tt := range tests {
+		a, _ := Parse(tt.a)
+		b, _ := Parse(tt.b)
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("%s compared to %s = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	servicesv1 "services-api/api/services/v1"
+	"services-api/internal/grpcapi"
+)
+
+// NewGRPCServer creates the gRPC server of the API. It serves the service
+// registry with the authentication and rate limits of the REST API, the
+// standard health checking service and, if enabled, reflection. It uses the
+// TLS settings of the HTTP server.
+func (s *Server) NewGRPCServer() (*grpc.Server, error) {
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(grpcapi.UnaryAuthInterceptor(s.authn, s.limiter)),
+		grpc.ChainStreamInterceptor(grpcapi.StreamAuthInterceptor(s.authn, s.limiter)),
+	}
+	if s.config.Server.TLS.Enabled {
+		tlsConfig, err := NewTLSConfig(s.config.Server.TLS)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	grpcServer := grpc.NewServer(options...)
+	servicesv1.RegisterServiceRegistryServer(grpcServer, grpcapi.NewServer(s.businesses.Services, s.businesses.Versions, s.stream))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(servicesv1.ServiceRegistry_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	if s.config.Server.GRPC.Reflection {
+		reflection.Register(grpcServer)
+	}
+	return grpcServer, nil
+}
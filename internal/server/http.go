@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"services-api/internal/config"
+)
+
+// NewHTTPServer creates the HTTP server for the API using the configured
+// timeouts, header limits and TLS settings
+func NewHTTPServer(cfg config.ServerConfig, handler http.Handler) (*http.Server, error) {
+	httpServer := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	if cfg.TLS.Enabled {
+		tlsConfig, err := NewTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		httpServer.TLSConfig = tlsConfig
+	}
+
+	return httpServer, nil
+}
+
+// NewAdminHTTPServer creates the HTTP server for the admin listener. It has
+// no write timeout so that long running profiles can complete.
+func NewAdminHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Admin.Addr(),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// ListenAndServe starts the server, serving HTTPS when it has a TLS configuration
+func ListenAndServe(httpServer *http.Server) error {
+	if httpServer.TLSConfig != nil {
+		// Certificates are provided by the TLS configuration
+		return httpServer.ListenAndServeTLS("", "")
+	}
+	return httpServer.ListenAndServe()
+}
+
+// AdminRouter returns the handler for the admin listener, serving metrics,
+// health and, if enabled, pprof
+func (s *Server) AdminRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/health", s.healthCheck)
+	router.GET("/metrics", gin.WrapH(s.metrics.Handler()))
+
+	if s.config.Server.Admin.Pprof {
+		router.Any("/debug/pprof/*profile", pprofHandler)
+	}
+
+	return router
+}
+
+// pprofHandler dispatches to the net/http/pprof handlers
+func pprofHandler(c *gin.Context) {
+	switch strings.TrimPrefix(c.Param("profile"), "/") {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		pprof.Index(c.Writer, c.Request)
+	}
+}
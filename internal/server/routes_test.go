@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"services-api/internal/config"
+)
+
+func TestRouter_AnonymousWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Authentication is optional by default, which only opens up reads
+	srv := NewServerWith(nil, config.Default(), Businesses{})
+
+	for _, route := range []struct{ method, path string }{
+		{"POST", "/api/v1/services"},
+		{"PATCH", "/api/v1/services/1"},
+		{"DELETE", "/api/v1/services/1"},
+		{"POST", "/api/v1/services/1/versions"},
+		{"PUT", "/api/v1/services/1/versions/1"},
+		{"DELETE", "/api/v1/services/1/versions/1"},
+		{"POST", "/api/v1/services/1/dependencies"},
+		{"DELETE", "/api/v1/services/1/dependencies/1"},
+		{"POST", "/api/v1/import"},
+	} {
+		req, _ := http.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s", route.method, route.path)
+		assert.Contains(t, w.Body.String(), "authentication_required", "%s %s", route.method, route.path)
+	}
+}
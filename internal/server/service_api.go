@@ -2,17 +2,25 @@ package server
 
 import (
 	"net/http"
+	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
 
+	"services-api/internal/auth"
 	"services-api/internal/business"
 	"services-api/internal/config"
 	"services-api/internal/handlers"
+	"services-api/internal/metrics"
 	"services-api/internal/middleware"
+	"services-api/internal/models"
+	"services-api/internal/outbox"
+	"services-api/internal/ratelimit"
 	"services-api/internal/repository"
+	"services-api/internal/webhook"
 )
 
 // HealthStatus represents the health check response
@@ -27,20 +35,92 @@ type Server struct {
 	router  *gin.Engine
 	db      *gorm.DB
 	config  *config.Config
+	metrics *metrics.HTTPMetrics
+	limiter *middleware.RateLimiter
+	authn   *middleware.Authenticator
+	cors    *middleware.CORS
+	secure  *middleware.SecurityHeaders
+	hooks   *webhook.Dispatcher
+	outbox  *outbox.Dispatcher
+	stream  *outbox.Stream
 	version string
+
+	businesses Businesses
+}
+
+// Businesses holds the business logic served by the API
+type Businesses struct {
+	Services     business.BusinessService
+	Versions     business.VersionBusiness
+	APIKeys      business.APIKeyBusiness
+	Dependencies business.DependencyBusiness
+	Impact       business.ImpactBusiness
+	CustomFields business.CustomFieldBusiness
+	Search       business.SearchBusiness
+	Batch        business.BatchBusiness
+	Catalog      business.CatalogBusiness
+	Webhooks     business.WebhookBusiness
+	Changes      business.ChangeBusiness
+}
+
+// NewBusinesses creates the business logic over the repositories of db
+func NewBusinesses(db *gorm.DB) Businesses {
+	serviceRepo := repository.NewServiceRepository(db)
+	versionRepo := repository.NewVersionRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	dependencyRepo := repository.NewDependencyRepository(db)
+	customFieldRepo := repository.NewCustomFieldRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
+	repositories := repository.Repositories{
+		Services:     serviceRepo,
+		Versions:     versionRepo,
+		Dependencies: dependencyRepo,
+		CustomFields: customFieldRepo,
+		Outbox:       outboxRepo,
+	}
+
+	return Businesses{
+		Services:     business.NewServiceBusiness(serviceRepo, customFieldRepo, unitOfWork),
+		Versions:     business.NewVersionBusiness(versionRepo, unitOfWork),
+		APIKeys:      business.NewAPIKeyBusiness(apiKeyRepo),
+		Dependencies: business.NewDependencyBusiness(dependencyRepo, serviceRepo),
+		Impact:       business.NewImpactBusiness(serviceRepo, dependencyRepo, apiKeyRepo),
+		CustomFields: business.NewCustomFieldBusiness(customFieldRepo),
+		Search:       business.NewSearchBusiness(repository.NewSearchRepository(db)),
+		Batch:        business.NewBatchBusiness(unitOfWork, repositories),
+		Catalog:      business.NewCatalogBusiness(unitOfWork, repositories),
+		Webhooks:     business.NewWebhookBusiness(repository.NewWebhookRepository(db)),
+		Changes:      business.NewChangeBusiness(outboxRepo),
+	}
 }
 
 // NewServer creates a new API server
 func NewServer(db *gorm.DB, cfg *config.Config) *Server {
+	return NewServerWith(db, cfg, NewBusinesses(db))
+}
+
+// NewServerWith creates a new API server routing requests to businesses.
+// db is used for the health check and the event dispatchers.
+func NewServerWith(db *gorm.DB, cfg *config.Config, businesses Businesses) *Server {
 	server := &Server{
 		router:  gin.Default(),
 		db:      db,
 		config:  cfg,
+		metrics: metrics.NewHTTPMetrics(),
+		limiter: middleware.NewRateLimiter(cfg.RateLimit, ratelimit.NewMemoryStore()),
+		cors:    middleware.NewCORS(cfg.CORS),
+		secure:  middleware.NewSecurityHeaders(cfg.Security),
+		hooks:   webhook.NewDispatcher(repository.NewWebhookRepository(db), cfg.Webhooks),
+		stream:  outbox.NewStream(repository.NewOutboxRepository(db), cfg.Outbox.PollInterval),
 		version: "1.0.0", // Set your API version here
+
+		businesses: businesses,
 	}
+	server.outbox = outbox.NewDispatcher(repository.NewOutboxRepository(db), server.eventPublisher(), cfg.Outbox)
 	
 	// Set up routes immediately on creation
-	server.setupRoutes()
+	server.setupRoutes(businesses)
 	
 	return server
 }
@@ -50,60 +130,198 @@ func (s *Server) Router() *gin.Engine {
 	return s.router
 }
 
+// Webhooks returns the dispatcher that delivers events to webhooks; it must
+// be run for deliveries to be sent
+func (s *Server) Webhooks() *webhook.Dispatcher {
+	return s.hooks
+}
+
+// Outbox returns the dispatcher that publishes the events of catalog
+// changes; it must be run for events to be published
+func (s *Server) Outbox() *outbox.Dispatcher {
+	return s.outbox
+}
+
+// Stream returns the stream following the event log for event stream
+// clients; it must be run for them to receive new events
+func (s *Server) Stream() *outbox.Stream {
+	return s.stream
+}
+
+// eventPublisher returns a publisher sending events to the configured publishers
+func (s *Server) eventPublisher() outbox.Publisher {
+	var publishers []outbox.Publisher
+	for _, name := range s.config.Outbox.Publishers {
+		switch name {
+		case config.PublisherWebhook:
+			publishers = append(publishers, s.hooks)
+		case config.PublisherStdout:
+			publishers = append(publishers, outbox.NewWriterPublisher(os.Stdout))
+		}
+	}
+	return outbox.Multi(publishers...)
+}
+
+// ApplyConfig updates the components that support runtime reconfiguration
+func (s *Server) ApplyConfig(cfg *config.Config) {
+	s.limiter.Update(cfg.RateLimit)
+	s.authn.Update(cfg.Auth)
+	s.cors.Update(cfg.CORS)
+	s.secure.Update(cfg.Security)
+	s.hooks.Update(cfg.Webhooks)
+}
+
 // Run starts the server
 func (s *Server) Run(addr string) error {
 	return s.router.Run(addr)
 }
 
 // setupRoutes configures all the routes for the API server
-func (s *Server) setupRoutes() {
-	// Initialize repositories
-	serviceRepo := repository.NewServiceRepository(s.db)
-	versionRepo := repository.NewVersionRepository(s.db)
-	
-	// Initialize services
-	serviceBusiness := business.NewServiceBusiness(serviceRepo)
-	versionBusiness := business.NewVersionBusiness(versionRepo)
-	
+func (s *Server) setupRoutes(b Businesses) {
 	// Initialize handlers
-	serviceHandler := handlers.NewServiceHandler(serviceBusiness)
-	versionHandler := handlers.NewVersionHandler(versionBusiness)
+	serviceHandler := handlers.NewServiceHandler(b.Services, b.Impact)
+	versionHandler := handlers.NewVersionHandler(b.Versions, b.Impact)
+	apiKeyHandler := handlers.NewAPIKeyHandler(b.APIKeys)
+	dependencyHandler := handlers.NewDependencyHandler(b.Dependencies)
+	impactHandler := handlers.NewImpactHandler(b.Impact)
+	customFieldHandler := handlers.NewCustomFieldHandler(b.CustomFields)
+	searchHandler := handlers.NewSearchHandler(b.Search)
+	batchHandler := handlers.NewBatchHandler(b.Batch)
+	catalogHandler := handlers.NewCatalogHandler(b.Catalog)
+	webhookHandler := handlers.NewWebhookHandler(b.Webhooks)
+	eventHandler := handlers.NewEventHandler(s.stream, b.Services)
+	changeHandler := handlers.NewChangeHandler(b.Changes)
+
+	// Initialize authentication
+	s.authn = middleware.NewAuthenticator(s.config.Auth, b.APIKeys)
 	
+	// Security and CORS headers apply to every response, including the
+	// Swagger UI and preflight requests that don't match a route
+	s.router.Use(s.secure.Middleware())
+	s.router.Use(s.cors.Middleware())
+
 	// Initialize Swagger UI
 	s.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.NewHandler()))
 
 	// Setup middleware
 	s.router.Use(middleware.RequestLogger())
 	s.router.Use(middleware.ErrorHandler())
-	
+	s.router.Use(middleware.Metrics(s.metrics))
+	s.router.Use(middleware.ClientCertificate())
+
 	// API v1 routes
 	v1 := s.router.Group("/api/v1")
 	{
-		// setup auth middleware for all the protected routes
-		// v1.Use(middleware.AuthMiddleware())
+		// Failed authentications are limited per IP address before the
+		// credentials are checked, so guessing them is throttled
+		v1.Use(s.limiter.AuthFailures())
+
+		// Authenticate API keys, JWTs and client certificates for all the routes
+		v1.Use(s.authn.Middleware())
+
+		// Rate limiting is keyed by the authenticated identity, so it runs after auth
+		v1.Use(s.limiter.Middleware())
+
+		read := middleware.RequireScope(auth.ScopeRead)
+		writeServices := middleware.RequireScope(auth.ScopeWriteServices)
+		writeVersions := middleware.RequireScope(auth.ScopeWriteVersions)
 
 		// Services endpoints
 		services := v1.Group("/services")
 		{
-			services.GET("", serviceHandler.ListServices)
-			services.GET("/:sid", serviceHandler.GetService)
-			services.POST("", serviceHandler.CreateService)
-			services.PATCH("/:sid", serviceHandler.UpdateService)
-			services.DELETE("/:sid", serviceHandler.DeleteService)
+			services.GET("", read, serviceHandler.ListServices)
+			services.GET("/:sid", read, serviceHandler.GetService)
+			services.POST("", writeServices, serviceHandler.CreateService)
+			services.PATCH("/:sid", writeServices, serviceHandler.UpdateService)
+			services.DELETE("/:sid", writeServices, serviceHandler.DeleteService)
 		}
 		
 		// Versions endpoints with renamed parameter to avoid conflict
 		versions := v1.Group("/services/:sid/versions")
 		{
-			versions.POST("", versionHandler.CreateVersion)
-			versions.GET("/:vid", versionHandler.GetVersion)
-			versions.PUT("/:vid", versionHandler.UpdateVersion)
-			versions.DELETE("/:vid", versionHandler.DeleteVersion)
+			versions.GET("", read, versionHandler.ListVersions)
+			versions.POST("", writeVersions, versionHandler.CreateVersion)
+			versions.GET("/:vid", read, versionHandler.GetVersion)
+			versions.PUT("/:vid", writeVersions, versionHandler.UpdateVersion)
+			versions.DELETE("/:vid", writeVersions, versionHandler.DeleteVersion)
+			versions.POST("/:vid/deprecate", writeVersions, versionHandler.DeprecateVersion)
+			versions.GET("/:vid/impact", read, impactHandler.VersionImpact)
+		}
+
+		// Dependency endpoints
+		dependencies := v1.Group("/services/:sid/dependencies")
+		{
+			dependencies.GET("", read, dependencyHandler.ListDependencies)
+			dependencies.POST("", writeServices, dependencyHandler.CreateDependency)
+			dependencies.GET("/:did", read, dependencyHandler.GetDependency)
+			dependencies.PATCH("/:did", writeServices, dependencyHandler.UpdateDependency)
+			dependencies.DELETE("/:did", writeServices, dependencyHandler.DeleteDependency)
+		}
+		v1.GET("/services/:sid/dependents", read, dependencyHandler.ListDependents)
+		v1.GET("/services/:sid/impact", read, impactHandler.ServiceImpact)
+		v1.GET("/graph", read, dependencyHandler.GetGraph)
+		v1.GET("/labels", read, serviceHandler.ListLabels)
+		v1.GET("/search", read, searchHandler.Search)
+		v1.GET("/events/stream", read, eventHandler.StreamEvents)
+		v1.GET("/changes", read, changeHandler.ListChanges)
+
+		// Catalog dumps; imports write services and versions, and replacing
+		// the catalog deletes services, which is an admin operation
+		v1.GET("/export", read, catalogHandler.Export)
+		replace := middleware.RequireScopeIf(auth.ScopeAdmin, func(c *gin.Context) bool {
+			dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+			return c.Query("mode") == models.ImportReplace && !dryRun
+		})
+		v1.POST("/import", writeServices, writeVersions, replace, catalogHandler.Import)
+
+		// Custom methods such as /services:batch
+		v1.POST("/:method", customMethods(map[string][]gin.HandlerFunc{
+			"services:batch": {writeServices, batchHandler.BatchServices},
+		}))
+		v1.POST("/services/:sid/:method", customMethods(map[string][]gin.HandlerFunc{
+			"versions:batch": {writeVersions, batchHandler.BatchVersions},
+		}))
+
+		// Custom field definitions are readable by everyone but only admins manage them
+		customFields := v1.Group("/custom-fields")
+		{
+			admin := middleware.RequireScope(auth.ScopeAdmin)
+			customFields.GET("", read, customFieldHandler.ListCustomFields)
+			customFields.GET("/:fid", read, customFieldHandler.GetCustomField)
+			customFields.POST("", admin, customFieldHandler.CreateCustomField)
+			customFields.PATCH("/:fid", admin, customFieldHandler.UpdateCustomField)
+			customFields.DELETE("/:fid", admin, customFieldHandler.DeleteCustomField)
+		}
+
+		// API key management endpoints, always restricted to admins
+		apiKeys := v1.Group("/api-keys", middleware.RequireScope(auth.ScopeAdmin))
+		{
+			apiKeys.POST("", apiKeyHandler.CreateAPIKey)
+			apiKeys.GET("", apiKeyHandler.ListAPIKeys)
+			apiKeys.POST("/:kid/rotate", apiKeyHandler.RotateAPIKey)
+			apiKeys.DELETE("/:kid", apiKeyHandler.RevokeAPIKey)
+		}
+
+		// Webhook subscriptions receive signed events, so only admins manage them
+		webhooks := v1.Group("/webhooks", middleware.RequireScope(auth.ScopeAdmin))
+		{
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("", webhookHandler.ListWebhooks)
+			webhooks.GET("/:wid", webhookHandler.GetWebhook)
+			webhooks.PUT("/:wid", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:wid", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:wid/deliveries", webhookHandler.ListDeliveries)
+			webhooks.POST("/:wid/deliveries/:did/redeliver", webhookHandler.Redeliver)
 		}
 	}
 
 	// Enhanced health check
 	s.router.GET("/health", s.healthCheck)
+
+	// Metrics are served on the admin listener when it is enabled
+	if !s.config.Server.Admin.Enabled {
+		s.router.GET("/metrics", gin.WrapH(s.metrics.Handler()))
+	}
 }
 
 // healthCheck handles the /health endpoint
@@ -133,4 +351,24 @@ func (s *Server) healthCheck(c *gin.Context) {
 	}
 	
 	c.JSON(http.StatusOK, health)
-}
\ No newline at end of file
+}
+
+// customMethods dispatches custom methods, whose names are appended to a
+// collection with a colon. gin can't match a static suffix after a path
+// segment, so they are routed on a parameter holding the last segment
+// and unknown names are reported as missing routes.
+func customMethods(methods map[string][]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		chain, ok := methods[c.Param("method")]
+		if !ok {
+			c.String(http.StatusNotFound, "404 page not found")
+			return
+		}
+		for _, handler := range chain {
+			handler(c)
+			if c.IsAborted() {
+				return
+			}
+		}
+	}
+}
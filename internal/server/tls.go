@@ -0,0 +1,161 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"services-api/internal/config"
+)
+
+// certificateCheckInterval limits how often the certificate files are checked
+// for changes during TLS handshakes
+const certificateCheckInterval = 10 * time.Second
+
+// certificateReloader keeps the server certificate and the client CA pool in
+// sync with the files on disk so they can be rotated without a restart.
+type certificateReloader struct {
+	cfg config.TLSConfig
+
+	mu        sync.RWMutex
+	tlsConfig *tls.Config
+	modTimes  []time.Time
+	lastCheck time.Time
+}
+
+// nextProtos are the ALPN protocols offered. The configurations returned by
+// GetConfigForClient replace the one net/http adds h2 to, so they need to
+// offer it themselves for HTTP/2 and gRPC to be negotiated.
+var nextProtos = []string{"h2", "http/1.1"}
+
+// NewTLSConfig builds the TLS configuration for the HTTP server. The
+// certificate, key and client CA files are reloaded when they change on disk.
+func NewTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	reloader := &certificateReloader{cfg: cfg}
+	if err := reloader.load(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tlsVersion(cfg.MinVersion),
+		NextProtos: nextProtos,
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &reloader.current().Certificates[0], nil
+		},
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			return reloader.current(), nil
+		},
+	}, nil
+}
+
+// current returns the active TLS configuration, reloading it first if the
+// files on disk have changed
+func (r *certificateReloader) current() *tls.Config {
+	r.mu.RLock()
+	due := time.Since(r.lastCheck) >= certificateCheckInterval
+	r.mu.RUnlock()
+
+	if due {
+		r.reloadIfChanged()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.tlsConfig
+}
+
+func (r *certificateReloader) reloadIfChanged() {
+	r.mu.Lock()
+	r.lastCheck = time.Now()
+	previous := r.modTimes
+	r.mu.Unlock()
+
+	current := r.fileModTimes()
+	changed := false
+	for i := range current {
+		if !current[i].Equal(previous[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := r.load(); err != nil {
+		slog.Warn("Failed to reload TLS certificates, keeping the current ones", "error", err)
+		return
+	}
+	log.Println("Reloaded TLS certificates")
+}
+
+// load reads the certificate files and swaps in a new TLS configuration
+func (r *certificateReloader) load() error {
+	modTimes := r.fileModTimes()
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tlsVersion(r.cfg.MinVersion),
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   clientAuthType(r.cfg.ClientAuth),
+		NextProtos:   nextProtos,
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA file %s", r.cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tlsConfig = tlsConfig
+	r.modTimes = modTimes
+	r.lastCheck = time.Now()
+	return nil
+}
+
+func (r *certificateReloader) fileModTimes() []time.Time {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile}
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func tlsVersion(version string) uint16 {
+	if version == "1.3" {
+		return tls.VersionTLS13
+	}
+	return tls.VersionTLS12
+}
+
+func clientAuthType(mode string) tls.ClientAuthType {
+	switch mode {
+	case "optional":
+		return tls.VerifyClientCertIfGiven
+	case "require":
+		return tls.RequireAndVerifyClientCert
+	}
+	return tls.NoClientCert
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"services-api/internal/auth"
+	"services-api/internal/config"
+	"services-api/internal/middleware"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert issues a certificate signed by parent, or a self-signed CA when parent is nil
+func newTestCert(t *testing.T, commonName string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestNewTLSConfig_ClientCertificateIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+
+	ca := newTestCert(t, "test-ca", nil)
+	serverCert := newTestCert(t, "localhost", ca)
+	clientCert := newTestCert(t, "ci-pipeline", ca)
+
+	cfg := config.TLSConfig{
+		Enabled:      true,
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+		MinVersion:   "1.2",
+		ClientAuth:   "require",
+	}
+	writeFile(t, cfg.CertFile, serverCert.certPEM)
+	writeFile(t, cfg.KeyFile, serverCert.keyPEM)
+	writeFile(t, cfg.ClientCAFile, ca.certPEM)
+
+	tlsConfig, err := NewTLSConfig(cfg)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(middleware.ClientCertificate())
+	router.GET("/whoami", func(c *gin.Context) {
+		identity, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		c.String(http.StatusOK, identity.Subject)
+	})
+
+	ts := httptest.NewUnstartedServer(router)
+	ts.TLS = tlsConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientPair, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{clientPair},
+	}}}
+	resp, err := client.Get(ts.URL + "/whoami")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	assert.Equal(t, "ci-pipeline", string(body[:n]))
+
+	// Clients without a certificate are rejected during the handshake
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	if resp, err := anonymous.Get(ts.URL + "/whoami"); err == nil {
+		resp.Body.Close()
+		t.Error("expected request without client certificate to fail")
+	}
+}
+
+func TestNewTLSConfig_NegotiatesHTTP2(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "test-ca", nil)
+	serverCert := newTestCert(t, "localhost", ca)
+	cfg := config.TLSConfig{
+		Enabled:    true,
+		CertFile:   filepath.Join(dir, "server.crt"),
+		KeyFile:    filepath.Join(dir, "server.key"),
+		MinVersion: "1.2",
+	}
+	writeFile(t, cfg.CertFile, serverCert.certPEM)
+	writeFile(t, cfg.KeyFile, serverCert.keyPEM)
+
+	tlsConfig, err := NewTLSConfig(cfg)
+	require.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	ts.EnableHTTP2 = true
+	ts.TLS = tlsConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "HTTP/2.0", resp.Proto)
+	assert.Equal(t, "h2", resp.TLS.NegotiatedProtocol)
+}
+
+func TestCertificateReloader_ReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "test-ca", nil)
+	first := newTestCert(t, "first", ca)
+	second := newTestCert(t, "second", ca)
+
+	cfg := config.TLSConfig{
+		CertFile: filepath.Join(dir, "server.crt"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	}
+	writeFile(t, cfg.CertFile, first.certPEM)
+	writeFile(t, cfg.KeyFile, first.keyPEM)
+
+	reloader := &certificateReloader{cfg: cfg}
+	require.NoError(t, reloader.load())
+
+	writeFile(t, cfg.CertFile, second.certPEM)
+	writeFile(t, cfg.KeyFile, second.keyPEM)
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(cfg.CertFile, future, future))
+
+	// Force the next handshake to check the files
+	reloader.lastCheck = time.Time{}
+	leaf, err := x509.ParseCertificate(reloader.current().Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "second", leaf.Subject.CommonName)
+
+	// A broken certificate keeps the previous one in place
+	writeFile(t, cfg.CertFile, []byte("garbage"))
+	future = future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(cfg.CertFile, future, future))
+	reloader.lastCheck = time.Time{}
+	leaf, err = x509.ParseCertificate(reloader.current().Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "second", leaf.Subject.CommonName)
+}
@@ -0,0 +1,262 @@
+// Package webhook delivers catalog events to webhook subscriptions. Every
+// matching event is stored as a delivery and sent in the background as a
+// signed JSON POST, retried with exponential backoff until it succeeds or
+// runs out of attempts. Webhooks whose deliveries keep failing are disabled.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"services-api/internal/business"
+	"services-api/internal/config"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// Headers of webhook requests
+const (
+	SignatureHeader = "X-Webhook-Signature-256" // sha256=<hex HMAC-SHA256 of the body keyed with the secret>
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	// pollInterval is how often due retries are looked for
+	pollInterval = 5 * time.Second
+
+	// workers is the number of deliveries sent concurrently
+	workers = 8
+
+	// maxResponseSize bounds how much of a response body is read
+	maxResponseSize = 4 << 10
+)
+
+// Dispatcher stores and sends the deliveries of catalog events
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	config atomic.Pointer[config.WebhookConfig]
+	client *http.Client
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+// NewDispatcher creates a dispatcher storing deliveries in repo
+func NewDispatcher(repo repository.WebhookRepository, cfg config.WebhookConfig) *Dispatcher {
+	d := &Dispatcher{
+		repo: repo,
+		client: &http.Client{
+			// Redirects aren't followed so payloads only go to the subscribed URL
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		wake: make(chan struct{}, 1),
+		now:  time.Now,
+	}
+	d.config.Store(&cfg)
+	return d
+}
+
+// Update replaces the delivery settings
+func (d *Dispatcher) Update(cfg config.WebhookConfig) {
+	d.config.Store(&cfg)
+}
+
+// Publish stores a delivery of event for every webhook subscribed to it and
+// wakes the dispatcher
+func (d *Dispatcher) Publish(ctx context.Context, event models.Event) error {
+	webhooks, err := d.repo.ListWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	now := d.now()
+	var deliveries []models.WebhookDelivery
+	for _, webhook := range webhooks {
+		if business.WebhookMatches(webhook, event) {
+			deliveries = append(deliveries, models.WebhookDelivery{
+				WebhookID:     webhook.ID,
+				EventID:       event.ID,
+				EventType:     event.Type,
+				Payload:       string(payload),
+				Status:        models.DeliveryPending,
+				NextAttemptAt: &now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := d.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to queue deliveries: %w", err)
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run sends the due deliveries until ctx is done, when woken by Publish and
+// every few seconds for retries and redeliveries
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for d.deliverDue(ctx) > 0 {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// deliverDue claims a batch of due deliveries, sends them concurrently and
+// returns how many were claimed
+func (d *Dispatcher) deliverDue(ctx context.Context) int {
+	cfg := d.config.Load()
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.now(), cfg.Timeout+time.Minute, workers)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to claim webhook deliveries", "error", err)
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, *cfg, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries)
+}
+
+// deliver makes one attempt and schedules the next one, or finishes the
+// delivery and records the outcome on the webhook
+func (d *Dispatcher) deliver(ctx context.Context, cfg config.WebhookConfig, delivery models.WebhookDelivery) {
+	webhook, err := d.repo.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		slog.Error("Failed to load webhook for delivery", "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID, "error", err)
+		return
+	}
+
+	now := d.now()
+	delivery.LastAttemptAt = &now
+	if webhook.DisabledAt != nil {
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.Error = "webhook is disabled"
+		d.saveDelivery(ctx, delivery)
+		return
+	}
+
+	delivery.Attempts++
+	delivery.ResponseStatus, err = d.send(ctx, cfg, *webhook, delivery)
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		delivery.Error = ""
+		if webhook.ConsecutiveFailures > 0 {
+			if err := d.repo.RecordWebhookSuccess(ctx, webhook.ID); err != nil {
+				slog.Error("Failed to reset the failures of webhook", "webhook_id", webhook.ID, "error", err)
+			}
+		}
+	case delivery.Attempts < cfg.MaxAttempts:
+		next := now.Add(backoff(cfg, delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.Error = err.Error()
+	default:
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.Error = err.Error()
+		reason := fmt.Sprintf("%d consecutive deliveries failed", cfg.DisableAfter)
+		disabled, err := d.repo.RecordWebhookFailure(ctx, webhook.ID, cfg.DisableAfter, reason, now)
+		if err != nil {
+			slog.Error("Failed to record the failure of webhook", "webhook_id", webhook.ID, "error", err)
+		} else if disabled {
+			slog.Warn("Disabled webhook", "webhook_id", webhook.ID, "url", webhook.URL, "reason", reason)
+		}
+	}
+	d.saveDelivery(ctx, delivery)
+}
+
+func (d *Dispatcher) saveDelivery(ctx context.Context, delivery models.WebhookDelivery) {
+	if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+		slog.Error("Failed to save webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send posts the payload of a delivery and returns the response status. Any
+// status outside 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, cfg config.WebhookConfig, webhook models.Webhook, delivery models.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "services-api-webhooks")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value of a payload: the hex encoded
+// HMAC-SHA256 of the body keyed with the webhook secret, prefixed with "sha256="
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the delay after the given number of failed attempts
+func backoff(cfg config.WebhookConfig, attempts int) time.Duration {
+	delay := cfg.RetryBackoff
+	for i := 1; i < attempts && delay < cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, cfg.MaxBackoff)
+}
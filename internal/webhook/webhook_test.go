@@ -0,0 +1,271 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"services-api/internal/config"
+	"services-api/internal/models"
+	"services-api/internal/repository"
+)
+
+// memoryRepo is an in-memory WebhookRepository
+type memoryRepo struct {
+	mu         sync.Mutex
+	webhooks   map[uint]*models.Webhook
+	deliveries []*models.WebhookDelivery
+}
+
+func newMemoryRepo(webhooks ...models.Webhook) *memoryRepo {
+	repo := &memoryRepo{webhooks: map[uint]*models.Webhook{}}
+	for i := range webhooks {
+		repo.webhooks[webhooks[i].ID] = &webhooks[i]
+	}
+	return repo
+}
+
+func (m *memoryRepo) CreateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error) {
+	panic("not used")
+}
+func (m *memoryRepo) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var webhooks []models.Webhook
+	for _, webhook := range m.webhooks {
+		webhooks = append(webhooks, *webhook)
+	}
+	return webhooks, nil
+}
+func (m *memoryRepo) GetWebhook(ctx context.Context, id uint) (*models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if webhook, ok := m.webhooks[id]; ok {
+		copied := *webhook
+		return &copied, nil
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memoryRepo) UpdateWebhook(ctx context.Context, webhook models.Webhook) (*models.Webhook, error) {
+	panic("not used")
+}
+func (m *memoryRepo) DeleteWebhook(ctx context.Context, id uint) error {
+	panic("not used")
+}
+func (m *memoryRepo) RecordWebhookSuccess(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.webhooks[id].ConsecutiveFailures = 0
+	return nil
+}
+func (m *memoryRepo) RecordWebhookFailure(ctx context.Context, id uint, disableAfter int, reason string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	webhook := m.webhooks[id]
+	webhook.ConsecutiveFailures++
+	if webhook.DisabledAt == nil && webhook.ConsecutiveFailures >= disableAfter {
+		webhook.DisabledAt, webhook.DisabledReason = &at, reason
+		return true, nil
+	}
+	return false, nil
+}
+func (m *memoryRepo) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, delivery := range deliveries {
+		if m.queued(delivery) {
+			continue
+		}
+		delivery.ID = uint(len(m.deliveries) + 1)
+		m.deliveries = append(m.deliveries, &delivery)
+	}
+	return nil
+}
+
+// queued reports whether the event of delivery is already queued for its
+// webhook, like the unique index of the table
+func (m *memoryRepo) queued(delivery models.WebhookDelivery) bool {
+	if delivery.RedeliveryOf != nil {
+		return false
+	}
+	for _, existing := range m.deliveries {
+		if existing.RedeliveryOf == nil && existing.WebhookID == delivery.WebhookID && existing.EventID == delivery.EventID {
+			return true
+		}
+	}
+	return false
+}
+func (m *memoryRepo) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]models.WebhookDelivery, error) {
+	panic("not used")
+}
+func (m *memoryRepo) GetDelivery(ctx context.Context, webhookID uint, id uint) (*models.WebhookDelivery, error) {
+	panic("not used")
+}
+func (m *memoryRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var claimed []models.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if len(claimed) < limit && delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			leased := now.Add(lease)
+			delivery.NextAttemptAt = &leased
+			claimed = append(claimed, *delivery)
+		}
+	}
+	return claimed, nil
+}
+func (m *memoryRepo) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	*m.deliveries[delivery.ID-1] = delivery
+	return nil
+}
+
+var testConfig = config.WebhookConfig{
+	Timeout:      time.Second,
+	MaxAttempts:  3,
+	RetryBackoff: time.Minute,
+	MaxBackoff:   90 * time.Second,
+	DisableAfter: 2,
+}
+
+// newTestDispatcher returns a dispatcher whose clock is advanced by the returned function
+func newTestDispatcher(repo *memoryRepo) (*Dispatcher, func(time.Duration)) {
+	d := NewDispatcher(repo, testConfig)
+	now := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	return d, func(elapsed time.Duration) { now = now.Add(elapsed) }
+}
+
+func TestDispatcher_DeliversSignedPayloads(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- request{r.Header, body}
+	}))
+	defer server.Close()
+
+	repo := newMemoryRepo(
+		models.Webhook{ID: 1, URL: server.URL, Events: []string{models.EventVersionCreated}, Secret: "0123456789abcdef", ConsecutiveFailures: 1},
+		models.Webhook{ID: 2, URL: server.URL, Events: []string{models.EventServiceDeleted}, Secret: "0123456789abcdef"},
+	)
+	d, _ := newTestDispatcher(repo)
+
+	event := models.Event{ID: "evt_1", Type: models.EventVersionCreated, ServiceID: 3, Data: json.RawMessage(`{"version":"1.0.0"}`)}
+	if err := d.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("expected a delivery for the subscribed webhook only, got %d", len(repo.deliveries))
+	}
+	// Events published again, as after a relay retry, aren't queued twice
+	if err := d.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("expected the event to be queued once, got %d deliveries", len(repo.deliveries))
+	}
+	if n := d.deliverDue(context.Background()); n != 1 {
+		t.Fatalf("expected one delivery to be sent, got %d", n)
+	}
+
+	req := <-received
+	if req.header.Get(SignatureHeader) != Sign("0123456789abcdef", req.body) {
+		t.Errorf("signature %q doesn't match the body", req.header.Get(SignatureHeader))
+	}
+	if req.header.Get(EventHeader) != models.EventVersionCreated || req.header.Get(DeliveryHeader) != "1" {
+		t.Errorf("unexpected headers: %v", req.header)
+	}
+	var sent models.Event
+	if err := json.Unmarshal(req.body, &sent); err != nil || sent.ID != "evt_1" || string(sent.Data) != `{"version":"1.0.0"}` {
+		t.Errorf("unexpected payload %s: %v", req.body, err)
+	}
+
+	delivery := repo.deliveries[0]
+	if delivery.Status != models.DeliverySucceeded || delivery.Attempts != 1 || delivery.ResponseStatus != 200 || delivery.DeliveredAt == nil {
+		t.Errorf("unexpected delivery: %+v", delivery)
+	}
+	if repo.webhooks[1].ConsecutiveFailures != 0 {
+		t.Error("expected a success to reset the failures")
+	}
+}
+
+func TestDispatcher_RetriesAndDisables(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	repo := newMemoryRepo(models.Webhook{ID: 1, URL: server.URL, Events: []string{"*"}, Secret: "0123456789abcdef"})
+	d, advance := newTestDispatcher(repo)
+	ctx := context.Background()
+
+	if err := d.Publish(ctx, models.Event{ID: "evt_1", Type: models.EventServiceCreated}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.deliverDue(ctx)
+	delivery := repo.deliveries[0]
+	if delivery.Status != models.DeliveryPending || delivery.Attempts != 1 || delivery.Error != "unexpected status 502" {
+		t.Fatalf("expected a retry to be scheduled, got %+v", delivery)
+	}
+
+	// Retries wait a minute, then the delay doubles up to the maximum
+	if n := d.deliverDue(ctx); n != 0 {
+		t.Errorf("expected no retry before the backoff, got %d", n)
+	}
+	advance(time.Minute)
+	d.deliverDue(ctx)
+	advance(time.Minute)
+	if n := d.deliverDue(ctx); n != 0 {
+		t.Errorf("expected the second retry to wait 90 seconds, got %d", n)
+	}
+	advance(30 * time.Second)
+	d.deliverDue(ctx)
+	if delivery.Status != models.DeliveryFailed || delivery.Attempts != 3 || delivery.NextAttemptAt != nil {
+		t.Fatalf("expected the delivery to fail after 3 attempts, got %+v", delivery)
+	}
+	if repo.webhooks[1].ConsecutiveFailures != 1 || repo.webhooks[1].DisabledAt != nil {
+		t.Fatalf("expected one failure to be counted, got %+v", repo.webhooks[1])
+	}
+
+	// A second failed delivery disables the webhook
+	if err := d.Publish(ctx, models.Event{ID: "evt_2", Type: models.EventServiceCreated}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		d.deliverDue(ctx)
+		advance(2 * time.Minute)
+	}
+	if repo.webhooks[1].DisabledAt == nil || repo.webhooks[1].DisabledReason != "2 consecutive deliveries failed" {
+		t.Errorf("expected the webhook to be disabled, got %+v", repo.webhooks[1])
+	}
+
+	if err := d.Publish(ctx, models.Event{ID: "evt_3", Type: models.EventServiceCreated}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.deliveries) != 2 {
+		t.Errorf("expected no deliveries for a disabled webhook, got %d", len(repo.deliveries))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := config.WebhookConfig{RetryBackoff: 30 * time.Second, MaxBackoff: time.Hour}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i, delay := range want {
+		if got := backoff(cfg, i+1); got != delay {
+			t.Errorf("backoff after %d attempts = %s, want %s", i+1, got, delay)
+		}
+	}
+	if got := backoff(cfg, 50); got != time.Hour {
+		t.Errorf("expected the backoff to be capped, got %s", got)
+	}
+}
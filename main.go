@@ -1,17 +1,12 @@
 package main
 
 import (
-	"context"
-	"log"
-	"net/http"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	"services-api/internal/config"
-	"services-api/internal/db"
-	"services-api/internal/server"
 
 	_ "services-api/docs"
 )
@@ -22,70 +17,39 @@ import (
 // @BasePath /api/v1
 // @host localhost:8080
 // @schemes http
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 
 func main() {
-	// Load configuration
-	cfg := config.Load()
-
-	// Initialize database
-	database, err := db.Initialize(cfg.DatabaseURL)
-	if err != nil {
-		log.Fatal("Failed to initialize database:", err)
-	}
-
-	// Configure connection pool
-	if err := db.ConfigureConnectionPool(database, 10, 100, time.Hour); err != nil {
-		log.Fatal("Failed to configure connection pool:", err)
-	}
-
-	// Run migrations
-	if err := db.Migrate(database); err != nil {
-		log.Fatal("Failed to run migrations:", err)
-	}
-
-	// Initialize API server (routes are set up in the constructor)
-	srv := server.NewServer(database, cfg)
-	
-	// Setup HTTP server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	os.Exit(runCommand(os.Args[1:]))
+}
+
+// runConfigCommand implements "config print", which writes the effective
+// configuration with secrets redacted and reports any validation errors.
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: services-api config print [flags]")
+		return 2
 	}
-	addr := ":" + port
 
-	// Create HTTP server with the router
-	httpServer := &http.Server{
-		Addr:    addr,
-		Handler: srv.Router(),
-	}
-
-	// Start server in a goroutine
-	go func() {
-		log.Printf("Starting server on port %s", port)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+	cfg, err := config.Load(args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
 		}
-	}()
-
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
-
-	// Create a deadline for the shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Shutdown the server
-	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+		fmt.Fprintln(os.Stderr, "Failed to load configuration:", err)
+		return 1
 	}
 
-	// Close database connections
-	if err := db.Close(database); err != nil {
-		log.Fatal("Error closing database connection:", err)
+	if err := cfg.Print(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to print configuration:", err)
+		return 1
 	}
 
-	log.Println("Server exited gracefully")
-}
\ No newline at end of file
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return 1
+	}
+	return 0
+}
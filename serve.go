@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	"services-api/internal/config"
+	"services-api/internal/db"
+	"services-api/internal/logging"
+	"services-api/internal/server"
+)
+
+// runServeCommand implements "serve", which runs the API server until it is
+// interrupted. Without a command the binary serves, so flags can still be
+// given directly.
+func runServeCommand(args []string) int {
+	// Load and validate configuration
+	cfg, err := config.Load(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		log.Fatal("Failed to load configuration:", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	// Configure logging
+	if err := logging.Setup(cfg.Log); err != nil {
+		log.Fatal("Failed to configure logging:", err)
+	}
+
+	// Reload runtime settings on SIGHUP or when the config file changes
+	watcher := config.NewWatcher(cfg, args, config.DefaultWatchInterval)
+	watcher.Subscribe(func(old, updated *config.Config) {
+		if err := logging.Setup(updated.Log); err != nil {
+			slog.Error("Failed to apply logging configuration", "error", err)
+		}
+	})
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go watcher.Run(watchCtx)
+
+	// Initialize database
+	database, err := db.Initialize(cfg.Database.URL)
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+
+	// Configure connection pool
+	if err := db.ConfigureConnectionPool(database, cfg.Database.MaxIdleConns, cfg.Database.MaxOpenConns, cfg.Database.ConnMaxLifetime); err != nil {
+		log.Fatal("Failed to configure connection pool:", err)
+	}
+
+	// Run migrations
+	if err := db.Migrate(database); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	// Initialize API server (routes are set up in the constructor)
+	srv := server.NewServer(database, cfg)
+	watcher.Subscribe(func(old, updated *config.Config) {
+		srv.ApplyConfig(updated)
+	})
+
+	// Publish the events of catalog changes, stream them and deliver them to webhooks
+	go srv.Outbox().Run(watchCtx)
+	go srv.Stream().Run(watchCtx)
+	go srv.Webhooks().Run(watchCtx)
+
+	// Reconcile the catalog with the sync directory on a schedule
+	if cfg.Sync.Enabled {
+		log.Printf("Syncing services from %s every %s", cfg.Sync.Dir, cfg.Sync.Interval)
+		go newReconciler(database, cfg.Sync).Run(watchCtx, cfg.Sync.Interval)
+	}
+
+	// Create HTTP server with the router
+	httpServer, err := server.NewHTTPServer(cfg.Server, srv.Router())
+	if err != nil {
+		log.Fatal("Failed to configure HTTP server:", err)
+	}
+	// Event streams never go idle, so end them when the shutdown starts
+	httpServer.RegisterOnShutdown(srv.Stream().Close)
+
+	// Start server in a goroutine
+	go func() {
+		log.Printf("Starting server on %s (TLS: %t)", httpServer.Addr, cfg.Server.TLS.Enabled)
+		if err := server.ListenAndServe(httpServer); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Start the admin listener for metrics, health and pprof
+	var adminServer *http.Server
+	if cfg.Server.Admin.Enabled {
+		adminServer = server.NewAdminHTTPServer(cfg.Server, srv.AdminRouter())
+		go func() {
+			log.Printf("Starting admin server on %s", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start admin server: %v", err)
+			}
+		}()
+	}
+
+	// Start the gRPC listener
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPC.Enabled {
+		grpcServer, err = srv.NewGRPCServer()
+		if err != nil {
+			log.Fatal("Failed to configure gRPC server:", err)
+		}
+		listener, err := net.Listen("tcp", cfg.Server.GRPC.Addr())
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC:", err)
+		}
+		go func() {
+			log.Printf("Starting gRPC server on %s (TLS: %t)", listener.Addr(), cfg.Server.TLS.Enabled)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+	stopWatching()
+
+	// Create a deadline for the shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// Shutdown the servers, going on with the cleanup if they take too long
+	if err := httpServer.Shutdown(ctx); err != nil {
+		slog.Warn("Server forced to shutdown", "error", err)
+		httpServer.Close()
+	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			slog.Warn("Admin server forced to shutdown", "error", err)
+		}
+	}
+
+	if grpcServer != nil {
+		stopGRPC(ctx, grpcServer)
+	}
+
+	// Close database connections
+	if err := db.Close(database); err != nil {
+		slog.Error("Error closing database connection", "error", err)
+		return 1
+	}
+
+	log.Println("Server exited gracefully")
+	return 0
+}
+
+// stopGRPC waits for the running gRPC calls to complete, then closes the
+// calls still running when ctx is done, such as watches
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Warn("gRPC server forced to shutdown")
+		grpcServer.Stop()
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+
+	"services-api/internal/config"
+	"services-api/internal/db"
+	"services-api/internal/reconcile"
+)
+
+// runSyncCommand implements "sync plan" and "sync apply", which compare the
+// catalog with the service files of the sync directory and print or apply
+// the changes. It fails when a file is skipped so it can gate CI pipelines.
+func runSyncCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "plan" && args[0] != "apply") {
+		fmt.Fprintln(os.Stderr, "usage: services-api sync plan|apply [flags]")
+		return exitStatus(errUsage)
+	}
+
+	fs := newFlagSet("sync "+args[0], "")
+	cfg, err := loadCommandConfig(fs, args[1:])
+	if err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitStatus(errUsage)
+	}
+	if cfg.Sync.Dir == "" {
+		fmt.Fprintln(os.Stderr, "The sync directory is required, set SYNC_DIR or -sync-dir")
+		return exitStatus(errUsage)
+	}
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return exitStatus(err)
+	}
+	defer db.Close(database)
+
+	reconciler := newReconciler(database, cfg.Sync)
+	var result *reconcile.Result
+	if args[0] == "apply" {
+		result, err = reconciler.Apply(context.Background())
+	} else {
+		result, err = reconciler.Plan(context.Background())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Sync failed:", err)
+		return 1
+	}
+
+	if err := reconcile.WritePlan(os.Stdout, result); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to print plan:", err)
+		return 1
+	}
+	if len(result.Report.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// newReconciler creates a reconciler of the sync directory over database
+func newReconciler(database *gorm.DB, cfg config.SyncConfig) *reconcile.Reconciler {
+	return reconcile.New(newCatalogBusiness(database), cfg.Dir, cfg.ManagedBy, cfg.Adopt)
+}